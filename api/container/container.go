@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,11 +24,40 @@ type TaskHandlerInterface interface {
 	CreateChildTask(c *gin.Context)
 	GetTask(c *gin.Context)
 	GetAllTasks(c *gin.Context)
+	FindTasksByDescription(c *gin.Context)
 	GetRootTask(c *gin.Context)
 	GetTaskChildren(c *gin.Context)
+	GetTaskRoot(c *gin.Context)
+	GetSiblingCount(c *gin.Context)
+	GetTaskPosition(c *gin.Context)
+	GetTaskActivity(c *gin.Context)
+	GetArchive(c *gin.Context)
+	RestoreArchivedTree(c *gin.Context)
+	PromoteToRoot(c *gin.Context)
+	GetTaskBlockers(c *gin.Context)
+	GetStatusOptions(c *gin.Context)
+	ExportSubtree(c *gin.Context)
 	UpdateTask(c *gin.Context)
 	UpdateTaskStatus(c *gin.Context)
 	MoveTask(c *gin.Context)
+	MoveTasks(c *gin.Context)
+	NudgeTasks(c *gin.Context)
+	ValidateTree(c *gin.Context)
+	ExportTasks(c *gin.Context)
+	ImportTasks(c *gin.Context)
+	GroupTasks(c *gin.Context)
+	UngroupTask(c *gin.Context)
+	GetTaskHistory(c *gin.Context)
+	GetTaskTimeline(c *gin.Context)
+	GetTaskGraph(c *gin.Context)
+	GetTaskCount(c *gin.Context)
+	GetTaskChanges(c *gin.Context)
+	GetPlan(c *gin.Context)
+	StartNextTasks(c *gin.Context)
+	ToggleTaskStatus(c *gin.Context)
+	TagSubtree(c *gin.Context)
+	ResetSubtree(c *gin.Context)
+	SetTaskKind(c *gin.Context)
 	DeleteTask(c *gin.Context)
 }
 
@@ -35,25 +66,167 @@ type HealthHandlerInterface interface {
 	HealthCheck(c *gin.Context)
 }
 
+// MetricsHandlerInterface defines the contract for metrics handlers
+type MetricsHandlerInterface interface {
+	Metrics(c *gin.Context)
+}
+
+// TemplateHandlerInterface defines the contract for task template handlers
+type TemplateHandlerInterface interface {
+	ListTemplates(c *gin.Context)
+	InstantiateTemplate(c *gin.Context)
+}
+
+// StatusHandlerInterface defines the contract for status presentation handlers
+type StatusHandlerInterface interface {
+	ListStatuses(c *gin.Context)
+}
+
+// StatsHandlerInterface defines the contract for progress statistics handlers
+type StatsHandlerInterface interface {
+	GetStats(c *gin.Context)
+}
+
+// AdminHandlerInterface defines the contract for maintenance handlers
+type AdminHandlerInterface interface {
+	CompactDataFile(c *gin.Context)
+	RenameStatus(c *gin.Context)
+	GetDiagnostics(c *gin.Context)
+}
+
 // Handler implementations are now in the handlers package
 
 // Config holds configuration settings for the API server
 type Config struct {
-	Port         string `json:"port"`
-	DataPath     string `json:"dataPath"`
-	LogLevel     string `json:"logLevel"`
-	EnableCORS   bool   `json:"enableCORS"`
-	EnableSwagger bool  `json:"enableSwagger"`
+	Port                 string        `json:"port"`
+	DataPath             string        `json:"dataPath"`
+	LogLevel             string        `json:"logLevel"`
+	LogFormat            string        `json:"logFormat"`
+	EnableCORS           bool          `json:"enableCORS"`
+	EnableSwagger        bool          `json:"enableSwagger"`
+	PositionBase         int           `json:"positionBase"`
+	TrimDescriptions     bool          `json:"trimDescriptions"`
+	SeedFile             string        `json:"seedFile"`
+	DoneParentPolicy     string        `json:"doneParentPolicy"`
+	APIBasePath          string        `json:"apiBasePath"`
+	MaxTotalTasks        int           `json:"maxTotalTasks"`
+	StrictSingleRoot     bool          `json:"strictSingleRoot"`
+	PersistMaxRetries    int           `json:"persistMaxRetries"`
+	PersistRetryBackoff  time.Duration `json:"persistRetryBackoff"`
+	TrustedProxies       []string      `json:"trustedProxies"`
+	MoveHistoryLimit     int           `json:"moveHistoryLimit"`
+	SwaggerHost          string        `json:"swaggerHost"`
+	SwaggerSchemes       []string      `json:"swaggerSchemes"`
+	AutoCreateRoot       string        `json:"autoCreateRoot"`
+	ToggleStatuses       []string      `json:"toggleStatuses"`
+	PersistCoalesce      bool          `json:"persistCoalesce"`
+	ResponseEnvelope     string        `json:"responseEnvelope"`
+	IDScheme             string        `json:"idScheme"`
+	MaxSubtreeOperation  int           `json:"maxSubtreeOperation"`
+	FileLock             bool          `json:"fileLock"`
+	StartupSelfTest      bool          `json:"startupSelfTest"`
+	HTTPReadTimeout      time.Duration `json:"httpReadTimeout"`
+	HTTPWriteTimeout     time.Duration `json:"httpWriteTimeout"`
+	HTTPIdleTimeout      time.Duration `json:"httpIdleTimeout"`
+	NormalizeOnWrite     bool          `json:"normalizeOnWrite"`
+	TemplateDir          string        `json:"templateDir"`
+	MaxDescriptionLength int           `json:"maxDescriptionLength"`
+	PersistDebounce      time.Duration `json:"persistDebounce"`
+	MessagesFile         string        `json:"messagesFile"`
+	CrossRootMoves       string        `json:"crossRootMoves"`
+	StatusColors         []string      `json:"statusColors"`
+	MaxInFlight          int           `json:"maxInFlight"`
+	ChildInsertMode      string        `json:"childInsertMode"`
+	DataFileMinify       bool          `json:"dataFileMinify"`
+	HealthCheckTimeout   time.Duration `json:"healthCheckTimeout"`
+	ReopenStatus         string        `json:"reopenStatus"`
+	StrictTimestamps     bool          `json:"strictTimestamps"`
+	TouchAncestors       string        `json:"touchAncestors"`
+	CollapseWhitespace   bool          `json:"collapseWhitespace"`
+	MaxBatchSize         int           `json:"maxBatchSize"`
+	EncryptionKey        string        `json:"-"` // never serialized
+	ChildDescTemplate    string        `json:"childDescriptionTemplate"`
+	MoveClampPosition    bool          `json:"moveClampPosition"`
+	SnapshotReads        bool          `json:"snapshotReads"`
+	StatusWeights        []string      `json:"statusWeights"`
+	ArchiveOnComplete    bool          `json:"archiveOnComplete"`
+	ArchivePath          string        `json:"archivePath"`
+	RequireDecomposition bool          `json:"requireDecomposition"`
+	DecompositionTags    []string      `json:"decompositionTags"`
+	DecompositionMin     int           `json:"decompositionMinChildren"`
+	EnableOpenAPIJSON    bool          `json:"enableOpenAPIJSON"`
+	TaskKinds            []string      `json:"taskKinds"`
+	DecompositionKinds   []string      `json:"decompositionKinds"`
+	DeleteGuard          string        `json:"deleteGuard"`
+	StatusHistoryLimit   int           `json:"statusHistoryLimit"`
+	CompleteStatuses     []string      `json:"completeStatuses"`
 }
 
 // LoadConfigFromEnv loads configuration from environment variables with defaults
 func LoadConfigFromEnv() *Config {
 	config := &Config{
-		Port:         getEnvOrDefault("PORT", "8080"),
-		DataPath:     getEnvOrDefault("DATA_PATH", "./data/tasks.json"),
-		LogLevel:     getEnvOrDefault("LOG_LEVEL", "info"),
-		EnableCORS:   getEnvBoolOrDefault("ENABLE_CORS", true),
-		EnableSwagger: getEnvBoolOrDefault("ENABLE_SWAGGER", true),
+		Port:                 getEnvOrDefault("PORT", "8080"),
+		DataPath:             getEnvOrDefault("DATA_PATH", "./data/tasks.json"),
+		LogLevel:             getEnvOrDefault("LOG_LEVEL", "info"),
+		LogFormat:            getEnvOrDefault("LOG_FORMAT", ""),
+		EnableCORS:           getEnvBoolOrDefault("ENABLE_CORS", true),
+		EnableSwagger:        getEnvBoolOrDefault("ENABLE_SWAGGER", true),
+		PositionBase:         getEnvIntOrDefault("POSITION_BASE", 0),
+		TrimDescriptions:     getEnvBoolOrDefault("TRIM_DESCRIPTIONS", false),
+		SeedFile:             getEnvOrDefault("SEED_FILE", ""),
+		DoneParentPolicy:     getEnvOrDefault("DONE_PARENT_POLICY", string(domain.DoneParentReject)),
+		APIBasePath:          getEnvOrDefault("API_BASE_PATH", "/api/v1"),
+		MaxTotalTasks:        getEnvIntOrDefault("MAX_TOTAL_TASKS", 0),
+		StrictSingleRoot:     getEnvBoolOrDefault("STRICT_SINGLE_ROOT", false),
+		PersistMaxRetries:    getEnvIntOrDefault("PERSIST_MAX_RETRIES", 3),
+		PersistRetryBackoff:  getEnvDurationOrDefault("PERSIST_RETRY_BACKOFF", 50*time.Millisecond),
+		TrustedProxies:       getEnvListOrDefault("TRUSTED_PROXIES", []string{"127.0.0.1/32", "::1/128"}),
+		MoveHistoryLimit:     getEnvIntOrDefault("MOVE_HISTORY_LIMIT", 50),
+		SwaggerHost:          getEnvOrDefault("SWAGGER_HOST", "localhost:8080"),
+		SwaggerSchemes:       getEnvListOrDefault("SWAGGER_SCHEMES", []string{"http", "https"}),
+		AutoCreateRoot:       getEnvOrDefault("AUTO_CREATE_ROOT", ""),
+		ToggleStatuses:       getEnvListOrDefault("TOGGLE_STATUSES", []string{"TODO", "DONE"}),
+		PersistCoalesce:      getEnvBoolOrDefault("PERSIST_COALESCE", false),
+		ResponseEnvelope:     getEnvOrDefault("RESPONSE_ENVELOPE", "bare"),
+		IDScheme:             getEnvOrDefault("ID_SCHEME", string(domain.IDSchemeUUID)),
+		MaxSubtreeOperation:  getEnvIntOrDefault("MAX_SUBTREE_OPERATION", 10000),
+		FileLock:             getEnvBoolOrDefault("FILE_LOCK", true),
+		StartupSelfTest:      getEnvBoolOrDefault("STARTUP_SELFTEST", false),
+		HTTPReadTimeout:      getEnvDurationOrDefault("HTTP_READ_TIMEOUT", 15*time.Second),
+		HTTPWriteTimeout:     getEnvDurationOrDefault("HTTP_WRITE_TIMEOUT", 15*time.Second),
+		HTTPIdleTimeout:      getEnvDurationOrDefault("HTTP_IDLE_TIMEOUT", 60*time.Second),
+		NormalizeOnWrite:     getEnvBoolOrDefault("NORMALIZE_ON_WRITE", false),
+		TemplateDir:          getEnvOrDefault("TEMPLATE_DIR", ""),
+		MaxDescriptionLength: getEnvIntOrDefault("MAX_DESCRIPTION_LENGTH", 0),
+		PersistDebounce:      getEnvDurationOrDefault("PERSIST_DEBOUNCE", 0),
+		MessagesFile:         getEnvOrDefault("MESSAGES_FILE", ""),
+		CrossRootMoves:       getEnvOrDefault("CROSS_ROOT_MOVES", string(domain.CrossRootMovesAllow)),
+		StatusColors:         getEnvListOrDefault("STATUS_COLORS", nil),
+		MaxInFlight:          getEnvIntOrDefault("MAX_INFLIGHT", 0),
+		ChildInsertMode:      getEnvOrDefault("CHILD_INSERT_MODE", string(domain.ChildInsertAppend)),
+		DataFileMinify:       getEnvBoolOrDefault("DATA_FILE_MINIFY", false),
+		HealthCheckTimeout:   getEnvDurationOrDefault("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		ReopenStatus:         getEnvOrDefault("REOPEN_STATUS", domain.StatusInProgress.String()),
+		StrictTimestamps:     getEnvBoolOrDefault("STRICT_TIMESTAMPS", false),
+		TouchAncestors:       getEnvOrDefault("TOUCH_ANCESTORS_ON_CHANGE", string(domain.TouchAncestorsOff)),
+		CollapseWhitespace:   getEnvBoolOrDefault("CANONICALIZE_WHITESPACE", false),
+		MaxBatchSize:         getEnvIntOrDefault("MAX_BATCH_SIZE", 500),
+		EncryptionKey:        getEnvOrDefault("ENCRYPTION_KEY", ""),
+		ChildDescTemplate:    getEnvOrDefault("CHILD_DESCRIPTION_TEMPLATE", ""),
+		MoveClampPosition:    getEnvBoolOrDefault("MOVE_CLAMP_POSITION", false),
+		SnapshotReads:        getEnvBoolOrDefault("SNAPSHOT_READS", false),
+		StatusWeights:        getEnvListOrDefault("STATUS_WEIGHTS", nil),
+		ArchiveOnComplete:    getEnvBoolOrDefault("ARCHIVE_ON_COMPLETE", false),
+		ArchivePath:          getEnvOrDefault("ARCHIVE_PATH", ""),
+		RequireDecomposition: getEnvBoolOrDefault("REQUIRE_DECOMPOSITION", false),
+		DecompositionTags:    getEnvListOrDefault("DECOMPOSITION_TAGS", []string{"epic"}),
+		DecompositionMin:     getEnvIntOrDefault("DECOMPOSITION_MIN_CHILDREN", 1),
+		EnableOpenAPIJSON:    getEnvBoolOrDefault("ENABLE_OPENAPI_JSON", false),
+		TaskKinds:            getEnvListOrDefault("TASK_KINDS", []string{domain.DefaultTaskKind, "spike", "milestone"}),
+		DecompositionKinds:   getEnvListOrDefault("DECOMPOSITION_KINDS", nil),
+		DeleteGuard:          getEnvOrDefault("DELETE_GUARD", string(domain.DeleteGuardCascade)),
+		StatusHistoryLimit:   getEnvIntOrDefault("STATUS_HISTORY_LIMIT", 50),
+		CompleteStatuses:     getEnvListOrDefault("COMPLETE_STATUSES", []string{domain.StatusDONE.String()}),
 	}
 	return config
 }
@@ -76,17 +249,61 @@ func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvIntOrDefault returns environment variable as int or default if not set/invalid
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDurationOrDefault returns environment variable as duration or default if not set/invalid
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvListOrDefault returns a comma-separated environment variable as a list or default if not set
+// Entries are trimmed and empty entries are dropped
+func getEnvListOrDefault(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var list []string
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				list = append(list, entry)
+			}
+		}
+		return list
+	}
+	return defaultValue
+}
+
 // Container holds all application dependencies and provides dependency injection
 // It implements singleton pattern for services to ensure single instances
 type Container struct {
-	config         *Config
-	taskRepository domain.TaskRepository
-	taskService    *domain.TaskService
-	
+	config             *Config
+	taskRepository     domain.TaskRepository
+	fileRepository     *infrastructure.FileTaskRepository // storage under taskRepository, for compaction, stats and closing
+	readiness          *domain.ReadinessEvaluatorService
+	taskService        *domain.TaskService
+	templateRepository domain.TemplateRepository
+	errorMessages      map[string]string // user-facing error messages by error code, from MESSAGES_FILE
+
 	// Singleton instances for handlers (created on first access)
-	taskHandler   TaskHandlerInterface
-	healthHandler HealthHandlerInterface
-	
+	taskHandler     TaskHandlerInterface
+	healthHandler   HealthHandlerInterface
+	templateHandler TemplateHandlerInterface
+	metricsHandler  MetricsHandlerInterface
+	statusHandler   StatusHandlerInterface
+	statsHandler    StatsHandlerInterface
+	adminHandler    AdminHandlerInterface
+
 	// Service lifecycle management
 	initialized bool
 	shutdown    bool
@@ -114,9 +331,17 @@ func configureSlog(config *Config) error {
 		Level: level,
 	}
 
-	// Use JSON handler for structured logging in production, text handler for development
+	// Use the configured format; when unset, JSON in production (release mode) and text for development
+	format := strings.ToLower(config.LogFormat)
+	if format == "" {
+		format = "text"
+		if gin.Mode() == gin.ReleaseMode {
+			format = "json"
+		}
+	}
+
 	var handler slog.Handler
-	if gin.Mode() == gin.ReleaseMode {
+	if format == "json" {
 		handler = slog.NewJSONHandler(os.Stdout, opts)
 	} else {
 		handler = slog.NewTextHandler(os.Stdout, opts)
@@ -128,6 +353,7 @@ func configureSlog(config *Config) error {
 
 	slog.Info("Logging configured",
 		slog.String("level", level.String()),
+		slog.String("format", format),
 		slog.String("mode", gin.Mode()),
 	)
 
@@ -152,28 +378,285 @@ func NewContainer(config *Config) (*Container, error) {
 	)
 
 	// Initialize the task repository with the configured data path
-	taskRepository, err := infrastructure.NewFileTaskRepository(config.DataPath)
+	fileRepository, err := infrastructure.NewFileTaskRepositoryWithOptions(config.DataPath, repositoryOptions(config))
 	if err != nil {
 		slog.Error("Failed to initialize task repository", slog.String("error", err.Error()))
 		return nil, fmt.Errorf("failed to initialize task repository: %w", err)
 	}
 
-	// Initialize the task service with the repository dependency
-	taskService := domain.NewTaskService(taskRepository)
+	// Open the archive of completed branches, kept in a file of its own
+	taskArchive, err := infrastructure.NewFileTaskArchive(archivePath(config), infrastructure.FileTaskArchiveOptions{
+		EncryptionKey:    config.EncryptionKey,
+		StrictTimestamps: config.StrictTimestamps,
+	})
+	if err != nil {
+		slog.Error("Failed to initialize task archive", slog.String("error", err.Error()))
+		fileRepository.Close() // Release the data file lock so a retry can start
+		return nil, fmt.Errorf("failed to initialize task archive: %w", err)
+	}
+
+	// Publish every change, so cached readiness states are invalidated whichever way a task is saved
+	taskRepository := domain.NewObservableTaskRepository(fileRepository)
+	complete := completeStatuses(config.CompleteStatuses)
+	readiness := domain.NewCachedReadinessEvaluatorService(taskRepository, domain.NewTreeNavigatorService(taskRepository), complete)
+
+	// Initialize the task service with the repository, archive and readiness dependencies
+	taskServiceConfig := serviceConfig(config)
+	taskServiceConfig.Archive = taskArchive
+	taskServiceConfig.Readiness = readiness
+	taskService := domain.NewTaskServiceWithConfig(taskRepository, taskServiceConfig)
+
+	// Seed an empty store from the configured seed file
+	if err := seedRepository(config, taskRepository, taskService); err != nil {
+		slog.Error("Failed to seed task repository", slog.String("error", err.Error()))
+		fileRepository.Close() // Release the data file lock so a retry can start
+		return nil, fmt.Errorf("failed to seed task repository: %w", err)
+	}
+
+	// Exercise the storage stack end to end before accepting traffic
+	if config.StartupSelfTest {
+		if err := runSelfTest(config); err != nil {
+			slog.Error("Startup self-test failed", slog.String("error", err.Error()))
+			fileRepository.Close() // Release the data file lock so a retry can start
+			return nil, fmt.Errorf("startup self-test failed: %w", err)
+		}
+	}
+
+	// Load the user-facing error messages, so a broken messages file fails startup
+	errorMessages, err := infrastructure.LoadMessagesFile(config.MessagesFile)
+	if err != nil {
+		slog.Error("Failed to load messages file", slog.String("error", err.Error()))
+		fileRepository.Close() // Release the data file lock so a retry can start
+		return nil, fmt.Errorf("failed to load messages file: %w", err)
+	}
 
 	// Create the container with all dependencies
 	container := &Container{
-		config:         config,
-		taskRepository: taskRepository,
-		taskService:    taskService,
-		initialized:    true,
-		shutdown:       false,
+		config:             config,
+		taskRepository:     taskRepository,
+		fileRepository:     fileRepository,
+		readiness:          readiness,
+		taskService:        taskService,
+		templateRepository: infrastructure.NewDirTemplateRepository(config.TemplateDir),
+		errorMessages:      errorMessages,
+		initialized:        true,
+		shutdown:           false,
 	}
 
 	slog.Info("Container initialized successfully")
 	return container, nil
 }
 
+// repositoryOptions derives the file repository options from the container configuration
+func repositoryOptions(config *Config) infrastructure.FileTaskRepositoryOptions {
+	return infrastructure.FileTaskRepositoryOptions{
+		MaxRetries:       config.PersistMaxRetries,
+		RetryBackoff:     config.PersistRetryBackoff,
+		StrictSingleRoot: config.StrictSingleRoot,
+		CoalesceWrites:   config.PersistCoalesce,
+		PersistDebounce:  config.PersistDebounce,
+		LockFile:         config.FileLock,
+		MinifyJSON:       config.DataFileMinify,
+		EncryptionKey:    config.EncryptionKey,
+		SnapshotReads:    config.SnapshotReads,
+		StrictTimestamps: config.StrictTimestamps,
+	}
+}
+
+// archivePath returns the configured archive file path, or the data file path with its
+// extension replaced by ".archive.json" (tasks.json becomes tasks.archive.json) when none is set
+func archivePath(config *Config) string {
+	if config.ArchivePath != "" {
+		return config.ArchivePath
+	}
+	return strings.TrimSuffix(config.DataPath, filepath.Ext(config.DataPath)) + ".archive.json"
+}
+
+// serviceConfig derives the task service configuration from the container configuration
+func serviceConfig(config *Config) domain.TaskServiceConfig {
+	return domain.TaskServiceConfig{
+		TrimDescriptions:         config.TrimDescriptions,
+		DoneParentPolicy:         domain.DoneParentPolicy(config.DoneParentPolicy),
+		MaxTotalTasks:            config.MaxTotalTasks,
+		MoveHistoryLimit:         config.MoveHistoryLimit,
+		StatusHistoryLimit:       config.StatusHistoryLimit,
+		ToggleStatuses:           toggleStatuses(config.ToggleStatuses),
+		IDGenerator:              domain.NewIDGenerator(domain.IDScheme(config.IDScheme)),
+		MaxSubtreeOperation:      config.MaxSubtreeOperation,
+		NormalizeOnWrite:         config.NormalizeOnWrite,
+		CrossRootMoves:           domain.CrossRootMovePolicy(config.CrossRootMoves),
+		ChildInsertMode:          domain.ChildInsertMode(config.ChildInsertMode),
+		ReopenStatus:             reopenStatus(config.ReopenStatus),
+		TouchAncestors:           domain.TouchAncestorsMode(config.TouchAncestors),
+		CollapseWhitespace:       config.CollapseWhitespace,
+		ChildDescriptionTemplate: config.ChildDescTemplate,
+		ClampMovePositions:       config.MoveClampPosition,
+		ArchiveOnComplete:        config.ArchiveOnComplete,
+		DecompositionTags:        decompositionTags(config),
+		MinDecomposedChildren:    config.DecompositionMin,
+		DecompositionKinds:       decompositionKinds(config),
+		Kinds:                    taskKinds(config),
+		DeleteGuard:              domain.DeleteGuard(config.DeleteGuard),
+		CompleteStatuses:         completeStatuses(config.CompleteStatuses),
+	}
+}
+
+// decompositionTags returns the tags whose tasks must be broken down before they are completed,
+// none unless REQUIRE_DECOMPOSITION is set
+func decompositionTags(config *Config) []string {
+	if !config.RequireDecomposition {
+		return nil
+	}
+	tags, err := domain.NormalizeTags(config.DecompositionTags)
+	if err != nil {
+		return nil
+	}
+	return tags
+}
+
+// decompositionKinds returns the kinds whose tasks must be broken down before they are completed,
+// none unless REQUIRE_DECOMPOSITION is set
+func decompositionKinds(config *Config) []string {
+	if !config.RequireDecomposition {
+		return nil
+	}
+	return taskKindNames(config.DecompositionKinds)
+}
+
+// taskKinds returns the kinds tasks may be given; with none, the domain allows only the default kind
+func taskKinds(config *Config) []string {
+	return taskKindNames(config.TaskKinds)
+}
+
+// taskKindNames normalizes kind names, dropping invalid ones
+func taskKindNames(names []string) []string {
+	kinds := make([]string, 0, len(names))
+	for _, name := range names {
+		if kind, err := domain.NormalizeKind(name); err == nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// completeStatuses parses the configured complete statuses, falling back to the default for an invalid set
+func completeStatuses(names []string) domain.CompleteStatuses {
+	complete, err := domain.ParseCompleteStatuses(names)
+	if err != nil {
+		return domain.CompleteStatuses{}
+	}
+	return complete
+}
+
+// reopenStatus parses the configured reopen status, falling back to the default if it is invalid
+func reopenStatus(name string) *domain.Status {
+	status, err := domain.NewStatus(name)
+	if err != nil {
+		return nil
+	}
+	return &status
+}
+
+// toggleStatuses parses the configured toggle pair, falling back to the default for anything invalid
+func toggleStatuses(names []string) []domain.Status {
+	if len(names) != 2 {
+		return nil
+	}
+	statuses := make([]domain.Status, len(names))
+	for i, name := range names {
+		status, err := domain.NewStatus(name)
+		if err != nil {
+			return nil
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// seedRepository imports the seed file into the repository if one is configured
+// An existing non-empty store is never overwritten
+func seedRepository(config *Config, repo domain.TaskRepository, service *domain.TaskService) error {
+	if config.SeedFile == "" {
+		return nil
+	}
+
+	existing, err := repo.FindAll()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		slog.Info("Skipping seed, task store is not empty",
+			slog.String("seed_file", config.SeedFile),
+			slog.Int("existing_tasks", len(existing)),
+		)
+		return nil
+	}
+
+	root, err := infrastructure.LoadTreeImportFile(config.SeedFile)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := service.ImportTree(root)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Seeded task store",
+		slog.String("seed_file", config.SeedFile),
+		slog.Int("seeded_tasks", len(tasks)),
+	)
+	return nil
+}
+
+// runSelfTest creates a throwaway task, reads it back from disk and deletes it, using the
+// configured repository and service settings against a scratch data file next to DATA_PATH
+// The real store is never touched, and the scratch file is removed afterwards
+func runSelfTest(config *Config) error {
+	start := time.Now()
+	path := config.DataPath + ".selftest"
+	defer os.Remove(path)
+
+	// The scratch file has no other users, so it needs no lock (and leaves no lock file)
+	options := repositoryOptions(config)
+	options.LockFile = false
+
+	repo, err := infrastructure.NewFileTaskRepositoryWithOptions(path, options)
+	if err != nil {
+		return fmt.Errorf("open scratch store: %w", err)
+	}
+	defer repo.Close()
+	service := domain.NewTaskServiceWithConfig(repo, serviceConfig(config))
+
+	task, err := service.CreateRootTask("Startup self-test")
+	if err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+	if err := repo.Flush(); err != nil {
+		return fmt.Errorf("write task: %w", err)
+	}
+
+	// Read the task back through a fresh repository so it comes from the file
+	reloaded, err := infrastructure.NewFileTaskRepositoryWithOptions(path, options)
+	if err != nil {
+		return fmt.Errorf("read task: %w", err)
+	}
+	defer reloaded.Close()
+	if _, err := reloaded.FindByID(task.ID()); err != nil {
+		return fmt.Errorf("read task: %w", err)
+	}
+
+	if err := service.DeleteTask(task.ID()); err != nil {
+		return fmt.Errorf("delete task: %w", err)
+	}
+
+	slog.Info("Startup self-test passed",
+		slog.String("scratch_path", path),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
+
 // NewContainerWithDefaults creates a container with default configuration loaded from environment
 func NewContainerWithDefaults() (*Container, error) {
 	config := LoadConfigFromEnv()
@@ -195,40 +678,134 @@ func (c *Container) TaskService() *domain.TaskService {
 	return c.taskService
 }
 
+// TemplateRepository returns the task template repository instance
+func (c *Container) TemplateRepository() domain.TemplateRepository {
+	return c.templateRepository
+}
+
+// ErrorMessages returns the user-facing error messages by error code, nil if none are configured
+func (c *Container) ErrorMessages() map[string]string {
+	return c.errorMessages
+}
+
 // GetTaskHandler returns the singleton task handler instance with injected dependencies
 // This method implements proper singleton service lifetime management
 func (c *Container) GetTaskHandler() TaskHandlerInterface {
 	if err := c.ensureNotShutdown(); err != nil {
 		panic(err) // Service access after shutdown is a programming error
 	}
-	
+
 	if c.taskHandler == nil {
-		c.taskHandler = handlers.NewTaskHandler(c.taskService, c.taskRepository)
+		c.taskHandler = handlers.NewTaskHandlerWithConfig(c.taskService, c.taskRepository, c.handlerConfig())
 	}
 	return c.taskHandler
 }
 
 // GetHealthHandler returns the singleton health handler instance
-// This method demonstrates singleton service creation without dependencies
+// The health check probes the task repository, bounded by HEALTH_CHECK_TIMEOUT
 func (c *Container) GetHealthHandler() HealthHandlerInterface {
 	if err := c.ensureNotShutdown(); err != nil {
 		panic(err) // Service access after shutdown is a programming error
 	}
-	
+
 	if c.healthHandler == nil {
-		c.healthHandler = handlers.NewHealthHandler()
+		c.healthHandler = handlers.NewHealthHandlerWithStorage(c.taskRepository, c.config.HealthCheckTimeout)
 	}
 	return c.healthHandler
 }
 
+// GetTemplateHandler returns the singleton template handler instance with injected dependencies
+func (c *Container) GetTemplateHandler() TemplateHandlerInterface {
+	if err := c.ensureNotShutdown(); err != nil {
+		panic(err) // Service access after shutdown is a programming error
+	}
+
+	if c.templateHandler == nil {
+		c.templateHandler = handlers.NewTemplateHandler(c.taskService, c.templateRepository, c.handlerConfig())
+	}
+	return c.templateHandler
+}
+
+// GetMetricsHandler returns the singleton metrics handler instance
+// Persistence counters come from the task repository when it keeps them
+func (c *Container) GetMetricsHandler() MetricsHandlerInterface {
+	if err := c.ensureNotShutdown(); err != nil {
+		panic(err) // Service access after shutdown is a programming error
+	}
+
+	if c.metricsHandler == nil {
+		c.metricsHandler = handlers.NewMetricsHandler(c.fileRepository)
+	}
+	return c.metricsHandler
+}
+
+// GetStatusHandler returns the singleton status handler instance
+// STATUS_COLORS is validated at startup; an invalid value falls back to the default colors
+func (c *Container) GetStatusHandler() StatusHandlerInterface {
+	if err := c.ensureNotShutdown(); err != nil {
+		panic(err) // Service access after shutdown is a programming error
+	}
+
+	if c.statusHandler == nil {
+		colors, err := handlers.ParseStatusColors(c.config.StatusColors)
+		if err != nil {
+			colors = handlers.DefaultStatusColors
+		}
+		c.statusHandler = handlers.NewStatusHandler(colors, completeStatuses(c.config.CompleteStatuses))
+	}
+	return c.statusHandler
+}
+
+// GetStatsHandler returns the singleton stats handler instance
+// STATUS_WEIGHTS is validated at startup; an invalid value falls back to the default weights
+func (c *Container) GetStatsHandler() StatsHandlerInterface {
+	if err := c.ensureNotShutdown(); err != nil {
+		panic(err) // Service access after shutdown is a programming error
+	}
+
+	if c.statsHandler == nil {
+		weights, err := domain.ParseStatusWeights(c.config.StatusWeights)
+		if err != nil {
+			weights = nil
+		}
+		c.statsHandler = handlers.NewStatsHandler(c.taskRepository, domain.NewProgressCalculator(weights, completeStatuses(c.config.CompleteStatuses)))
+	}
+	return c.statsHandler
+}
+
+// GetAdminHandler returns the singleton admin handler instance
+// Compaction goes through the task repository when it has a data file
+func (c *Container) GetAdminHandler() AdminHandlerInterface {
+	if err := c.ensureNotShutdown(); err != nil {
+		panic(err) // Service access after shutdown is a programming error
+	}
+
+	if c.adminHandler == nil {
+		c.adminHandler = handlers.NewAdminHandler(c.taskService, c.fileRepository)
+	}
+	return c.adminHandler
+}
+
 // CreateTaskHandler creates a new task handler instance (non-singleton)
 // This method is provided for cases where a new instance is explicitly needed
 func (c *Container) CreateTaskHandler() TaskHandlerInterface {
 	if err := c.ensureNotShutdown(); err != nil {
 		panic(err)
 	}
-	
-	return handlers.NewTaskHandler(c.taskService, c.taskRepository)
+
+	return handlers.NewTaskHandlerWithConfig(c.taskService, c.taskRepository, c.handlerConfig())
+}
+
+// handlerConfig derives the task handler configuration from the container configuration
+func (c *Container) handlerConfig() handlers.TaskHandlerConfig {
+	return handlers.TaskHandlerConfig{
+		PositionBase:              c.config.PositionBase,
+		AutoCreateRootDescription: c.config.AutoCreateRoot,
+		BasePath:                  c.config.APIBasePath,
+		CompleteStatuses:          completeStatuses(c.config.CompleteStatuses),
+		Readiness:                 c.readiness,
+		StrictTimestamps:          c.config.StrictTimestamps,
+	}
 }
 
 // CreateHealthHandler creates a new health handler instance (non-singleton)
@@ -237,7 +814,7 @@ func (c *Container) CreateHealthHandler() HealthHandlerInterface {
 	if err := c.ensureNotShutdown(); err != nil {
 		panic(err)
 	}
-	
+
 	return handlers.NewHealthHandler()
 }
 
@@ -286,12 +863,17 @@ func (c *Container) ensureNotShutdown() error {
 // GetServiceStatus returns the current status of all managed services
 func (c *Container) GetServiceStatus() map[string]interface{} {
 	status := map[string]interface{}{
-		"initialized":    c.initialized,
-		"shutdown":       c.shutdown,
-		"taskHandler":    c.taskHandler != nil,
-		"healthHandler":  c.healthHandler != nil,
-		"taskRepository": c.taskRepository != nil,
-		"taskService":    c.taskService != nil,
+		"initialized":     c.initialized,
+		"shutdown":        c.shutdown,
+		"taskHandler":     c.taskHandler != nil,
+		"healthHandler":   c.healthHandler != nil,
+		"templateHandler": c.templateHandler != nil,
+		"metricsHandler":  c.metricsHandler != nil,
+		"statusHandler":   c.statusHandler != nil,
+		"statsHandler":    c.statsHandler != nil,
+		"adminHandler":    c.adminHandler != nil,
+		"taskRepository":  c.taskRepository != nil,
+		"taskService":     c.taskService != nil,
 	}
 	return status
 }
@@ -302,9 +884,14 @@ func (c *Container) ResetSingletons() error {
 	if err := c.ensureNotShutdown(); err != nil {
 		return err
 	}
-	
+
 	c.taskHandler = nil
 	c.healthHandler = nil
+	c.templateHandler = nil
+	c.metricsHandler = nil
+	c.statusHandler = nil
+	c.statsHandler = nil
+	c.adminHandler = nil
 	return nil
 }
 
@@ -314,17 +901,25 @@ func (c *Container) Shutdown() error {
 	if c.shutdown {
 		return nil // Already shut down
 	}
-	
+
 	// Mark as shut down to prevent further service access
 	c.shutdown = true
-	
+
 	// Clear singleton references to help with garbage collection
 	c.taskHandler = nil
 	c.healthHandler = nil
-	
-	// Currently no cleanup needed for file repository
-	// This method provides extension point for future cleanup needs
-	// (e.g., database connections, background workers, etc.)
-	
+	c.templateHandler = nil
+	c.metricsHandler = nil
+	c.statusHandler = nil
+	c.statsHandler = nil
+	c.adminHandler = nil
+
+	// Flush pending writes and stop the repository's background writer, if any
+	if c.fileRepository != nil {
+		if err := c.fileRepository.Close(); err != nil {
+			return fmt.Errorf("failed to close task repository: %w", err)
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
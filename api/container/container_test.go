@@ -1,12 +1,21 @@
 package container
 
 import (
+	"bytes"
+	"discovery-tree/domain"
+	"encoding/base64"
+	"encoding/json"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewContainer_ConfiguresSlog(t *testing.T) {
@@ -46,6 +55,47 @@ func TestLoadConfigFromEnv_Defaults(t *testing.T) {
 	os.Unsetenv("LOG_LEVEL")
 	os.Unsetenv("ENABLE_CORS")
 	os.Unsetenv("ENABLE_SWAGGER")
+	os.Unsetenv("POSITION_BASE")
+	os.Unsetenv("API_BASE_PATH")
+	os.Unsetenv("TRUSTED_PROXIES")
+	os.Unsetenv("RESPONSE_ENVELOPE")
+	os.Unsetenv("ID_SCHEME")
+	os.Unsetenv("FILE_LOCK")
+	os.Unsetenv("HTTP_READ_TIMEOUT")
+	os.Unsetenv("HTTP_WRITE_TIMEOUT")
+	os.Unsetenv("HTTP_IDLE_TIMEOUT")
+	os.Unsetenv("NORMALIZE_ON_WRITE")
+	os.Unsetenv("TEMPLATE_DIR")
+	os.Unsetenv("MAX_DESCRIPTION_LENGTH")
+	os.Unsetenv("PERSIST_DEBOUNCE")
+	os.Unsetenv("MESSAGES_FILE")
+	os.Unsetenv("CROSS_ROOT_MOVES")
+	os.Unsetenv("STATUS_COLORS")
+	os.Unsetenv("MAX_INFLIGHT")
+	os.Unsetenv("CHILD_INSERT_MODE")
+	os.Unsetenv("DATA_FILE_MINIFY")
+	os.Unsetenv("HEALTH_CHECK_TIMEOUT")
+	os.Unsetenv("REOPEN_STATUS")
+	os.Unsetenv("STRICT_TIMESTAMPS")
+	os.Unsetenv("TOUCH_ANCESTORS_ON_CHANGE")
+	os.Unsetenv("CANONICALIZE_WHITESPACE")
+	os.Unsetenv("MAX_BATCH_SIZE")
+	os.Unsetenv("ENCRYPTION_KEY")
+	os.Unsetenv("CHILD_DESCRIPTION_TEMPLATE")
+	os.Unsetenv("MOVE_CLAMP_POSITION")
+	os.Unsetenv("SNAPSHOT_READS")
+	os.Unsetenv("STATUS_WEIGHTS")
+	os.Unsetenv("ARCHIVE_ON_COMPLETE")
+	os.Unsetenv("ARCHIVE_PATH")
+	os.Unsetenv("REQUIRE_DECOMPOSITION")
+	os.Unsetenv("DECOMPOSITION_TAGS")
+	os.Unsetenv("DECOMPOSITION_MIN_CHILDREN")
+	os.Unsetenv("ENABLE_OPENAPI_JSON")
+	os.Unsetenv("TASK_KINDS")
+	os.Unsetenv("DECOMPOSITION_KINDS")
+	os.Unsetenv("DELETE_GUARD")
+	os.Unsetenv("STATUS_HISTORY_LIMIT")
+	os.Unsetenv("COMPLETE_STATUSES")
 	
 	config := LoadConfigFromEnv()
 	
@@ -54,6 +104,47 @@ func TestLoadConfigFromEnv_Defaults(t *testing.T) {
 	assert.Equal(t, "info", config.LogLevel)
 	assert.True(t, config.EnableCORS)
 	assert.True(t, config.EnableSwagger)
+	assert.Equal(t, 0, config.PositionBase)
+	assert.Equal(t, "/api/v1", config.APIBasePath)
+	assert.Equal(t, []string{"127.0.0.1/32", "::1/128"}, config.TrustedProxies)
+	assert.Equal(t, "bare", config.ResponseEnvelope)
+	assert.Equal(t, "uuid", config.IDScheme)
+	assert.True(t, config.FileLock)
+	assert.Equal(t, 15*time.Second, config.HTTPReadTimeout)
+	assert.Equal(t, 15*time.Second, config.HTTPWriteTimeout)
+	assert.Equal(t, 60*time.Second, config.HTTPIdleTimeout)
+	assert.False(t, config.NormalizeOnWrite)
+	assert.Empty(t, config.TemplateDir)
+	assert.Equal(t, 0, config.MaxDescriptionLength)
+	assert.Zero(t, config.PersistDebounce)
+	assert.Empty(t, config.MessagesFile)
+	assert.Equal(t, "allow", config.CrossRootMoves)
+	assert.Empty(t, config.StatusColors)
+	assert.Equal(t, 0, config.MaxInFlight)
+	assert.Equal(t, "append", config.ChildInsertMode)
+	assert.False(t, config.DataFileMinify)
+	assert.Equal(t, 2*time.Second, config.HealthCheckTimeout)
+	assert.Equal(t, "In Progress", config.ReopenStatus)
+	assert.False(t, config.StrictTimestamps)
+	assert.Equal(t, "off", config.TouchAncestors)
+	assert.False(t, config.CollapseWhitespace)
+	assert.Equal(t, 500, config.MaxBatchSize)
+	assert.Empty(t, config.EncryptionKey)
+	assert.Empty(t, config.ChildDescTemplate)
+	assert.False(t, config.MoveClampPosition)
+	assert.False(t, config.SnapshotReads)
+	assert.Nil(t, config.StatusWeights)
+	assert.False(t, config.ArchiveOnComplete)
+	assert.Empty(t, config.ArchivePath)
+	assert.False(t, config.RequireDecomposition)
+	assert.Equal(t, []string{"epic"}, config.DecompositionTags)
+	assert.Equal(t, 1, config.DecompositionMin)
+	assert.False(t, config.EnableOpenAPIJSON)
+	assert.Equal(t, []string{"task", "spike", "milestone"}, config.TaskKinds)
+	assert.Empty(t, config.DecompositionKinds)
+	assert.Equal(t, "cascade", config.DeleteGuard)
+	assert.Equal(t, 50, config.StatusHistoryLimit)
+	assert.Equal(t, []string{"DONE"}, config.CompleteStatuses)
 }
 
 func TestLoadConfigFromEnv_CustomValues(t *testing.T) {
@@ -63,6 +154,8 @@ func TestLoadConfigFromEnv_CustomValues(t *testing.T) {
 	os.Setenv("LOG_LEVEL", "debug")
 	os.Setenv("ENABLE_CORS", "false")
 	os.Setenv("ENABLE_SWAGGER", "false")
+	os.Setenv("POSITION_BASE", "1")
+	os.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
 	
 	config := LoadConfigFromEnv()
 	
@@ -71,6 +164,8 @@ func TestLoadConfigFromEnv_CustomValues(t *testing.T) {
 	assert.Equal(t, "debug", config.LogLevel)
 	assert.False(t, config.EnableCORS)
 	assert.False(t, config.EnableSwagger)
+	assert.Equal(t, 1, config.PositionBase)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, config.TrustedProxies)
 	
 	// Clean up
 	os.Unsetenv("PORT")
@@ -78,6 +173,8 @@ func TestLoadConfigFromEnv_CustomValues(t *testing.T) {
 	os.Unsetenv("LOG_LEVEL")
 	os.Unsetenv("ENABLE_CORS")
 	os.Unsetenv("ENABLE_SWAGGER")
+	os.Unsetenv("POSITION_BASE")
+	os.Unsetenv("TRUSTED_PROXIES")
 }
 
 func TestConfigureSlog_DifferentLevels(t *testing.T) {
@@ -107,4 +204,224 @@ func TestConfigureSlog_DifferentLevels(t *testing.T) {
 			assert.True(t, slog.Default().Enabled(nil, tt.expected))
 		})
 	}
-}
\ No newline at end of file
+}
+func TestConfigureSlog_LogFormat(t *testing.T) {
+	defer gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		ginMode   string
+		logFormat string
+		wantJSON  bool
+	}{
+		{"JSON in test mode", gin.TestMode, "json", true},
+		{"Text in release mode", gin.ReleaseMode, "text", false},
+		{"Unset follows release mode", gin.ReleaseMode, "", true},
+		{"Unset follows debug mode", gin.DebugMode, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(tt.ginMode)
+
+			err := configureSlog(&Config{LogLevel: "error", LogFormat: tt.logFormat})
+			require.NoError(t, err)
+
+			_, isJSON := slog.Default().Handler().(*slog.JSONHandler)
+			_, isText := slog.Default().Handler().(*slog.TextHandler)
+			assert.Equal(t, tt.wantJSON, isJSON)
+			assert.Equal(t, !tt.wantJSON, isText)
+		})
+	}
+}
+
+func TestNewContainer_SeedsEmptyStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	seedPath := filepath.Join(dir, "seed.json")
+	seed := `{
+		"description": "Seed root",
+		"children": [
+			{"description": "First", "status": "DONE"},
+			{"description": "Second", "children": [{"description": "Nested"}]}
+		]
+	}`
+	require.NoError(t, os.WriteFile(seedPath, []byte(seed), 0644))
+
+	config := &Config{
+		Port:     "8080",
+		DataPath: filepath.Join(dir, "tasks.json"),
+		LogLevel: "error",
+		SeedFile: seedPath,
+	}
+
+	container, err := NewContainer(config)
+	require.NoError(t, err)
+
+	tasks, err := container.TaskRepository().FindAll()
+	require.NoError(t, err)
+	assert.Len(t, tasks, 4)
+
+	root, err := container.TaskRepository().FindRoot()
+	require.NoError(t, err)
+	assert.Equal(t, "Seed root", root.Description())
+
+	// A second startup must not overwrite the now non-empty store
+	container, err = NewContainer(config)
+	require.NoError(t, err)
+
+	tasks, err = container.TaskRepository().FindAll()
+	require.NoError(t, err)
+	assert.Len(t, tasks, 4)
+}
+
+func TestNewContainer_InvalidSeedFailsStartup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	seedPath := filepath.Join(dir, "seed.json")
+	// Root marked DONE with an incomplete child violates bottom-to-top completion
+	seed := `{"description": "Root", "status": "DONE", "children": [{"description": "Child"}]}`
+	require.NoError(t, os.WriteFile(seedPath, []byte(seed), 0644))
+
+	config := &Config{
+		Port:     "8080",
+		DataPath: filepath.Join(dir, "tasks.json"),
+		LogLevel: "error",
+		SeedFile: seedPath,
+	}
+
+	container, err := NewContainer(config)
+	assert.Error(t, err)
+	assert.Nil(t, container)
+	assert.Contains(t, err.Error(), "failed to seed task repository")
+}
+
+func TestNewContainer_StartupSelfTestLeavesNoResidue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	config := &Config{
+		Port:            "8080",
+		DataPath:        filepath.Join(dir, "tasks.json"),
+		LogLevel:        "error",
+		StartupSelfTest: true,
+	}
+
+	container, err := NewContainer(config)
+	require.NoError(t, err)
+	defer container.Shutdown()
+
+	// The real store is empty and the scratch file is gone
+	count, err := container.TaskRepository().Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), "selftest")
+	}
+}
+
+func TestNewContainer_StartupSelfTestWithEncryption(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	config := &Config{
+		Port:            "8080",
+		DataPath:        filepath.Join(dir, "tasks.json"),
+		LogLevel:        "error",
+		StartupSelfTest: true,
+		EncryptionKey:   base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)),
+	}
+
+	// The scratch file is encrypted too, so it has to be read back with the key
+	container, err := NewContainer(config)
+	require.NoError(t, err)
+	defer container.Shutdown()
+
+	count, err := container.TaskRepository().Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestNewContainer_StartupSelfTestFailureFailsStartup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A directory where the scratch file should go makes the self-test's store unreadable
+	dir := t.TempDir()
+	dataPath := filepath.Join(dir, "tasks.json")
+	require.NoError(t, os.Mkdir(dataPath+".selftest", 0755))
+
+	config := &Config{
+		Port:            "8080",
+		DataPath:        dataPath,
+		LogLevel:        "error",
+		StartupSelfTest: true,
+		FileLock:        true,
+	}
+
+	container, err := NewContainer(config)
+	assert.Error(t, err)
+	assert.Nil(t, container)
+	assert.Contains(t, err.Error(), "startup self-test failed")
+
+	// The data file lock was released, so a startup without the self-test succeeds
+	config.StartupSelfTest = false
+	container, err = NewContainer(config)
+	require.NoError(t, err)
+	container.Shutdown()
+}
+
+func TestNewContainer_WiresCachedReadiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &Config{
+		Port:     "8080",
+		DataPath: filepath.Join(t.TempDir(), "tasks.json"),
+		LogLevel: "error",
+	}
+
+	container, err := NewContainer(config)
+	require.NoError(t, err)
+	defer container.Shutdown()
+
+	// Every change goes through the observable repository the readiness cache listens to
+	_, ok := container.TaskRepository().(*domain.ObservableTaskRepository)
+	require.True(t, ok, "expected the task repository to publish changes")
+
+	service := container.TaskService()
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	first, err := service.CreateChildTask("First", root.ID())
+	require.NoError(t, err)
+	second, err := service.CreateChildTask("Second", root.ID())
+	require.NoError(t, err)
+
+	handler := container.GetTaskHandler()
+	isReady := func(id domain.TaskID) interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id.String()+"?include=readiness", nil)
+		handler.GetTask(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response["readiness"].(map[string]interface{})["ready"]
+	}
+
+	// The cached state is invalidated when the left sibling is completed through the service
+	assert.Equal(t, false, isReady(second.ID()))
+	require.NoError(t, service.ChangeTaskStatus(first.ID(), domain.StatusDONE))
+	assert.Equal(t, true, isReady(second.ID()))
+
+	// StartNextTasks picks up the same states
+	started, err := service.StartNextTasks(1)
+	require.NoError(t, err)
+	require.Len(t, started, 1)
+	assert.Equal(t, second.ID(), started[0].ID())
+}
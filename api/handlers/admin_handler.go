@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"discovery-tree/api/middleware"
+	"discovery-tree/api/models"
+	"discovery-tree/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compactor rewrites a repository's data file
+type Compactor interface {
+	DataFileSize() (int, error)
+	Compact() (int, error)
+}
+
+// AdminHandler handles HTTP requests for maintenance operations on the task store
+type AdminHandler struct {
+	taskService *domain.TaskService
+	compactor   Compactor
+}
+
+// NewAdminHandler creates a new AdminHandler
+// A nil compactor, such as a repository without a data file, makes compaction fail
+func NewAdminHandler(taskService *domain.TaskService, compactor Compactor) *AdminHandler {
+	return &AdminHandler{
+		taskService: taskService,
+		compactor:   compactor,
+	}
+}
+
+// CompactDataFile renumbers sibling positions and rewrites the data file
+// @Summary Compact the data file
+// @Description Renumbers the children of every task to contiguous positions, keeping their order, then rewrites the data file from the in-memory state in one atomic write, indented or minified per DATA_FILE_MINIFY. Reports the file size before and after and how many tasks were renumbered.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.CompactResponse "Data file compacted"
+// @Failure 409 {object} models.ErrorResponse "The task store has no data file"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/admin/compact [post]
+func (h *AdminHandler) CompactDataFile(c *gin.Context) {
+	if h.compactor == nil {
+		middleware.HandleError(c, domain.NewConstraintViolationError("compact", "the task store has no data file to compact"))
+		return
+	}
+
+	// Measured first, since renumbering writes the file too
+	bytesBefore, err := h.compactor.DataFileSize()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	renumbered, err := h.taskService.NormalizePositions()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	bytesAfter, err := h.compactor.Compact()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.CompactResponse{
+		BytesBefore:     bytesBefore,
+		BytesAfter:      bytesAfter,
+		TasksRenumbered: renumbered,
+	})
+}
+
+// RenameStatus moves every task in one status to another
+// @Summary Rename a status across all tasks
+// @Description Migration tool for workflow changes: sets every task in status from to status to, in one transaction, and reports how many tasks changed. Both must be valid statuses and differ; Root Work Item can be neither. The rename is rejected if it would leave a complete task with an incomplete child.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.RenameStatusRequest true "Statuses to rename from and to"
+// @Success 200 {object} models.RenameStatusResponse "Status renamed"
+// @Failure 400 {object} models.ErrorResponse "Invalid or identical statuses, or Root Work Item"
+// @Failure 409 {object} models.ErrorResponse "Rename would break bottom-to-top completion"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/admin/status/rename [post]
+func (h *AdminHandler) RenameStatus(c *gin.Context) {
+	var req models.RenameStatusRequest
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	from, err := domain.NewStatus(req.From)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	to, err := domain.NewStatus(req.To)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	renamed, err := h.taskService.RenameStatus(from, to)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.RenameStatusResponse{
+		From:    from.String(),
+		To:      to.String(),
+		Renamed: renamed,
+	})
+}
+
+// GetDiagnostics reports invariant violations in the stored tasks
+// @Summary Diagnose the task store
+// @Description Read-only report for operators who suspect data corruption: checks every stored task against the tree invariants and lists the violations, plus the tasks whose parent is missing, one task on each cycle, every root when there is not exactly one, and sibling groups sharing a position. Nothing is changed; use it to decide whether to repair the data.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.DiagnosticsResponse "Diagnostics report"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/admin/diagnostics [get]
+func (h *AdminHandler) GetDiagnostics(c *gin.Context) {
+	diagnostics, err := h.taskService.Diagnose()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.DiagnosticsToResponse(diagnostics))
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"discovery-tree/api/models"
+	"discovery-tree/domain"
+	"discovery-tree/infrastructure"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_CompactDataFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	// Write a tree with gaps: root -> A at 0, B at 10, C at 20, and B -> B1 at 5
+	repo, err := infrastructure.NewFileTaskRepository(path)
+	require.NoError(t, err)
+	root, err := domain.NewTask("Root", nil, 0)
+	require.NoError(t, err)
+	rootID := root.ID()
+	a, _ := domain.NewTask("A", &rootID, 0)
+	b, _ := domain.NewTask("B", &rootID, 10)
+	c, _ := domain.NewTask("C", &rootID, 20)
+	bID := b.ID()
+	b1, _ := domain.NewTask("B1", &bID, 5)
+	require.NoError(t, repo.SaveAll([]*domain.Task{root, a, b, c, b1}))
+
+	// Reopen it minified, as after switching DATA_FILE_MINIFY on
+	repo, err = infrastructure.NewFileTaskRepositoryWithOptions(path, infrastructure.FileTaskRepositoryOptions{MinifyJSON: true})
+	require.NoError(t, err)
+	handler := NewAdminHandler(domain.NewTaskService(repo), repo)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/admin/compact", nil)
+	handler.CompactDataFile(ctx)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(3), response["tasksRenumbered"])
+
+	// The reported sizes match the file before and after
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, float64(info.Size()), response["bytesAfter"])
+	assert.Less(t, response["bytesAfter"], response["bytesBefore"])
+
+	// Positions read back from the file are contiguous, in their previous order
+	reloaded, err := infrastructure.NewFileTaskRepository(path)
+	require.NoError(t, err)
+	positions := func(parentID domain.TaskID) map[string]int {
+		children, err := reloaded.FindByParentID(&parentID)
+		require.NoError(t, err)
+		result := make(map[string]int, len(children))
+		for _, child := range children {
+			result[child.Description()] = child.Position()
+		}
+		return result
+	}
+	assert.Equal(t, map[string]int{"A": 0, "B": 1, "C": 2}, positions(rootID))
+	assert.Equal(t, map[string]int{"B1": 0}, positions(bID))
+}
+
+func TestAdminHandler_CompactDataFile_NoDataFile(t *testing.T) {
+	handler := NewAdminHandler(domain.NewTaskService(domain.NewInMemoryTaskRepository()), nil)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/admin/compact", nil)
+	handler.CompactDataFile(ctx)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminHandler_RenameStatus(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+	require.NoError(t, service.ChangeTaskStatus(a.ID(), domain.StatusInProgress))
+	require.NoError(t, service.ChangeTaskStatus(b.ID(), domain.StatusInProgress))
+	handler := NewAdminHandler(service, nil)
+
+	rename := func(body string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest("POST", "/api/v1/admin/status/rename", strings.NewReader(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		handler.RenameStatus(ctx)
+		return w
+	}
+
+	w := rename(`{"from": "In Progress", "to": "Blocked"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "In Progress", response["from"])
+	assert.Equal(t, "Blocked", response["to"])
+	assert.Equal(t, float64(2), response["renamed"])
+
+	for _, task := range []*domain.Task{a, b} {
+		found, err := repo.FindByID(task.ID())
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusBlocked, found.Status())
+	}
+	found, err := repo.FindByID(c.ID())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusTODO, found.Status())
+
+	// Unknown, identical and Root Work Item statuses are rejected
+	assert.Equal(t, http.StatusBadRequest, rename(`{"from": "Doing", "to": "Blocked"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, rename(`{"from": "TODO", "to": "TODO"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, rename(`{"from": "TODO", "to": "Root Work Item"}`).Code)
+}
+
+func TestAdminHandler_GetDiagnostics(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+
+	// Save a corrupted store directly: root -> A, and an orphan whose parent does not exist
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	a, _ := domain.NewTask("A", &rootID, 0)
+	missing := domain.NewTaskID()
+	orphan, _ := domain.NewTask("Orphan", &missing, 0)
+	require.NoError(t, repo.SaveAll([]*domain.Task{root, a, orphan}))
+	seqBefore, err := repo.LastSeq()
+	require.NoError(t, err)
+
+	handler := NewAdminHandler(domain.NewTaskService(repo), nil)
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("GET", "/api/v1/admin/diagnostics", nil)
+	handler.GetDiagnostics(ctx)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.DiagnosticsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Healthy)
+	assert.Equal(t, 3, response.TaskCount)
+	require.Len(t, response.Orphans, 1)
+	assert.Equal(t, orphan.ID().String(), response.Orphans[0].TaskID)
+	require.NotNil(t, response.Orphans[0].ParentID)
+	assert.Equal(t, missing.String(), *response.Orphans[0].ParentID)
+	assert.Empty(t, response.Cycles)
+	assert.Empty(t, response.DuplicatePositions)
+	// The orphan still names a parent, so the store has a single root and no roots are listed
+	assert.Empty(t, response.Roots)
+	assert.Contains(t, w.Body.String(), `"constraint":"missing-parent"`)
+
+	// Nothing was changed
+	seqAfter, err := repo.LastSeq()
+	require.NoError(t, err)
+	assert.Equal(t, seqBefore, seqAfter)
+	count, err := repo.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
@@ -1,26 +1,59 @@
 package handlers
 
 import (
+	"context"
+	"discovery-tree/api/middleware"
+	"errors"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// StorageChecker is the storage operation the health check probes
+type StorageChecker interface {
+	Count() (int, error)
+}
+
+// errStorageTimeout reports a storage probe that did not finish within the health check timeout
+var errStorageTimeout = errors.New("storage check timed out")
+
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	storage StorageChecker
+	timeout time.Duration
+
+	mu    sync.Mutex
+	probe *storageProbe // the probe in flight, shared by every check started while it runs
+}
+
+// storageProbe is a single call to StorageChecker.Count; done is closed once count and err are set
+type storageProbe struct {
+	done  chan struct{}
+	count int
+	err   error
+}
 
-// NewHealthHandler creates a new HealthHandler
+// NewHealthHandler creates a new HealthHandler that reports the API as healthy without probing storage
 func NewHealthHandler() *HealthHandler {
 	return &HealthHandler{}
 }
 
+// NewHealthHandlerWithStorage creates a new HealthHandler that also probes storage
+// A probe taking longer than timeout makes the check fail; 0 means no timeout
+func NewHealthHandlerWithStorage(storage StorageChecker, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{storage: storage, timeout: timeout}
+}
+
 // HealthCheck returns the health status of the API
 // @Summary Health check
-// @Description Returns the health status of the Discovery Tree API
+// @Description Returns the health status of the Discovery Tree API. Storage is probed by counting tasks; a failing probe, or one taking longer than HEALTH_CHECK_TIMEOUT, returns 503 with the reason in error.
 // @Tags health
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{} "API is healthy"
+// @Failure 503 {object} map[string]interface{} "Storage check failed or timed out"
 // @Router /health [get]
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	response := gin.H{
@@ -28,6 +61,62 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 		"service": "discovery-tree-api",
 		"version": "1.0.0",
 	}
-	
-	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+
+	if h.storage != nil {
+		count, err := h.checkStorage(c.Request.Context())
+		if err != nil {
+			response["status"] = "unhealthy"
+			response["error"] = err.Error()
+			middleware.Respond(c, http.StatusServiceUnavailable, response)
+			return
+		}
+		response["taskCount"] = count
+	}
+
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// checkStorage counts the tasks, giving up once the timeout passes or the request is canceled
+// A probe given up on keeps running in the background until storage answers; checks made
+// meanwhile wait on that probe instead of starting another, so wedged storage holds at most
+// one probe goroutine however often the health check is polled
+func (h *HealthHandler) checkStorage(ctx context.Context) (int, error) {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	probe := h.startProbe()
+	select {
+	case <-probe.done:
+		return probe.count, probe.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, errStorageTimeout
+		}
+		return 0, ctx.Err()
+	}
+}
+
+// startProbe returns the probe in flight, starting one if there is none
+func (h *HealthHandler) startProbe() *storageProbe {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.probe != nil {
+		return h.probe
+	}
+
+	probe := &storageProbe{done: make(chan struct{})}
+	h.probe = probe
+	go func() {
+		probe.count, probe.err = h.storage.Count()
+
+		// Later checks start a fresh probe rather than reusing this result
+		h.mu.Lock()
+		h.probe = nil
+		h.mu.Unlock()
+		close(probe.done)
+	}()
+	return probe
+}
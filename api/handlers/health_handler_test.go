@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStorage answers Count after delay
+type stubStorage struct {
+	delay time.Duration
+	count int
+	err   error
+}
+
+func (s stubStorage) Count() (int, error) {
+	time.Sleep(s.delay)
+	return s.count, s.err
+}
+
+// blockedStorage counts its probes and answers Count only once release is closed
+type blockedStorage struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (s *blockedStorage) Count() (int, error) {
+	s.calls.Add(1)
+	<-s.release
+	return 1, nil
+}
+
+// checkHealth runs the health check and decodes its response
+func checkHealth(t *testing.T, handler *HealthHandler) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/health", nil)
+	handler.HealthCheck(c)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w.Code, response
+}
+
+func TestHealthHandler_HealthCheck(t *testing.T) {
+	code, response := checkHealth(t, NewHealthHandlerWithStorage(stubStorage{count: 3}, time.Second))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "healthy", response["status"])
+	assert.Equal(t, float64(3), response["taskCount"])
+}
+
+func TestHealthHandler_HealthCheck_SlowStorage(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	handler := NewHealthHandlerWithStorage(stubStorage{delay: 2 * time.Second}, timeout)
+
+	start := time.Now()
+	code, response := checkHealth(t, handler)
+	elapsed := time.Since(start)
+
+	// The probe gives up at the timeout instead of waiting for storage
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", response["status"])
+	assert.Equal(t, "storage check timed out", response["error"])
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestHealthHandler_HealthCheck_StorageError(t *testing.T) {
+	code, response := checkHealth(t, NewHealthHandlerWithStorage(stubStorage{err: errors.New("disk on fire")}, time.Second))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "disk on fire", response["error"])
+}
+
+func TestHealthHandler_HealthCheck_WedgedStorageSharesOneProbe(t *testing.T) {
+	storage := &blockedStorage{release: make(chan struct{})}
+	handler := NewHealthHandlerWithStorage(storage, 10*time.Millisecond)
+
+	// Every check times out while storage is wedged, but they all wait on the same probe
+	for i := 0; i < 5; i++ {
+		code, response := checkHealth(t, handler)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "storage check timed out", response["error"])
+	}
+	assert.Equal(t, int32(1), storage.calls.Load())
+
+	// Once storage answers the checks recover, and a finished probe is not reused
+	close(storage.release)
+	require.Eventually(t, func() bool {
+		code, _ := checkHealth(t, handler)
+		return code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	calls := storage.calls.Load()
+	code, _ := checkHealth(t, handler)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, calls+1, storage.calls.Load())
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"discovery-tree/infrastructure"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersistStatsSource reports how a repository's changes map to file writes
+type PersistStatsSource interface {
+	PersistStats() infrastructure.PersistStats
+}
+
+// MetricsHandler serves runtime counters in the Prometheus text exposition format
+type MetricsHandler struct {
+	persistStats PersistStatsSource
+}
+
+// NewMetricsHandler creates a new MetricsHandler
+// A nil source, such as a repository without a data file, reports zero for every counter
+func NewMetricsHandler(persistStats PersistStatsSource) *MetricsHandler {
+	return &MetricsHandler{persistStats: persistStats}
+}
+
+// Metrics returns the persistence counters
+// @Summary Metrics
+// @Description Returns counters in the Prometheus text format. persist_requested_total counts changes that asked to be written to the data file, persist_flushed_total the file writes performed and persist_coalesced_total the changes written by a write shared with an earlier change (see PERSIST_COALESCE and PERSIST_DEBOUNCE). persist_pending is the number of changes not yet written.
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "Metrics in the Prometheus text format"
+// @Router /metrics [get]
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	var stats infrastructure.PersistStats
+	if h.persistStats != nil {
+		stats = h.persistStats.PersistStats()
+	}
+
+	var b strings.Builder
+	writeMetric(&b, "persist_requested_total", "counter", "Changes that asked to be written to the data file.", stats.Requested)
+	writeMetric(&b, "persist_flushed_total", "counter", "Writes of the data file performed.", stats.Flushed)
+	writeMetric(&b, "persist_coalesced_total", "counter", "Changes written by a write shared with an earlier change.", stats.Coalesced)
+	writeMetric(&b, "persist_pending", "gauge", "Changes not yet written to the data file.", stats.Pending)
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+// writeMetric appends a metric with its HELP and TYPE lines
+func writeMetric(b *strings.Builder, name, kind, help string, value uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, kind, name, value)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"discovery-tree/infrastructure"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedPersistStats reports the same counters on every call
+type fixedPersistStats infrastructure.PersistStats
+
+func (s fixedPersistStats) PersistStats() infrastructure.PersistStats {
+	return infrastructure.PersistStats(s)
+}
+
+// serveMetrics runs the metrics handler and returns the response
+func serveMetrics(handler *MetricsHandler) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/metrics", nil)
+	handler.Metrics(c)
+	return w
+}
+
+func TestMetricsHandler_Metrics(t *testing.T) {
+	w := serveMetrics(NewMetricsHandler(fixedPersistStats{Requested: 7, Flushed: 3, Coalesced: 3, Pending: 1}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	body := w.Body.String()
+	assert.Contains(t, body, "# TYPE persist_requested_total counter\npersist_requested_total 7\n")
+	assert.Contains(t, body, "# TYPE persist_flushed_total counter\npersist_flushed_total 3\n")
+	assert.Contains(t, body, "# TYPE persist_coalesced_total counter\npersist_coalesced_total 3\n")
+	assert.Contains(t, body, "# TYPE persist_pending gauge\npersist_pending 1\n")
+}
+
+func TestMetricsHandler_Metrics_WithoutSource(t *testing.T) {
+	w := serveMetrics(NewMetricsHandler(nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "persist_requested_total 0\n")
+	assert.Contains(t, w.Body.String(), "persist_pending 0\n")
+}
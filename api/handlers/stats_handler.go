@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"discovery-tree/api/middleware"
+	"discovery-tree/api/models"
+	"discovery-tree/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler serves progress statistics over the whole store or a subtree
+type StatsHandler struct {
+	taskRepository domain.TaskRepository
+	progress       *domain.ProgressCalculator
+}
+
+// NewStatsHandler creates a new StatsHandler
+// A nil calculator counts only DONE tasks as done
+func NewStatsHandler(taskRepository domain.TaskRepository, progress *domain.ProgressCalculator) *StatsHandler {
+	if progress == nil {
+		progress = domain.NewProgressCalculator(nil, domain.CompleteStatuses{})
+	}
+	return &StatsHandler{
+		taskRepository: taskRepository,
+		progress:       progress,
+	}
+}
+
+// GetStats reports the progress of all tasks or of a subtree
+// @Summary Get progress statistics
+// @Description Counts the tasks of the store, or of the subtree under taskId, per status and reports how much of them is done. percentComplete counts complete tasks; weightedPercentComplete credits each task with its status weight from STATUS_WEIGHTS, so In Progress can count partly. With the default weights both are equal. Root Work Item tasks are not counted, and no counted tasks is 0% done.
+// @Tags stats
+// @Produce json
+// @Param taskId query string false "Limit the statistics to this task's subtree (UUID format)" format(uuid)
+// @Success 200 {object} models.StatsResponse "Successfully computed statistics"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	var tasks []*domain.Task
+	var taskIDParam *string
+	if idParam := c.Query("taskId"); idParam != "" {
+		// Validate UUID format
+		if err := middleware.ValidateUUID(c, idParam, "taskId"); err != nil {
+			return
+		}
+		taskID, err := domain.TaskIDFromString(idParam)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		tasks, err = h.taskRepository.FindSubtree(taskID)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		canonical := taskID.String()
+		taskIDParam = &canonical
+	} else {
+		var err error
+		tasks, err = h.taskRepository.FindAll()
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+	}
+
+	progress := h.progress.Calculate(tasks)
+	response := models.StatsResponse{
+		TaskID:                  taskIDParam,
+		Tasks:                   progress.Tasks,
+		Completed:               progress.Completed,
+		ByStatus:                make(map[string]int),
+		PercentComplete:         progress.Percent,
+		WeightedPercentComplete: progress.WeightedPercent,
+	}
+	// List every counted status so clients see stable keys, including zeros
+	for status := domain.StatusTODO; status.IsValid(); status++ {
+		if status != domain.StatusRootWorkItem {
+			response.ByStatus[status.String()] = progress.ByStatus[status]
+		}
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"discovery-tree/domain"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+
+	// Create tree: root -> A (In Progress) -> A1 (DONE), A2 (TODO), and root -> B (TODO)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+	service.CreateChildTask("A2", a.ID())
+	service.CreateChildTask("B", root.ID())
+	require.NoError(t, service.ChangeTaskStatus(a.ID(), domain.StatusInProgress))
+	require.NoError(t, service.ChangeTaskStatus(a1.ID(), domain.StatusDONE))
+
+	getStats := func(handler *StatsHandler, query string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/stats"+query, nil)
+		handler.GetStats(c)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) map[string]interface{} {
+		require.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Binary by default: 1 of the 4 counted tasks is done
+	binary := NewStatsHandler(repo, nil)
+	response := decode(getStats(binary, ""))
+	assert.Nil(t, response["taskId"])
+	assert.Equal(t, float64(4), response["tasks"])
+	assert.Equal(t, float64(1), response["completed"])
+	assert.Equal(t, float64(25), response["percentComplete"])
+	assert.Equal(t, float64(25), response["weightedPercentComplete"])
+	assert.Equal(t, map[string]interface{}{
+		"TODO": float64(2), "In Progress": float64(1), "DONE": float64(1), "Blocked": float64(0),
+	}, response["byStatus"])
+
+	// Weighted over the subtree of A: DONE + half of In Progress over 3 tasks
+	weights, err := domain.ParseStatusWeights([]string{"In Progress=0.5"})
+	require.NoError(t, err)
+	weighted := NewStatsHandler(repo, domain.NewProgressCalculator(weights, domain.CompleteStatuses{}))
+	response = decode(getStats(weighted, "?taskId="+a.ID().String()))
+	assert.Equal(t, a.ID().String(), response["taskId"])
+	assert.Equal(t, float64(3), response["tasks"])
+	assert.InDelta(t, 100.0/3, response["percentComplete"], 1e-9)
+	assert.InDelta(t, 50.0, response["weightedPercentComplete"], 1e-9)
+
+	// The same subtree without weights matches the binary percentage
+	response = decode(getStats(binary, "?taskId="+a.ID().String()))
+	assert.InDelta(t, 100.0/3, response["weightedPercentComplete"], 1e-9)
+
+	assert.Equal(t, http.StatusBadRequest, getStats(binary, "?taskId=not-a-uuid").Code)
+	assert.Equal(t, http.StatusNotFound, getStats(binary, "?taskId="+domain.NewTaskID().String()).Code)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"discovery-tree/api/middleware"
+	"discovery-tree/api/models"
+	"discovery-tree/domain"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultStatusColors holds the color of each status unless STATUS_COLORS overrides it
+var DefaultStatusColors = map[domain.Status]string{
+	domain.StatusTODO:         "#9e9e9e",
+	domain.StatusInProgress:   "#2196f3",
+	domain.StatusDONE:         "#4caf50",
+	domain.StatusBlocked:      "#f44336",
+	domain.StatusRootWorkItem: "#673ab7",
+}
+
+// statusDisplayNames holds the human-readable name of each status
+var statusDisplayNames = map[domain.Status]string{
+	domain.StatusTODO:         "To Do",
+	domain.StatusInProgress:   "In Progress",
+	domain.StatusDONE:         "Done",
+	domain.StatusBlocked:      "Blocked",
+	domain.StatusRootWorkItem: "Root Work Item",
+}
+
+// hexColorPattern matches #RGB and #RRGGBB colors
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ParseStatusColors applies Name=#color entries over DefaultStatusColors
+// Every status gets a color; an entry naming an unknown status or holding a color
+// other than #RGB or #RRGGBB is an error
+func ParseStatusColors(entries []string) (map[domain.Status]string, error) {
+	colors := make(map[domain.Status]string, len(DefaultStatusColors))
+	for status, color := range DefaultStatusColors {
+		colors[status] = color
+	}
+
+	for _, entry := range entries {
+		name, color, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid status color %q (must be Name=#color)", entry)
+		}
+		status, err := domain.NewStatus(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status color %q: unknown status", entry)
+		}
+		color = strings.TrimSpace(color)
+		if !hexColorPattern.MatchString(color) {
+			return nil, fmt.Errorf("invalid status color %q: color must be #RGB or #RRGGBB", entry)
+		}
+		colors[status] = color
+	}
+	return colors, nil
+}
+
+// StatusHandler serves the presentation of task statuses
+type StatusHandler struct {
+	colors   map[domain.Status]string
+	complete domain.CompleteStatuses
+}
+
+// NewStatusHandler creates a new StatusHandler reporting the given statuses as complete
+// Statuses missing from colors use their default color
+func NewStatusHandler(colors map[domain.Status]string, complete domain.CompleteStatuses) *StatusHandler {
+	return &StatusHandler{colors: colors, complete: complete}
+}
+
+// ListStatuses returns every valid status with its display name and color
+// @Summary List statuses
+// @Description Returns every valid task status in workflow order with its display name, its color (see STATUS_COLORS) and whether it counts as complete, so all clients render statuses alike. name is the value used in requests and responses.
+// @Tags statuses
+// @Produce json
+// @Success 200 {array} models.StatusResponse "Successfully listed statuses"
+// @Router /api/v1/statuses [get]
+func (h *StatusHandler) ListStatuses(c *gin.Context) {
+	var response []models.StatusResponse
+	for status := domain.StatusTODO; status.IsValid(); status++ {
+		color, ok := h.colors[status]
+		if !ok {
+			color = DefaultStatusColors[status]
+		}
+		response = append(response, models.StatusResponse{
+			Name:        status.String(),
+			DisplayName: statusDisplayNames[status],
+			Color:       color,
+			Complete:    h.complete.Contains(status),
+		})
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
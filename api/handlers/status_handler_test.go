@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"discovery-tree/domain"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusHandler_ListStatuses(t *testing.T) {
+	colors, err := ParseStatusColors([]string{"DONE=#2e7d32", "In Progress = #abc"})
+	require.NoError(t, err)
+	complete, err := domain.NewCompleteStatuses(domain.StatusDONE, domain.StatusBlocked)
+	require.NoError(t, err)
+	handler := NewStatusHandler(colors, complete)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/statuses", nil)
+	handler.ListStatuses(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// Every valid status appears, in order, with a color
+	var names []string
+	for status := domain.StatusTODO; status.IsValid(); status++ {
+		names = append(names, status.String())
+	}
+	require.Len(t, response, len(names))
+	for i, entry := range response {
+		assert.Equal(t, names[i], entry["name"])
+		assert.NotEmpty(t, entry["displayName"])
+		assert.Regexp(t, `^#[0-9a-fA-F]{3,6}$`, entry["color"])
+	}
+
+	// Configured colors override the defaults; the rest keep theirs
+	assert.Equal(t, "#2e7d32", response[domain.StatusDONE]["color"])
+	assert.Equal(t, "#abc", response[domain.StatusInProgress]["color"])
+	assert.Equal(t, DefaultStatusColors[domain.StatusTODO], response[domain.StatusTODO]["color"])
+	assert.Equal(t, true, response[domain.StatusDONE]["complete"])
+	assert.Equal(t, true, response[domain.StatusBlocked]["complete"])
+	assert.Equal(t, false, response[domain.StatusTODO]["complete"])
+}
+
+func TestParseStatusColors_Invalid(t *testing.T) {
+	for _, entries := range [][]string{
+		{"DONE"},
+		{"Finished=#fff"},
+		{"DONE=green"},
+		{"DONE=#12345"},
+	} {
+		_, err := ParseStatusColors(entries)
+		assert.Error(t, err, "entries %v", entries)
+	}
+}
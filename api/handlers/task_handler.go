@@ -4,23 +4,132 @@ import (
 	"discovery-tree/api/middleware"
 	"discovery-tree/api/models"
 	"discovery-tree/domain"
+	"discovery-tree/infrastructure"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// TaskHandlerConfig holds presentation settings for the task handler
+type TaskHandlerConfig struct {
+	// PositionBase is the index of the first sibling as seen by clients (0 or 1)
+	// Positions are always stored 0-based; the handler translates at the API boundary
+	PositionBase int
+
+	// AutoCreateRootDescription, when non-empty, makes GET /tasks/root create a root task
+	// with this description if none exists instead of returning 404
+	AutoCreateRootDescription string
+
+	// BasePath is the prefix of the versioned API routes (e.g. "/api/v1"), used to build
+	// the Location header of created tasks. Empty means "/api/v1"
+	BasePath string
+
+	// CompleteStatuses are the statuses readiness, the plan and the Markdown export treat as
+	// complete; it should match the task service's. Defaults to just DONE
+	CompleteStatuses domain.CompleteStatuses
+
+	// Readiness evaluates the readiness embedded in task details and the blockers of a task,
+	// typically a cached evaluator shared with the task service. nil evaluates against
+	// the task repository on every request
+	Readiness domain.ReadinessEvaluator
+
+	// StrictTimestamps rejects imported JSON Lines records with a zero or out-of-order
+	// timestamp instead of correcting them; see infrastructure.DTOOptions
+	StrictTimestamps bool
+}
+
 // TaskHandler handles HTTP requests for task operations
 type TaskHandler struct {
 	taskService    *domain.TaskService
 	taskRepository domain.TaskRepository
+	navigator      domain.TreeNavigator
+	readiness      domain.ReadinessEvaluator
+	config         TaskHandlerConfig
 }
 
-// NewTaskHandler creates a new TaskHandler with injected dependencies
+// NewTaskHandler creates a new TaskHandler with injected dependencies and default configuration
 func NewTaskHandler(taskService *domain.TaskService, taskRepository domain.TaskRepository) *TaskHandler {
+	return NewTaskHandlerWithConfig(taskService, taskRepository, TaskHandlerConfig{})
+}
+
+// NewTaskHandlerWithConfig creates a new TaskHandler with injected dependencies and configuration
+func NewTaskHandlerWithConfig(taskService *domain.TaskService, taskRepository domain.TaskRepository, config TaskHandlerConfig) *TaskHandler {
+	navigator := domain.NewTreeNavigatorService(taskRepository)
+	readiness := config.Readiness
+	if readiness == nil {
+		readiness = domain.NewReadinessEvaluatorService(taskRepository, navigator, config.CompleteStatuses)
+	}
 	return &TaskHandler{
 		taskService:    taskService,
 		taskRepository: taskRepository,
+		navigator:      navigator,
+		readiness:      readiness,
+		config:         config,
+	}
+}
+
+// toResponse converts a domain Task to a TaskResponse using the configured position base
+// The child flags come from a single count of the task's children; lists use toResponseList instead
+func (h *TaskHandler) toResponse(task *domain.Task) (models.TaskResponse, error) {
+	id := task.ID()
+	count, err := h.taskRepository.CountByParentID(&id)
+	if err != nil {
+		return models.TaskResponse{}, err
+	}
+	return models.TaskToResponseWithChildCount(task, h.config.PositionBase, count), nil
+}
+
+// childCounts counts the children of every task in one pass over the store, keyed by canonical parent ID
+func (h *TaskHandler) childCounts() (map[string]int, error) {
+	counts := make(map[string]int)
+	err := h.taskRepository.ForEach(func(task *domain.Task) error {
+		if task.ParentID() != nil {
+			counts[task.ParentID().Canonical().String()]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// toResponseList converts domain Tasks to TaskResponses, counting children in one pass over the store
+func (h *TaskHandler) toResponseList(tasks []*domain.Task) ([]models.TaskResponse, error) {
+	counts, err := h.childCounts()
+	if err != nil {
+		return nil, err
+	}
+	return models.TasksToResponsesWithChildCounts(tasks, h.config.PositionBase, counts), nil
+}
+
+// toResponses converts domain Tasks to a TaskResponse list for an included detail key
+func (h *TaskHandler) toResponses(tasks []*domain.Task, childCounts map[string]int) *[]models.TaskResponse {
+	responses := models.TasksToResponsesWithChildCounts(tasks, h.config.PositionBase, childCounts)
+	return &responses
+}
+
+// toInternalPosition converts a client-facing position to the 0-based stored position
+// Returns a ValidationError if the position is below the configured base
+func (h *TaskHandler) toInternalPosition(field string, position int) (int, error) {
+	return h.config.toInternalPosition(field, position)
+}
+
+// toInternalPosition converts a client-facing position to the 0-based stored position
+// Returns a ValidationError if the position is below the configured base
+func (c TaskHandlerConfig) toInternalPosition(field string, position int) (int, error) {
+	if position < c.PositionBase {
+		return 0, domain.NewValidationError(field, "position-below-base", fmt.Sprintf("position must be at least %d", c.PositionBase))
 	}
+	return position - c.PositionBase, nil
 }
 
 // CreateRootTask creates a new root task
@@ -31,6 +140,7 @@ func NewTaskHandler(taskService *domain.TaskService, taskRepository domain.TaskR
 // @Produce json
 // @Param request body models.CreateRootTaskRequest true "Root task creation request"
 // @Success 201 {object} models.TaskResponse "Successfully created root task"
+// @Header 201 {string} Location "Path of the created task"
 // @Failure 400 {object} models.ErrorResponse "Invalid request data"
 // @Failure 409 {object} models.ErrorResponse "Root task already exists"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
@@ -50,21 +160,28 @@ func (h *TaskHandler) CreateRootTask(c *gin.Context) {
 		return
 	}
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusCreated, response)
+	// Convert to response model and return, pointing Location at the new task
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	c.Header("Location", h.taskLocation(task.ID()))
+	middleware.Respond(c, http.StatusCreated, response)
 }
 
 // CreateChildTask creates a new child task
 // @Summary Create child task
-// @Description Creates a new child task under the specified parent task
+// @Description Creates a new child task under the specified parent task. It is appended after the last child (or placed first with CHILD_INSERT_MODE=prepend) unless a position is given, in which case children at or after it shift right.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param request body models.CreateChildTaskRequest true "Child task creation request"
 // @Success 201 {object} models.TaskResponse "Successfully created child task"
-// @Failure 400 {object} models.ErrorResponse "Invalid request data"
+// @Header 201 {string} Location "Path of the created task"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data or position out of range"
 // @Failure 404 {object} models.ErrorResponse "Parent task not found"
+// @Failure 409 {object} models.ErrorResponse "No root task exists yet, or the parent is DONE"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /api/v1/tasks [post]
 func (h *TaskHandler) CreateChildTask(c *gin.Context) {
@@ -82,27 +199,205 @@ func (h *TaskHandler) CreateChildTask(c *gin.Context) {
 		return
 	}
 
-	// Create the child task using the service
-	task, err := h.taskService.CreateChildTask(req.Description, parentID)
+	// Create the child task using the service, at the requested position if any
+	var task *domain.Task
+	if req.Position != nil {
+		position, posErr := h.toInternalPosition("position", int(*req.Position))
+		if posErr != nil {
+			middleware.HandleError(c, posErr)
+			return
+		}
+		task, err = h.taskService.CreateChildTaskAt(req.Description, parentID, position)
+	} else {
+		task, err = h.taskService.CreateChildTask(req.Description, parentID)
+	}
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusCreated, response)
+	// Convert to response model and return, pointing Location at the new task
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	c.Header("Location", h.taskLocation(task.ID()))
+	middleware.Respond(c, http.StatusCreated, response)
+}
+
+// taskLocation returns the URL path of a task, as sent in the Location header of creates
+func (h *TaskHandler) taskLocation(id domain.TaskID) string {
+	basePath := strings.TrimRight(h.config.BasePath, "/")
+	if basePath == "" {
+		basePath = "/api/v1"
+	}
+	return basePath + "/tasks/" + id.Canonical().String()
+}
+
+// taskIncludes lists the related data requested via ?include= on GET /tasks/{id}
+type taskIncludes struct {
+	children  bool
+	ancestors bool
+	siblings  bool
+	readiness bool
+}
+
+// parseTaskIncludes parses a comma-separated include list
+// Returns a ValidationError for unknown values
+func parseTaskIncludes(raw string) (taskIncludes, error) {
+	var includes taskIncludes
+	if raw == "" {
+		return includes, nil
+	}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "children":
+			includes.children = true
+		case "ancestors":
+			includes.ancestors = true
+		case "siblings":
+			includes.siblings = true
+		case "readiness":
+			includes.readiness = true
+		default:
+			return includes, domain.NewValidationError("include", "unknown-include", fmt.Sprintf("unknown include %q (must be one of: children, ancestors, siblings, readiness)", name))
+		}
+	}
+	return includes, nil
+}
+
+// childSorts orders siblings for ?sort= on the children and subtree endpoints
+// position, the stored order, is the default and needs no sorting
+var childSorts = map[string]func(a, b *domain.Task) bool{
+	"position":    nil,
+	"status":      func(a, b *domain.Task) bool { return a.Status() < b.Status() },
+	"createdAt":   func(a, b *domain.Task) bool { return a.CreatedBefore(b) },
+	"description": func(a, b *domain.Task) bool { return a.Description() < b.Description() },
+}
+
+// parseChildSort parses the ?sort= key, returning nil for the stored position order
+// Returns a ValidationError for unknown keys
+func parseChildSort(c *gin.Context) (func(a, b *domain.Task) bool, error) {
+	key := c.DefaultQuery("sort", "position")
+	less, ok := childSorts[key]
+	if !ok {
+		return nil, domain.NewValidationError("sort", "unknown-sort", fmt.Sprintf("unknown sort %q (must be one of: position, status, createdAt, description)", key))
+	}
+	return less, nil
+}
+
+// sortSiblings reorders siblings by less for presentation only; ties keep their position order
+func sortSiblings(siblings []*domain.Task, less func(a, b *domain.Task) bool) {
+	sort.SliceStable(siblings, func(i, j int) bool {
+		return less(siblings[i], siblings[j])
+	})
+}
+
+// sortSubtree returns a depth-first subtree, as from FindSubtree, with every task's children
+// reordered by less; stored positions are unchanged
+func sortSubtree(tasks []*domain.Task, less func(a, b *domain.Task) bool) []*domain.Task {
+	if len(tasks) == 0 {
+		return tasks
+	}
+
+	// Children keep their depth-first order, which is their position order
+	children := make(map[string][]*domain.Task)
+	for _, task := range tasks[1:] {
+		if task.ParentID() != nil {
+			key := task.ParentID().String()
+			children[key] = append(children[key], task)
+		}
+	}
+	for _, siblings := range children {
+		sortSiblings(siblings, less)
+	}
+
+	sorted := make([]*domain.Task, 0, len(tasks))
+	var walk func(task *domain.Task)
+	walk = func(task *domain.Task) {
+		sorted = append(sorted, task)
+		for _, child := range children[task.ID().String()] {
+			walk(child)
+		}
+	}
+	walk(tasks[0])
+	return sorted
+}
+
+// any reports whether any related data was requested
+func (i taskIncludes) any() bool {
+	return i.children || i.ancestors || i.siblings || i.readiness
+}
+
+// toDetailResponse builds the response for a task with the requested related data embedded
+func (h *TaskHandler) toDetailResponse(task *domain.Task, includes taskIncludes) (models.TaskDetailResponse, error) {
+	taskResponse, err := h.toResponse(task)
+	if err != nil {
+		return models.TaskDetailResponse{}, err
+	}
+	response := models.TaskDetailResponse{TaskResponse: taskResponse}
+
+	// Count children once for every included list
+	var childCounts map[string]int
+	if includes.children || includes.ancestors || includes.siblings {
+		var err error
+		if childCounts, err = h.childCounts(); err != nil {
+			return response, err
+		}
+	}
+
+	if includes.children {
+		children, err := h.navigator.GetChildren(task.ID())
+		if err != nil {
+			return response, err
+		}
+		response.Children = h.toResponses(children, childCounts)
+	}
+
+	if includes.ancestors {
+		ancestors, err := h.navigator.GetAncestors(task.ID())
+		if err != nil {
+			return response, err
+		}
+		response.Ancestors = h.toResponses(ancestors, childCounts)
+	}
+
+	if includes.siblings {
+		siblings, err := h.navigator.GetSiblings(task.ID())
+		if err != nil {
+			return response, err
+		}
+		response.Siblings = h.toResponses(siblings, childCounts)
+	}
+
+	if includes.readiness {
+		state, err := h.readiness.EvaluateReadiness(task.ID())
+		if err != nil {
+			return response, err
+		}
+		frontier, err := h.readiness.IsFrontier(task.ID())
+		if err != nil {
+			return response, err
+		}
+		readiness := models.ReadinessToResponse(state, frontier)
+		response.Readiness = &readiness
+	}
+
+	return response, nil
 }
 
 // GetTask retrieves a specific task by ID
 // @Summary Get task by ID
-// @Description Retrieves a specific task by its unique identifier
+// @Description Retrieves a specific task by its unique identifier. The include parameter embeds related data under dedicated keys: children and siblings (including the task itself) in position order, ancestors from the root down to the parent, and readiness, which also reports whether the task is on the completion frontier (the first open task in depth-first order, the next thing to do in its tree).
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID (UUID format)" format(uuid)
-// @Success 200 {object} models.TaskResponse "Successfully retrieved task"
-// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Param include query string false "Comma-separated related data to embed: children, ancestors, siblings, readiness"
+// @Success 200 {object} models.TaskDetailResponse "Successfully retrieved task"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format or unknown include value"
 // @Failure 404 {object} models.ErrorResponse "Task not found"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /api/v1/tasks/{id} [get]
@@ -121,6 +416,13 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
+	// Parse the requested related data
+	includes, err := parseTaskIncludes(c.Query("include"))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
 	// Find the task using the repository
 	task, err := h.taskRepository.FindByID(taskID)
 	if err != nil {
@@ -128,18 +430,35 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusOK, response)
+	// Without includes, keep the lean task response
+	if !includes.any() {
+		response, err := h.toResponse(task)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		middleware.Respond(c, http.StatusOK, response)
+		return
+	}
+
+	// Embed the requested related data
+	response, err := h.toDetailResponse(task, includes)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
 }
 
 // GetAllTasks retrieves all tasks
 // @Summary Get all tasks
-// @Description Retrieves all tasks in the discovery tree
+// @Description Retrieves all tasks in the discovery tree, optionally only those of one kind
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param kind query string false "Only return tasks of this kind (e.g. spike)"
 // @Success 200 {array} models.TaskResponse "Successfully retrieved all tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid kind"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /api/v1/tasks [get]
 func (h *TaskHandler) GetAllTasks(c *gin.Context) {
@@ -150,235 +469,1452 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 		return
 	}
 
-	// Convert all tasks to response models
-	responses := make([]models.TaskResponse, len(tasks))
-	for i, task := range tasks {
-		responses[i] = models.TaskToResponse(task)
+	// Count children from the whole list, so filtered tasks keep their hasChildren
+	childCounts := models.ChildCounts(tasks)
+	if value, ok := c.GetQuery("kind"); ok {
+		kind, err := domain.NormalizeKind(value)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		tasks = domain.FilterByKind(tasks, kind)
 	}
 
-	c.JSON(http.StatusOK, responses)
-}
-
-// GetRootTask retrieves the root task
-// @Summary Get root task
-// @Description Retrieves the root task of the discovery tree
-// @Tags tasks
-// @Accept json
-// @Produce json
-// @Success 200 {object} models.TaskResponse "Successfully retrieved root task"
-// @Failure 404 {object} models.ErrorResponse "Root task not found"
-// @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /api/v1/tasks/root [get]
-func (h *TaskHandler) GetRootTask(c *gin.Context) {
-	// Find the root task using the repository
-	task, err := h.taskRepository.FindRoot()
-	if err != nil {
-		middleware.HandleError(c, err)
-		return
-	}
+	// Convert all tasks to response models
+	responses := models.TasksToResponsesWithChildCounts(tasks, h.config.PositionBase, childCounts)
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusOK, response)
+	middleware.Respond(c, http.StatusOK, responses)
 }
 
-// GetTaskChildren retrieves children of a specific task
-// @Summary Get task children
-// @Description Retrieves all child tasks of the specified parent task, ordered by position
+// FindTasksByDescription retrieves all tasks with exactly the given description
+// @Summary Find tasks by description
+// @Description Retrieves every task whose description exactly matches value, oldest first. Descriptions are not unique, so the result is a list, empty when nothing matches. Matching is case-sensitive unless ci=true.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param id path string true "Parent task ID (UUID format)" format(uuid)
-// @Success 200 {array} models.TaskResponse "Successfully retrieved child tasks"
-// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
-// @Failure 404 {object} models.ErrorResponse "Parent task not found"
+// @Param value query string true "Description to match"
+// @Param ci query bool false "Match case-insensitively"
+// @Success 200 {array} models.TaskResponse "Successfully retrieved matching tasks"
+// @Failure 400 {object} models.ErrorResponse "Missing value or invalid ci value"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /api/v1/tasks/{id}/children [get]
-func (h *TaskHandler) GetTaskChildren(c *gin.Context) {
-	idParam := c.Param("id")
-	
-	// Validate UUID format
-	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+// @Router /api/v1/tasks/by-description [get]
+func (h *TaskHandler) FindTasksByDescription(c *gin.Context) {
+	value := c.Query("value")
+	if value == "" {
+		middleware.HandleError(c, domain.NewValidationError("value", "missing-value", "value is required"))
 		return
 	}
-	
-	// Convert ID string to TaskID
-	taskID, err := domain.TaskIDFromString(idParam)
+
+	caseInsensitive, err := parseBoolQuery(c, "ci")
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// First verify the parent task exists
-	_, err = h.taskRepository.FindByID(taskID)
+	var tasks []*domain.Task
+	if caseInsensitive {
+		tasks, err = h.findByDescriptionFold(value)
+	} else {
+		tasks, err = h.taskRepository.FindByDescription(value)
+	}
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Find children using the repository
-	children, err := h.taskRepository.FindByParentID(&taskID)
+	responses, err := h.toResponseList(tasks)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
+	middleware.Respond(c, http.StatusOK, responses)
+}
 
-	// Convert all children to response models
-	responses := make([]models.TaskResponse, len(children))
-	for i, child := range children {
-		responses[i] = models.TaskToResponse(child)
+// findByDescriptionFold retrieves all tasks whose description matches value ignoring case,
+// in the same order as FindByDescription
+func (h *TaskHandler) findByDescriptionFold(value string) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	err := h.taskRepository.ForEach(func(task *domain.Task) error {
+		if strings.EqualFold(task.Description(), value) {
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	c.JSON(http.StatusOK, responses)
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedBefore(tasks[j])
+	})
+	return tasks, nil
 }
 
-// UpdateTask updates a task's description
-// @Summary Update task description
-// @Description Updates the description of an existing task
+// GetTaskGraph retrieves all tasks as an adjacency list
+// @Summary Get task graph
+// @Description Retrieves all tasks as nodes plus explicit parent-child edges, for graph-visualization clients. Nodes are ordered roots first, then grouped by parent in position order; edges follow their child nodes.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param id path string true "Task ID (UUID format)" format(uuid)
-// @Param request body models.UpdateTaskRequest true "Task update request"
-// @Success 200 {object} models.TaskResponse "Successfully updated task"
-// @Failure 400 {object} models.ErrorResponse "Invalid request data or task ID format"
-// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Success 200 {object} models.TaskGraphResponse "Successfully retrieved task graph"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /api/v1/tasks/{id} [put]
-func (h *TaskHandler) UpdateTask(c *gin.Context) {
-	idParam := c.Param("id")
-	var req models.UpdateTaskRequest
-	
-	// Validate UUID format
-	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
-		return
-	}
-	
-	// Bind and validate the request
-	if err := middleware.BindJSON(c, &req); err != nil {
-		return
-	}
-
-	// Convert ID string to TaskID
-	taskID, err := domain.TaskIDFromString(idParam)
+// @Router /api/v1/tasks/graph [get]
+func (h *TaskHandler) GetTaskGraph(c *gin.Context) {
+	// Find all tasks in a single repository call
+	tasks, err := h.taskRepository.FindAll()
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Find the task first
-	task, err := h.taskRepository.FindByID(taskID)
-	if err != nil {
-		middleware.HandleError(c, err)
-		return
-	}
+	// Convert to the graph response model and return
+	response := models.TasksToGraphResponseWithPositionBase(tasks, h.config.PositionBase)
+	middleware.Respond(c, http.StatusOK, response)
+}
 
-	// Update the description
-	err = task.UpdateDescription(req.Description)
+// GetTaskCount retrieves the number of tasks, optionally broken down by status
+// @Summary Count tasks
+// @Description Returns the number of tasks without fetching them, for "N tasks" labels. With byStatus=true, also returns the number of tasks in each status; every status is listed, with zero when no task has it. An empty store counts as zero.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param byStatus query bool false "Include the count per status"
+// @Success 200 {object} models.TaskCountResponse "Successfully counted tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid byStatus value"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/count [get]
+func (h *TaskHandler) GetTaskCount(c *gin.Context) {
+	byStatus, err := parseBoolQuery(c, "byStatus")
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Save the updated task
-	err = h.taskRepository.Save(task)
+	total, err := h.taskRepository.Count()
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusOK, response)
+	response := models.TaskCountResponse{Total: total}
+	if byStatus {
+		counts, err := h.taskRepository.CountByStatus()
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		// List every status so clients see stable keys, including zeros
+		response.ByStatus = make(map[string]int)
+		for status := domain.StatusTODO; status.IsValid(); status++ {
+			response.ByStatus[status.String()] = counts[status]
+		}
+	}
+	middleware.Respond(c, http.StatusOK, response)
 }
 
-// UpdateTaskStatus updates a task's status
-// @Summary Update task status
-// @Description Updates the status of an existing task
+// GetTaskChanges retrieves the tasks changed after a sequence number
+// @Summary List changed tasks
+// @Description Returns the tasks whose latest change has a sequence number above since, ordered by seq, for clients syncing without relying on timestamps. Every save assigns the task the store's next sequence number, which persists across restarts. Pass the returned seq as since on the next call. Deleted tasks are not listed.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param id path string true "Task ID (UUID format)" format(uuid)
-// @Param request body models.UpdateStatusRequest true "Status update request"
-// @Success 200 {object} models.TaskResponse "Successfully updated task status"
-// @Failure 400 {object} models.ErrorResponse "Invalid request data, task ID format, or status value"
-// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Param since query int false "Return tasks changed after this sequence number (default: 0, every task)"
+// @Param limit query int false "Maximum number of tasks to return (default: no limit)"
+// @Success 200 {object} models.TaskChangesResponse "Successfully retrieved changed tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid since or limit"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /api/v1/tasks/{id}/status [put]
-func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
-	idParam := c.Param("id")
-	var req models.UpdateStatusRequest
-	
-	// Validate UUID format
-	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
-		return
+// @Router /api/v1/tasks/changes [get]
+func (h *TaskHandler) GetTaskChanges(c *gin.Context) {
+	var since uint64
+	if value := c.Query("since"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			middleware.HandleError(c, domain.NewValidationError("since", "invalid-since", "since must be a non-negative integer"))
+			return
+		}
+		since = parsed
 	}
-	
-	// Bind and validate the request
-	if err := middleware.BindJSON(c, &req); err != nil {
-		return
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			middleware.HandleError(c, domain.NewValidationError("limit", "invalid-limit", "limit must be a positive integer"))
+			return
+		}
+		limit = parsed
 	}
 
-	// Convert ID string to TaskID
-	taskID, err := domain.TaskIDFromString(idParam)
+	// Read the cursor first; tasks changed after it are left for the next call
+	last, err := h.taskRepository.LastSeq()
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Convert status string to Status
-	status, err := domain.NewStatus(req.Status)
+	var changed []*domain.Task
+	err = h.taskRepository.ForEach(func(task *domain.Task) error {
+		if task.Seq() > since && task.Seq() <= last {
+			changed = append(changed, task)
+		}
+		return nil
+	})
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].Seq() < changed[j].Seq()
+	})
 
-	// Update the status using the service (includes validation)
-	err = h.taskService.ChangeTaskStatus(taskID, status)
+	response := models.TaskChangesResponse{Seq: last}
+	if since > last {
+		response.Seq = since
+	}
+	if limit > 0 && len(changed) > limit {
+		changed = changed[:limit]
+		response.Seq = changed[limit-1].Seq()
+		response.HasMore = true
+	}
+
+	response.Tasks, err = h.toResponseList(changed)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
+	middleware.Respond(c, http.StatusOK, response)
+}
 
-	// Retrieve the updated task to return
-	task, err := h.taskRepository.FindByID(taskID)
+// GetPlan retrieves the tasks that remain to be done, in completion order
+// @Summary Get completion plan
+// @Description Returns the to-do list derived from the tree: every incomplete task without incomplete children, in the order it becomes ready under left-to-right, bottom-to-top completion (pre-order over incomplete branches, leftmost first). Each step carries its path of ancestors from the root down to its parent. An incomplete branch whose children are all complete is a step itself. A complete tree has an empty plan.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.PlanStepResponse "Successfully retrieved plan"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/plan [get]
+func (h *TaskHandler) GetPlan(c *gin.Context) {
+	tasks, err := h.taskRepository.FindAll()
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusOK, response)
+	response := models.PlanToResponseWithPositionBase(domain.BuildPlan(tasks, h.config.CompleteStatuses), h.config.PositionBase, models.ChildCounts(tasks))
+	middleware.Respond(c, http.StatusOK, response)
 }
 
-// MoveTask moves a task to a new position or parent
-// @Summary Move task
-// @Description Moves a task to a new position or under a different parent task
+// StartNextTasks starts the next ready tasks in plan order
+// @Summary Start the next ready tasks
+// @Description Sets up to count ready TODO tasks to In Progress in one transaction, taken in the order of GET /tasks/plan, and returns them. Readiness is re-evaluated after each task is started. Fewer than count tasks are returned if fewer are ready.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param count query int false "Maximum number of tasks to start (default 1)"
+// @Success 200 {array} models.TaskResponse "Successfully started tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid count"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/start-next [post]
+func (h *TaskHandler) StartNextTasks(c *gin.Context) {
+	count := 1
+	if value := c.Query("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			middleware.HandleError(c, domain.NewValidationError("count", "invalid-count", "count must be a positive integer"))
+			return
+		}
+		count = parsed
+	}
+
+	started, err := h.taskService.StartNextTasks(count)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response, err := h.toResponseList(started)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetRootTask retrieves the root task
+// @Summary Get root task
+// @Description Retrieves the root task of the discovery tree. If AUTO_CREATE_ROOT is configured and no root exists, the root is created with the configured description and returned.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TaskResponse "Successfully retrieved root task"
+// @Failure 404 {object} models.ErrorResponse "Root task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/root [get]
+func (h *TaskHandler) GetRootTask(c *gin.Context) {
+	// Find the root task using the repository
+	task, err := h.taskRepository.FindRoot()
+	if _, notFound := err.(domain.NotFoundError); notFound && h.config.AutoCreateRootDescription != "" {
+		task, err = h.autoCreateRoot()
+	}
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response model and return
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// autoCreateRoot creates the root task with the configured description
+// If a concurrent request created it first, that root is returned instead
+func (h *TaskHandler) autoCreateRoot() (*domain.Task, error) {
+	task, err := h.taskService.CreateRootTask(h.config.AutoCreateRootDescription)
+	if e, ok := err.(domain.ConstraintViolationError); ok && e.Constraint == "single_root" {
+		return h.taskRepository.FindRoot()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Auto-created root task", slog.String("task_id", task.ID().String()))
+	return task, nil
+}
+
+// GetTaskChildren retrieves children of a specific task
+// @Summary Get task children
+// @Description Retrieves all child tasks of the specified parent task, ordered by position unless sort names another order. Sorting only affects the response; stored positions are unchanged.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Parent task ID (UUID format)" format(uuid)
+// @Param sort query string false "Order of the children (default: position); ties keep position order" Enums(position, status, createdAt, description)
+// @Success 200 {array} models.TaskResponse "Successfully retrieved child tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format or unknown sort"
+// @Failure 404 {object} models.ErrorResponse "Parent task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/children [get]
+func (h *TaskHandler) GetTaskChildren(c *gin.Context) {
+	idParam := c.Param("id")
+	
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+	
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	less, err := parseChildSort(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// First verify the parent task exists
+	_, err = h.taskRepository.FindByID(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Find children using the repository
+	children, err := h.taskRepository.FindByParentID(&taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if less != nil {
+		sortSiblings(children, less)
+	}
+
+	// Convert all children to response models
+	responses, err := h.toResponseList(children)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, responses)
+}
+
+// GetTaskRoot retrieves the root of the tree containing a specific task
+// @Summary Get root of task
+// @Description Retrieves the topmost ancestor of the specified task (the task itself if it is a root)
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {object} models.TaskResponse "Successfully retrieved root task"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 409 {object} models.ErrorResponse "Parent chain contains a cycle"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/root [get]
+func (h *TaskHandler) GetTaskRoot(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Walk up to the topmost ancestor using the navigator
+	root, err := h.navigator.GetRootOf(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response model and return
+	response, err := h.toResponse(root)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetTaskBlockers retrieves what keeps a task from being ready
+// @Summary Get task blockers
+// @Description Returns the incomplete tasks that keep the task from being ready, for a "why can't I start this" panel: its left sibling (type leftSibling), then its incomplete children in position order (type child). A ready task has an empty list.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {array} models.BlockerResponse "Successfully retrieved blockers"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/blockers [get]
+func (h *TaskHandler) GetTaskBlockers(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	blockers, err := h.readiness.FindBlockers(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response := models.BlockersToResponse(blockers)
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetTaskActivity summarizes when the tasks of a subtree were created and changed
+// @Summary Get subtree activity
+// @Description Summarizes a task and its descendants for a "branch health" card, in one pass over the subtree: the earliest createdAt, the latest updatedAt, how many tasks were updated in the last days days, and the number of tasks in each status; every status is listed, with zero when no task has it.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param days query int false "Length of the recent window in days (default 7)"
+// @Success 200 {object} models.TaskActivityResponse "Successfully summarized activity"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format or days"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 413 {object} models.ErrorResponse "Subtree exceeds MAX_SUBTREE_OPERATION"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/activity [get]
+func (h *TaskHandler) GetTaskActivity(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	days := 7
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			middleware.HandleError(c, domain.NewValidationError("days", "invalid-days", "days must be a positive integer"))
+			return
+		}
+		days = parsed
+	}
+
+	subtree, err := h.taskService.FindSubtree(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	activity := domain.SummarizeActivity(subtree, time.Now().AddDate(0, 0, -days))
+	response := models.TaskActivityResponse{
+		TaskID:            subtree[0].ID().String(),
+		Total:             activity.Total,
+		EarliestCreatedAt: activity.EarliestCreatedAt,
+		LatestUpdatedAt:   activity.LatestUpdatedAt,
+		Days:              days,
+		ChangedRecently:   activity.ChangedSince,
+		ByStatus:          make(map[string]int),
+	}
+	// List every status so clients see stable keys, including zeros
+	for status := domain.StatusTODO; status.IsValid(); status++ {
+		response.ByStatus[status.String()] = activity.ByStatus[status]
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetArchive lists the completed branches moved out of the tree
+// @Summary List archived trees
+// @Description Lists the top-level branches archived when ARCHIVE_ON_COMPLETE moved them out of the tree after their whole subtree became DONE, oldest first. Archived tasks are not returned by the other task endpoints. The list is empty while archiving is off.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.ArchivedTreeResponse "Successfully retrieved the archive"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/archive [get]
+func (h *TaskHandler) GetArchive(c *gin.Context) {
+	trees, err := h.taskService.ArchivedTrees()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response := make([]models.ArchivedTreeResponse, len(trees))
+	for i, tree := range trees {
+		response[i] = models.ArchivedTreeToResponse(tree, h.config.PositionBase)
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// RestoreArchivedTree moves an archived branch back into the tree
+// @Summary Restore archived tree
+// @Description Moves an archived branch back under the root it was archived from (or the current root if that one is gone), after the root's last child, and removes it from the archive. Returns the restored tasks in depth-first order, the top task first.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the archived branch's top task (UUID format)" format(uuid)
+// @Success 200 {array} models.TaskResponse "Successfully restored the branch"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Archived tree not found, or no root to restore it under"
+// @Failure 409 {object} models.ErrorResponse "A task of the branch already exists in the tree, or MAX_TOTAL_TASKS would be exceeded"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/archive/{id}/restore [post]
+func (h *TaskHandler) RestoreArchivedTree(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	rootID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	tasks, err := h.taskService.RestoreArchivedTree(rootID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response, err := h.toResponseList(tasks)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetSiblingCount retrieves the number of siblings of a task and its index among them
+// @Summary Get sibling count
+// @Description Returns how many siblings the task has (itself included) and its position among them, so clients can render "2 of 5" labels without fetching the sibling list. The index uses the configured position base.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {object} models.SiblingCountResponse "Successfully retrieved sibling count"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/siblings/count [get]
+func (h *TaskHandler) GetSiblingCount(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	task, err := h.taskRepository.FindByID(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Count the siblings without collecting them; the task's own position is its index
+	total, err := h.taskRepository.CountByParentID(task.ParentID())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response := models.SiblingCountResponse{
+		Total: total,
+		Index: task.Position() + h.config.PositionBase,
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetTaskPosition retrieves a task's current parent and position
+// @Summary Get task position
+// @Description Returns only the task's parent ID, its position among its siblings (using the configured position base) and the number of siblings, itself included, so drag-and-drop clients can check a cached position before moving the task without fetching the whole task.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {object} models.TaskPositionResponse "Successfully retrieved task position"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/position [get]
+func (h *TaskHandler) GetTaskPosition(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	task, err := h.taskRepository.FindByID(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Count the siblings without collecting them
+	siblingCount, err := h.taskRepository.CountByParentID(task.ParentID())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	var parentID *string
+	if task.ParentID() != nil {
+		id := task.ParentID().Canonical().String()
+		parentID = &id
+	}
+
+	response := models.TaskPositionResponse{
+		ParentID:     parentID,
+		Position:     task.Position() + h.config.PositionBase,
+		SiblingCount: siblingCount,
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// ToggleTaskStatus flips a task between TODO and DONE
+// @Summary Toggle task status
+// @Description Flips the task between TODO and DONE (or the configured TOGGLE_STATUSES pair): a DONE task becomes TODO and any other task becomes DONE. Completing a task requires all its children to be DONE.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {object} models.TaskResponse "Successfully toggled task status"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 409 {object} models.ErrorResponse "Task has incomplete children"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/toggle [post]
+func (h *TaskHandler) ToggleTaskStatus(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Toggle the status using the service (includes validation)
+	task, err := h.taskService.ToggleTaskStatus(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response model and return
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetTaskHistory retrieves the move history of a task
+// @Summary Get task move history
+// @Description Retrieves the explicit moves of the specified task, oldest first. Positions shifted only to make room for other tasks are not recorded, and only the most recent moves are kept.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {array} models.MoveRecordResponse "Successfully retrieved move history"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/history [get]
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Retrieve task from repository
+	task, err := h.taskRepository.FindByID(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response models and return
+	response := models.MoveHistoryToResponseWithPositionBase(task.MoveHistory(), h.config.PositionBase)
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetTaskTimeline retrieves the status changes of a task
+// @Summary Get task status timeline
+// @Description Retrieves the explicit status changes of the specified task, oldest first, each with the previous status, the new status and when it changed. Changes made through the status, toggle, reset and status rename endpoints are recorded; the initial status and changes made as a side effect of other operations (such as reopening a DONE parent) are not. Only the most recent STATUS_HISTORY_LIMIT changes are kept.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {array} models.StatusRecordResponse "Successfully retrieved status timeline"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/timeline [get]
+func (h *TaskHandler) GetTaskTimeline(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Retrieve task from repository
+	task, err := h.taskRepository.FindByID(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.StatusHistoryToResponse(task.StatusHistory()))
+}
+
+// UngroupTask replaces a task with its children
+// @Summary Ungroup a task
+// @Description Moves all children of the specified task up to take its place among its siblings (later siblings shift right), then deletes the task. The root task cannot be ungrouped.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {array} models.TaskResponse "Successfully ungrouped; returns the promoted children"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 409 {object} models.ErrorResponse "Cannot ungroup the root task"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/ungroup [post]
+func (h *TaskHandler) UngroupTask(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Promote the children and delete the task using the service
+	children, err := h.taskService.UngroupTask(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response models and return
+	responses, err := h.toResponseList(children)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, responses)
+}
+
+// TagSubtree adds and removes tags on a task and all its descendants
+// @Summary Tag a subtree
+// @Description Adds and removes tags on the specified task and every descendant in a single write. Tags must be non-empty, at most 50 characters, and contain no whitespace or commas; a tag cannot be both added and removed.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param request body models.TagSubtreeRequest true "Tags to add and remove"
+// @Success 200 {object} models.TagSubtreeResponse "Successfully tagged; returns the number of tasks whose tags changed"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data, task ID format, or tag"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 413 {object} models.ErrorResponse "Subtree exceeds MAX_SUBTREE_OPERATION"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/tags/subtree [post]
+func (h *TaskHandler) TagSubtree(c *gin.Context) {
+	idParam := c.Param("id")
+	var req models.TagSubtreeRequest
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Apply the tag changes across the subtree using the service (includes validation)
+	affected, err := h.taskService.TagSubtree(taskID, req.Add, req.Remove)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.TagSubtreeResponse{Affected: affected})
+}
+
+// ResetSubtree sets a task and all its descendants back to TODO
+// @Summary Reset a subtree to TODO
+// @Description Sets the specified task and every descendant to TODO in a single transaction, clearing completedAt, to reopen a branch for rework. A Root Work Item keeps its status. The task's DONE ancestors are reopened to the configured REOPEN_STATUS (In Progress by default), since a DONE task cannot have incomplete children, and are returned.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {object} models.ResetSubtreeResponse "Successfully reset; returns the number of tasks set to TODO and the reopened ancestors"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 413 {object} models.ErrorResponse "Subtree exceeds MAX_SUBTREE_OPERATION"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/reset [post]
+func (h *TaskHandler) ResetSubtree(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	reset, reopened, err := h.taskService.ResetSubtree(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	reopenedResponses, err := h.toResponseList(reopened)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, models.ResetSubtreeResponse{Reset: reset, Reopened: reopenedResponses})
+}
+
+// UpdateTask updates a task's description
+// @Summary Update task description
+// @Description Updates the description of an existing task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param request body models.UpdateTaskRequest true "Task update request"
+// @Success 200 {object} models.TaskResponse "Successfully updated task"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data or task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id} [put]
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	idParam := c.Param("id")
+	var req models.UpdateTaskRequest
+	
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+	
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Update the description using the service (includes validation)
+	task, err := h.taskService.UpdateTaskDescription(taskID, req.Description)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response model and return
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// SetTaskKind changes a task's kind
+// @Summary Set task kind
+// @Description Changes the kind of an existing task, e.g. to mark it a research spike or a milestone. The kind must be one of the configured TASK_KINDS.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param request body models.SetKindRequest true "New kind"
+// @Success 200 {object} models.TaskResponse "Successfully changed the kind"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data, task ID format, or unknown kind"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/kind [put]
+func (h *TaskHandler) SetTaskKind(c *gin.Context) {
+	idParam := c.Param("id")
+	var req models.SetKindRequest
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Change the kind using the service (includes validation against the configured kinds)
+	task, err := h.taskService.SetTaskKind(taskID, req.Kind)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GetStatusOptions retrieves the statuses a task can move to right now
+// @Summary Get status options
+// @Description Returns the statuses PUT /tasks/{id}/status would accept for the task right now, in status order, for context-aware status dropdowns. The current status is not included, and complete statuses are left out while the task has incomplete children.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Success 200 {array} string "Successfully retrieved status options"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/status/options [get]
+func (h *TaskHandler) GetStatusOptions(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	options, err := h.taskService.StatusOptions(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response := make([]string, len(options))
+	for i, status := range options {
+		response[i] = status.String()
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// UpdateTaskStatus updates a task's status
+// @Summary Update task status
+// @Description Updates the status of an existing task. With ARCHIVE_ON_COMPLETE, a change that leaves a whole top-level branch DONE moves the branch to the archive (see GET /tasks/archive); the task is still returned, read from the archive.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param request body models.UpdateStatusRequest true "Status update request"
+// @Success 200 {object} models.TaskResponse "Successfully updated task status"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data, task ID format, or status value"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/status [put]
+func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
+	idParam := c.Param("id")
+	var req models.UpdateStatusRequest
+	
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+	
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert status string to Status
+	status, err := domain.NewStatus(req.Status)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Update the status using the service (includes validation)
+	err = h.taskService.ChangeTaskStatus(taskID, status)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Retrieve the updated task to return, from the archive if the change archived its branch
+	task, err := h.taskRepository.FindByID(taskID)
+	if _, ok := err.(domain.NotFoundError); ok {
+		task, err = h.taskService.FindArchivedTask(taskID)
+	}
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response model and return
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// MoveTask moves a task to a new position or parent
+// @Summary Move task
+// @Description Moves a task to a new position or under a different parent task. Moving an incomplete task under a DONE parent is rejected with 409, unless reconcile=true, which reopens the parent and its DONE ancestors to the configured REOPEN_STATUS (In Progress by default) and returns them alongside the moved task.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param reconcile query bool false "Reopen DONE ancestors at the destination instead of rejecting the move"
 // @Param request body models.MoveTaskRequest true "Move task request"
-// @Success 200 {object} models.TaskResponse "Successfully moved task"
+// @Success 200 {object} models.TaskResponse "Successfully moved task (models.MoveTaskResponse with reconcile=true)"
 // @Failure 400 {object} models.ErrorResponse "Invalid request data, task ID format, or would create cycle"
 // @Failure 404 {object} models.ErrorResponse "Task or parent task not found"
+// @Failure 409 {object} models.ErrorResponse "Incomplete task moved under a DONE parent without reconcile"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/move [put]
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	idParam := c.Param("id")
+	var req models.MoveTaskRequest
+	
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
+	reconcile, err := parseBoolQuery(c, "reconcile")
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert parent ID string to TaskID if provided
+	var newParentID *domain.TaskID
+	if req.ParentID != nil {
+		// Validate parent UUID format if provided
+		if err := middleware.ValidateUUID(c, *req.ParentID, "parentId"); err != nil {
+			return
+		}
+		
+		parentID, err := domain.TaskIDFromString(*req.ParentID)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		newParentID = &parentID
+	}
+
+	// Translate the client-facing position to the stored 0-based position
+	position, err := h.toInternalPosition("position", int(req.Position))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Move the task using the service (includes validation and position adjustments)
+	var reopened []*domain.Task
+	if reconcile {
+		reopened, err = h.taskService.MoveTaskAndReconcile(taskID, newParentID, position)
+	} else {
+		err = h.taskService.MoveTask(taskID, newParentID, position)
+	}
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Retrieve the updated task to return
+	task, err := h.taskRepository.FindByID(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert to response model and return
+	if reconcile {
+		reopenedResponses, err := h.toResponseList(reopened)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		taskResponse, err := h.toResponse(task)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		middleware.Respond(c, http.StatusOK, models.MoveTaskResponse{
+			Task:     taskResponse,
+			Reopened: reopenedResponses,
+		})
+		return
+	}
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// parseBoolQuery parses an optional boolean query parameter (absent means false)
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, domain.NewValidationError(name, "invalid-boolean", fmt.Sprintf("invalid %s value %q (must be true or false)", name, value))
+	}
+	return parsed, nil
+}
+
+// PromoteToRoot makes a task the root of the tree
+// @Summary Promote task to root
+// @Description Makes the task the root of the tree, keeping its subtree: its parent is cleared, its status becomes Root Work Item and its former siblings close the gap. Rejected with 409 while a root exists, unless replaceRoot=true, which deletes the current root; that is only allowed when the task is the root's only child, so no other branch is lost. Promoting the root itself returns it unchanged.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param replaceRoot query bool false "Delete the current root, whose only child the task must be"
+// @Success 200 {object} models.TaskResponse "Successfully promoted task"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format or replaceRoot value"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 409 {object} models.ErrorResponse "A root exists without replaceRoot, or the root has other branches"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /api/v1/tasks/{id}/move [put]
-func (h *TaskHandler) MoveTask(c *gin.Context) {
+// @Router /api/v1/tasks/{id}/promote-root [post]
+func (h *TaskHandler) PromoteToRoot(c *gin.Context) {
 	idParam := c.Param("id")
-	var req models.MoveTaskRequest
-	
+
 	// Validate UUID format
 	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
 		return
 	}
-	
+
+	// Convert ID string to TaskID
+	taskID, err := domain.TaskIDFromString(idParam)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	replaceRoot, err := parseBoolQuery(c, "replaceRoot")
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	task, err := h.taskService.PromoteToRoot(taskID, replaceRoot)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response, err := h.toResponse(task)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// GroupTasks wraps several tasks in a new parent task
+// @Summary Group tasks under a new task
+// @Description Creates a task with the given description under parentId at position, then moves the listed tasks, in order, to be its children. All moves are validated before any is applied.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.GroupTasksRequest true "Group request"
+// @Success 201 {object} models.TaskResponse "Successfully created group task"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data or task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task or parent task not found"
+// @Failure 409 {object} models.ErrorResponse "Grouping would create cycle, selection overlaps, or parent is DONE"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/group [post]
+func (h *TaskHandler) GroupTasks(c *gin.Context) {
+	var req models.GroupTasksRequest
+
 	// Bind and validate the request
 	if err := middleware.BindJSON(c, &req); err != nil {
 		return
 	}
 
+	// Convert parent ID string to TaskID
+	parentID, err := domain.TaskIDFromString(req.ParentID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert child ID strings to TaskIDs
+	childIDs := make([]domain.TaskID, len(req.ChildIDs))
+	for i, id := range req.ChildIDs {
+		childID, err := domain.TaskIDFromString(id)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		childIDs[i] = childID
+	}
+
+	// Translate the client-facing position to the stored 0-based position
+	position, err := h.toInternalPosition("position", int(req.Position))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Create the group and move the tasks using the service
+	group, err := h.taskService.GroupTasks(childIDs, req.Description, parentID, position)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response, err := h.toResponse(group)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	middleware.Respond(c, http.StatusCreated, response)
+}
+
+// ExportTasks streams every task in the store as JSON Lines
+// @Summary Export all tasks
+// @Description Streams every task as one storage-format JSON object per line (JSON Lines). The tasks are copied in one read and encoded afterwards, so a slow client does not hold up changes to the store. Tasks are written in no particular order.
+// @Tags tasks
+// @Produce application/x-ndjson
+// @Param format query string true "Export format" Enums(jsonl)
+// @Success 200 {string} string "One task JSON object per line"
+// @Failure 400 {object} models.ErrorResponse "Unsupported export format"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/export [get]
+func (h *TaskHandler) ExportTasks(c *gin.Context) {
+	format := c.Query("format")
+	if format != "jsonl" {
+		middleware.HandleError(c, domain.NewValidationError("format", "unsupported-format", fmt.Sprintf("unsupported export format %q (supported: jsonl)", format)))
+		return
+	}
+
+	// Copy the tasks under the repository's lock, so a slow client holds up only its own
+	// response rather than every writer
+	var dtos []infrastructure.TaskDTO
+	err := h.taskRepository.ForEach(func(task *domain.Task) error {
+		dtos = append(dtos, infrastructure.ToDTO(task))
+		return nil
+	})
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	// Encode each task to the response; Encode terminates every object with a newline
+	encoder := json.NewEncoder(c.Writer)
+	for _, dto := range dtos {
+		if err := encoder.Encode(dto); err != nil {
+			// The status line has already been sent, so the stream is cut short instead
+			slog.Error("Task export aborted", slog.String("error", err.Error()))
+			c.Abort()
+			return
+		}
+	}
+}
+
+// ExportSubtree returns a task and its descendants in the requested format
+// @Summary Export a subtree
+// @Description Returns the task and all its descendants, so a single branch can be shared without the whole tree. format=json nests the tasks as in API responses; jsonl writes one storage-format task per line (the import format); csv writes id, description, status, parentId and position columns (the CSV import format) with the exported task as a root; markdown writes a nested checklist with complete tasks checked. sort orders every task's children in the output, as on GET /tasks/{id}/children; stored positions, including the csv position column, are unchanged.
+// @Tags tasks
+// @Produce json
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Produce text/markdown
+// @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param format query string true "Export format" Enums(json, jsonl, csv, markdown)
+// @Param sort query string false "Order of each task's children (default: position); ties keep position order" Enums(position, status, createdAt, description)
+// @Success 200 {object} models.TaskTreeResponse "The subtree in the requested format"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format, unsupported export format or unknown sort"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 413 {object} models.ErrorResponse "Subtree exceeds MAX_SUBTREE_OPERATION"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/{id}/export [get]
+func (h *TaskHandler) ExportSubtree(c *gin.Context) {
+	idParam := c.Param("id")
+
+	// Validate UUID format
+	if err := middleware.ValidateUUID(c, idParam, "id"); err != nil {
+		return
+	}
+
 	// Convert ID string to TaskID
 	taskID, err := domain.TaskIDFromString(idParam)
 	if err != nil {
@@ -386,51 +1922,296 @@ func (h *TaskHandler) MoveTask(c *gin.Context) {
 		return
 	}
 
-	// Convert parent ID string to TaskID if provided
-	var newParentID *domain.TaskID
-	if req.ParentID != nil {
-		// Validate parent UUID format if provided
-		if err := middleware.ValidateUUID(c, *req.ParentID, "parentId"); err != nil {
-			return
+	format := c.Query("format")
+	var write func(w io.Writer, tasks []*domain.Task) error
+	var contentType string
+	switch format {
+	case "json":
+	case "jsonl":
+		write, contentType = infrastructure.WriteTaskJSONL, "application/x-ndjson"
+	case "csv":
+		write, contentType = infrastructure.WriteTaskCSV, "text/csv; charset=utf-8"
+	case "markdown":
+		write = func(w io.Writer, tasks []*domain.Task) error {
+			return infrastructure.WriteTaskMarkdown(w, tasks, h.config.CompleteStatuses)
 		}
-		
-		parentID, err := domain.TaskIDFromString(*req.ParentID)
+		contentType = "text/markdown; charset=utf-8"
+	default:
+		middleware.HandleError(c, domain.NewValidationError("format", "unsupported-format", fmt.Sprintf("unsupported export format %q (supported: json, jsonl, csv, markdown)", format)))
+		return
+	}
+
+	less, err := parseChildSort(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Load the subtree before writing anything, so an unknown ID or an oversized subtree
+	// still gets an error status
+	tasks, err := h.taskService.FindSubtree(taskID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if less != nil {
+		tasks = sortSubtree(tasks, less)
+	}
+
+	if write == nil {
+		middleware.Respond(c, http.StatusOK, models.SubtreeToTreeResponse(tasks, h.config.PositionBase))
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if err := write(c.Writer, tasks); err != nil {
+		// The status line has already been sent, so the output is cut short instead
+		slog.Error("Subtree export aborted", slog.String("task_id", taskID.String()), slog.String("error", err.Error()))
+		c.Abort()
+	}
+}
+
+// ImportTasks imports a flat collection of tasks from JSON Lines or CSV
+// @Summary Import tasks
+// @Description Reads one task JSON object per line (format=jsonl, the export format) or one task per CSV row (format=csv), validates the complete resulting tree (single root, no orphans, no cycles), then replaces or merges the store in a single operation. The CSV header names the columns: description is required; id, key, status, parentId and position are optional. Rows without an id get a generated one, and other rows may name them as parentId by their key. A parentId may refer to a row later in the file, or in merge mode to an existing task. An empty status is TODO and an empty position appends after the siblings listed before the row.
+// @Tags tasks
+// @Accept application/x-ndjson
+// @Accept text/csv
+// @Produce json
+// @Param format query string true "Import format" Enums(jsonl, csv)
+// @Param mode query string false "Replace the store or merge into it (default: merge)" Enums(replace, merge)
+// @Param request body string true "One task JSON object per line, or CSV with a header row"
+// @Success 200 {object} models.ImportResponse "Successfully imported tasks"
+// @Failure 400 {object} models.ErrorResponse "Unsupported format or mode, or malformed record (message includes the line or row number)"
+// @Failure 409 {object} models.ErrorResponse "Resulting tree violates an invariant"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/import [post]
+func (h *TaskHandler) ImportTasks(c *gin.Context) {
+	format := c.Query("format")
+	if format != "jsonl" && format != "csv" {
+		middleware.HandleError(c, domain.NewValidationError("format", "unsupported-format", fmt.Sprintf("unsupported import format %q (supported: jsonl, csv)", format)))
+		return
+	}
+
+	mode := domain.ImportMode(c.DefaultQuery("mode", string(domain.ImportModeMerge)))
+	if !mode.IsValid() {
+		middleware.HandleError(c, domain.NewValidationError("mode", "invalid-import-mode", fmt.Sprintf("invalid import mode %q (must be one of: replace, merge)", mode)))
+		return
+	}
+
+	// Read the records from the request body
+	var tasks []*domain.Task
+	var err error
+	if format == "csv" {
+		// Rows without an id get new IDs from the service, which keeps them clear of stored tasks
+		tasks, err = h.taskService.ImportTasksFrom(func(newID func() (domain.TaskID, error), kept func(domain.TaskID) bool) ([]*domain.Task, error) {
+			return infrastructure.ReadTaskCSV(c.Request.Body, newID, kept)
+		}, mode)
+	} else {
+		tasks, err = infrastructure.ReadTaskJSONLWithOptions(c.Request.Body, infrastructure.DTOOptions{StrictTimestamps: h.config.StrictTimestamps})
+		if err == nil {
+			// Validate the resulting tree and store it using the service
+			err = h.taskService.ImportTasks(tasks, mode)
+		}
+	}
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	total, err := h.taskRepository.Count()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.ImportResponse{
+		Mode:     string(mode),
+		Imported: len(tasks),
+		Total:    total,
+	})
+}
+
+// ValidateTree checks a nested tree against the tree invariants without creating anything
+// @Summary Validate a tree
+// @Description Checks a nested tree (same shape as a seed import) against the tree invariants: single root, Root Work Item only at the root, no cycles, contiguous sibling positions, and bottom-to-top completion. Nothing is created; all violations are returned.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.TreeNodeRequest true "Nested tree to validate"
+// @Success 200 {object} models.TreeValidationResponse "Validation result with any invariant violations"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/validate [post]
+func (h *TaskHandler) ValidateTree(c *gin.Context) {
+	var req models.TreeNodeRequest
+
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert the nested request to a domain import node
+	root, err := models.TreeNodeRequestToImportNode(req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Check the tree using the service (never persists anything)
+	violations, err := h.taskService.ValidateTree(root)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, models.InvariantViolationsToResponse(violations))
+}
+
+// MoveTasks moves several tasks under a single parent
+// @Summary Move multiple tasks
+// @Description Moves the listed tasks, in order, to sequential positions under the target parent. All moves are validated before any is applied.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.MoveTasksRequest true "Bulk move request"
+// @Success 200 {array} models.TaskResponse "Successfully moved tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data or task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task or parent task not found"
+// @Failure 409 {object} models.ErrorResponse "Move would create cycle or selection overlaps"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/move-many [post]
+func (h *TaskHandler) MoveTasks(c *gin.Context) {
+	var req models.MoveTasksRequest
+
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert parent ID string to TaskID
+	parentID, err := domain.TaskIDFromString(req.ParentID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Convert task ID strings to TaskIDs
+	taskIDs := make([]domain.TaskID, len(req.IDs))
+	for i, id := range req.IDs {
+		taskID, err := domain.TaskIDFromString(id)
 		if err != nil {
 			middleware.HandleError(c, err)
 			return
 		}
-		newParentID = &parentID
+		taskIDs[i] = taskID
 	}
 
-	// Move the task using the service (includes validation and position adjustments)
-	err = h.taskService.MoveTask(taskID, newParentID, req.Position)
+	// Translate the client-facing start position to the stored 0-based position
+	startPosition, err := h.toInternalPosition("startPosition", int(req.StartPosition))
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Retrieve the updated task to return
-	task, err := h.taskRepository.FindByID(taskID)
+	// Move the tasks using the service (validates all moves before applying any)
+	err = h.taskService.MoveTasks(taskIDs, parentID, startPosition)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	// Convert to response model and return
-	response := models.TaskToResponse(task)
-	c.JSON(http.StatusOK, response)
+	// Retrieve the moved tasks to return, in the order requested
+	tasks := make([]*domain.Task, len(taskIDs))
+	for i, taskID := range taskIDs {
+		task, err := h.taskRepository.FindByID(taskID)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		tasks[i] = task
+	}
+	responses, err := h.toResponseList(tasks)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, responses)
+}
+
+// NudgeTasks shifts several siblings one position up or down
+// @Summary Nudge tasks up or down
+// @Description Shifts the listed siblings one position up or down within their shared parent, keeping their relative order; the selection need not be contiguous. Each unselected sibling the selection passes moves the other way. Rejected if the tasks do not share a parent or the selection already includes the first (up) or last (down) child.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.NudgeTasksRequest true "Nudge request"
+// @Success 200 {array} models.TaskResponse "Successfully nudged tasks, in the order requested"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data or task ID format"
+// @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 409 {object} models.ErrorResponse "Tasks do not share a parent or the shift would go out of range"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/nudge [post]
+func (h *TaskHandler) NudgeTasks(c *gin.Context) {
+	var req models.NudgeTasksRequest
+
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	// Convert task ID strings to TaskIDs
+	taskIDs := make([]domain.TaskID, len(req.IDs))
+	for i, id := range req.IDs {
+		taskID, err := domain.TaskIDFromString(id)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		taskIDs[i] = taskID
+	}
+
+	// Shift the tasks using the service (all in one transaction)
+	if err := h.taskService.NudgeTasks(taskIDs, domain.NudgeDirection(req.Direction)); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Retrieve the nudged tasks to return, in the order requested
+	tasks := make([]*domain.Task, len(taskIDs))
+	for i, taskID := range taskIDs {
+		task, err := h.taskRepository.FindByID(taskID)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		tasks[i] = task
+	}
+	responses, err := h.toResponseList(tasks)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.Respond(c, http.StatusOK, responses)
 }
 
 // DeleteTask deletes a task
 // @Summary Delete task
-// @Description Deletes a task and all its descendants. Adjusts sibling positions automatically.
+// @Description Deletes a task and all its descendants (strategy=cascade, the default), or deletes only the task and moves its children up to take its place among its siblings (strategy=reparent). Adjusts sibling positions automatically. With DELETE_GUARD=block, deleting a task that is not DONE is rejected with 409 (code incomplete-delete) unless force=true.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID (UUID format)" format(uuid)
+// @Param strategy query string false "What happens to the task's children" Enums(cascade, reparent) default(cascade)
+// @Param force query bool false "Delete incomplete tasks even when DELETE_GUARD is block"
 // @Success 204 "Successfully deleted task"
-// @Failure 400 {object} models.ErrorResponse "Invalid task ID format"
+// @Failure 400 {object} models.ErrorResponse "Invalid task ID format, strategy or force value"
 // @Failure 404 {object} models.ErrorResponse "Task not found"
+// @Failure 409 {object} models.ErrorResponse "Cannot reparent the children of the root task, or incomplete tasks are guarded"
+// @Failure 413 {object} models.ErrorResponse "Subtree exceeds MAX_SUBTREE_OPERATION"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
 // @Router /api/v1/tasks/{id} [delete]
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
@@ -448,8 +2229,20 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	// Delete the task using the service (includes cascading deletion and position adjustments)
-	err = h.taskService.DeleteTask(taskID)
+	strategy := domain.DeleteStrategy(c.DefaultQuery("strategy", string(domain.DeleteCascade)))
+	if !strategy.IsValid() {
+		middleware.HandleError(c, domain.NewValidationError("strategy", "invalid-delete-strategy", fmt.Sprintf("invalid delete strategy %q (must be one of: cascade, reparent)", strategy)))
+		return
+	}
+
+	force, err := parseBoolQuery(c, "force")
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// Delete the task using the service (includes the delete guard, cascading deletion or reparenting, and position adjustments)
+	err = h.taskService.DeleteTaskWithStrategy(taskID, strategy, force)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
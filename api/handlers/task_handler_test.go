@@ -2,11 +2,18 @@ package handlers
 
 import (
 	"bytes"
+	"discovery-tree/api/models"
 	"discovery-tree/domain"
+	"discovery-tree/infrastructure"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -46,6 +53,7 @@ func TestTaskHandler_CreateRootTask_Success(t *testing.T) {
 	assert.Equal(t, "Root Work Item", response["status"])
 	assert.Nil(t, response["parentId"])
 	assert.Equal(t, float64(0), response["position"])
+	assert.Equal(t, "/api/v1/tasks/"+response["id"].(string), w.Header().Get("Location"))
 }
 
 func TestTaskHandler_CreateRootTask_ValidationError(t *testing.T) {
@@ -136,4 +144,2633 @@ func TestTaskHandler_GetTask_NotFound(t *testing.T) {
 	require.NoError(t, err)
 	
 	assert.Equal(t, "NotFoundError", response["error"])
-}
\ No newline at end of file
+}
+
+func TestTaskHandler_GetTask_IncludeChildren(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	_, err = service.CreateChildTask("A", root.ID())
+	require.NoError(t, err)
+	_, err = service.CreateChildTask("B", root.ID())
+	require.NoError(t, err)
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: root.ID().String()}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+root.ID().String()+"?include=children", nil)
+
+	// Execute
+	handler.GetTask(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, root.ID().String(), response["id"])
+	children, ok := response["children"].([]interface{})
+	require.True(t, ok, "expected children list")
+	require.Len(t, children, 2)
+	assert.Equal(t, "A", children[0].(map[string]interface{})["description"])
+	assert.Equal(t, "B", children[1].(map[string]interface{})["description"])
+
+	// Keys that were not requested are omitted
+	assert.NotContains(t, response, "ancestors")
+	assert.NotContains(t, response, "siblings")
+	assert.NotContains(t, response, "readiness")
+}
+
+func TestTaskHandler_ChildFlags(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> parent -> leaf
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	leaf, _ := service.CreateChildTask("Leaf", parent.ID())
+
+	gin.SetMode(gin.TestMode)
+
+	// A single task counts its own children
+	getTask := func(id string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id, nil)
+		handler.GetTask(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := getTask(parent.ID().String())
+	assert.Equal(t, true, response["hasChildren"])
+	assert.Equal(t, false, response["isLeaf"])
+
+	response = getTask(leaf.ID().String())
+	assert.Equal(t, false, response["hasChildren"])
+	assert.Equal(t, true, response["isLeaf"])
+
+	// The list counts children for every task at once
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks", nil)
+	handler.GetAllTasks(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 3)
+	flags := make(map[string][2]interface{})
+	for _, task := range list {
+		flags[task["description"].(string)] = [2]interface{}{task["hasChildren"], task["isLeaf"]}
+	}
+	assert.Equal(t, [2]interface{}{true, false}, flags["Root"])
+	assert.Equal(t, [2]interface{}{true, false}, flags["Parent"])
+	assert.Equal(t, [2]interface{}{false, true}, flags["Leaf"])
+}
+
+// countFailingRepository fails every per-task child count
+type countFailingRepository struct {
+	domain.TaskRepository
+}
+
+func (r countFailingRepository) CountByParentID(parentID *domain.TaskID) (int, error) {
+	return 0, errors.New("count failed")
+}
+
+func TestTaskHandler_ChildCountErrors(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, countFailingRepository{repo})
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	_, _ = service.CreateChildTask("A", root.ID())
+	_, _ = service.CreateChildTask("B", root.ID())
+
+	gin.SetMode(gin.TestMode)
+
+	// A failed count is an error, not a leaf
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: root.ID().String()}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+root.ID().String(), nil)
+	handler.GetTask(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// Lists count every child in one pass instead of once per task
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: root.ID().String()}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+root.ID().String()+"/children", nil)
+	handler.GetTaskChildren(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var children []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &children))
+	require.Len(t, children, 2)
+	assert.Equal(t, true, children[0]["isLeaf"])
+}
+
+func TestTaskHandler_GetTask_IncludeChildrenAndReadiness(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	first, err := service.CreateChildTask("First", root.ID())
+	require.NoError(t, err)
+	second, err := service.CreateChildTask("Second", root.ID())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		taskID           domain.TaskID
+		expectedReady    bool
+		expectedFrontier bool
+		childCount       int
+	}{
+		{"Parent with open children", root.ID(), false, false, 2},
+		{"Leftmost leaf", first.ID(), true, true, 0},
+		{"Leaf behind open sibling", second.ID(), false, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.taskID.String()}}
+			c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+tt.taskID.String()+"?include=children,readiness", nil)
+
+			handler.GetTask(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			// An included but empty list is present as []
+			children, ok := response["children"].([]interface{})
+			require.True(t, ok, "expected children list")
+			assert.Len(t, children, tt.childCount)
+
+			readiness, ok := response["readiness"].(map[string]interface{})
+			require.True(t, ok, "expected readiness object")
+			assert.Equal(t, tt.expectedReady, readiness["ready"])
+			assert.Equal(t, tt.expectedFrontier, readiness["frontier"])
+			if !tt.expectedReady {
+				assert.NotEmpty(t, readiness["reasons"])
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetTask_UnknownInclude(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: root.ID().String()}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+root.ID().String()+"?include=children,grandparents", nil)
+
+	// Execute
+	handler.GetTask(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ValidationError", response["error"])
+	assert.Equal(t, "unknown-include", response["code"])
+	assert.Equal(t, "include", response["field"])
+}
+
+func TestTaskHandler_CreateChildTask_Position(t *testing.T) {
+	tests := []struct {
+		name           string
+		position       *int
+		expectedStatus int
+		expectedOrder  []string
+	}{
+		{"Front", intPtr(0), http.StatusCreated, []string{"New", "A", "B"}},
+		{"Middle", intPtr(1), http.StatusCreated, []string{"A", "New", "B"}},
+		{"End", intPtr(2), http.StatusCreated, []string{"A", "B", "New"}},
+		{"OmittedAppends", nil, http.StatusCreated, []string{"A", "B", "New"}},
+		{"OutOfRange", intPtr(3), http.StatusBadRequest, []string{"A", "B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskService(repo)
+			handler := NewTaskHandler(service, repo)
+
+			// Create tree: root -> a, b
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+			_, _ = service.CreateChildTask("A", root.ID())
+			_, _ = service.CreateChildTask("B", root.ID())
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			requestBody := map[string]interface{}{
+				"description": "New",
+				"parentId":    root.ID().String(),
+			}
+			if tt.position != nil {
+				requestBody["position"] = *tt.position
+			}
+			jsonBody, _ := json.Marshal(requestBody)
+			c.Request = httptest.NewRequest("POST", "/api/v1/tasks", bytes.NewBuffer(jsonBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			// Execute
+			handler.CreateChildTask(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			rootID := root.ID()
+			children, err := repo.FindByParentID(&rootID)
+			require.NoError(t, err)
+			require.Len(t, children, len(tt.expectedOrder))
+			for i, description := range tt.expectedOrder {
+				assert.Equal(t, description, children[i].Description())
+				assert.Equal(t, i, children[i].Position())
+			}
+		})
+	}
+}
+
+func TestTaskHandler_MoveTasks_Success(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a, b, target
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	target, _ := service.CreateChildTask("Target", root.ID())
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	// Prepare request
+	requestBody := map[string]interface{}{
+		"ids":           []string{b.ID().String(), a.ID().String()},
+		"parentId":      target.ID().String(),
+		"startPosition": 0,
+	}
+	jsonBody, _ := json.Marshal(requestBody)
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/move-many", bytes.NewBuffer(jsonBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.MoveTasks(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response, 2)
+
+	assert.Equal(t, b.ID().String(), response[0]["id"])
+	assert.Equal(t, target.ID().String(), response[0]["parentId"])
+	assert.Equal(t, float64(0), response[0]["position"])
+	assert.Equal(t, a.ID().String(), response[1]["id"])
+	assert.Equal(t, float64(1), response[1]["position"])
+}
+
+func TestTaskHandler_MoveTask_PositionBase(t *testing.T) {
+	tests := []struct {
+		name             string
+		positionBase     int
+		requestPosition  int
+		expectedStatus   int
+		expectedStored   int
+		expectedResponse int
+	}{
+		{"ZeroBased", 0, 0, http.StatusOK, 0, 0},
+		{"OneBased", 1, 1, http.StatusOK, 0, 1},
+		{"OneBasedRejectsZero", 1, 0, http.StatusBadRequest, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskService(repo)
+			handler := NewTaskHandlerWithConfig(service, repo, TaskHandlerConfig{PositionBase: tt.positionBase})
+
+			// Create tree: root -> a, b
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+			_, _ = service.CreateChildTask("A", root.ID())
+			b, _ := service.CreateChildTask("B", root.ID())
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: b.ID().String()}}
+
+			// Move b to the first position
+			requestBody := map[string]interface{}{
+				"parentId": root.ID().String(),
+				"position": tt.requestPosition,
+			}
+			jsonBody, _ := json.Marshal(requestBody)
+			c.Request = httptest.NewRequest("PUT", "/api/v1/tasks/"+b.ID().String()+"/move", bytes.NewBuffer(jsonBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			// Execute
+			handler.MoveTask(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err = json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, "ValidationError", response["error"])
+				assert.Equal(t, "position-below-base", response["code"])
+				assert.Equal(t, "position", response["field"])
+
+				// Verify nothing moved
+				stored, _ := repo.FindByID(b.ID())
+				assert.Equal(t, 1, stored.Position())
+				return
+			}
+
+			assert.Equal(t, float64(tt.expectedResponse), response["position"])
+
+			stored, _ := repo.FindByID(b.ID())
+			assert.Equal(t, tt.expectedStored, stored.Position())
+		})
+	}
+}
+
+func TestTaskHandler_MoveTask_FloatPosition(t *testing.T) {
+	tests := []struct {
+		name            string
+		position        string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{"IntegralFloat", "0.0", http.StatusOK, ""},
+		{"Fraction", "0.5", http.StatusBadRequest, "Field 'position' must be a whole number, got 0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskService(repo)
+			handler := NewTaskHandler(service, repo)
+
+			// Create tree: root -> a, b
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+			_, _ = service.CreateChildTask("A", root.ID())
+			b, _ := service.CreateChildTask("B", root.ID())
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: b.ID().String()}}
+
+			body := `{"parentId": "` + root.ID().String() + `", "position": ` + tt.position + `}`
+			c.Request = httptest.NewRequest("PUT", "/api/v1/tasks/"+b.ID().String()+"/move", strings.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			// Execute
+			handler.MoveTask(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			stored, _ := repo.FindByID(b.ID())
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, tt.expectedMessage, response["message"])
+				assert.Equal(t, 1, stored.Position())
+				return
+			}
+			assert.Equal(t, 0, stored.Position())
+		})
+	}
+}
+
+func TestTaskHandler_GetTaskRoot_Success(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> child -> grandchild
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	child, _ := service.CreateChildTask("Child", root.ID())
+	grandchild, _ := service.CreateChildTask("Grandchild", child.ID())
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: grandchild.ID().String()}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+grandchild.ID().String()+"/root", nil)
+
+	// Execute
+	handler.GetTaskRoot(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, root.ID().String(), response["id"])
+	assert.Nil(t, response["parentId"])
+}
+
+func TestTaskHandler_GetTaskBlockers(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> A, B; B -> B1 (DONE), B2
+	// B is blocked both by its left sibling A and by its child B2
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	b1, _ := service.CreateChildTask("B1", b.ID())
+	b2, _ := service.CreateChildTask("B2", b.ID())
+	require.NoError(t, service.ChangeTaskStatus(b1.ID(), domain.StatusDONE))
+
+	get := func(id string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/blockers", nil)
+		handler.GetTaskBlockers(c)
+		return w
+	}
+
+	w := get(b.ID().String())
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, "leftSibling", response[0]["type"])
+	assert.Equal(t, a.ID().String(), response[0]["taskId"])
+	assert.Equal(t, "A", response[0]["description"])
+	assert.Equal(t, "child", response[1]["type"])
+	assert.Equal(t, b2.ID().String(), response[1]["taskId"])
+	assert.Equal(t, "B2", response[1]["description"])
+
+	// A ready task has an empty list
+	w = get(a.ID().String())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	w = get(domain.NewTaskID().String())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTaskHandler_GetStatusOptions(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> parent -> child
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	child, _ := service.CreateChildTask("Child", parent.ID())
+
+	get := func(id string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/status/options", nil)
+		handler.GetStatusOptions(c)
+		return w
+	}
+
+	// A parent with an incomplete child cannot be DONE
+	w := get(parent.ID().String())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `["In Progress", "Blocked", "Root Work Item"]`, w.Body.String())
+
+	// A leaf can move to any other status
+	w = get(child.ID().String())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `["In Progress", "DONE", "Blocked", "Root Work Item"]`, w.Body.String())
+
+	w = get(domain.NewTaskID().String())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTaskHandler_GetSiblingCount(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+
+	// Create tree: root -> A, B, C
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	service.CreateChildTask("C", root.ID())
+
+	tests := []struct {
+		name           string
+		positionBase   int
+		id             string
+		expectedStatus int
+		expectedIndex  float64
+	}{
+		{"MiddleChild", 0, b.ID().String(), http.StatusOK, 1},
+		{"MiddleChildOneBased", 1, b.ID().String(), http.StatusOK, 2},
+		{"UnknownTask", 0, domain.NewTaskID().String(), http.StatusNotFound, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTaskHandlerWithConfig(service, repo, TaskHandlerConfig{PositionBase: tt.positionBase})
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.id}}
+			c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+tt.id+"/siblings/count", nil)
+
+			handler.GetSiblingCount(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, float64(3), response["total"])
+			assert.Equal(t, tt.expectedIndex, response["index"])
+		})
+	}
+}
+
+func TestTaskHandler_GetTaskPosition(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+
+	// Create tree: root -> A, B, C
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+
+	getPosition := func(handler *TaskHandler, id string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Params = gin.Params{{Key: "id", Value: id}}
+		ctx.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/position", nil)
+		handler.GetTaskPosition(ctx)
+		return w
+	}
+
+	// Move C under A, then to the front of the root's children
+	aID := a.ID()
+	require.NoError(t, service.MoveTask(c.ID(), &aID, 0))
+	handler := NewTaskHandler(service, repo)
+	w := getPosition(handler, c.ID().String())
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.TaskPositionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.ParentID)
+	assert.Equal(t, a.ID().String(), *response.ParentID)
+	assert.Equal(t, 0, response.Position)
+	assert.Equal(t, 1, response.SiblingCount)
+
+	rootID := root.ID()
+	require.NoError(t, service.MoveTask(c.ID(), &rootID, 0))
+	handler = NewTaskHandlerWithConfig(service, repo, TaskHandlerConfig{PositionBase: 1})
+	w = getPosition(handler, c.ID().String())
+	require.Equal(t, http.StatusOK, w.Code)
+	response = models.TaskPositionResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.ParentID)
+	assert.Equal(t, root.ID().String(), *response.ParentID)
+	assert.Equal(t, 1, response.Position) // first child, one-based
+	assert.Equal(t, 3, response.SiblingCount)
+
+	// The root has no parent
+	w = getPosition(handler, root.ID().String())
+	require.Equal(t, http.StatusOK, w.Code)
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+	assert.Nil(t, raw["parentId"])
+	assert.Contains(t, raw, "parentId")
+
+	// Unknown tasks are 404
+	assert.Equal(t, http.StatusNotFound, getPosition(handler, domain.NewTaskID().String()).Code)
+}
+
+func TestTaskHandler_PromoteToRoot(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> A -> A1
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, err := service.CreateChildTask("A", root.ID())
+	require.NoError(t, err)
+	_, err = service.CreateChildTask("A1", a.ID())
+	require.NoError(t, err)
+
+	promote := func(id, query string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("POST", "/api/v1/tasks/"+id+"/promote-root"+query, nil)
+		handler.PromoteToRoot(c)
+		return w
+	}
+
+	// A root exists, so promoting needs replaceRoot
+	assert.Equal(t, http.StatusConflict, promote(a.ID().String(), "").Code)
+	assert.Equal(t, http.StatusBadRequest, promote(a.ID().String(), "?replaceRoot=maybe").Code)
+	assert.Equal(t, http.StatusNotFound, promote(domain.NewTaskID().String(), "?replaceRoot=true").Code)
+
+	w := promote(a.ID().String(), "?replaceRoot=true")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, a.ID().String(), response["id"])
+	assert.Nil(t, response["parentId"])
+	assert.Equal(t, "Root Work Item", response["status"])
+	assert.Equal(t, float64(0), response["position"])
+
+	newRoot, err := repo.FindRoot()
+	require.NoError(t, err)
+	assert.Equal(t, a.ID(), newRoot.ID())
+	_, err = repo.FindByID(root.ID())
+	assert.Error(t, err)
+}
+
+func TestTaskHandler_FindTasksByDescription(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> deploy, deploy, Build
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	service.CreateChildTask("deploy", root.ID())
+	service.CreateChildTask("deploy", root.ID())
+	service.CreateChildTask("Build", root.ID())
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"SingleMatch", "value=Build", http.StatusOK, 1},
+		{"MultipleMatches", "value=deploy", http.StatusOK, 2},
+		{"NoMatch", "value=Ship", http.StatusOK, 0},
+		{"CaseSensitiveByDefault", "value=BUILD", http.StatusOK, 0},
+		{"CaseInsensitive", "value=BUILD&ci=true", http.StatusOK, 1},
+		{"MissingValue", "", http.StatusBadRequest, 0},
+		{"InvalidCI", "value=Build&ci=maybe", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/tasks/by-description?"+tt.query, nil)
+
+			handler.FindTasksByDescription(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			// No match is an empty list, not null
+			var response []map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response)
+			assert.Len(t, response, tt.expectedCount)
+		})
+	}
+}
+
+func TestTaskHandler_ValidateTree(t *testing.T) {
+	tests := []struct {
+		name                string
+		body                string
+		expectedStatus      int
+		expectedValid       bool
+		expectedConstraints []interface{}
+	}{
+		{
+			name:                "ValidTree",
+			body:                `{"description": "Root", "children": [{"description": "A", "status": "DONE"}, {"description": "B"}]}`,
+			expectedStatus:      http.StatusOK,
+			expectedValid:       true,
+			expectedConstraints: []interface{}{},
+		},
+		{
+			name:                "DoneOverIncomplete",
+			body:                `{"description": "Root", "children": [{"description": "A", "status": "DONE", "children": [{"description": "A1"}]}]}`,
+			expectedStatus:      http.StatusOK,
+			expectedValid:       false,
+			expectedConstraints: []interface{}{"bottom-to-top-completion"},
+		},
+		{
+			name:                "MisplacedRootStatus",
+			body:                `{"description": "Root", "status": "TODO", "children": [{"description": "A", "status": "Root Work Item"}]}`,
+			expectedStatus:      http.StatusOK,
+			expectedValid:       false,
+			expectedConstraints: []interface{}{"root-status", "root-status"},
+		},
+		{
+			name:           "EmptyDescription",
+			body:           `{"description": "Root", "children": [{"description": ""}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskService(repo)
+			handler := NewTaskHandler(service, repo)
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/tasks/validate", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			// Execute
+			handler.ValidateTree(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			// Validation never creates tasks
+			all, _ := repo.FindAll()
+			assert.Empty(t, all)
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedValid, response["valid"])
+			constraints := []interface{}{}
+			for _, v := range response["violations"].([]interface{}) {
+				constraints = append(constraints, v.(map[string]interface{})["constraint"])
+			}
+			assert.Equal(t, tt.expectedConstraints, constraints)
+		})
+	}
+}
+
+func TestTaskHandler_ExportTasks_JSONL(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a -> a1
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	_, _ = service.CreateChildTask("A1", a.ID())
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/export?format=jsonl", nil)
+
+	// Execute
+	handler.ExportTasks(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	seen := map[string]bool{}
+	for _, line := range lines {
+		var dto infrastructure.TaskDTO
+		require.NoError(t, json.Unmarshal([]byte(line), &dto))
+
+		task, err := infrastructure.FromDTO(dto)
+		require.NoError(t, err)
+		seen[task.Description()] = true
+	}
+	assert.Equal(t, map[string]bool{"Root": true, "A": true, "A1": true}, seen)
+}
+
+// stalledWriter is a response writer whose first write blocks until released,
+// like a client that stops reading
+type stalledWriter struct {
+	*httptest.ResponseRecorder
+	writing chan struct{} // closed when the first write starts
+	release chan struct{} // close to let writes finish
+	once    sync.Once
+}
+
+func (w *stalledWriter) Write(data []byte) (int, error) {
+	w.once.Do(func() { close(w.writing) })
+	<-w.release
+	return w.ResponseRecorder.Write(data)
+}
+
+func TestTaskHandler_ExportTasks_SlowClientDoesNotBlockWriters(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+
+	// Start an export whose client stops reading
+	gin.SetMode(gin.TestMode)
+	w := &stalledWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		writing:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/export?format=jsonl", nil)
+	exported := make(chan struct{})
+	go func() {
+		handler.ExportTasks(c)
+		close(exported)
+	}()
+	<-w.writing
+
+	// A change goes through while the export is stuck writing
+	created := make(chan error, 1)
+	go func() {
+		_, err := service.CreateChildTask("A", root.ID())
+		created <- err
+	}()
+	select {
+	case err := <-created:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("creating a task waited for the stalled export")
+	}
+
+	close(w.release)
+	<-exported
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTaskHandler_ExportTasks_UnsupportedFormat(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	handler := NewTaskHandler(domain.NewTaskService(repo), repo)
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/export?format=xml", nil)
+
+	// Execute
+	handler.ExportTasks(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "format")
+}
+
+func TestTaskHandler_ImportTasks_JSONL(t *testing.T) {
+	// Export a tree from one store
+	source := domain.NewInMemoryTaskRepository()
+	sourceService := domain.NewTaskService(source)
+	root, err := sourceService.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := sourceService.CreateChildTask("A", root.ID())
+	_, _ = sourceService.CreateChildTask("A1", a.ID())
+
+	var body strings.Builder
+	_ = source.ForEach(func(task *domain.Task) error {
+		line, err := json.Marshal(infrastructure.ToDTO(task))
+		body.Write(line)
+		body.WriteString("\n")
+		return err
+	})
+
+	// Import it into a store holding a different tree
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	_, err = service.CreateRootTask("Old root")
+	require.NoError(t, err)
+	handler := NewTaskHandler(service, repo)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/import?format=jsonl&mode=replace", strings.NewReader(body.String()))
+	c.Request.Header.Set("Content-Type", "application/x-ndjson")
+
+	// Execute
+	handler.ImportTasks(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "replace", response["mode"])
+	assert.Equal(t, float64(3), response["imported"])
+	assert.Equal(t, float64(3), response["total"])
+
+	// The old tree was replaced by the imported one
+	importedRoot, err := repo.FindRoot()
+	require.NoError(t, err)
+	assert.True(t, importedRoot.ID().Equals(root.ID()))
+}
+
+func TestTaskHandler_ImportTasks_DanglingParent(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	missingParent := domain.NewTaskID()
+	orphan, _ := domain.NewTask("Orphan", &missingParent, 0)
+
+	var body strings.Builder
+	for _, task := range []*domain.Task{root, orphan} {
+		line, _ := json.Marshal(infrastructure.ToDTO(task))
+		body.Write(line)
+		body.WriteString("\n")
+	}
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/import?format=jsonl&mode=replace", strings.NewReader(body.String()))
+
+	// Execute
+	handler.ImportTasks(c)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "missing-parent")
+
+	// Nothing was stored
+	all, _ := repo.FindAll()
+	assert.Empty(t, all)
+}
+
+func TestTaskHandler_ImportTasks_MalformedLine(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	handler := NewTaskHandler(domain.NewTaskService(repo), repo)
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	line, _ := json.Marshal(infrastructure.ToDTO(root))
+	body := string(line) + "\n{not json}\n"
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/import?format=jsonl", strings.NewReader(body))
+
+	// Execute
+	handler.ImportTasks(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "line 2")
+}
+
+func TestTaskHandler_GetTaskChanges(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	_, _ = service.CreateChildTask("B", root.ID())
+
+	changes := func(query string) models.TaskChangesResponse {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/changes"+query, nil)
+		handler.GetTaskChanges(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response models.TaskChangesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	descriptions := func(response models.TaskChangesResponse) []string {
+		var result []string
+		for _, task := range response.Tasks {
+			result = append(result, task.Description)
+		}
+		return result
+	}
+
+	// Everything, in the order it changed
+	all := changes("")
+	assert.Equal(t, []string{"Root", "A", "B"}, descriptions(all))
+	assert.False(t, all.HasMore)
+
+	// Nothing new since the cursor
+	assert.Empty(t, changes(fmt.Sprintf("?since=%d", all.Seq)).Tasks)
+
+	// Changing A moves it past B
+	_, err = service.UpdateTaskDescription(a.ID(), "A2")
+	require.NoError(t, err)
+	next := changes(fmt.Sprintf("?since=%d", all.Seq))
+	assert.Equal(t, []string{"A2"}, descriptions(next))
+	assert.Greater(t, next.Seq, all.Seq)
+	assert.Equal(t, next.Seq, next.Tasks[0].Seq)
+
+	// A limit pages through the changes with the returned cursor
+	page := changes("?limit=2")
+	assert.Equal(t, []string{"Root", "B"}, descriptions(page))
+	assert.True(t, page.HasMore)
+	rest := changes(fmt.Sprintf("?since=%d&limit=2", page.Seq))
+	assert.Equal(t, []string{"A2"}, descriptions(rest))
+	assert.False(t, rest.HasMore)
+
+	// Invalid cursors are rejected
+	for _, query := range []string{"?since=-1", "?since=x", "?limit=0"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/changes"+query, nil)
+		handler.GetTaskChanges(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestTaskHandler_NudgeTasks(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a, b, c, d
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+	d, _ := service.CreateChildTask("D", root.ID())
+
+	nudge := func(direction string, ids ...string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		jsonBody, _ := json.Marshal(map[string]interface{}{"ids": ids, "direction": direction})
+		ctx.Request = httptest.NewRequest("POST", "/api/v1/tasks/nudge", bytes.NewBuffer(jsonBody))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		handler.NudgeTasks(ctx)
+		return w
+	}
+
+	order := func() []string {
+		rootID := root.ID()
+		children, err := repo.FindByParentID(&rootID)
+		require.NoError(t, err)
+		descriptions := make([]string, len(children))
+		for i, child := range children {
+			descriptions[i] = child.Description()
+		}
+		return descriptions
+	}
+
+	// Nudge b and c up: they pass a
+	w := nudge("up", b.ID().String(), c.ID().String())
+	require.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, b.ID().String(), response[0]["id"])
+	assert.Equal(t, float64(0), response[0]["position"])
+	assert.Equal(t, float64(1), response[1]["position"])
+	assert.Equal(t, []string{"B", "C", "A", "D"}, order())
+
+	// Nudge them down twice: back past a, then past d
+	require.Equal(t, http.StatusOK, nudge("down", b.ID().String(), c.ID().String()).Code)
+	assert.Equal(t, []string{"A", "B", "C", "D"}, order())
+	require.Equal(t, http.StatusOK, nudge("down", c.ID().String(), b.ID().String()).Code)
+	assert.Equal(t, []string{"A", "D", "B", "C"}, order())
+
+	// Past the end, the whole nudge is rejected
+	assert.Equal(t, http.StatusConflict, nudge("down", a.ID().String(), c.ID().String()).Code)
+	assert.Equal(t, []string{"A", "D", "B", "C"}, order())
+
+	// An unknown direction is rejected at binding
+	assert.Equal(t, http.StatusBadRequest, nudge("left", d.ID().String()).Code)
+}
+
+func TestTaskHandler_ChildSort(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> parent -> c (DONE), b (In Progress), a (TODO)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	c, _ := service.CreateChildTask("C", parent.ID())
+	b, _ := service.CreateChildTask("B", parent.ID())
+	_, _ = service.CreateChildTask("A", parent.ID())
+	require.NoError(t, service.ChangeTaskStatus(c.ID(), domain.StatusDONE))
+	require.NoError(t, service.ChangeTaskStatus(b.ID(), domain.StatusInProgress))
+
+	get := func(path string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Params = gin.Params{{Key: "id", Value: parent.ID().String()}}
+		ctx.Request = httptest.NewRequest("GET", path, nil)
+		handle(ctx)
+		return w
+	}
+
+	children := func(query string) []string {
+		w := get("/api/v1/tasks/"+parent.ID().String()+"/children"+query, handler.GetTaskChildren)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		var descriptions []string
+		for _, child := range response {
+			descriptions = append(descriptions, child["description"].(string))
+		}
+		return descriptions
+	}
+
+	// Default and explicit position order is the stored order
+	assert.Equal(t, []string{"C", "B", "A"}, children(""))
+	assert.Equal(t, []string{"C", "B", "A"}, children("?sort=position"))
+
+	// Other sorts reorder the response only
+	assert.Equal(t, []string{"A", "B", "C"}, children("?sort=status"))
+	assert.Equal(t, []string{"A", "B", "C"}, children("?sort=description"))
+	assert.Equal(t, []string{"C", "B", "A"}, children(""))
+
+	// The subtree export sorts each task's children the same way
+	w := get("/api/v1/tasks/"+parent.ID().String()+"/export?format=markdown&sort=status", handler.ExportSubtree)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "- [ ] Parent\n  - [ ] A\n  - [ ] B _(In Progress)_\n  - [x] C\n", w.Body.String())
+
+	// Unknown sort keys, including priority, which tasks do not have, are rejected
+	for _, query := range []string{"?sort=priority", "?sort=bogus"} {
+		w := get("/api/v1/tasks/"+parent.ID().String()+"/children"+query, handler.GetTaskChildren)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	w = get("/api/v1/tasks/"+parent.ID().String()+"/export?format=json&sort=priority", handler.ExportSubtree)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTaskHandler_ExportSubtree(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> b, a (-> a1 DONE, a2 In Progress -> a2x); a is exported
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	_, _ = service.CreateChildTask("B", root.ID())
+	a, _ := service.CreateChildTask("A", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+	a2, _ := service.CreateChildTask("A2", a.ID())
+	a2x, _ := service.CreateChildTask("A2x", a2.ID())
+	require.NoError(t, service.ChangeTaskStatus(a1.ID(), domain.StatusDONE))
+	require.NoError(t, service.ChangeTaskStatus(a2.ID(), domain.StatusInProgress))
+
+	export := func(id, format string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/export?format="+format, nil)
+		handler.ExportSubtree(c)
+		return w
+	}
+
+	t.Run("json", func(t *testing.T) {
+		w := export(a.ID().String(), "json")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var tree struct {
+			Description string `json:"description"`
+			Children    []struct {
+				Description string            `json:"description"`
+				Children    []json.RawMessage `json:"children"`
+			} `json:"children"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+		assert.Equal(t, "A", tree.Description)
+		require.Len(t, tree.Children, 2)
+		assert.Equal(t, "A1", tree.Children[0].Description)
+		assert.Empty(t, tree.Children[0].Children)
+		assert.Equal(t, "A2", tree.Children[1].Description)
+		assert.Len(t, tree.Children[1].Children, 1)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		w := export(a.ID().String(), "jsonl")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		// The lines read back as the subtree, in depth-first order
+		tasks, err := infrastructure.ReadTaskJSONL(w.Body)
+		require.NoError(t, err)
+		require.Len(t, tasks, 4)
+		for i, expected := range []*domain.Task{a, a1, a2, a2x} {
+			assert.True(t, tasks[i].ID().Equals(expected.ID()), "task %d", i)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		w := export(a.ID().String(), "csv")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+		// The exported task becomes a root, and its descendants keep their parents
+		expected := "id,description,status,parentId,position\n" +
+			a.ID().String() + ",A,TODO,,0\n" +
+			a1.ID().String() + ",A1,DONE," + a.ID().String() + ",0\n" +
+			a2.ID().String() + ",A2,In Progress," + a.ID().String() + ",1\n" +
+			a2x.ID().String() + ",A2x,TODO," + a2.ID().String() + ",0\n"
+		assert.Equal(t, expected, w.Body.String())
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		w := export(a.ID().String(), "markdown")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+
+		expected := "- [ ] A\n" +
+			"  - [x] A1\n" +
+			"  - [ ] A2 _(In Progress)_\n" +
+			"    - [ ] A2x\n"
+		assert.Equal(t, expected, w.Body.String())
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		w := export(domain.NewTaskID().String(), "markdown")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		w := export(a.ID().String(), "xml")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// importCSV posts body to the import endpoint as CSV
+func importCSV(handler *TaskHandler, mode, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/import?format=csv&mode="+mode, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "text/csv")
+	handler.ImportTasks(c)
+	return w
+}
+
+func TestTaskHandler_ExportSubtree_TooLarge(t *testing.T) {
+	// Setup with room for subtrees of two tasks
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{MaxSubtreeOperation: 2})
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a (-> a1, a2), so a's subtree holds 3 tasks
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+	_, _ = service.CreateChildTask("A2", a.ID())
+
+	export := func(id string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/export?format=jsonl", nil)
+		handler.ExportSubtree(c)
+		return w
+	}
+
+	w := export(a.ID().String())
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "SUBTREE_TOO_LARGE")
+
+	w = export(a1.ID().String())
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTaskHandler_ImportTasks_CSV(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// IDs are omitted, so rows refer to their parents by key
+	body := "key,description,status,parentId,position\n" +
+		"root,Root,,,\n" +
+		"a,A,DONE,root,0\n" +
+		"b,\"B, with a comma\",In Progress,root,1\n" +
+		"b1,B1,,b,\n"
+
+	// Execute
+	w := importCSV(handler, "replace", body)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(4), response["imported"])
+	assert.Equal(t, float64(4), response["total"])
+
+	root, err := repo.FindRoot()
+	require.NoError(t, err)
+	assert.Equal(t, "Root", root.Description())
+	rootID := root.ID()
+	children, _ := repo.FindByParentID(&rootID)
+	require.Len(t, children, 2)
+	assert.Equal(t, "A", children[0].Description())
+	assert.Equal(t, domain.StatusDONE, children[0].Status())
+	assert.Equal(t, "B, with a comma", children[1].Description())
+	assert.Equal(t, domain.StatusInProgress, children[1].Status())
+	bID := children[1].ID()
+	grandchildren, _ := repo.FindByParentID(&bID)
+	require.Len(t, grandchildren, 1)
+	assert.Equal(t, "B1", grandchildren[0].Description())
+	assert.Equal(t, domain.StatusTODO, grandchildren[0].Status())
+}
+
+func TestTaskHandler_ImportTasks_CSVForwardReference(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	rootID := domain.NewTaskID()
+	parentID := domain.NewTaskID()
+
+	// The child row comes before the row of its parent
+	body := "id,description,parentId,position\n" +
+		rootID.String() + ",Root,,0\n" +
+		domain.NewTaskID().String() + ",Child," + parentID.String() + ",0\n" +
+		parentID.String() + ",Parent," + rootID.String() + ",0\n"
+
+	// Execute
+	w := importCSV(handler, "replace", body)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	children, _ := repo.FindByParentID(&parentID)
+	require.Len(t, children, 1)
+	assert.Equal(t, "Child", children[0].Description())
+}
+
+func TestTaskHandler_ImportTasks_CSVMergeUnderStoredParent(t *testing.T) {
+	// Setup: the import runs in a transaction on the file repository
+	repo, err := infrastructure.NewFileTaskRepository(t.TempDir() + "/tasks.json")
+	require.NoError(t, err)
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+
+	// A row without an id gets a new one and may name a stored task as its parent
+	body := "description,parentId\n" +
+		"Child," + root.ID().String() + "\n"
+
+	// Execute
+	w := importCSV(handler, "merge", body)
+
+	// Assert
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	rootID := root.ID()
+	children, _ := repo.FindByParentID(&rootID)
+	require.Len(t, children, 1)
+	assert.Equal(t, "Child", children[0].Description())
+	assert.NotEqual(t, rootID, children[0].ID())
+}
+
+func TestTaskHandler_ImportTasks_CSVMissingParent(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	body := "key,description,parentId\n" +
+		"root,Root,\n" +
+		"a,A,root\n" +
+		"b,B,nowhere\n"
+
+	// Execute
+	w := importCSV(handler, "replace", body)
+
+	// Assert: the header is row 1, so the third task is row 4
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "row 4")
+	assert.Contains(t, w.Body.String(), "nowhere")
+
+	// Nothing was stored
+	all, _ := repo.FindAll()
+	assert.Empty(t, all)
+}
+
+func TestTaskHandler_GroupTasks_Success(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a, b, c
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+
+	requestBody := map[string]interface{}{
+		"childIds":    []string{a.ID().String(), b.ID().String(), c.ID().String()},
+		"description": "Group",
+		"parentId":    root.ID().String(),
+		"position":    0,
+	}
+	jsonBody, _ := json.Marshal(requestBody)
+	ctx.Request = httptest.NewRequest("POST", "/api/v1/tasks/group", bytes.NewBuffer(jsonBody))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.GroupTasks(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Group", response["description"])
+	assert.Equal(t, root.ID().String(), response["parentId"])
+	assert.Equal(t, float64(0), response["position"])
+
+	// All three tasks moved under the group in order
+	groupID, err := domain.TaskIDFromString(response["id"].(string))
+	require.NoError(t, err)
+	children, _ := repo.FindByParentID(&groupID)
+	require.Len(t, children, 3)
+	assert.Equal(t, "A", children[0].Description())
+	assert.Equal(t, "B", children[1].Description())
+	assert.Equal(t, "C", children[2].Description())
+}
+
+func TestTaskHandler_UngroupTask_Success(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a (-> a1, a2), b
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	_, _ = service.CreateChildTask("B", root.ID())
+	_, _ = service.CreateChildTask("A1", a.ID())
+	_, _ = service.CreateChildTask("A2", a.ID())
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: a.ID().String()}}
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/"+a.ID().String()+"/ungroup", nil)
+
+	// Execute
+	handler.UngroupTask(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, "A1", response[0]["description"])
+	assert.Equal(t, float64(0), response[0]["position"])
+	assert.Equal(t, "A2", response[1]["description"])
+	assert.Equal(t, float64(1), response[1]["position"])
+
+	rootID := root.ID()
+	children, _ := repo.FindByParentID(&rootID)
+	require.Len(t, children, 3)
+	assert.Equal(t, "B", children[2].Description())
+}
+
+func TestTaskHandler_GetTaskHistory_Success(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a, b and move a under b
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	bID := b.ID()
+	require.NoError(t, service.MoveTask(a.ID(), &bID, 0))
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: a.ID().String()}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+a.ID().String()+"/history", nil)
+
+	// Execute
+	handler.GetTaskHistory(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, root.ID().String(), response[0]["fromParentId"])
+	assert.Equal(t, bID.String(), response[0]["toParentId"])
+	assert.Equal(t, float64(0), response[0]["fromPosition"])
+	assert.Equal(t, float64(0), response[0]["toPosition"])
+	assert.NotEmpty(t, response[0]["at"])
+}
+
+func TestTaskHandler_GetTaskHistory_NotFound(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	missingID := domain.NewTaskID().String()
+	c.Params = gin.Params{{Key: "id", Value: missingID}}
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+missingID+"/history", nil)
+
+	// Execute
+	handler.GetTaskHistory(c)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTaskHandler_GetTaskTimeline(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	for _, status := range []domain.Status{domain.StatusInProgress, domain.StatusDONE, domain.StatusTODO} {
+		require.NoError(t, service.ChangeTaskStatus(a.ID(), status))
+	}
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	request := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/timeline", nil)
+		handler.GetTaskTimeline(c)
+		return w
+	}
+
+	// Execute
+	w := request(a.ID().String())
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.StatusRecordResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 3)
+	assert.Equal(t, "TODO", response[0].From)
+	assert.Equal(t, "In Progress", response[0].To)
+	assert.Equal(t, "In Progress", response[1].From)
+	assert.Equal(t, "DONE", response[1].To)
+	assert.Equal(t, "DONE", response[2].From)
+	assert.Equal(t, "TODO", response[2].To)
+	for _, record := range response {
+		assert.False(t, record.At.IsZero())
+	}
+
+	// A task without changes has an empty timeline
+	w = request(root.ID().String())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]", strings.TrimSpace(w.Body.String()))
+
+	w = request(domain.NewTaskID().String())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// intPtr returns a pointer to the given int
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestTaskHandler_GetTaskGraph(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a (-> a1), b
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/graph", nil)
+
+	// Execute
+	handler.GetTaskGraph(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]string      `json:"edges"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Nodes, 4)
+
+	// Every non-root node has exactly one edge to its parent
+	roots := 0
+	for _, node := range response.Nodes {
+		if node["parentId"] == nil {
+			roots++
+		}
+	}
+	assert.Equal(t, len(response.Nodes)-roots, len(response.Edges))
+
+	// The root comes first and siblings keep their position order
+	assert.Equal(t, root.ID().String(), response.Nodes[0]["id"])
+	edges := make(map[string]string)
+	for _, edge := range response.Edges {
+		edges[edge["child"]] = edge["parent"]
+	}
+	assert.Equal(t, root.ID().String(), edges[a.ID().String()])
+	assert.Equal(t, root.ID().String(), edges[b.ID().String()])
+	assert.Equal(t, a.ID().String(), edges[a1.ID().String()])
+
+	// Repeated requests produce the same order
+	w2 := httptest.NewRecorder()
+	c2, _ := gin.CreateTestContext(w2)
+	c2.Request = httptest.NewRequest("GET", "/api/v1/tasks/graph", nil)
+	handler.GetTaskGraph(c2)
+	assert.Equal(t, w.Body.String(), w2.Body.String())
+}
+
+func TestTaskHandler_GetTaskCount(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	count := func(query string) (int, map[string]interface{}) {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/count"+query, nil)
+		handler.GetTaskCount(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	// An empty store counts as zero
+	code, response := count("?byStatus=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(0), response["total"])
+	assert.Equal(t, float64(0), response["byStatus"].(map[string]interface{})["TODO"])
+
+	// Create tree: root -> a (DONE), b (In Progress), c (TODO)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	_, _ = service.CreateChildTask("C", root.ID())
+	require.NoError(t, service.ChangeTaskStatus(a.ID(), domain.StatusDONE))
+	require.NoError(t, service.ChangeTaskStatus(b.ID(), domain.StatusInProgress))
+
+	code, response = count("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(4), response["total"])
+	assert.NotContains(t, response, "byStatus")
+
+	code, response = count("?byStatus=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(4), response["total"])
+	assert.Equal(t, map[string]interface{}{
+		"TODO":           float64(1),
+		"In Progress":    float64(1),
+		"DONE":           float64(1),
+		"Blocked":        float64(0),
+		"Root Work Item": float64(1),
+	}, response["byStatus"])
+
+	code, _ = count("?byStatus=maybe")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestTaskHandler_GetPlan(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandlerWithConfig(service, repo, TaskHandlerConfig{PositionBase: 1})
+
+	// Create tree: root -> a (-> a1 DONE, a2), b
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+	a2, _ := service.CreateChildTask("A2", a.ID())
+	require.NoError(t, service.ChangeTaskStatus(a1.ID(), domain.StatusDONE))
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/plan", nil)
+
+	// Execute
+	handler.GetPlan(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []struct {
+		Task map[string]interface{}   `json:"task"`
+		Path []map[string]interface{} `json:"path"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+
+	assert.Equal(t, a2.ID().String(), response[0].Task["id"])
+	assert.Equal(t, float64(2), response[0].Task["position"])
+	require.Len(t, response[0].Path, 2)
+	assert.Equal(t, root.ID().String(), response[0].Path[0]["id"])
+	assert.Equal(t, a.ID().String(), response[0].Path[1]["id"])
+
+	assert.Equal(t, b.ID().String(), response[1].Task["id"])
+	require.Len(t, response[1].Path, 1)
+	assert.Equal(t, root.ID().String(), response[1].Path[0]["id"])
+}
+
+func TestTaskHandler_StartNextTasks(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> a (-> a1, a2), b; only a1 is ready
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	a, _ := service.CreateChildTask("A", root.ID())
+	service.CreateChildTask("B", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+	service.CreateChildTask("A2", a.ID())
+
+	post := func(query string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/v1/tasks/start-next"+query, nil)
+		handler.StartNextTasks(c)
+		return w
+	}
+
+	// Fewer tasks than requested are ready, so only those are started
+	w := post("?count=3")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, a1.ID().String(), response[0]["id"])
+	assert.Equal(t, "In Progress", response[0]["status"])
+
+	// Nothing else is ready until A1 is done
+	w = post("")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	for _, query := range []string{"?count=0", "?count=-1", "?count=many"} {
+		w = post(query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestTaskHandler_GetRootTask_AutoCreateRoot(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandlerWithConfig(service, repo, TaskHandlerConfig{AutoCreateRootDescription: "My discovery tree"})
+	gin.SetMode(gin.TestMode)
+
+	getRoot := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks/root", nil)
+		handler.GetRootTask(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// The first access creates the root
+	first := getRoot()
+	assert.Equal(t, "My discovery tree", first["description"])
+	assert.Equal(t, "Root Work Item", first["status"])
+
+	// The second access returns the same root without creating another
+	second := getRoot()
+	assert.Equal(t, first["id"], second["id"])
+
+	count, err := repo.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestTaskHandler_GetRootTask_NotFoundByDefault(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/tasks/root", nil)
+
+	// Execute
+	handler.GetRootTask(c)
+
+	// Assert nothing was created
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	count, err := repo.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestTaskHandler_ToggleTaskStatus(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> parent -> leaf
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	leaf, _ := service.CreateChildTask("Leaf", parent.ID())
+	gin.SetMode(gin.TestMode)
+
+	toggle := func(taskID domain.TaskID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: taskID.String()}}
+		c.Request = httptest.NewRequest("POST", "/api/v1/tasks/"+taskID.String()+"/toggle", nil)
+		handler.ToggleTaskStatus(c)
+		return w
+	}
+
+	// The parent cannot be completed while the leaf is TODO
+	w := toggle(parent.ID())
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// The leaf toggles to DONE and back
+	for _, expected := range []string{"DONE", "TODO"} {
+		w = toggle(leaf.ID())
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, expected, response["status"])
+	}
+}
+
+func TestTaskHandler_UpdateTaskStatus_ListsIncompleteChildren(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create tree: root -> parent -> a (TODO), b (In Progress)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	a, _ := service.CreateChildTask("A", parent.ID())
+	b, _ := service.CreateChildTask("B", parent.ID())
+	require.NoError(t, service.ChangeTaskStatus(b.ID(), domain.StatusInProgress))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: parent.ID().String()}}
+	c.Request = httptest.NewRequest("PUT", "/api/v1/tasks/"+parent.ID().String()+"/status", strings.NewReader(`{"status": "DONE"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.UpdateTaskStatus(c)
+
+	// Assert: the 409 names both incomplete children
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response struct {
+		Code    string                   `json:"code"`
+		Details []map[string]interface{} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "bottom-to-top-completion", response.Code)
+	require.Len(t, response.Details, 2)
+	assert.Equal(t, a.ID().String(), response.Details[0]["taskId"])
+	assert.Equal(t, "A", response.Details[0]["description"])
+	assert.Equal(t, "TODO", response.Details[0]["status"])
+	assert.Equal(t, b.ID().String(), response.Details[1]["taskId"])
+	assert.Equal(t, "B", response.Details[1]["description"])
+	assert.Equal(t, "In Progress", response.Details[1]["status"])
+}
+
+func TestTaskHandler_TagSubtree(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// Create a 5-node subtree under the root: parent -> (a -> a1, b -> b1)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	a, _ := service.CreateChildTask("A", parent.ID())
+	b, _ := service.CreateChildTask("B", parent.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+	b1, _ := service.CreateChildTask("B1", b.ID())
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: parent.ID().String()}}
+	body := `{"add":["backend"]}`
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/"+parent.ID().String()+"/tags/subtree", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.TagSubtree(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(5), response["affected"])
+
+	for _, id := range []domain.TaskID{parent.ID(), a.ID(), b.ID(), a1.ID(), b1.ID()} {
+		task, err := repo.FindByID(id)
+		require.NoError(t, err)
+		assert.True(t, task.HasTag("backend"), "task %s should be tagged", task.Description())
+	}
+	stored, _ := repo.FindByID(root.ID())
+	assert.False(t, stored.HasTag("backend"))
+}
+
+func TestTaskHandler_TagSubtree_InvalidTag(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: root.ID().String()}}
+	body := `{"add":["two words"]}`
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/"+root.ID().String()+"/tags/subtree", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.TagSubtree(c)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "invalid-tag-characters", response["code"])
+	assert.Equal(t, "tags", response["field"])
+}
+
+func TestTaskHandler_ResetSubtree(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandler(service, repo)
+
+	// root -> parent -> (a, b), with everything under the root DONE
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	a, _ := service.CreateChildTask("A", parent.ID())
+	b, _ := service.CreateChildTask("B", parent.ID())
+	for _, task := range []*domain.Task{a, b, parent} {
+		require.NoError(t, service.ChangeTaskStatus(task.ID(), domain.StatusDONE))
+	}
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: a.ID().String()}}
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/"+a.ID().String()+"/reset", nil)
+
+	// Execute
+	handler.ResetSubtree(c)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.ResetSubtreeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Reset)
+	require.Len(t, response.Reopened, 1)
+	assert.Equal(t, parent.ID().String(), response.Reopened[0].ID)
+	assert.Equal(t, "In Progress", response.Reopened[0].Status)
+
+	stored, _ := repo.FindByID(a.ID())
+	assert.Equal(t, domain.StatusTODO, stored.Status())
+	assert.Nil(t, stored.CompletedAt())
+	stored, _ = repo.FindByID(b.ID())
+	assert.Equal(t, domain.StatusDONE, stored.Status())
+}
+
+func TestTaskHandler_TaskKinds(t *testing.T) {
+	// Setup
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{Kinds: []string{"task", "spike", "milestone"}})
+	handler := NewTaskHandler(service, repo)
+
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	spike, _ := service.CreateChildTask("Try the new parser", root.ID())
+	_, _ = service.CreateChildTask("Write docs", root.ID())
+	gin.SetMode(gin.TestMode)
+
+	setKind := func(id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Request = httptest.NewRequest("PUT", "/api/v1/tasks/"+id+"/kind", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.SetTaskKind(c)
+		return w
+	}
+	listTasks := func(query string) (*httptest.ResponseRecorder, []models.TaskResponse) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/v1/tasks"+query, nil)
+		handler.GetAllTasks(c)
+		var responses []models.TaskResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &responses))
+		}
+		return w, responses
+	}
+
+	// Changing the kind returns the task with its new kind
+	w := setKind(spike.ID().String(), `{"kind":"spike"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "spike", response.Kind)
+
+	// Unknown kinds are rejected
+	w = setKind(spike.ID().String(), `{"kind":"bug"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = setKind(spike.ID().String(), `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = setKind(domain.NewTaskID().String(), `{"kind":"spike"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Every task reports its kind, and ?kind= filters the list
+	w, all := listTasks("")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, all, 3)
+	for _, task := range all {
+		if task.ID == spike.ID().String() {
+			assert.Equal(t, "spike", task.Kind)
+		} else {
+			assert.Equal(t, "task", task.Kind)
+		}
+	}
+
+	w, spikes := listTasks("?kind=spike")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, spikes, 1)
+	assert.Equal(t, spike.ID().String(), spikes[0].ID)
+
+	w, milestones := listTasks("?kind=milestone")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, milestones)
+	assert.Equal(t, "[]", strings.TrimSpace(w.Body.String()))
+
+	w, _ = listTasks("?kind=two%20words")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTaskHandler_CreateChildTask_ShortIDParent(t *testing.T) {
+	// Setup with short IDs
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{IDGenerator: domain.ShortIDGenerator{}})
+	handler := NewTaskHandler(service, repo)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	require.True(t, domain.IsShortID(root.ID().String()))
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	jsonBody, _ := json.Marshal(map[string]interface{}{
+		"description": "Child",
+		"parentId":    root.ID().String(),
+	})
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks", bytes.NewBuffer(jsonBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.CreateChildTask(c)
+
+	// Assert the short parent ID was accepted and the child got a short ID
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, root.ID().String(), response["parentId"])
+	assert.True(t, domain.IsShortID(response["id"].(string)))
+}
+
+func TestTaskHandler_CreateChildTask_Location(t *testing.T) {
+	// Setup with a custom base path, given with a trailing slash
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTaskHandlerWithConfig(service, repo, TaskHandlerConfig{BasePath: "/discovery/api/v1/"})
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+
+	// Create Gin context
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	jsonBody, _ := json.Marshal(map[string]interface{}{
+		"description": "Child",
+		"parentId":    root.ID().String(),
+	})
+	c.Request = httptest.NewRequest("POST", "/discovery/api/v1/tasks", bytes.NewBuffer(jsonBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	// Execute
+	handler.CreateChildTask(c)
+
+	// Assert Location points at the created task under the base path
+	require.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "/discovery/api/v1/tasks/"+response["id"].(string), w.Header().Get("Location"))
+
+	// Failed creates carry no Location
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/discovery/api/v1/tasks", bytes.NewBufferString(`{"description":"Orphan","parentId":"`+domain.NewTaskID().String()+`"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.CreateChildTask(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+}
+
+func TestTaskHandler_MoveTask_Reconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"RejectedWithoutReconcile", "", http.StatusConflict},
+		{"ReopensWithReconcile", "?reconcile=true", http.StatusOK},
+		{"InvalidReconcile", "?reconcile=maybe", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskService(repo)
+			handler := NewTaskHandler(service, repo)
+
+			// Create tree: root -> (done -> doneChild), todo
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+			done, _ := service.CreateChildTask("Done", root.ID())
+			doneChild, _ := service.CreateChildTask("Done child", done.ID())
+			todo, _ := service.CreateChildTask("Todo", root.ID())
+			require.NoError(t, service.ChangeTaskStatus(doneChild.ID(), domain.StatusDONE))
+			require.NoError(t, service.ChangeTaskStatus(done.ID(), domain.StatusDONE))
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: todo.ID().String()}}
+
+			// Move the incomplete task under the DONE task
+			jsonBody, _ := json.Marshal(map[string]interface{}{
+				"parentId": done.ID().String(),
+				"position": 0,
+			})
+			c.Request = httptest.NewRequest("PUT", "/api/v1/tasks/"+todo.ID().String()+"/move"+tt.query, bytes.NewBuffer(jsonBody))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			// Execute
+			handler.MoveTask(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			stored, err := repo.FindByID(done.ID())
+			require.NoError(t, err)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Equal(t, domain.StatusDONE, stored.Status())
+				return
+			}
+
+			assert.Equal(t, domain.StatusInProgress, stored.Status())
+			task := response["task"].(map[string]interface{})
+			assert.Equal(t, done.ID().String(), task["parentId"])
+			reopened := response["reopened"].([]interface{})
+			require.Len(t, reopened, 1)
+			assert.Equal(t, done.ID().String(), reopened[0].(map[string]interface{})["id"])
+			assert.Equal(t, "In Progress", reopened[0].(map[string]interface{})["status"])
+		})
+	}
+}
+
+func TestTaskHandler_DeleteTask_Strategy(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"DefaultCascade", "", http.StatusNoContent, 2},
+		{"Reparent", "?strategy=reparent", http.StatusNoContent, 4},
+		{"InvalidStrategy", "?strategy=orphan", http.StatusBadRequest, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskService(repo)
+			handler := NewTaskHandler(service, repo)
+
+			// Create tree: root -> a (-> a1, a2), b
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+			a, _ := service.CreateChildTask("A", root.ID())
+			_, _ = service.CreateChildTask("B", root.ID())
+			_, _ = service.CreateChildTask("A1", a.ID())
+			_, _ = service.CreateChildTask("A2", a.ID())
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: a.ID().String()}}
+			c.Request = httptest.NewRequest("DELETE", "/api/v1/tasks/"+a.ID().String()+tt.query, nil)
+
+			// Execute
+			handler.DeleteTask(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, c.Writer.Status())
+			count, err := repo.Count()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, count)
+		})
+	}
+}
+
+func TestTaskHandler_DeleteTask_Guard(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{"BlocksInProgressBranch", "", http.StatusConflict, 4},
+		{"BlocksReparent", "?strategy=reparent", http.StatusConflict, 4},
+		{"ForcedDeleteSucceeds", "?force=true", http.StatusNoContent, 2},
+		{"InvalidForce", "?force=maybe", http.StatusBadRequest, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			repo := domain.NewInMemoryTaskRepository()
+			service := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{DeleteGuard: domain.DeleteGuardBlock})
+			handler := NewTaskHandler(service, repo)
+
+			// Create tree: root -> a (In Progress, -> a1), b
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+			a, _ := service.CreateChildTask("A", root.ID())
+			_, _ = service.CreateChildTask("B", root.ID())
+			_, _ = service.CreateChildTask("A1", a.ID())
+			require.NoError(t, service.ChangeTaskStatus(a.ID(), domain.StatusInProgress))
+
+			// Create Gin context
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: a.ID().String()}}
+			c.Request = httptest.NewRequest("DELETE", "/api/v1/tasks/"+a.ID().String()+tt.query, nil)
+
+			// Execute
+			handler.DeleteTask(c)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, c.Writer.Status())
+			count, err := repo.Count()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCount, count)
+
+			if tt.expectedStatus == http.StatusConflict {
+				var response models.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "incomplete-delete", response.Code)
+				require.NotEmpty(t, response.Details)
+				assert.Equal(t, a.ID().String(), response.Details[0].TaskID)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetTaskActivity(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	now := domain.NormalizeTimestamp(time.Now())
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	// Tree: root -> A -> A1, A2; the root is older and newer than the whole subtree of A
+	rootID, aID := domain.NewTaskID(), domain.NewTaskID()
+	root := domain.ReconstructTask(domain.TaskFields{ID: rootID, Description: "Root", Status: domain.StatusRootWorkItem, CreatedAt: daysAgo(60), UpdatedAt: now})
+	a := domain.ReconstructTask(domain.TaskFields{ID: aID, Description: "A", Status: domain.StatusInProgress, ParentID: &rootID, CreatedAt: daysAgo(30), UpdatedAt: daysAgo(20)})
+	doneAt := daysAgo(2)
+	a1 := domain.ReconstructTask(domain.TaskFields{ID: domain.NewTaskID(), Description: "A1", Status: domain.StatusDONE, ParentID: &aID, CreatedAt: daysAgo(10), UpdatedAt: daysAgo(2), CompletedAt: &doneAt})
+	a2 := domain.ReconstructTask(domain.TaskFields{ID: domain.NewTaskID(), Description: "A2", Status: domain.StatusTODO, ParentID: &aID, Position: 1, CreatedAt: daysAgo(5), UpdatedAt: daysAgo(1)})
+	require.NoError(t, repo.SaveAll([]*domain.Task{root, a, a1, a2}))
+	handler := NewTaskHandler(domain.NewTaskService(repo), repo)
+
+	getActivity := func(id, query string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Params = gin.Params{{Key: "id", Value: id}}
+		ctx.Request = httptest.NewRequest("GET", "/api/v1/tasks/"+id+"/activity"+query, nil)
+		handler.GetTaskActivity(ctx)
+		return w
+	}
+
+	w := getActivity(aID.String(), "?days=7")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.TaskActivityResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, aID.String(), response.TaskID)
+	assert.Equal(t, 3, response.Total)
+	assert.True(t, daysAgo(30).Equal(response.EarliestCreatedAt), "earliest createdAt %v", response.EarliestCreatedAt)
+	assert.True(t, daysAgo(1).Equal(response.LatestUpdatedAt), "latest updatedAt %v", response.LatestUpdatedAt)
+	assert.Equal(t, 7, response.Days)
+	assert.Equal(t, 2, response.ChangedRecently)
+	assert.Equal(t, map[string]int{
+		"TODO": 1, "In Progress": 1, "DONE": 1, "Blocked": 0, "Root Work Item": 0,
+	}, response.ByStatus)
+
+	// A wider window includes A itself
+	w = getActivity(aID.String(), "?days=21")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.ChangedRecently)
+
+	assert.Equal(t, http.StatusBadRequest, getActivity(aID.String(), "?days=0").Code)
+	assert.Equal(t, http.StatusNotFound, getActivity(domain.NewTaskID().String(), "").Code)
+}
+
+func TestTaskHandler_CreateChildTask_DescriptionTemplate(t *testing.T) {
+	createChild := func(template string) *httptest.ResponseRecorder {
+		repo := domain.NewInMemoryTaskRepository()
+		service := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{ChildDescriptionTemplate: template})
+		handler := NewTaskHandler(service, repo)
+		root, err := service.CreateRootTask("Login page")
+		require.NoError(t, err)
+
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBody, _ := json.Marshal(map[string]interface{}{
+			"description": "",
+			"parentId":    root.ID().String(),
+		})
+		c.Request = httptest.NewRequest("POST", "/api/v1/tasks", bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateChildTask(c)
+		return w
+	}
+
+	// With a template, the blank child is named after its parent
+	w := createChild("Investigate {parent}")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Investigate Login page", response["description"])
+
+	// Without one, a blank description is still rejected
+	w = createChild("")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ValidationError", response["error"])
+}
+
+func TestTaskHandler_ArchiveOnComplete(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{
+		Archive:           domain.NewInMemoryTaskArchive(),
+		ArchiveOnComplete: true,
+	})
+	handler := NewTaskHandler(service, repo)
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	branch, err := service.CreateChildTask("Release 1", root.ID())
+	require.NoError(t, err)
+	leaf, err := service.CreateChildTask("Ship it", branch.ID())
+	require.NoError(t, err)
+	require.NoError(t, service.ChangeTaskStatus(leaf.ID(), domain.StatusDONE))
+
+	gin.SetMode(gin.TestMode)
+	request := func(method, url string, params gin.Params, body interface{}, handle func(*gin.Context)) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = params
+		jsonBody, _ := json.Marshal(body)
+		c.Request = httptest.NewRequest(method, url, bytes.NewBuffer(jsonBody))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handle(c)
+		return w
+	}
+
+	// Completing the branch archives it, and the response still shows the task
+	id := branch.ID().String()
+	w := request("PUT", "/api/v1/tasks/"+id+"/status", gin.Params{{Key: "id", Value: id}},
+		map[string]string{"status": "DONE"}, handler.UpdateTaskStatus)
+	require.Equal(t, http.StatusOK, w.Code)
+	var task models.TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+	assert.Equal(t, id, task.ID)
+	assert.Equal(t, "DONE", task.Status)
+
+	all, err := repo.FindAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 1, "only the root should stay active")
+
+	w = request("GET", "/api/v1/tasks/archive", nil, nil, handler.GetArchive)
+	require.Equal(t, http.StatusOK, w.Code)
+	var archive []models.ArchivedTreeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &archive))
+	require.Len(t, archive, 1)
+	assert.Equal(t, id, archive[0].RootID)
+	assert.Equal(t, root.ID().String(), archive[0].ParentID)
+	require.Len(t, archive[0].Tasks, 2)
+	assert.True(t, archive[0].Tasks[0].HasChildren)
+	assert.Equal(t, "Ship it", archive[0].Tasks[1].Description)
+
+	// Restoring puts the branch back and empties the archive
+	w = request("POST", "/api/v1/tasks/archive/"+id+"/restore", gin.Params{{Key: "id", Value: id}}, nil, handler.RestoreArchivedTree)
+	require.Equal(t, http.StatusOK, w.Code)
+	var restored []models.TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	require.Len(t, restored, 2)
+	assert.Equal(t, id, restored[0].ID)
+	all, err = repo.FindAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	w = request("GET", "/api/v1/tasks/archive", nil, nil, handler.GetArchive)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &archive))
+	assert.Empty(t, archive)
+
+	// Restoring again finds nothing to restore
+	w = request("POST", "/api/v1/tasks/archive/"+id+"/restore", gin.Params{{Key: "id", Value: id}}, nil, handler.RestoreArchivedTree)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
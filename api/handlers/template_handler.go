@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"discovery-tree/api/middleware"
+	"discovery-tree/api/models"
+	"discovery-tree/domain"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler handles HTTP requests for listing and instantiating task templates
+type TemplateHandler struct {
+	taskService *domain.TaskService
+	templates   domain.TemplateRepository
+	config      TaskHandlerConfig
+}
+
+// NewTemplateHandler creates a new TemplateHandler with injected dependencies
+// The task handler configuration applies to the positions of requests and created tasks
+func NewTemplateHandler(taskService *domain.TaskService, templates domain.TemplateRepository, config TaskHandlerConfig) *TemplateHandler {
+	return &TemplateHandler{
+		taskService: taskService,
+		templates:   templates,
+		config:      config,
+	}
+}
+
+// isRootOnly reports whether a template's top node is a Root Work Item, so it can only become the root
+func isRootOnly(template domain.TaskTemplate) bool {
+	return template.Root.Status != nil && *template.Root.Status == domain.StatusRootWorkItem
+}
+
+// ListTemplates retrieves the available task templates
+// @Summary List task templates
+// @Description Lists the named templates found in TEMPLATE_DIR, ordered by name. Each is a nested tree JSON file that POST /tasks/from-template instantiates. A file that cannot be loaded is listed with an error instead of failing the list.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.TemplateResponse "Successfully retrieved templates"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templates.FindAll()
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	response := make([]models.TemplateResponse, len(templates))
+	for i, template := range templates {
+		// A broken template file is reported on its own entry so the others stay usable
+		if template.Err != nil {
+			slog.Warn("Failed to load task template", slog.String("template", template.Name), slog.String("error", template.Err.Error()))
+			response[i] = models.TemplateResponse{Name: template.Name, Error: template.Err.Error()}
+			continue
+		}
+		response[i] = models.TemplateResponse{
+			Name:      template.Name,
+			TaskCount: template.Root.Count(),
+			RootOnly:  isRootOnly(template),
+		}
+	}
+	middleware.Respond(c, http.StatusOK, response)
+}
+
+// InstantiateTemplate creates a subtree from a named template
+// @Summary Create tasks from a template
+// @Description Instantiates the named template as a subtree with fresh IDs under parentId, at position among its children (appended when omitted). Without parentId the template becomes the root task, which fails if a root already exists. A template whose top node is a Root Work Item can only be instantiated as the root. Returns the created tasks, top task first.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param request body models.InstantiateTemplateRequest true "Template instantiation request"
+// @Success 201 {array} models.TaskResponse "Successfully created tasks"
+// @Failure 400 {object} models.ErrorResponse "Invalid request data or template"
+// @Failure 404 {object} models.ErrorResponse "Template or parent task not found"
+// @Failure 409 {object} models.ErrorResponse "Root task already exists or parent is DONE"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /api/v1/tasks/from-template [post]
+func (h *TemplateHandler) InstantiateTemplate(c *gin.Context) {
+	var req models.InstantiateTemplateRequest
+
+	// Bind and validate the request
+	if err := middleware.BindJSON(c, &req); err != nil {
+		return
+	}
+
+	template, err := h.templates.FindByName(req.TemplateName)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	var tasks []*domain.Task
+	if req.ParentID == nil {
+		// Without a parent the template becomes the root, which has no position to choose
+		if req.Position != nil {
+			middleware.HandleError(c, domain.NewValidationError("position", "position-without-parent", "position requires parentId"))
+			return
+		}
+		tasks, err = h.taskService.ImportTree(template.Root)
+	} else {
+		if isRootOnly(template) {
+			middleware.HandleError(c, domain.NewValidationError("parentId", "root-only-template", "template "+template.Name+" can only be instantiated as the root task"))
+			return
+		}
+
+		parentID, parseErr := domain.TaskIDFromString(*req.ParentID)
+		if parseErr != nil {
+			middleware.HandleError(c, parseErr)
+			return
+		}
+
+		var position *int
+		if req.Position != nil {
+			internal, posErr := h.config.toInternalPosition("position", int(*req.Position))
+			if posErr != nil {
+				middleware.HandleError(c, posErr)
+				return
+			}
+			position = &internal
+		}
+		tasks, err = h.taskService.ImportSubtree(template.Root, parentID, position)
+	}
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	// The created tasks are a whole new subtree, so their children are all in the list
+	response := models.TasksToResponsesWithChildCounts(tasks, h.config.PositionBase, models.ChildCounts(tasks))
+	middleware.Respond(c, http.StatusCreated, response)
+}
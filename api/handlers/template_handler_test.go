@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"discovery-tree/domain"
+	"discovery-tree/infrastructure"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTemplateHandler creates a handler over a template directory holding a two-level
+// "scaffold" template and a root-only "discovery" template
+func setupTemplateHandler(t *testing.T) (*TemplateHandler, *domain.InMemoryTaskRepository, *domain.TaskService) {
+	dir := t.TempDir()
+	scaffold := `{"description": "Scaffold", "children": [{"description": "Research", "status": "DONE"}, {"description": "Build"}]}`
+	discovery := `{"description": "Discovery", "status": "Root Work Item"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scaffold.json"), []byte(scaffold), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "discovery.json"), []byte(discovery), 0644))
+
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	handler := NewTemplateHandler(service, infrastructure.NewDirTemplateRepository(dir), TaskHandlerConfig{})
+	return handler, repo, service
+}
+
+func TestTemplateHandler_ListTemplates(t *testing.T) {
+	handler, _, _ := setupTemplateHandler(t)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/templates", nil)
+
+	handler.ListTemplates(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, "discovery", response[0]["name"])
+	assert.Equal(t, true, response[0]["rootOnly"])
+	assert.Equal(t, "scaffold", response[1]["name"])
+	assert.Equal(t, float64(3), response[1]["taskCount"])
+	assert.Equal(t, false, response[1]["rootOnly"])
+}
+
+func TestTemplateHandler_ListTemplates_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scaffold.json"), []byte(`{"description": "Scaffold"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`not json`), 0644))
+
+	service := domain.NewTaskService(domain.NewInMemoryTaskRepository())
+	handler := NewTemplateHandler(service, infrastructure.NewDirTemplateRepository(dir), TaskHandlerConfig{})
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/templates", nil)
+
+	handler.ListTemplates(c)
+
+	// The broken file is reported on its own entry; the valid template is still listed
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, "broken", response[0]["name"])
+	assert.NotEmpty(t, response[0]["error"])
+	assert.Equal(t, "scaffold", response[1]["name"])
+	assert.Equal(t, float64(1), response[1]["taskCount"])
+	assert.NotContains(t, response[1], "error")
+}
+
+func TestTemplateHandler_InstantiateTemplate_UnderExistingParent(t *testing.T) {
+	handler, repo, service := setupTemplateHandler(t)
+
+	// Create tree: root -> parent -> existing
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	existing, _ := service.CreateChildTask("Existing", parent.ID())
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"templateName": "scaffold", "parentId": "` + parent.ID().String() + `", "position": 0}`
+	c.Request = httptest.NewRequest("POST", "/api/v1/tasks/from-template", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.InstantiateTemplate(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 3)
+	assert.Equal(t, "Scaffold", response[0]["description"])
+	assert.Equal(t, parent.ID().String(), response[0]["parentId"])
+	assert.Equal(t, float64(0), response[0]["position"])
+	assert.Equal(t, "Research", response[1]["description"])
+	assert.Equal(t, "DONE", response[1]["status"])
+	assert.Equal(t, response[0]["id"], response[1]["parentId"])
+	assert.Equal(t, "Build", response[2]["description"])
+
+	// The existing child made room for the template
+	stored, _ := repo.FindByID(existing.ID())
+	assert.Equal(t, 1, stored.Position())
+
+	all, _ := repo.FindAll()
+	assert.Len(t, all, 6)
+	assert.Empty(t, domain.CheckTreeInvariants(all, domain.CompleteStatuses{}))
+}
+
+func TestTemplateHandler_InstantiateTemplate_Rejections(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           func(parentID string) string
+		expectedStatus int
+	}{
+		{"UnknownTemplate", func(parentID string) string {
+			return `{"templateName": "missing", "parentId": "` + parentID + `"}`
+		}, http.StatusNotFound},
+		{"RootOnlyUnderParent", func(parentID string) string {
+			return `{"templateName": "discovery", "parentId": "` + parentID + `"}`
+		}, http.StatusBadRequest},
+		{"RootOnlyWhenRootExists", func(string) string {
+			return `{"templateName": "discovery"}`
+		}, http.StatusConflict},
+		{"UnknownParent", func(string) string {
+			return `{"templateName": "scaffold", "parentId": "` + domain.NewTaskID().String() + `"}`
+		}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, repo, service := setupTemplateHandler(t)
+			root, err := service.CreateRootTask("Root")
+			require.NoError(t, err)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/tasks/from-template", strings.NewReader(tt.body(root.ID().String())))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.InstantiateTemplate(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			count, _ := repo.Count()
+			assert.Equal(t, 1, count)
+		})
+	}
+}
@@ -265,13 +265,16 @@ func TestErrorScenariosAcrossEndpoints(t *testing.T) {
 		resp = makeRequest(t, engine, "DELETE", "/api/v1/tasks/"+nonExistentID, nil)
 		assert.Equal(t, http.StatusNotFound, resp.Code)
 
-		// Create child with non-existent parent
+		// Create child before any root exists: the missing root is reported instead of the parent
 		childReq := map[string]interface{}{
 			"description": "Child task",
 			"parentId":    nonExistentID,
 		}
 		resp = makeRequest(t, engine, "POST", "/api/v1/tasks", childReq)
-		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		err = json.Unmarshal(resp.Body.Bytes(), &errorResp)
+		require.NoError(t, err)
+		assert.Equal(t, "no-root", errorResp["code"])
 	})
 
 	t.Run("Conflict Errors", func(t *testing.T) {
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"discovery-tree/api/models"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response envelope modes
+const (
+	// EnvelopeBare writes payloads and errors as bare JSON objects
+	EnvelopeBare = "bare"
+	// EnvelopeWrapped nests payloads under "data" and errors under "error", next to "meta"
+	EnvelopeWrapped = "wrapped"
+)
+
+// envelopeKey is the gin context key holding the response envelope mode
+const envelopeKey = "response_envelope"
+
+// ResponseEnvelope middleware selects the response shape written by Respond and the error helpers
+// Without it, responses are bare
+func ResponseEnvelope(mode string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, mode)
+		c.Next()
+	}
+}
+
+// isWrapped reports whether responses to this request use the wrapped envelope
+func isWrapped(c *gin.Context) bool {
+	return c.GetString(envelopeKey) == EnvelopeWrapped
+}
+
+// Respond writes a success payload as JSON, nested under data with meta in wrapped mode
+// List payloads report their length as meta.count
+func Respond(c *gin.Context, statusCode int, payload interface{}) {
+	if !isWrapped(c) {
+		c.JSON(statusCode, payload)
+		return
+	}
+
+	var meta models.EnvelopeMeta
+	if value := reflect.ValueOf(payload); value.Kind() == reflect.Slice {
+		count := value.Len()
+		meta.Count = &count
+	}
+	c.JSON(statusCode, models.EnvelopeResponse{Data: payload, Meta: meta})
+}
+
+// respondError writes an error response as JSON, nested under error in wrapped mode
+// The message is replaced by the one configured for its code, if any (see ErrorMessages)
+func respondError(c *gin.Context, statusCode int, errorResp models.ErrorResponse) {
+	errorResp.Message = userMessage(c, errorResp)
+	if !isWrapped(c) {
+		c.JSON(statusCode, errorResp)
+		return
+	}
+	c.JSON(statusCode, models.EnvelopeResponse{Error: &errorResp})
+}
@@ -4,55 +4,125 @@ import (
 	"discovery-tree/api/models"
 	"discovery-tree/domain"
 	"discovery-tree/infrastructure"
+	"errors"
 	"log/slog"
 	"net/http"
+	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
 )
 
-// ErrorHandler middleware handles errors consistently across all endpoints
+// errorMessagesKey is the gin context key holding the user-facing error messages by code
+const errorMessagesKey = "error_messages"
+
+// ErrorMessages middleware sets the user-facing messages of error responses, keyed by error code
+// Responses with other codes keep their default message; nil or empty keeps every default
+// Logs always carry the original error text
+func ErrorMessages(messages map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(errorMessagesKey, messages)
+		c.Next()
+	}
+}
+
+// userMessage returns the message configured for an error response's code, or its default message
+func userMessage(c *gin.Context, errorResp models.ErrorResponse) string {
+	value, _ := c.Get(errorMessagesKey)
+	if messages, ok := value.(map[string]string); ok {
+		if message, ok := messages[errorResp.Code]; ok {
+			return message
+		}
+	}
+	return errorResp.Message
+}
+
+// ErrorHandler middleware recovers panics into the standard ErrorResponse JSON shape
+// Panics carrying an error are mapped like handler errors; anything else becomes a 500.
+// The panic and its stack are logged via slog, and the response carries the request ID
 func ErrorHandler() gin.HandlerFunc {
-	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		if err, ok := recovered.(error); ok {
-			statusCode, errorResp := MapDomainError(err)
-			
-			// Log the error with structured logging
-			slog.Error("Request error recovered",
-				slog.String("method", c.Request.Method),
-				slog.String("path", c.Request.URL.Path),
-				slog.String("error", err.Error()),
-				slog.Int("status", statusCode),
-				slog.String("client_ip", c.ClientIP()),
-			)
-			
-			c.JSON(statusCode, errorResp)
-		} else {
-			// Handle non-error panics
-			slog.Error("Non-error panic recovered",
-				slog.String("method", c.Request.Method),
-				slog.String("path", c.Request.URL.Path),
-				slog.Any("recovered", recovered),
-				slog.String("client_ip", c.ClientIP()),
-			)
-			
-			errorResp := models.ErrorResponse{
-				Error:   "InternalServerError",
-				Code:    "PANIC_RECOVERED",
-				Message: "An unexpected error occurred",
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
 			}
-			c.JSON(http.StatusInternalServerError, errorResp)
-		}
-		c.Abort()
-	})
+
+			requestID := GetRequestID(c)
+			stack := string(debug.Stack())
+
+			var statusCode int
+			var errorResp models.ErrorResponse
+			if err, ok := recovered.(error); ok {
+				statusCode, errorResp = MapDomainError(err)
+				
+				// Log the error with structured logging
+				slog.Error("Request error recovered",
+					slog.String("method", c.Request.Method),
+					slog.String("path", c.Request.URL.Path),
+					slog.String("error", err.Error()),
+					slog.Int("status", statusCode),
+					slog.String("client_ip", c.ClientIP()),
+					slog.String("request_id", requestID),
+					slog.String("stack", stack),
+				)
+			} else {
+				// Handle non-error panics
+				slog.Error("Non-error panic recovered",
+					slog.String("method", c.Request.Method),
+					slog.String("path", c.Request.URL.Path),
+					slog.Any("recovered", recovered),
+					slog.String("client_ip", c.ClientIP()),
+					slog.String("request_id", requestID),
+					slog.String("stack", stack),
+				)
+				
+				statusCode = http.StatusInternalServerError
+				errorResp = models.ErrorResponse{
+					Error:   "InternalServerError",
+					Code:    "PANIC_RECOVERED",
+					Message: "An unexpected error occurred",
+				}
+			}
+
+			errorResp.RequestID = requestID
+			if c.Writer.Written() {
+				// Part of the response is already sent, so no error body can follow
+				c.Abort()
+				return
+			}
+			respondError(c, statusCode, errorResp)
+			c.Abort()
+		}()
+
+		c.Next()
+	}
 }
 
 // MapDomainError converts domain errors to HTTP status codes and error responses
 func MapDomainError(err error) (int, models.ErrorResponse) {
+	// Storage errors may be wrapped, so match them anywhere in the chain
+	var fsErr infrastructure.FileSystemError
+	if errors.As(err, &fsErr) {
+		if fsErr.IsTransient() {
+			return http.StatusServiceUnavailable, models.ErrorResponse{
+				Error:   "ServiceUnavailable",
+				Code:    "STORAGE_UNAVAILABLE",
+				Message: "Storage is temporarily unavailable, please retry",
+			}
+		}
+		return http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "InternalServerError",
+			Code:    "FILESYSTEM_ERROR",
+			Message: "A storage error occurred",
+		}
+	}
+
 	switch e := err.(type) {
 	case domain.ValidationError:
 		return http.StatusBadRequest, models.ErrorResponse{
 			Error:   "ValidationError",
-			Code:    e.Field,
+			Code:    e.Rule,
+			Field:   e.Field,
 			Message: e.Message,
 		}
 	case domain.NotFoundError:
@@ -66,12 +136,13 @@ func MapDomainError(err error) (int, models.ErrorResponse) {
 			Error:   "ConstraintViolationError",
 			Code:    e.Constraint,
 			Message: e.Message,
+			Details: constraintDetailsToResponse(e.Details()),
 		}
-	case infrastructure.FileSystemError:
-		return http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "InternalServerError",
-			Code:    "FILESYSTEM_ERROR",
-			Message: "A storage error occurred",
+	case domain.SubtreeTooLargeError:
+		return http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Error:   "SubtreeTooLargeError",
+			Code:    "SUBTREE_TOO_LARGE",
+			Message: e.Error(),
 		}
 	default:
 		return http.StatusInternalServerError, models.ErrorResponse{
@@ -95,5 +166,21 @@ func HandleError(c *gin.Context, err error) {
 		slog.String("client_ip", c.ClientIP()),
 	)
 	
-	c.JSON(statusCode, errorResp)
-}
\ No newline at end of file
+	respondError(c, statusCode, errorResp)
+}
+
+// constraintDetailsToResponse converts the tasks named by a constraint violation, nil if there are none
+func constraintDetailsToResponse(details []domain.ConstraintDetail) []models.ErrorDetailResponse {
+	if len(details) == 0 {
+		return nil
+	}
+	responses := make([]models.ErrorDetailResponse, len(details))
+	for i, detail := range details {
+		responses[i] = models.ErrorDetailResponse{
+			TaskID:      detail.TaskID.Canonical().String(),
+			Description: detail.Description,
+			Status:      detail.Status.String(),
+		}
+	}
+	return responses
+}
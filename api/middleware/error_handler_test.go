@@ -5,8 +5,10 @@ import (
 	"discovery-tree/domain"
 	"discovery-tree/infrastructure"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -23,10 +25,10 @@ func TestMapDomainError(t *testing.T) {
 	}{
 		{
 			name:           "ValidationError",
-			err:            domain.NewValidationError("description", "cannot be empty"),
+			err:            domain.NewValidationError("description", "empty-description", "cannot be empty"),
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "ValidationError",
-			expectedCode:   "description",
+			expectedCode:   "empty-description",
 		},
 		{
 			name:           "NotFoundError",
@@ -42,6 +44,13 @@ func TestMapDomainError(t *testing.T) {
 			expectedError:  "ConstraintViolationError",
 			expectedCode:   "UNIQUE_ROOT",
 		},
+		{
+			name:           "SubtreeTooLargeError",
+			err:            domain.NewSubtreeTooLargeError("task-1", 11, 10),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "SubtreeTooLargeError",
+			expectedCode:   "SUBTREE_TOO_LARGE",
+		},
 		{
 			name:           "FileSystemError",
 			err:            infrastructure.NewFileSystemError("read", "/path/to/file", assert.AnError),
@@ -49,6 +58,20 @@ func TestMapDomainError(t *testing.T) {
 			expectedError:  "InternalServerError",
 			expectedCode:   "FILESYSTEM_ERROR",
 		},
+		{
+			name:           "WrappedFileSystemError",
+			err:            fmt.Errorf("saving task: %w", infrastructure.NewFileSystemError("write", "/path/to/file", assert.AnError)),
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "InternalServerError",
+			expectedCode:   "FILESYSTEM_ERROR",
+		},
+		{
+			name:           "TransientFileSystemError",
+			err:            infrastructure.NewFileSystemError("write", "/path/to/file", os.ErrDeadlineExceeded),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedError:  "ServiceUnavailable",
+			expectedCode:   "STORAGE_UNAVAILABLE",
+		},
 		{
 			name:           "UnknownError",
 			err:            assert.AnError,
@@ -80,7 +103,7 @@ func TestHandleError(t *testing.T) {
 	req, _ := http.NewRequest("GET", "/test", nil)
 	c.Request = req
 	
-	err := domain.NewValidationError("description", "cannot be empty")
+	err := domain.NewValidationError("description", "empty-description", "cannot be empty")
 	HandleError(c, err)
 	
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -89,7 +112,8 @@ func TestHandleError(t *testing.T) {
 	jsonErr := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, jsonErr)
 	assert.Equal(t, "ValidationError", response.Error)
-	assert.Equal(t, "description", response.Code)
+	assert.Equal(t, "empty-description", response.Code)
+	assert.Equal(t, "description", response.Field)
 }
 
 func TestErrorHandler(t *testing.T) {
@@ -100,7 +124,7 @@ func TestErrorHandler(t *testing.T) {
 	
 	// Test panic recovery
 	router.GET("/panic", func(c *gin.Context) {
-		panic(domain.NewValidationError("test", "test error"))
+		panic(domain.NewValidationError("test", "test", "test error"))
 	})
 	
 	w := httptest.NewRecorder()
@@ -113,4 +137,55 @@ func TestErrorHandler(t *testing.T) {
 	jsonErr := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, jsonErr)
 	assert.Equal(t, "ValidationError", response.Error)
-}
\ No newline at end of file
+}
+func TestErrorHandler_NonErrorPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(ErrorHandler())
+	
+	// Simulate a nil dereference in a handler
+	router.GET("/panic", func(c *gin.Context) {
+		var task *domain.Task
+		_ = task.Description()
+	})
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+	
+	var response models.ErrorResponse
+	jsonErr := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, jsonErr)
+	assert.Equal(t, "InternalServerError", response.Error)
+	assert.Equal(t, "req-123", response.RequestID)
+}
+
+func TestErrorHandler_AssignsRequestIDWithoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	router := gin.New()
+	router.Use(ErrorHandler())
+	
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/panic", nil)
+	router.ServeHTTP(w, req)
+	
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	
+	var response models.ErrorResponse
+	jsonErr := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, jsonErr)
+	assert.Equal(t, "PANIC_RECOVERED", response.Code)
+	assert.NotEmpty(t, response.RequestID)
+	assert.Equal(t, response.RequestID, w.Header().Get(RequestIDHeader))
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"discovery-tree/api/models"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlightRetryAfter is the Retry-After value, in seconds, sent with a shed request
+const InFlightRetryAfter = 1
+
+// InFlightLimit rejects requests with 503 and a Retry-After header once max requests
+// are being served, instead of letting bursts queue on the repository's write lock
+// A max of 0 or less disables the limit. Register it only on the routes to protect
+func InFlightLimit(max int) gin.HandlerFunc {
+	if max <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	slots := make(chan struct{}, max)
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+			c.Next()
+		default:
+			slog.Warn("Request shed: too many requests in flight",
+				slog.String("method", c.Request.Method),
+				slog.String("path", c.Request.URL.Path),
+				slog.Int("max_inflight", max),
+			)
+			c.Header("Retry-After", strconv.Itoa(InFlightRetryAfter))
+			respondError(c, http.StatusServiceUnavailable, models.ErrorResponse{
+				Error:   "ServiceUnavailable",
+				Code:    "TOO_MANY_REQUESTS",
+				Message: "The server is busy, please retry",
+			})
+			c.Abort()
+		}
+	}
+}
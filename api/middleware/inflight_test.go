@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightLimit_ShedsOverflow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const limit = 2
+	entered := make(chan struct{}, limit)
+	release := make(chan struct{})
+
+	// Slow API routes hold their slot until released; health sits outside the limited group
+	engine := gin.New()
+	engine.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api := engine.Group("/api/v1")
+	api.Use(InFlightLimit(limit))
+	api.GET("/slow", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	// Saturate the limit
+	var wg sync.WaitGroup
+	codes := make(chan int, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- get("/api/v1/slow").Code
+		}()
+	}
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+
+	// The overflow request is shed with 503 and Retry-After
+	w := get("/api/v1/slow")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "TOO_MANY_REQUESTS", response["code"])
+
+	// Exempt routes are still served
+	assert.Equal(t, http.StatusOK, get("/health").Code)
+
+	// Once the slow requests finish, their slots are free again
+	close(release)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, http.StatusOK, get("/api/v1/slow").Code)
+}
+
+func TestInFlightLimit_ZeroIsUnlimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(InFlightLimit(0))
+	engine.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/ok", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header carrying the request ID on requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin context key holding the request ID
+const requestIDKey = "request_id"
+
+// RequestID middleware assigns every request an ID, reusing the client's X-Request-ID if present,
+// and echoes it in the response header
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// GetRequestID returns the request ID assigned by the RequestID middleware
+// If the middleware did not run, a new ID is assigned and echoed in the response header
+func GetRequestID(c *gin.Context) string {
+	if requestID := c.GetString(requestIDKey); requestID != "" {
+		return requestID
+	}
+
+	requestID := uuid.New().String()
+	c.Set(requestIDKey, requestID)
+	c.Header(RequestIDHeader, requestID)
+	return requestID
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoWriteTimeout lifts the server's write deadline for a streaming route, so a large
+// response is not cut off by HTTP_WRITE_TIMEOUT. Register it on streaming routes only
+func NoWriteTimeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// A zero deadline means none; writers that cannot set deadlines (e.g. in tests) are left as is
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			slog.Debug("Could not lift write deadline", slog.String("error", err.Error()))
+		}
+		c.Next()
+	}
+}
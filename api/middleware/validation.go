@@ -2,13 +2,56 @@ package middleware
 
 import (
 	"discovery-tree/api/models"
+	"discovery-tree/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
-	"github.com/google/uuid"
 )
 
+// maxDescriptionLengthKey is the gin context key holding the limit of "maxdesc" fields
+const maxDescriptionLengthKey = "max_description_length"
+
+// MaxDescriptionLength middleware sets the maximum description length, in characters, that
+// BindJSON accepts in request body fields tagged limit:"maxdesc"; 0 disables the check
+// Oversized descriptions are rejected while binding, before the domain sees them
+func MaxDescriptionLength(max int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(maxDescriptionLengthKey, max)
+		c.Next()
+	}
+}
+
+// maxBatchSizeKey is the gin context key holding the limit of "maxbatch" fields
+const maxBatchSizeKey = "max_batch_size"
+
+// MaxBatchSize middleware sets the maximum number of entries that BindJSON accepts in the ID
+// arrays of batch request bodies, tagged limit:"maxbatch"; 0 disables the check
+// Oversized batches are rejected while binding, before any task is looked up
+func MaxBatchSize(max int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(maxBatchSizeKey, max)
+		c.Next()
+	}
+}
+
+// init registers the "taskid" binding tag, which accepts both UUIDs and short IDs
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = v.RegisterValidation("taskid", func(fl validator.FieldLevel) bool {
+			_, err := domain.TaskIDFromString(fl.Field().String())
+			return err == nil
+		})
+	}
+}
+
 // ValidationErrorHandler middleware handles validation errors consistently
 func ValidationErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,7 +68,7 @@ func ValidationErrorHandler() gin.HandlerFunc {
 					Code:    "INVALID_REQUEST",
 					Message: formatValidationError(validationErr),
 				}
-				c.JSON(http.StatusBadRequest, errorResp)
+				respondError(c, http.StatusBadRequest, errorResp)
 				c.Abort()
 				return
 			}
@@ -36,26 +79,105 @@ func ValidationErrorHandler() gin.HandlerFunc {
 				Code:    "INVALID_REQUEST",
 				Message: err.Error(),
 			}
-			c.JSON(http.StatusBadRequest, errorResp)
+			respondError(c, http.StatusBadRequest, errorResp)
 			c.Abort()
 		}
 	}
 }
 
 // BindJSON is a helper function that binds JSON and handles validation errors consistently
+// Fields tagged with a limit are checked against the limits the request's middleware set
 func BindJSON(c *gin.Context, obj interface{}) error {
 	if err := c.ShouldBindJSON(obj); err != nil {
+		namePositionField(err, obj)
 		errorResp := models.ErrorResponse{
 			Error:   "ValidationError",
 			Code:    "INVALID_REQUEST",
 			Message: formatBindingError(err),
 		}
-		c.JSON(http.StatusBadRequest, errorResp)
+		respondError(c, http.StatusBadRequest, errorResp)
+		return err
+	}
+	if err := checkLimits(c, obj); err != nil {
+		errorResp := models.ErrorResponse{
+			Error:   "ValidationError",
+			Code:    "INVALID_REQUEST",
+			Message: err.Error(),
+		}
+		respondError(c, http.StatusBadRequest, errorResp)
 		return err
 	}
 	return nil
 }
 
+// checkLimits checks the fields of a bound request body tagged limit:"maxdesc" or
+// limit:"maxbatch" against the request's MaxDescriptionLength and MaxBatchSize, returning an
+// error naming the first field over its limit
+func checkLimits(c *gin.Context, obj interface{}) error {
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		switch field.Tag.Get("limit") {
+		case "maxdesc":
+			max := c.GetInt(maxDescriptionLengthKey)
+			if max > 0 && utf8.RuneCountInString(value.Field(i).String()) > max {
+				return fmt.Errorf("Field '%s' must be at most %d characters long", field.Name, max)
+			}
+		case "maxbatch":
+			max := c.GetInt(maxBatchSizeKey)
+			if max > 0 && value.Field(i).Len() > max {
+				return fmt.Errorf("Field '%s' must have at most %d entries", field.Name, max)
+			}
+		}
+	}
+	return nil
+}
+
+// namePositionField names the field of a fractional-number error raised by models.Position,
+// which the JSON decoder leaves unnamed, when obj has exactly one Position field
+func namePositionField(err error, obj interface{}) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field != "" {
+		return
+	}
+
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	positionType := reflect.TypeOf(models.Position(0))
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType != positionType {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	if len(names) == 1 {
+		typeErr.Field = names[0]
+	}
+}
+
 // formatValidationError formats validator.ValidationErrors into a user-friendly message
 func formatValidationError(validationErrors validator.ValidationErrors) string {
 	if len(validationErrors) == 0 {
@@ -73,6 +195,8 @@ func formatValidationError(validationErrors validator.ValidationErrors) string {
 		return "Field '" + err.Field() + "' must be at most " + err.Param() + " characters long"
 	case "uuid":
 		return "Field '" + err.Field() + "' must be a valid UUID"
+	case "taskid":
+		return "Field '" + err.Field() + "' must be a valid UUID or short ID"
 	case "oneof":
 		return "Field '" + err.Field() + "' must be one of: " + err.Param()
 	default:
@@ -94,11 +218,21 @@ func formatBindingError(err error) string {
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
 			return formatValidationError(validationErrors)
 		}
+		// A fractional number for an integer field, e.g. a position of 1.5
+		// The decoder names the field only for plain ints; BindJSON names models.Position fields
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Type.Kind() == reflect.Int && strings.HasPrefix(typeErr.Value, "number ") {
+			value := strings.TrimPrefix(typeErr.Value, "number ")
+			if typeErr.Field == "" {
+				return "Numbers must be whole, got " + value
+			}
+			return "Field '" + typeErr.Field + "' must be a whole number, got " + value
+		}
 		return "Invalid JSON format: " + err.Error()
 	}
 }
 
-// ValidateUUID is a helper function to validate UUID strings
+// ValidateUUID is a helper function to validate task ID strings (UUIDs or short IDs)
 func ValidateUUID(c *gin.Context, uuidStr string, fieldName string) error {
 	if uuidStr == "" {
 		errorResp := models.ErrorResponse{
@@ -106,18 +240,18 @@ func ValidateUUID(c *gin.Context, uuidStr string, fieldName string) error {
 			Code:    "INVALID_UUID",
 			Message: "Field '" + fieldName + "' cannot be empty",
 		}
-		c.JSON(http.StatusBadRequest, errorResp)
+		respondError(c, http.StatusBadRequest, errorResp)
 		return validator.ValidationErrors{}
 	}
 
-	// Use google/uuid package for proper UUID validation
-	if _, err := uuid.Parse(uuidStr); err != nil {
+	// Accept any format TaskIDFromString does, so short IDs pass alongside UUIDs
+	if _, err := domain.TaskIDFromString(uuidStr); err != nil {
 		errorResp := models.ErrorResponse{
 			Error:   "ValidationError",
 			Code:    "INVALID_UUID",
-			Message: "Field '" + fieldName + "' must be a valid UUID",
+			Message: "Field '" + fieldName + "' must be a valid UUID or short ID",
 		}
-		c.JSON(http.StatusBadRequest, errorResp)
+		respondError(c, http.StatusBadRequest, errorResp)
 		return validator.ValidationErrors{}
 	}
 
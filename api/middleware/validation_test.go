@@ -89,6 +89,13 @@ func TestValidateUUID(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			shouldFail:     false,
 		},
+		{
+			name:           "ValidShortID",
+			uuid:           "k3xq7mzp2a",
+			fieldName:      "id",
+			expectedStatus: http.StatusOK,
+			shouldFail:     false,
+		},
 		{
 			name:           "InvalidUUID",
 			uuid:           "invalid-uuid",
@@ -203,4 +210,106 @@ func TestFormatBindingError(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestBindJSON_NamesFractionalPositionField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		obj      interface{}
+		body     string
+		expected string
+	}{
+		{"Position", &models.MoveTaskRequest{}, `{"position": 1.5}`, "Field 'position' must be a whole number, got 1.5"},
+		{"PointerPosition", &models.CreateChildTaskRequest{}, `{"position": 2.5}`, "Field 'position' must be a whole number, got 2.5"},
+		{"StartPosition", &models.MoveTasksRequest{}, `{"startPosition": 0.5}`, "Field 'startPosition' must be a whole number, got 0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/test", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			assert.Error(t, BindJSON(c, tt.obj))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response models.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expected, response.Message)
+		})
+	}
+}
+
+func TestBindJSON_MaxDescriptionLengthPerEngine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Each engine enforces its own limit, so servers in one process do not share it
+	newEngine := func(max int) *gin.Engine {
+		engine := gin.New()
+		engine.Use(MaxDescriptionLength(max))
+		engine.POST("/test", func(c *gin.Context) {
+			var req models.CreateRootTaskRequest
+			if err := BindJSON(c, &req); err != nil {
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+		return engine
+	}
+	strict, lenient, unlimited := newEngine(5), newEngine(20), newEngine(0)
+
+	post := func(engine *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/test", bytes.NewBufferString(`{"description": "Eleven char"}`))
+		req.Header.Set("Content-Type", "application/json")
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(strict)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Field 'Description' must be at most 5 characters long", response.Message)
+
+	assert.Equal(t, http.StatusOK, post(lenient).Code)
+	assert.Equal(t, http.StatusOK, post(unlimited).Code)
+}
+
+func TestBindJSON_MaxBatchSizePerEngine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Each engine enforces its own limit, so servers in one process do not share it
+	newEngine := func(max int) *gin.Engine {
+		engine := gin.New()
+		engine.Use(MaxBatchSize(max))
+		engine.POST("/test", func(c *gin.Context) {
+			var req models.NudgeTasksRequest
+			if err := BindJSON(c, &req); err != nil {
+				return
+			}
+			c.Status(http.StatusOK)
+		})
+		return engine
+	}
+	strict, lenient := newEngine(1), newEngine(2)
+
+	body := `{"ids": ["550e8400-e29b-41d4-a716-446655440000", "550e8400-e29b-41d4-a716-446655440001"], "direction": "up"}`
+	post := func(engine *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/test", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(strict)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Field 'IDs' must have at most 1 entries", response.Message)
+
+	assert.Equal(t, http.StatusOK, post(lenient).Code)
+}
@@ -2,25 +2,299 @@ package models
 
 import (
 	"discovery-tree/domain"
+	"sort"
 )
 
 // TaskToResponse converts a domain Task to a TaskResponse
 func TaskToResponse(task *domain.Task) TaskResponse {
+	return TaskToResponseWithPositionBase(task, 0)
+}
+
+// TaskToResponseWithPositionBase converts a domain Task to a TaskResponse,
+// shifting the 0-based stored position by positionBase
+// HasChildren and IsLeaf are left unset; see TaskToResponseWithChildCount
+func TaskToResponseWithPositionBase(task *domain.Task, positionBase int) TaskResponse {
 	var parentID *string
 	if task.ParentID() != nil {
-		parentIDStr := task.ParentID().String()
+		parentIDStr := task.ParentID().Canonical().String()
 		parentID = &parentIDStr
 	}
 
 	return TaskResponse{
-		ID:          task.ID().String(),
+		ID:          task.ID().Canonical().String(),
 		Description: task.Description(),
 		Status:      task.Status().String(),
 		ParentID:    parentID,
-		Position:    task.Position(),
+		Position:    task.Position() + positionBase,
 		CreatedAt:   task.CreatedAt(),
 		UpdatedAt:   task.UpdatedAt(),
+		CompletedAt: task.CompletedAt(),
+		Tags:        task.Tags(),
+		Kind:        task.Kind(),
+		Seq:         task.Seq(),
+	}
+}
+
+// TaskToResponseWithChildCount converts a domain Task like TaskToResponseWithPositionBase
+// and sets HasChildren and IsLeaf from the task's number of children
+func TaskToResponseWithChildCount(task *domain.Task, positionBase, childCount int) TaskResponse {
+	response := TaskToResponseWithPositionBase(task, positionBase)
+	response.HasChildren = childCount > 0
+	response.IsLeaf = childCount == 0
+	return response
+}
+
+// TasksToResponsesWithPositionBase converts domain Tasks to TaskResponses, preserving order
+// HasChildren and IsLeaf are left unset; see TasksToResponsesWithChildCounts
+func TasksToResponsesWithPositionBase(tasks []*domain.Task, positionBase int) []TaskResponse {
+	responses := make([]TaskResponse, len(tasks))
+	for i, task := range tasks {
+		responses[i] = TaskToResponseWithPositionBase(task, positionBase)
+	}
+	return responses
+}
+
+// TasksToResponsesWithChildCounts converts domain Tasks to TaskResponses, preserving order,
+// taking each task's number of children from childCounts (see ChildCounts)
+func TasksToResponsesWithChildCounts(tasks []*domain.Task, positionBase int, childCounts map[string]int) []TaskResponse {
+	responses := make([]TaskResponse, len(tasks))
+	for i, task := range tasks {
+		responses[i] = TaskToResponseWithChildCount(task, positionBase, childCounts[task.ID().Canonical().String()])
+	}
+	return responses
+}
+
+// ChildCounts counts the children of every task in one pass, keyed by canonical parent ID
+// tasks must include all children of the tasks whose counts are read, e.g. the whole store
+func ChildCounts(tasks []*domain.Task) map[string]int {
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		if task.ParentID() != nil {
+			counts[task.ParentID().Canonical().String()]++
+		}
+	}
+	return counts
+}
+
+// SubtreeToTreeResponse nests tasks given in depth-first order with the subtree root first
+// (as returned by FindSubtree) under that root; tasks must not be empty
+func SubtreeToTreeResponse(tasks []*domain.Task, positionBase int) TaskTreeResponse {
+	childCounts := ChildCounts(tasks)
+	index := 0
+	var build func() TaskTreeResponse
+	build = func() TaskTreeResponse {
+		task := tasks[index]
+		index++
+		count := childCounts[task.ID().Canonical().String()]
+		node := TaskTreeResponse{
+			TaskResponse: TaskToResponseWithChildCount(task, positionBase, count),
+			Children:     make([]TaskTreeResponse, 0, count),
+		}
+		// In depth-first order each child's subtree directly follows the previous one
+		for i := 0; i < count && index < len(tasks); i++ {
+			node.Children = append(node.Children, build())
+		}
+		return node
+	}
+	return build()
+}
+
+// ReadinessToResponse converts a domain ReadinessState and whether the task is on the
+// completion frontier to a ReadinessResponse
+func ReadinessToResponse(state domain.ReadinessState, frontier bool) ReadinessResponse {
+	return ReadinessResponse{
+		Ready:               state.IsReady(),
+		LeftSiblingComplete: state.LeftSiblingComplete(),
+		AllChildrenComplete: state.AllChildrenComplete(),
+		Frontier:            frontier,
+		Reasons:             state.Reasons(),
+	}
+}
+
+// BlockersToResponse converts domain Blockers to BlockerResponses, preserving order
+func BlockersToResponse(blockers []domain.Blocker) []BlockerResponse {
+	responses := make([]BlockerResponse, len(blockers))
+	for i, blocker := range blockers {
+		responses[i] = BlockerResponse{
+			Type:        string(blocker.Type),
+			TaskID:      blocker.Task.ID().Canonical().String(),
+			Description: blocker.Task.Description(),
+		}
+	}
+	return responses
+}
+
+// TasksToGraphResponseWithPositionBase converts tasks to a TaskGraphResponse with one edge per non-root task
+// Nodes are ordered roots first (earliest created first), then grouped by parent ID in position order;
+// edges follow the order of their child nodes
+func TasksToGraphResponseWithPositionBase(tasks []*domain.Task, positionBase int) TaskGraphResponse {
+	ordered := make([]*domain.Task, len(tasks))
+	copy(ordered, tasks)
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.IsRoot() || b.IsRoot() {
+			if a.IsRoot() && b.IsRoot() {
+				return a.CreatedBefore(b)
+			}
+			return a.IsRoot()
+		}
+		if aParent, bParent := a.ParentID().String(), b.ParentID().String(); aParent != bParent {
+			return aParent < bParent
+		}
+		if a.Position() != b.Position() {
+			return a.Position() < b.Position()
+		}
+		return a.ID().String() < b.ID().String()
+	})
+
+	response := TaskGraphResponse{
+		Nodes: make([]TaskResponse, len(ordered)),
+		Edges: make([]TaskEdgeResponse, 0, len(ordered)),
+	}
+	childCounts := ChildCounts(tasks)
+	for i, task := range ordered {
+		response.Nodes[i] = TaskToResponseWithChildCount(task, positionBase, childCounts[task.ID().Canonical().String()])
+		if task.ParentID() != nil {
+			response.Edges = append(response.Edges, TaskEdgeResponse{
+				Parent: task.ParentID().Canonical().String(),
+				Child:  task.ID().Canonical().String(),
+			})
+		}
+	}
+	return response
+}
+
+// PlanToResponseWithPositionBase converts plan steps to PlanStepResponses, keeping their order
+// childCounts gives each task's number of children (see ChildCounts)
+func PlanToResponseWithPositionBase(steps []domain.PlanStep, positionBase int, childCounts map[string]int) []PlanStepResponse {
+	responses := make([]PlanStepResponse, len(steps))
+	for i, step := range steps {
+		responses[i] = PlanStepResponse{
+			Task: TaskToResponseWithChildCount(step.Task, positionBase, childCounts[step.Task.ID().Canonical().String()]),
+			Path: TasksToResponsesWithChildCounts(step.Path, positionBase, childCounts),
+		}
+	}
+	return responses
+}
+
+// MoveHistoryToResponseWithPositionBase converts a task's move history to MoveRecordResponses,
+// shifting the 0-based stored positions by positionBase
+func MoveHistoryToResponseWithPositionBase(history []domain.MoveRecord, positionBase int) []MoveRecordResponse {
+	responses := make([]MoveRecordResponse, len(history))
+	for i, record := range history {
+		responses[i] = MoveRecordResponse{
+			FromParentID: optionalTaskIDString(record.FromParentID),
+			ToParentID:   optionalTaskIDString(record.ToParentID),
+			FromPosition: record.FromPosition + positionBase,
+			ToPosition:   record.ToPosition + positionBase,
+			At:           record.At,
+		}
+	}
+	return responses
+}
+
+// StatusHistoryToResponse converts a task's status history to StatusRecordResponses
+func StatusHistoryToResponse(history []domain.StatusRecord) []StatusRecordResponse {
+	responses := make([]StatusRecordResponse, len(history))
+	for i, record := range history {
+		responses[i] = StatusRecordResponse{
+			From: record.From.String(),
+			To:   record.To.String(),
+			At:   record.At,
+		}
 	}
+	return responses
+}
+
+// optionalTaskIDString converts an optional task ID to its canonical string (nil stays nil)
+func optionalTaskIDString(id *domain.TaskID) *string {
+	if id == nil {
+		return nil
+	}
+	idStr := id.Canonical().String()
+	return &idStr
+}
+
+// TreeNodeRequestToImportNode converts a TreeNodeRequest to a domain TreeImportNode
+// Returns a ValidationError if any status value is invalid
+func TreeNodeRequestToImportNode(req TreeNodeRequest) (domain.TreeImportNode, error) {
+	node := domain.TreeImportNode{
+		Description: req.Description,
+	}
+
+	if req.Status != "" {
+		status, err := domain.NewStatus(req.Status)
+		if err != nil {
+			return domain.TreeImportNode{}, err
+		}
+		node.Status = &status
+	}
+
+	for _, childReq := range req.Children {
+		child, err := TreeNodeRequestToImportNode(childReq)
+		if err != nil {
+			return domain.TreeImportNode{}, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+// InvariantViolationsToResponse converts domain invariant violations to a TreeValidationResponse
+func InvariantViolationsToResponse(violations []domain.InvariantViolation) TreeValidationResponse {
+	response := TreeValidationResponse{
+		Valid:      len(violations) == 0,
+		Violations: make([]InvariantViolationResponse, len(violations)),
+	}
+	for i, v := range violations {
+		response.Violations[i] = InvariantViolationResponse{
+			Constraint: v.Constraint,
+			Message:    v.Message,
+		}
+	}
+	return response
+}
+
+// DiagnosticsToResponse converts a tree diagnostics report to a DiagnosticsResponse
+// Every list is present, empty when nothing was found
+func DiagnosticsToResponse(diagnostics domain.TreeDiagnostics) DiagnosticsResponse {
+	response := DiagnosticsResponse{
+		Healthy:            diagnostics.Healthy(),
+		TaskCount:          diagnostics.TaskCount,
+		Violations:         InvariantViolationsToResponse(diagnostics.Violations).Violations,
+		Orphans:            diagnosticTasksToResponse(diagnostics.Orphans),
+		Cycles:             diagnosticTasksToResponse(diagnostics.Cycles),
+		Roots:              diagnosticTasksToResponse(diagnostics.Roots),
+		DuplicatePositions: make([]DuplicatePositionResponse, len(diagnostics.DuplicatePositions)),
+	}
+	for i, group := range diagnostics.DuplicatePositions {
+		response.DuplicatePositions[i] = DuplicatePositionResponse{
+			ParentID: group.ParentID.String(),
+			Position: group.Position,
+			Tasks:    diagnosticTasksToResponse(group.Tasks),
+		}
+	}
+	return response
+}
+
+// diagnosticTasksToResponse converts tasks to DiagnosticTaskResponses
+func diagnosticTasksToResponse(tasks []*domain.Task) []DiagnosticTaskResponse {
+	response := make([]DiagnosticTaskResponse, len(tasks))
+	for i, task := range tasks {
+		var parentID *string
+		if task.ParentID() != nil {
+			id := task.ParentID().String()
+			parentID = &id
+		}
+		response[i] = DiagnosticTaskResponse{
+			TaskID:      task.ID().String(),
+			Description: task.Description(),
+			ParentID:    parentID,
+			Position:    task.Position(),
+		}
+	}
+	return response
 }
 
 // ErrorToResponse converts a domain error to an ErrorResponse
@@ -44,6 +318,12 @@ func ErrorToResponse(err error) ErrorResponse {
 			Code:    e.Constraint,
 			Message: e.Message,
 		}
+	case domain.SubtreeTooLargeError:
+		return ErrorResponse{
+			Error:   "SubtreeTooLargeError",
+			Code:    "SUBTREE_TOO_LARGE",
+			Message: e.Error(),
+		}
 	default:
 		return ErrorResponse{
 			Error:   "InternalServerError",
@@ -51,4 +331,16 @@ func ErrorToResponse(err error) ErrorResponse {
 			Message: "An unexpected error occurred",
 		}
 	}
-}
\ No newline at end of file
+}
+
+// ArchivedTreeToResponse converts an archived tree to an ArchivedTreeResponse, shifting
+// positions by positionBase; child counts are taken from the archived tree itself
+func ArchivedTreeToResponse(tree domain.ArchivedTree, positionBase int) ArchivedTreeResponse {
+	return ArchivedTreeResponse{
+		RootID:     tree.Root().ID().Canonical().String(),
+		ParentID:   tree.ParentID.Canonical().String(),
+		Position:   tree.Position + positionBase,
+		ArchivedAt: tree.ArchivedAt,
+		Tasks:      TasksToResponsesWithChildCounts(tree.Tasks, positionBase, ChildCounts(tree.Tasks)),
+	}
+}
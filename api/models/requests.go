@@ -1,19 +1,62 @@
 package models
 
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Position is a position in a request body
+// It accepts integral floats such as 1.0, which some JSON clients send for whole numbers
+type Position int
+
+// UnmarshalJSON decodes a whole JSON number, rejecting fractions with a json.UnmarshalTypeError
+// so binding reports the offending field and value
+func (p *Position) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+
+	position, ok := parseWholeNumber(number.String())
+	if !ok {
+		return &json.UnmarshalTypeError{Value: "number " + number.String(), Type: reflect.TypeOf(0)}
+	}
+	*p = Position(position)
+	return nil
+}
+
+// parseWholeNumber parses a JSON number that must hold a whole value, such as a position
+// Integral floats like "1.0" or "1e2" are accepted since some clients write them; fractions
+// and values outside the int range are not
+func parseWholeNumber(number string) (int, bool) {
+	if n, err := strconv.Atoi(number); err == nil {
+		return n, true
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
 // CreateRootTaskRequest represents the request to create a root task
 type CreateRootTaskRequest struct {
-	Description string `json:"description" binding:"required,min=1"`
+	Description string `json:"description" binding:"required,min=1" limit:"maxdesc"`
 }
 
 // CreateChildTaskRequest represents the request to create a child task
 type CreateChildTaskRequest struct {
-	Description string `json:"description" binding:"required,min=1"`
-	ParentID    string `json:"parentId" binding:"required,uuid"`
+	Description string    `json:"description" limit:"maxdesc"` // blank uses CHILD_DESCRIPTION_TEMPLATE, if set
+	ParentID    string    `json:"parentId" binding:"required,taskid"`
+	Position    *Position `json:"position,omitempty"` // appends after the last child when omitted (see CHILD_INSERT_MODE)
 }
 
 // UpdateTaskRequest represents the request to update a task's description
 type UpdateTaskRequest struct {
-	Description string `json:"description" binding:"required,min=1"`
+	Description string `json:"description" binding:"required,min=1" limit:"maxdesc"`
 }
 
 // UpdateStatusRequest represents the request to update a task's status
@@ -21,8 +64,60 @@ type UpdateStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=TODO 'In Progress' DONE Blocked 'Root Work Item'"`
 }
 
+// RenameStatusRequest represents the request to move every task from one status to another
+type RenameStatusRequest struct {
+	From string `json:"from" binding:"required,oneof=TODO 'In Progress' DONE Blocked 'Root Work Item'"`
+	To   string `json:"to" binding:"required,oneof=TODO 'In Progress' DONE Blocked 'Root Work Item'"`
+}
+
 // MoveTaskRequest represents the request to move a task to a new position or parent
 type MoveTaskRequest struct {
-	ParentID *string `json:"parentId" binding:"omitempty,uuid"`
-	Position int     `json:"position" binding:"min=0"`
-}
\ No newline at end of file
+	ParentID *string  `json:"parentId" binding:"omitempty,taskid"`
+	Position Position `json:"position" binding:"min=0"`
+}
+
+// MoveTasksRequest represents the request to move several tasks under a single parent
+type MoveTasksRequest struct {
+	IDs           []string `json:"ids" binding:"required,min=1,dive,taskid" limit:"maxbatch"`
+	ParentID      string   `json:"parentId" binding:"required,taskid"`
+	StartPosition Position `json:"startPosition" binding:"min=0"`
+}
+
+// NudgeTasksRequest represents the request to shift several siblings one position up or down
+type NudgeTasksRequest struct {
+	IDs       []string `json:"ids" binding:"required,min=1,dive,taskid" limit:"maxbatch"`
+	Direction string   `json:"direction" binding:"required,oneof=up down"`
+}
+
+// TreeNodeRequest represents a task and its children in a nested tree
+type TreeNodeRequest struct {
+	Description string            `json:"description" binding:"required,min=1"`
+	Status      string            `json:"status,omitempty" binding:"omitempty,oneof=TODO 'In Progress' DONE Blocked 'Root Work Item'"`
+	Children    []TreeNodeRequest `json:"children,omitempty" binding:"omitempty,dive"`
+}
+
+// GroupTasksRequest represents the request to wrap several tasks in a new parent task
+type GroupTasksRequest struct {
+	ChildIDs    []string `json:"childIds" binding:"required,min=1,dive,taskid" limit:"maxbatch"`
+	Description string   `json:"description" binding:"required,min=1" limit:"maxdesc"`
+	ParentID    string   `json:"parentId" binding:"required,taskid"`
+	Position    Position `json:"position" binding:"min=0"`
+}
+
+// SetKindRequest represents the request to change a task's kind
+type SetKindRequest struct {
+	Kind string `json:"kind" binding:"required"`
+}
+
+// TagSubtreeRequest represents the request to add and remove tags across a subtree
+type TagSubtreeRequest struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// InstantiateTemplateRequest represents the request to create a subtree from a named template
+type InstantiateTemplateRequest struct {
+	TemplateName string    `json:"templateName" binding:"required,min=1"`
+	ParentID     *string   `json:"parentId" binding:"omitempty,taskid"` // instantiates the template as the root when omitted
+	Position     *Position `json:"position,omitempty"`                  // appends after the last child when omitted
+}
@@ -4,6 +4,19 @@ import (
 	"time"
 )
 
+// EnvelopeResponse wraps every response when RESPONSE_ENVELOPE is "wrapped"
+// Success responses carry the payload in Data; error responses carry Error and a null Data
+type EnvelopeResponse struct {
+	Data  interface{}    `json:"data"`
+	Meta  EnvelopeMeta   `json:"meta"`
+	Error *ErrorResponse `json:"error"`
+}
+
+// EnvelopeMeta holds metadata about a wrapped payload
+type EnvelopeMeta struct {
+	Count *int `json:"count,omitempty"` // Number of items, for list payloads
+}
+
 // TaskResponse represents the API response for a task
 type TaskResponse struct {
 	ID          string     `json:"id"`
@@ -13,11 +26,241 @@ type TaskResponse struct {
 	Position    int        `json:"position"`
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	Tags        []string   `json:"tags"`
+	Kind        string     `json:"kind"`        // Classification such as task, spike or milestone; see TASK_KINDS
+	HasChildren bool       `json:"hasChildren"` // Whether the task has at least one child
+	IsLeaf      bool       `json:"isLeaf"`      // Whether the task has no children
+	Seq         uint64     `json:"seq"`         // Store sequence number of the task's latest change; see GET /tasks/changes
+}
+
+// TaskTreeResponse represents a task with its descendants nested under it
+type TaskTreeResponse struct {
+	TaskResponse
+	Children []TaskTreeResponse `json:"children"` // Ordered by position; [] for a leaf
+}
+
+// TaskDetailResponse represents a task together with the related data requested via ?include=
+// Each related key is present only when included; an included but empty list is []
+type TaskDetailResponse struct {
+	TaskResponse
+	Children  *[]TaskResponse    `json:"children,omitempty"`
+	Ancestors *[]TaskResponse    `json:"ancestors,omitempty"`
+	Siblings  *[]TaskResponse    `json:"siblings,omitempty"`
+	Readiness *ReadinessResponse `json:"readiness,omitempty"`
+}
+
+// ReadinessResponse represents whether a task is ready to be worked on
+type ReadinessResponse struct {
+	Ready               bool     `json:"ready"`
+	LeftSiblingComplete bool     `json:"leftSiblingComplete"`
+	AllChildrenComplete bool     `json:"allChildrenComplete"`
+	Frontier            bool     `json:"frontier"` // Whether the task is the next thing to do in its tree, not just allowed
+	Reasons             []string `json:"reasons"`
+}
+
+// BlockerResponse represents an incomplete task that keeps another task from being ready
+type BlockerResponse struct {
+	Type        string `json:"type"` // "leftSibling" or "child"
+	TaskID      string `json:"taskId"`
+	Description string `json:"description"`
+}
+
+// TaskGraphResponse represents the task tree as an adjacency list of nodes and parent-child edges
+type TaskGraphResponse struct {
+	Nodes []TaskResponse     `json:"nodes"`
+	Edges []TaskEdgeResponse `json:"edges"`
+}
+
+// TaskEdgeResponse represents a parent-child edge in a TaskGraphResponse
+type TaskEdgeResponse struct {
+	Parent string `json:"parent"`
+	Child  string `json:"child"`
+}
+
+// PlanStepResponse represents a task that remains to be done, with the path leading to it
+type PlanStepResponse struct {
+	Task TaskResponse   `json:"task"`
+	Path []TaskResponse `json:"path"` // Ancestors from the root down to the task's parent
+}
+
+// MoveRecordResponse represents one entry of a task's move history in API responses
+type MoveRecordResponse struct {
+	FromParentID *string   `json:"fromParentId"`
+	ToParentID   *string   `json:"toParentId"`
+	FromPosition int       `json:"fromPosition"`
+	ToPosition   int       `json:"toPosition"`
+	At           time.Time `json:"at"`
+}
+
+// StatusRecordResponse represents one entry of a task's status timeline in API responses
+type StatusRecordResponse struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
 }
 
 // ErrorResponse represents the API response for errors
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
\ No newline at end of file
+	Error     string                `json:"error"`
+	Code      string                `json:"code"`
+	Field     string                `json:"field,omitempty"` // Invalid field of a validation error
+	Message   string                `json:"message"`
+	Details   []ErrorDetailResponse `json:"details,omitempty"` // Tasks causing a constraint violation, when known
+	RequestID string                `json:"requestId,omitempty"`
+}
+
+// ErrorDetailResponse names a task that causes an error, e.g. an incomplete child blocking completion
+type ErrorDetailResponse struct {
+	TaskID      string `json:"taskId"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+// InvariantViolationResponse represents a single broken tree invariant
+type InvariantViolationResponse struct {
+	Constraint string `json:"constraint"`
+	Message    string `json:"message"`
+}
+
+// TreeValidationResponse represents the API response for a tree validation
+type TreeValidationResponse struct {
+	Valid      bool                         `json:"valid"`
+	Violations []InvariantViolationResponse `json:"violations"`
+}
+
+// DiagnosticTaskResponse identifies a task involved in a diagnosed problem
+type DiagnosticTaskResponse struct {
+	TaskID      string  `json:"taskId"`
+	Description string  `json:"description"`
+	ParentID    *string `json:"parentId"`
+	Position    int     `json:"position"`
+}
+
+// DuplicatePositionResponse is a group of siblings sharing one position
+type DuplicatePositionResponse struct {
+	ParentID string                   `json:"parentId"`
+	Position int                      `json:"position"` // Stored 0-based position
+	Tasks    []DiagnosticTaskResponse `json:"tasks"`
+}
+
+// DiagnosticsResponse represents the API response for a data diagnostics report
+type DiagnosticsResponse struct {
+	Healthy            bool                         `json:"healthy"`            // Whether no invariant is violated
+	TaskCount          int                          `json:"taskCount"`          // Number of tasks checked
+	Violations         []InvariantViolationResponse `json:"violations"`         // Every violated invariant
+	Orphans            []DiagnosticTaskResponse     `json:"orphans"`            // Tasks whose parent does not exist
+	Cycles             []DiagnosticTaskResponse     `json:"cycles"`             // One task on each cycle
+	Roots              []DiagnosticTaskResponse     `json:"roots"`              // Every root, when there is not exactly one
+	DuplicatePositions []DuplicatePositionResponse  `json:"duplicatePositions"` // Sibling groups sharing a position
+}
+
+// MoveTaskResponse represents the API response for a move with ?reconcile=true
+type MoveTaskResponse struct {
+	Task     TaskResponse   `json:"task"`
+	Reopened []TaskResponse `json:"reopened"` // Ancestors reopened to the reopen status, nearest first
+}
+
+// TagSubtreeResponse reports the result of tagging a subtree
+type TagSubtreeResponse struct {
+	Affected int `json:"affected"` // Number of tasks whose tags changed
+}
+
+// ResetSubtreeResponse reports the result of resetting a subtree to TODO
+type ResetSubtreeResponse struct {
+	Reset    int            `json:"reset"`    // Number of tasks set to TODO
+	Reopened []TaskResponse `json:"reopened"` // DONE ancestors reopened to the reopen status, nearest first
+}
+
+// SiblingCountResponse reports a task's place among its siblings, for "X of N" labels
+type SiblingCountResponse struct {
+	Total int `json:"total"` // Number of siblings, the task included
+	Index int `json:"index"` // The task's position among them, using the configured position base
+}
+
+// TaskPositionResponse reports where a task currently sits in the tree, without the task itself
+type TaskPositionResponse struct {
+	ParentID     *string `json:"parentId"`     // Parent task ID, null for a root task
+	Position     int     `json:"position"`     // Position among its siblings, using the configured position base
+	SiblingCount int     `json:"siblingCount"` // Number of siblings, the task included
+}
+
+// TaskChangesResponse lists the tasks changed after a sequence number
+type TaskChangesResponse struct {
+	Tasks   []TaskResponse `json:"tasks"`   // Changed tasks, ordered by seq
+	Seq     uint64         `json:"seq"`     // Cursor to pass as since on the next call
+	HasMore bool           `json:"hasMore"` // Whether limit cut the list short; call again with seq to continue
+}
+
+// TaskCountResponse reports how many tasks the store holds
+type TaskCountResponse struct {
+	Total    int            `json:"total"`              // Number of tasks in the store
+	ByStatus map[string]int `json:"byStatus,omitempty"` // Tasks per status name, every status included; only with ?byStatus=true
+}
+
+// TaskActivityResponse summarizes when the tasks of a subtree were created and changed
+type TaskActivityResponse struct {
+	TaskID            string         `json:"taskId"`            // Top task of the subtree
+	Total             int            `json:"total"`             // Tasks in the subtree, the top task included
+	EarliestCreatedAt time.Time      `json:"earliestCreatedAt"` // Earliest CreatedAt in the subtree
+	LatestUpdatedAt   time.Time      `json:"latestUpdatedAt"`   // Latest UpdatedAt in the subtree
+	Days              int            `json:"days"`              // Length of the recent window in days
+	ChangedRecently   int            `json:"changedRecently"`   // Tasks updated within the last days
+	ByStatus          map[string]int `json:"byStatus"`          // Tasks per status name, every status included
+}
+
+// ArchivedTreeResponse represents a completed top-level branch moved to the archive
+type ArchivedTreeResponse struct {
+	RootID     string         `json:"rootId"`     // Top task of the branch; restore it with POST /tasks/archive/{id}/restore
+	ParentID   string         `json:"parentId"`   // Root task the branch was a child of
+	Position   int            `json:"position"`   // Position under that root when archived, using the configured position base
+	ArchivedAt time.Time      `json:"archivedAt"` // When the branch was archived
+	Tasks      []TaskResponse `json:"tasks"`      // The branch in depth-first order, its top task first
+}
+
+// StatusResponse describes how a task status is presented
+type StatusResponse struct {
+	Name        string `json:"name"`        // Status value used in requests and responses
+	DisplayName string `json:"displayName"` // Human-readable name
+	Color       string `json:"color"`       // Hex color, from STATUS_COLORS or the default
+	Complete    bool   `json:"complete"`    // Whether the status counts as complete
+}
+
+// StatsResponse reports the progress of the store or a subtree
+type StatsResponse struct {
+	TaskID                  *string        `json:"taskId"`                  // Top task of the subtree, null for the whole store
+	Tasks                   int            `json:"tasks"`                   // Tasks counted; Root Work Item tasks are not
+	Completed               int            `json:"completed"`               // Counted tasks in a complete status
+	ByStatus                map[string]int `json:"byStatus"`                // Counted tasks per status name, every counted status included
+	PercentComplete         float64        `json:"percentComplete"`         // Completed tasks as a percentage of counted tasks
+	WeightedPercentComplete float64        `json:"weightedPercentComplete"` // Status weights (see STATUS_WEIGHTS) summed as a percentage of counted tasks
+}
+
+// CompactResponse reports the result of rewriting the data file
+type CompactResponse struct {
+	BytesBefore     int `json:"bytesBefore"`     // Size of the data file before compaction
+	BytesAfter      int `json:"bytesAfter"`      // Size of the data file after compaction
+	TasksRenumbered int `json:"tasksRenumbered"` // Tasks whose position changed to close gaps among their siblings
+}
+
+// RenameStatusResponse reports the result of moving every task from one status to another
+type RenameStatusResponse struct {
+	From    string `json:"from"`    // Status the tasks had
+	To      string `json:"to"`      // Status the tasks have now
+	Renamed int    `json:"renamed"` // Number of tasks changed
+}
+
+// TemplateResponse describes a task template available for instantiation
+type TemplateResponse struct {
+	Name      string `json:"name"`
+	TaskCount int    `json:"taskCount"`       // Number of tasks an instantiation creates
+	RootOnly  bool   `json:"rootOnly"`        // Whether the template can only be instantiated as the root task
+	Error     string `json:"error,omitempty"` // Why the template file could not be loaded; such a template cannot be instantiated
+}
+
+// ImportResponse represents the API response for a bulk import
+type ImportResponse struct {
+	Mode     string `json:"mode"`
+	Imported int    `json:"imported"`
+	Total    int    `json:"total"`
+}
@@ -2,8 +2,11 @@ package server
 
 import (
 	"discovery-tree/api/container"
-	_ "discovery-tree/docs" // Import generated docs
+	"discovery-tree/api/middleware"
+	"discovery-tree/docs"
 	"log/slog"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -12,51 +15,116 @@ import (
 
 // RouteConfig holds configuration for route setup
 type RouteConfig struct {
-	EnableSwagger bool
-	APIVersion    string
+	EnableSwagger     bool
+	EnableOpenAPIJSON bool // Serve the OpenAPI document at {BasePath}/openapi.json without the Swagger UI
+	APIVersion        string
+	BasePath          string   // Prefix of the versioned API routes (e.g. "/api/v1")
+	SwaggerHost       string   // Host served in the OpenAPI document; empty keeps the annotation value
+	SwaggerSchemes    []string // Schemes served in the OpenAPI document; empty keeps the annotation values
+	MaxInFlight       int      // Maximum concurrent API requests before shedding with 503; 0 means unlimited
 }
 
 // SetupRoutes configures all API routes for the given engine and container
 func SetupRoutes(engine *gin.Engine, container *container.Container) {
 	config := &RouteConfig{
-		EnableSwagger: container.Config().EnableSwagger,
-		APIVersion:    "v1",
+		EnableSwagger:     container.Config().EnableSwagger,
+		EnableOpenAPIJSON: container.Config().EnableOpenAPIJSON,
+		APIVersion:        "v1",
+		SwaggerHost:       container.Config().SwaggerHost,
+		SwaggerSchemes:    container.Config().SwaggerSchemes,
+		MaxInFlight:       container.Config().MaxInFlight,
 	}
+	config.BasePath = normalizeBasePath(container.Config().APIBasePath, "/api/"+config.APIVersion)
 	
-	setupHealthRoutes(engine, container)
+	setupHealthRoutes(engine, container, config)
+	setupMetricsRoutes(engine, container, config)
 	setupAPIRoutes(engine, container, config)
 	setupSwaggerRoutes(engine, config)
 	
 	slog.Info("All routes configured successfully",
 		slog.Int("total_routes", len(engine.Routes())),
 		slog.String("api_version", config.APIVersion),
+		slog.String("base_path", config.BasePath),
 		slog.Bool("swagger_enabled", config.EnableSwagger),
+		slog.Bool("openapi_json_enabled", config.EnableSwagger || config.EnableOpenAPIJSON),
 	)
 }
 
+// normalizeBasePath ensures the base path has a leading slash and no trailing slash
+// An empty base path falls back to the given default
+func normalizeBasePath(basePath, defaultPath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return defaultPath
+	}
+	return "/" + basePath
+}
+
+// pathPrefix returns the part of the base path in front of "/api/{version}"
+// (e.g. "/discovery" for "/discovery/api/v1"), or "" if the base path does not end in it
+func (c *RouteConfig) pathPrefix() string {
+	prefix := strings.TrimSuffix(c.BasePath, "/api/"+c.APIVersion)
+	if prefix == c.BasePath {
+		return ""
+	}
+	return prefix
+}
+
+// healthPath returns the health check path, which shares the base path's prefix
+func (c *RouteConfig) healthPath() string {
+	return c.pathPrefix() + "/health"
+}
+
+// metricsPath returns the metrics path, which shares the base path's prefix
+func (c *RouteConfig) metricsPath() string {
+	return c.pathPrefix() + "/metrics"
+}
+
 // setupHealthRoutes configures health check routes
-func setupHealthRoutes(engine *gin.Engine, container *container.Container) {
+func setupHealthRoutes(engine *gin.Engine, container *container.Container, config *RouteConfig) {
 	healthHandler := container.GetHealthHandler()
 	
 	// Health check endpoint (outside of API versioning for simplicity)
-	engine.GET("/health", healthHandler.HealthCheck)
+	engine.GET(config.healthPath(), healthHandler.HealthCheck)
 	
-	slog.Debug("Health routes configured")
+	slog.Debug("Health routes configured", slog.String("path", config.healthPath()))
+}
+
+// setupMetricsRoutes configures the metrics route
+func setupMetricsRoutes(engine *gin.Engine, container *container.Container, config *RouteConfig) {
+	metricsHandler := container.GetMetricsHandler()
+	
+	// Metrics endpoint, next to the health check
+	engine.GET(config.metricsPath(), metricsHandler.Metrics)
+	
+	slog.Debug("Metrics routes configured", slog.String("path", config.metricsPath()))
 }
 
 // setupAPIRoutes configures versioned API routes
 func setupAPIRoutes(engine *gin.Engine, container *container.Container, config *RouteConfig) {
 	// API version group
-	apiGroup := engine.Group("/api/" + config.APIVersion)
+	apiGroup := engine.Group(config.BasePath)
+
+	// Shed load beyond MAX_INFLIGHT; health and metrics live outside the group and stay reachable
+	apiGroup.Use(middleware.InFlightLimit(config.MaxInFlight))
 	
 	// Setup task routes
 	setupTaskRoutes(apiGroup, container)
+
+	// Setup template routes
+	setupTemplateRoutes(apiGroup, container)
+	setupStatusRoutes(apiGroup, container)
+	setupStatsRoutes(apiGroup, container)
+	setupAdminRoutes(apiGroup, container)
 	
 	// Future: Setup other resource routes here
 	// setupUserRoutes(apiGroup, container)
 	// setupProjectRoutes(apiGroup, container)
 	
-	slog.Debug("API routes configured", slog.String("version", config.APIVersion))
+	slog.Debug("API routes configured",
+		slog.String("version", config.APIVersion),
+		slog.String("base_path", config.BasePath),
+	)
 }
 
 // setupTaskRoutes configures all task-related routes
@@ -73,6 +141,20 @@ func setupTaskRoutes(apiGroup *gin.RouterGroup, container *container.Container)
 	// General task collection operations
 	tasks.POST("", taskHandler.CreateChildTask)      // Create child task
 	tasks.GET("", taskHandler.GetAllTasks)           // Get all tasks
+	tasks.POST("/move-many", taskHandler.MoveTasks)  // Move several tasks to one parent
+	tasks.POST("/nudge", taskHandler.NudgeTasks)      // Shift several siblings one position up or down
+	tasks.POST("/validate", taskHandler.ValidateTree) // Validate a nested tree without creating it
+	tasks.GET("/export", middleware.NoWriteTimeout(), taskHandler.ExportTasks) // Stream all tasks as JSON Lines (exempt from HTTP_WRITE_TIMEOUT)
+	tasks.POST("/import", taskHandler.ImportTasks)    // Replace or merge tasks from JSON Lines
+	tasks.POST("/group", taskHandler.GroupTasks)      // Wrap several tasks in a new parent
+	tasks.GET("/graph", taskHandler.GetTaskGraph)     // Get all tasks as nodes and edges
+	tasks.GET("/count", taskHandler.GetTaskCount)     // Count tasks, optionally by status
+	tasks.GET("/changes", taskHandler.GetTaskChanges) // List tasks changed after a sequence number
+	tasks.GET("/plan", taskHandler.GetPlan)           // Get the remaining tasks in completion order
+	tasks.POST("/start-next", taskHandler.StartNextTasks) // Start the next ready tasks in plan order
+	tasks.GET("/by-description", taskHandler.FindTasksByDescription) // Find tasks whose description matches exactly
+	tasks.GET("/archive", taskHandler.GetArchive)     // List completed branches moved out of the tree
+	tasks.POST("/archive/:id/restore", taskHandler.RestoreArchivedTree) // Move an archived branch back into the tree
 	
 	// Individual task operations (by ID)
 	tasks.GET("/:id", taskHandler.GetTask)           // Get specific task
@@ -81,39 +163,139 @@ func setupTaskRoutes(apiGroup *gin.RouterGroup, container *container.Container)
 	
 	// Task status operations
 	tasks.PUT("/:id/status", taskHandler.UpdateTaskStatus) // Update task status
+	tasks.GET("/:id/status/options", taskHandler.GetStatusOptions) // Get the statuses the task can move to now
+	tasks.PUT("/:id/kind", taskHandler.SetTaskKind)        // Change task kind
 	
 	// Task hierarchy operations
 	tasks.PUT("/:id/move", taskHandler.MoveTask)           // Move task
 	tasks.GET("/:id/children", taskHandler.GetTaskChildren) // Get task children
+	tasks.GET("/:id/root", taskHandler.GetTaskRoot)         // Get root of task's tree
+	tasks.GET("/:id/siblings/count", taskHandler.GetSiblingCount) // Get sibling count and index for "X of N" labels
+	tasks.GET("/:id/position", taskHandler.GetTaskPosition) // Get just the parent, position and sibling count
+	tasks.GET("/:id/activity", taskHandler.GetTaskActivity) // Summarize when the subtree was created and changed
+	tasks.GET("/:id/blockers", taskHandler.GetTaskBlockers) // Get the incomplete tasks keeping a task from being ready
+	tasks.POST("/:id/ungroup", taskHandler.UngroupTask)     // Replace task with its children
+	tasks.POST("/:id/promote-root", taskHandler.PromoteToRoot) // Make the task the root, optionally replacing the current one
+	tasks.GET("/:id/history", taskHandler.GetTaskHistory)   // Get task move history
+	tasks.GET("/:id/timeline", taskHandler.GetTaskTimeline) // Get task status changes
+	tasks.GET("/:id/export", middleware.NoWriteTimeout(), taskHandler.ExportSubtree) // Export a subtree as JSON, JSON Lines, CSV or Markdown
+	tasks.POST("/:id/toggle", taskHandler.ToggleTaskStatus) // Flip status between TODO and DONE
+	tasks.POST("/:id/tags/subtree", taskHandler.TagSubtree) // Add and remove tags on a task and its descendants
+	tasks.POST("/:id/reset", taskHandler.ResetSubtree)     // Set a task and its descendants back to TODO
 	
 	slog.Debug("Task routes configured",
-		slog.Int("task_routes", 9), // Number of task-related routes
+		slog.Int("task_routes", 39), // Number of task-related routes
+	)
+}
+
+// setupTemplateRoutes configures the task template routes
+func setupTemplateRoutes(apiGroup *gin.RouterGroup, container *container.Container) {
+	templateHandler := container.GetTemplateHandler()
+
+	apiGroup.GET("/templates", templateHandler.ListTemplates)                  // List templates from TEMPLATE_DIR
+	apiGroup.POST("/tasks/from-template", templateHandler.InstantiateTemplate) // Create a subtree from a template
+
+	slog.Debug("Template routes configured",
+		slog.Int("template_routes", 2), // Number of template-related routes
+	)
+}
+
+// setupStatusRoutes configures the status presentation routes
+func setupStatusRoutes(apiGroup *gin.RouterGroup, container *container.Container) {
+	statusHandler := container.GetStatusHandler()
+
+	apiGroup.GET("/statuses", statusHandler.ListStatuses) // List statuses with display names and colors
+
+	slog.Debug("Status routes configured",
+		slog.Int("status_routes", 1), // Number of status-related routes
+	)
+}
+
+// setupStatsRoutes configures the progress statistics routes
+func setupStatsRoutes(apiGroup *gin.RouterGroup, container *container.Container) {
+	statsHandler := container.GetStatsHandler()
+
+	apiGroup.GET("/stats", statsHandler.GetStats) // Count tasks per status and report binary and weighted progress
+
+	slog.Debug("Stats routes configured",
+		slog.Int("stats_routes", 1), // Number of stats routes
+	)
+}
+
+// setupAdminRoutes configures the maintenance routes
+func setupAdminRoutes(apiGroup *gin.RouterGroup, container *container.Container) {
+	adminHandler := container.GetAdminHandler()
+
+	apiGroup.POST("/admin/compact", adminHandler.CompactDataFile)    // Renumber positions and rewrite the data file
+	apiGroup.POST("/admin/status/rename", adminHandler.RenameStatus) // Move every task from one status to another
+	apiGroup.GET("/admin/diagnostics", adminHandler.GetDiagnostics)  // Report invariant violations without changing anything
+
+	slog.Debug("Admin routes configured",
+		slog.Int("admin_routes", 3), // Number of admin routes
 	)
 }
 
 // setupSwaggerRoutes configures Swagger documentation routes
 func setupSwaggerRoutes(engine *gin.Engine, config *RouteConfig) {
-	if !config.EnableSwagger {
+	if !config.EnableSwagger && !config.EnableOpenAPIJSON {
 		slog.Debug("Swagger documentation disabled")
 		return
 	}
-	
+
+	configureSwaggerInfo(config)
+
 	// API documentation group
-	apiGroup := engine.Group("/api/" + config.APIVersion)
-	
+	apiGroup := engine.Group(config.BasePath)
+
+	// Raw OpenAPI document for machine clients, served without the UI
+	apiGroup.GET("/openapi.json", serveOpenAPIDocument)
+
+	if !config.EnableSwagger {
+		slog.Debug("Swagger UI disabled, serving the OpenAPI document only",
+			slog.String("openapi_json", config.BasePath+"/openapi.json"),
+		)
+		return
+	}
+
 	// Swagger UI endpoint (this serves both the UI and the JSON)
 	apiGroup.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	
+
+	// Bare docs path opens the UI; trailing slashes are trimmed, so /docs/ lands here too
+	apiGroup.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, c.Request.URL.Path+"/index.html")
+	})
+
 	slog.Debug("Swagger routes configured",
-		slog.String("swagger_ui", "/api/"+config.APIVersion+"/docs/index.html"),
-		slog.String("swagger_json", "/api/"+config.APIVersion+"/docs/doc.json"),
+		slog.String("swagger_ui", config.BasePath+"/docs/index.html"),
+		slog.String("swagger_json", config.BasePath+"/docs/doc.json"),
+		slog.String("openapi_json", config.BasePath+"/openapi.json"),
+		slog.String("swagger_host", docs.SwaggerInfo.Host),
 	)
 }
 
+// configureSwaggerInfo points the served OpenAPI document at the configured base path, host and schemes
+func configureSwaggerInfo(config *RouteConfig) {
+	// Documented paths already include "/api/{version}", so the spec's base path is the prefix in front of it
+	docs.SwaggerInfo.BasePath = "/" + strings.TrimPrefix(config.pathPrefix(), "/")
+
+	// Point "Try it out" at the deployed host instead of the annotated localhost default
+	if config.SwaggerHost != "" {
+		docs.SwaggerInfo.Host = config.SwaggerHost
+	}
+	if len(config.SwaggerSchemes) > 0 {
+		docs.SwaggerInfo.Schemes = config.SwaggerSchemes
+	}
+}
+
+// serveOpenAPIDocument writes the embedded OpenAPI document, the one the Swagger UI serves as doc.json
+func serveOpenAPIDocument(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(docs.SwaggerInfo.ReadDoc()))
+}
+
 // GetRoutesSummary returns a summary of all configured routes
 func GetRoutesSummary(engine *gin.Engine) map[string]interface{} {
 	routes := engine.Routes()
-	
+
 	summary := map[string]interface{}{
 		"total_routes": len(routes),
 		"routes_by_method": make(map[string]int),
@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +16,7 @@ import (
 type Server struct {
 	container  *container.Container
 	engine     *gin.Engine
+	handler    http.Handler // engine behind the trailing-slash policy
 	httpServer *http.Server
 }
 
@@ -49,6 +50,19 @@ func (s *Server) setupEngine() {
 	// Create Gin engine
 	s.engine = gin.New()
 
+	// Trailing-slash policy: /path/ is served as /path by trimTrailingSlash instead of
+	// being redirected, since clients following a 301 may drop the method and body
+	s.engine.RedirectTrailingSlash = false
+	s.engine.RedirectFixedPath = false
+	s.handler = trimTrailingSlash(s.engine)
+
+	// Only trust forwarding headers from the configured proxies, so c.ClientIP() is the real client
+	// An empty list trusts no proxy and uses the remote address
+	if err := s.engine.SetTrustedProxies(s.container.Config().TrustedProxies); err != nil {
+		slog.Error("Invalid trusted proxies", slog.String("error", err.Error()))
+		panic(fmt.Sprintf("invalid trusted proxies: %v", err))
+	}
+
 	// Add global middleware
 	s.setupMiddleware()
 
@@ -60,7 +74,22 @@ func (s *Server) setupEngine() {
 
 // setupMiddleware configures all middleware for the server
 func (s *Server) setupMiddleware() {
-	// Recovery middleware (should be first)
+	// Request ID middleware (first, so every response including recovered panics carries one)
+	s.engine.Use(middleware.RequestID())
+
+	// Response envelope selection (ahead of everything that writes a response)
+	s.engine.Use(middleware.ResponseEnvelope(s.container.Config().ResponseEnvelope))
+
+	// Request-level description limit, enforced while binding request bodies
+	s.engine.Use(middleware.MaxDescriptionLength(s.container.Config().MaxDescriptionLength))
+
+	// Request-level batch size limit, enforced while binding batch request bodies
+	s.engine.Use(middleware.MaxBatchSize(s.container.Config().MaxBatchSize))
+
+	// User-facing error messages from MESSAGES_FILE, applied to every error response
+	s.engine.Use(middleware.ErrorMessages(s.container.ErrorMessages()))
+
+	// Recovery middleware (ahead of everything that can panic)
 	s.engine.Use(middleware.ErrorHandler())
 
 	// Logging middleware
@@ -87,17 +116,14 @@ func (s *Server) Start() error {
 	config := s.container.Config()
 	
 	// Create HTTP server
-	s.httpServer = &http.Server{
-		Addr:         ":" + config.Port,
-		Handler:      s.engine,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
+	s.httpServer = s.newHTTPServer()
 
 	slog.Info("Starting HTTP server",
 		slog.String("port", config.Port),
 		slog.String("address", s.httpServer.Addr),
+		slog.Duration("read_timeout", s.httpServer.ReadTimeout),
+		slog.Duration("write_timeout", s.httpServer.WriteTimeout),
+		slog.Duration("idle_timeout", s.httpServer.IdleTimeout),
 	)
 
 	// Start server in a goroutine so it doesn't block
@@ -111,6 +137,20 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// newHTTPServer creates the http.Server for Start with the configured timeouts
+// ReadTimeout bounds reading a whole request (headers and body), which guards against slow
+// clients; WriteTimeout bounds writing the response, except on routes using NoWriteTimeout
+func (s *Server) newHTTPServer() *http.Server {
+	config := s.container.Config()
+	return &http.Server{
+		Addr:         ":" + config.Port,
+		Handler:      s.handler,
+		ReadTimeout:  config.HTTPReadTimeout,
+		WriteTimeout: config.HTTPWriteTimeout,
+		IdleTimeout:  config.HTTPIdleTimeout,
+	}
+}
+
 // Stop gracefully shuts down the HTTP server
 func (s *Server) Stop(ctx context.Context) error {
 	if s.httpServer == nil {
@@ -134,6 +174,29 @@ func (s *Server) Engine() *gin.Engine {
 	return s.engine
 }
 
+// Handler returns the HTTP handler served by Start: the engine behind the trailing-slash policy
+// Requests sent to Engine directly bypass that policy, so tests of it should use Handler
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// trimTrailingSlash serves requests for /path/ as /path, so both forms reach the same route with
+// the same response instead of a redirect. The root path "/" is left unchanged
+func trimTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path := r.URL.Path; len(path) > 1 && strings.HasSuffix(path, "/") {
+			r.URL.Path = strings.TrimRight(path, "/")
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			if r.URL.RawPath != "" {
+				r.URL.RawPath = strings.TrimRight(r.URL.RawPath, "/")
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Container returns the dependency injection container
 func (s *Server) Container() *container.Container {
 	return s.container
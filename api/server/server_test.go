@@ -2,9 +2,18 @@ package server
 
 import (
 	"discovery-tree/api/container"
+	"discovery-tree/docs"
+	"discovery-tree/domain"
+	"encoding/json"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -230,4 +239,695 @@ func TestGetRoutesSummary(t *testing.T) {
 	assert.True(t, ok)
 	assert.Greater(t, methodCounts["GET"], 0)
 	assert.Greater(t, methodCounts["POST"], 0)
-}
\ No newline at end of file
+}
+func TestServer_CustomAPIBasePath(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	// Create a test container mounted behind a proxy prefix
+	config := &container.Config{
+		Port:          "8080",
+		DataPath:      t.TempDir() + "/tasks.json",
+		LogLevel:      "info",
+		EnableCORS:    true,
+		EnableSwagger: false,
+		APIBasePath:   "/discovery/api/v1",
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	// Create server
+	server := NewServer(testContainer)
+
+	// Create a root task at the prefixed path
+	body := strings.NewReader(`{"description": "Prefixed root"}`)
+	req, err := http.NewRequest("POST", "/discovery/api/v1/tasks/root", body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "Prefixed root")
+
+	// The unprefixed path is no longer routed
+	req, err = http.NewRequest("GET", "/api/v1/tasks/root", nil)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	server.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// The health check shares the proxy prefix
+	req, err = http.NewRequest("GET", "/discovery/health", nil)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	server.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestServer_TrustedProxies(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	// Create a test container trusting only the load balancer subnet
+	config := &container.Config{
+		Port:           "8080",
+		DataPath:       t.TempDir() + "/tasks.json",
+		LogLevel:       "info",
+		EnableCORS:     true,
+		EnableSwagger:  false,
+		TrustedProxies: []string{"10.0.0.0/8"},
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	// Create server with a route echoing the resolved client IP
+	server := NewServer(testContainer)
+	server.Engine().GET("/client-ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	// A forwarded header from a trusted proxy resolves to the real client
+	req, err := http.NewRequest("GET", "/client-ip", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "10.1.2.3:4567"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w := httptest.NewRecorder()
+	server.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "203.0.113.7", w.Body.String())
+
+	// The same header from an untrusted peer is ignored
+	req, err = http.NewRequest("GET", "/client-ip", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "198.51.100.9:4567"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w = httptest.NewRecorder()
+	server.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "198.51.100.9", w.Body.String())
+}
+
+func TestServer_SwaggerHostAndSchemes(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	// The served spec is a package-level value, so restore it for other tests
+	originalHost, originalSchemes := docs.SwaggerInfo.Host, docs.SwaggerInfo.Schemes
+	defer func() {
+		docs.SwaggerInfo.Host, docs.SwaggerInfo.Schemes = originalHost, originalSchemes
+	}()
+
+	// Create a test container with Swagger pointed at a staging domain
+	config := &container.Config{
+		Port:           "8080",
+		DataPath:       t.TempDir() + "/tasks.json",
+		LogLevel:       "info",
+		EnableCORS:     true,
+		EnableSwagger:  true,
+		SwaggerHost:    "staging.example.com",
+		SwaggerSchemes: []string{"https"},
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	// Create server
+	server := NewServer(testContainer)
+
+	// Fetch the served OpenAPI document
+	req, err := http.NewRequest("GET", "/api/v1/docs/doc.json", nil)
+	require.NoError(t, err)
+	req.RequestURI = "/api/v1/docs/doc.json" // gin-swagger routes on the raw request URI
+	w := httptest.NewRecorder()
+	server.Engine().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		Host    string   `json:"host"`
+		Schemes []string `json:"schemes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "staging.example.com", spec.Host)
+	assert.Equal(t, []string{"https"}, spec.Schemes)
+}
+
+func TestServer_OpenAPIJSONWithoutSwaggerUI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The served spec is a package-level value, so restore it for other tests
+	originalBasePath := docs.SwaggerInfo.BasePath
+	defer func() { docs.SwaggerInfo.BasePath = originalBasePath }()
+
+	newServer := func(enableOpenAPIJSON bool) *Server {
+		config := &container.Config{
+			Port:              "8080",
+			DataPath:          t.TempDir() + "/tasks.json",
+			LogLevel:          "info",
+			EnableSwagger:     false,
+			EnableOpenAPIJSON: enableOpenAPIJSON,
+		}
+		testContainer, err := container.NewContainer(config)
+		require.NoError(t, err)
+		t.Cleanup(func() { testContainer.Shutdown() })
+		return NewServer(testContainer)
+	}
+	get := func(server *Server, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	// The raw spec is served while the UI stays disabled
+	server := newServer(true)
+	w := get(server, "/api/v1/openapi.json")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var spec struct {
+		Swagger string                 `json:"swagger"`
+		Info    map[string]interface{} `json:"info"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "2.0", spec.Swagger)
+	assert.Equal(t, "Discovery Tree API", spec.Info["title"])
+	assert.Contains(t, spec.Paths, "/api/v1/tasks")
+	assert.Contains(t, spec.Paths, "/api/v1/tasks/{id}")
+
+	assert.Equal(t, http.StatusNotFound, get(server, "/api/v1/docs/index.html").Code)
+	assert.Equal(t, http.StatusNotFound, get(server, "/api/v1/docs").Code)
+
+	// Without either option nothing is served
+	assert.Equal(t, http.StatusNotFound, get(newServer(false), "/api/v1/openapi.json").Code)
+}
+
+func TestServer_OpenAPIDocumentCoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:              "8080",
+		DataPath:          t.TempDir() + "/tasks.json",
+		LogLevel:          "info",
+		EnableSwagger:     true,
+		EnableOpenAPIJSON: true,
+	}
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+	server := NewServer(testContainer)
+
+	req := httptest.NewRequest("GET", "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+
+	// Every API route is documented under its method, except the documentation routes themselves
+	for _, route := range server.Engine().Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") || route.Path == "/api/v1/openapi.json" || strings.HasPrefix(route.Path, "/api/v1/docs") {
+			continue
+		}
+		path := regexp.MustCompile(`:(\w+)`).ReplaceAllString(route.Path, "{$1}")
+		operations, ok := spec.Paths[path]
+		if assert.True(t, ok, "route %s %s is missing from the OpenAPI document", route.Method, route.Path) {
+			assert.Contains(t, operations, strings.ToLower(route.Method), "route %s %s is missing from the OpenAPI document", route.Method, route.Path)
+		}
+	}
+}
+
+func TestServer_TrailingSlashPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:          "8080",
+		DataPath:      t.TempDir() + "/tasks.json",
+		LogLevel:      "info",
+		EnableSwagger: true,
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+
+	_, err = testContainer.TaskService().CreateRootTask("Root")
+	require.NoError(t, err)
+
+	// Both forms of the tasks collection are the same route, without a redirect
+	var bodies []string
+	for _, path := range []string{"/api/v1/tasks", "/api/v1/tasks/"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.Empty(t, w.Header().Get("Location"), path)
+		bodies = append(bodies, w.Body.String())
+	}
+	assert.Equal(t, bodies[0], bodies[1])
+	assert.Contains(t, bodies[0], "Root")
+
+	// A POST keeps its method and body instead of being redirected
+	for _, path := range []string{"/api/v1/tasks", "/api/v1/tasks/"} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"description": ""}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+
+	// The bare docs path still opens the Swagger UI
+	for _, path := range []string{"/api/v1/docs", "/api/v1/docs/"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code, path)
+		assert.Equal(t, "/api/v1/docs/index.html", w.Header().Get("Location"), path)
+	}
+}
+
+func TestServer_WrappedResponseEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:             "8080",
+		DataPath:         t.TempDir() + "/tasks.json",
+		LogLevel:         "info",
+		ResponseEnvelope: "wrapped",
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+
+	root, err := testContainer.TaskService().CreateRootTask("Root")
+	require.NoError(t, err)
+	_, err = testContainer.TaskService().CreateChildTask("Child", root.ID())
+	require.NoError(t, err)
+
+	serve := func(path string) map[string]interface{} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+
+		var envelope map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+		assert.Contains(t, envelope, "data")
+		assert.Contains(t, envelope, "meta")
+		assert.Contains(t, envelope, "error")
+		return envelope
+	}
+
+	// A list is nested under data and counted in meta
+	envelope := serve("/api/v1/tasks")
+	tasks, ok := envelope["data"].([]interface{})
+	require.True(t, ok, "expected a task list under data")
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, float64(2), envelope["meta"].(map[string]interface{})["count"])
+	assert.Nil(t, envelope["error"])
+
+	// A single object is nested under data without a count
+	envelope = serve("/api/v1/tasks/" + root.ID().String())
+	task, ok := envelope["data"].(map[string]interface{})
+	require.True(t, ok, "expected a task under data")
+	assert.Equal(t, "Root", task["description"])
+	assert.NotContains(t, envelope["meta"], "count")
+	assert.Nil(t, envelope["error"])
+
+	// Errors are nested under error with null data
+	envelope = serve("/api/v1/tasks/" + domain.NewTaskID().String())
+	assert.Nil(t, envelope["data"])
+	errorBody, ok := envelope["error"].(map[string]interface{})
+	require.True(t, ok, "expected an error object")
+	assert.Equal(t, "NotFoundError", errorBody["error"])
+}
+
+func TestServer_ReadTimeoutDropsSlowClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:            "8080",
+		DataPath:        t.TempDir() + "/tasks.json",
+		LogLevel:        "error",
+		HTTPReadTimeout: 200 * time.Millisecond,
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+	httpServer := server.newHTTPServer()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go httpServer.Serve(listener)
+	defer httpServer.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Announce a body but only send part of it, then stall
+	_, err = conn.Write([]byte("POST /api/v1/tasks HTTP/1.1\r\nHost: localhost\r\n" +
+		"Content-Type: application/json\r\nContent-Length: 100\r\n\r\n{\"descr"))
+	require.NoError(t, err)
+
+	// The server gives up on the request well before our own deadline
+	started := time.Now()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = io.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(started), 3*time.Second)
+
+	count, err := testContainer.TaskRepository().Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestServer_MaxDescriptionLength(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:                 "8080",
+		DataPath:             t.TempDir() + "/tasks.json",
+		LogLevel:             "error",
+		MaxDescriptionLength: 10,
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	// One character over the limit is rejected before the service creates anything
+	w := post("/api/v1/tasks/root", `{"description": "Eleven char"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "INVALID_REQUEST", response["code"])
+	assert.Equal(t, "Field 'Description' must be at most 10 characters long", response["message"])
+	count, err := testContainer.TaskRepository().Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// Exactly at the limit, counted in characters rather than bytes, is accepted
+	w = post("/api/v1/tasks/root", `{"description": "Ten chars✓"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// Child creation enforces the same limit
+	w = post("/api/v1/tasks", `{"description": "Far too long for it", "parentId": "`+response["id"].(string)+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestServer_MaxBatchSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:         "8080",
+		DataPath:     t.TempDir() + "/tasks.json",
+		LogLevel:     "error",
+		MaxBatchSize: 3,
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, strings.NewReader(string(jsonBody)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	// Create tree: root -> A, B, C, D, target
+	service := testContainer.TaskService()
+	root, err := service.CreateRootTask("Root")
+	require.NoError(t, err)
+	var ids []string
+	for _, description := range []string{"A", "B", "C", "D"} {
+		task, err := service.CreateChildTask(description, root.ID())
+		require.NoError(t, err)
+		ids = append(ids, task.ID().String())
+	}
+	target, err := service.CreateChildTask("Target", root.ID())
+	require.NoError(t, err)
+	targetID := target.ID()
+
+	// One over the limit is rejected before anything moves
+	w := post("/api/v1/tasks/move-many", map[string]interface{}{"ids": ids, "parentId": target.ID().String()})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "INVALID_REQUEST", response["code"])
+	assert.Equal(t, "Field 'IDs' must have at most 3 entries", response["message"])
+	count, err := testContainer.TaskRepository().CountByParentID(&targetID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// The other batch endpoints enforce the same limit
+	w = post("/api/v1/tasks/nudge", map[string]interface{}{"ids": ids, "direction": "down"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post("/api/v1/tasks/group", map[string]interface{}{"childIds": ids, "description": "Group", "parentId": root.ID().String()})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Exactly at the limit is accepted
+	w = post("/api/v1/tasks/move-many", map[string]interface{}{"ids": ids[:3], "parentId": target.ID().String()})
+	assert.Equal(t, http.StatusOK, w.Code)
+	count, err = testContainer.TaskRepository().CountByParentID(&targetID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestServer_MessagesFileOverridesErrorMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	messagesFile := t.TempDir() + "/messages.json"
+	messages := `{"single_root": "This tree already has a root task.", "unknown-include": "Pick children, ancestors, siblings or readiness."}`
+	require.NoError(t, os.WriteFile(messagesFile, []byte(messages), 0644))
+
+	config := &container.Config{
+		Port:         "8080",
+		DataPath:     t.TempDir() + "/tasks.json",
+		LogLevel:     "error",
+		MessagesFile: messagesFile,
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/v1/tasks/root", `{"description": "Root"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var root map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &root))
+
+	// The second root is rejected with the configured message under the same code
+	w = post("/api/v1/tasks/root", `{"description": "Another root"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "single_root", response["code"])
+	assert.Equal(t, "This tree already has a root task.", response["message"])
+
+	// Codes without an override keep their default message
+	w = post("/api/v1/tasks/root", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEqual(t, "This tree already has a root task.", response["message"])
+
+	// Validation errors are keyed by their rule, so other checks on query parameters keep theirs
+	get := func(path string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	response = get("/api/v1/tasks/" + root["id"].(string) + "?include=grandparents")
+	assert.Equal(t, "unknown-include", response["code"])
+	assert.Equal(t, "include", response["field"])
+	assert.Equal(t, "Pick children, ancestors, siblings or readiness.", response["message"])
+	response = get("/api/v1/tasks/" + root["id"].(string) + "/children?sort=priority")
+	assert.Equal(t, "unknown-sort", response["code"])
+	assert.Contains(t, response["message"], "priority")
+
+	// Another server in the same process keeps the default messages
+	otherContainer, err := container.NewContainer(&container.Config{
+		Port:     "8080",
+		DataPath: t.TempDir() + "/tasks.json",
+		LogLevel: "error",
+	})
+	require.NoError(t, err)
+	defer otherContainer.Shutdown()
+	_, err = otherContainer.TaskService().CreateRootTask("Root")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/tasks/root", strings.NewReader(`{"description": "Another root"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	NewServer(otherContainer).Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "root task already exists", response["message"])
+}
+
+func TestServer_MalformedPathIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	config := &container.Config{
+		Port:     "8080",
+		DataPath: t.TempDir() + "/tasks.json",
+		LogLevel: "error",
+	}
+
+	testContainer, err := container.NewContainer(config)
+	require.NoError(t, err)
+	defer testContainer.Shutdown()
+
+	server := NewServer(testContainer)
+
+	// Every route taking a task ID in its path
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/v1/tasks/:id"},
+		{"PUT", "/api/v1/tasks/:id"},
+		{"DELETE", "/api/v1/tasks/:id"},
+		{"PUT", "/api/v1/tasks/:id/status"},
+		{"GET", "/api/v1/tasks/:id/status/options"},
+		{"PUT", "/api/v1/tasks/:id/move"},
+		{"GET", "/api/v1/tasks/:id/children"},
+		{"GET", "/api/v1/tasks/:id/root"},
+		{"GET", "/api/v1/tasks/:id/siblings/count"},
+		{"GET", "/api/v1/tasks/:id/position"},
+		{"GET", "/api/v1/tasks/:id/activity"},
+		{"GET", "/api/v1/tasks/:id/blockers"},
+		{"POST", "/api/v1/tasks/:id/ungroup"},
+		{"POST", "/api/v1/tasks/:id/promote-root"},
+		{"GET", "/api/v1/tasks/:id/history"},
+		{"GET", "/api/v1/tasks/:id/export"},
+		{"POST", "/api/v1/tasks/:id/toggle"},
+		{"POST", "/api/v1/tasks/:id/tags/subtree"},
+		{"POST", "/api/v1/tasks/:id/reset"},
+		{"PUT", "/api/v1/tasks/:id/kind"},
+		{"GET", "/api/v1/tasks/:id/timeline"},
+		{"POST", "/api/v1/tasks/archive/:id/restore"},
+	}
+
+	// A route added with an ID parameter must be listed above
+	listed := make(map[string]bool)
+	for _, route := range routes {
+		listed[route.method+" "+route.path] = true
+	}
+	for _, route := range server.GetRoutes() {
+		if strings.Contains(route.Path, ":") {
+			assert.True(t, listed[route.Method+" "+route.Path], "route %s %s takes a path parameter but is not covered", route.Method, route.Path)
+		}
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			// The body is valid JSON for every route, so only the ID can be at fault
+			path := strings.Replace(route.path, ":id", "not-a-uuid", 1)
+			req := httptest.NewRequest(route.method, path, strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.Handler().ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "ValidationError", response["error"])
+			assert.Equal(t, "INVALID_UUID", response["code"])
+			assert.Equal(t, "Field 'id' must be a valid UUID or short ID", response["message"])
+		})
+	}
+}
+
+func TestServer_NonCanonicalPathIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, scheme := range []string{"uuid", "short"} {
+		t.Run(scheme, func(t *testing.T) {
+			config := &container.Config{
+				Port:     "8080",
+				DataPath: t.TempDir() + "/tasks.json",
+				LogLevel: "error",
+				IDScheme: scheme,
+			}
+
+			testContainer, err := container.NewContainer(config)
+			require.NoError(t, err)
+			defer testContainer.Shutdown()
+
+			server := NewServer(testContainer)
+
+			req := httptest.NewRequest("POST", "/api/v1/tasks/root", strings.NewReader(`{"description": "Root"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.Handler().ServeHTTP(w, req)
+			require.Equal(t, http.StatusCreated, w.Code)
+			var created map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+			id := created["id"].(string)
+
+			// An uppercase ID finds the same task, reported in canonical form
+			req = httptest.NewRequest("GET", "/api/v1/tasks/"+strings.ToUpper(id), nil)
+			w = httptest.NewRecorder()
+			server.Handler().ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			var found map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+			assert.Equal(t, id, found["id"])
+		})
+	}
+}
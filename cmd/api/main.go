@@ -6,8 +6,65 @@
 //   - PORT: HTTP server port (default: 8080)
 //   - DATA_PATH: Path to JSON file for task persistence (default: ./data/tasks.json)
 //   - LOG_LEVEL: Logging level - debug, info, warn, error (default: info)
+//   - LOG_FORMAT: Log output format - text, json (default: json in release mode, text otherwise)
 //   - ENABLE_CORS: Enable Cross-Origin Resource Sharing (default: true)
 //   - ENABLE_SWAGGER: Enable Swagger/OpenAPI documentation (default: true)
+//   - ENABLE_OPENAPI_JSON: Serve the OpenAPI document at {API_BASE_PATH}/openapi.json even with ENABLE_SWAGGER off (default: false)
+//   - POSITION_BASE: Index of the first sibling position in the API, 0 or 1 (default: 0)
+//   - TRIM_DESCRIPTIONS: Trim leading/trailing whitespace from descriptions (default: false)
+//   - SEED_FILE: Nested-tree JSON file imported when the task store is empty (default: none)
+//   - DONE_PARENT_POLICY: Creating a child under a DONE parent - reject, reopen (default: reject)
+//   - API_BASE_PATH: Prefix of the versioned API routes (default: /api/v1)
+//   - MAX_TOTAL_TASKS: Maximum number of tasks in the store, 0 for unlimited (default: 0)
+//   - PERSIST_DEBOUNCE: Delay before each batched write so changes within it share one write; requires PERSIST_COALESCE (default: 0)
+//   - MESSAGES_FILE: JSON file mapping error codes to the user-facing messages shown instead of the defaults (default: none)
+//   - CROSS_ROOT_MOVES: Whether a task may be moved into another root's tree - allow, deny (default: allow)
+//   - STATUS_COLORS: Comma-separated Name=#color overrides of the colors served by GET /statuses (default: none)
+//   - MAX_INFLIGHT: Maximum concurrent API requests; more are rejected with 503, 0 for unlimited (default: 0)
+//   - CHILD_INSERT_MODE: Where a new child goes when no position is given - append, prepend (default: append)
+//   - DATA_FILE_MINIFY: Write the data file without indentation (default: false)
+//   - HEALTH_CHECK_TIMEOUT: Maximum time the health check waits on storage before returning 503, 0 for none (default: 2s)
+//   - REOPEN_STATUS: Status a DONE task is reopened to by child creation, reconcile moves and imports (default: In Progress)
+//   - COMPLETE_STATUSES: Comma-separated statuses that count as complete for readiness, bottom-to-top completion and reopening (default: DONE)
+//   - STRICT_TIMESTAMPS: Reject loaded or imported tasks with zero timestamps or updatedAt before createdAt instead of correcting them (default: false)
+//   - TOUCH_ANCESTORS_ON_CHANGE: Tasks whose updatedAt and seq bump when a child is created, deleted or moved - off, parent, all (default: off)
+//   - CANONICALIZE_WHITESPACE: Collapse whitespace runs inside descriptions to single spaces (default: false)
+//   - MAX_BATCH_SIZE: Maximum number of task IDs accepted by one bulk move, nudge or group request, 0 for unlimited (default: 500)
+//   - ENCRYPTION_KEY: Base64 encoded AES key (16, 24 or 32 bytes) to encrypt the data file with AES-GCM (default: unset, plaintext)
+//   - CHILD_DESCRIPTION_TEMPLATE: Description for children created with a blank one, {parent} is replaced with the parent's description (default: unset, blank is rejected)
+//   - MOVE_CLAMP_POSITION: Move a task given a position past its last sibling to the end instead of rejecting the move (default: false)
+//   - SNAPSHOT_READS: Serve reads from a copy of the tasks swapped in after each successful write, so reads never wait for writes (default: false)
+//   - STATUS_WEIGHTS: Comma-separated Name=weight shares (0 to 1) of a task counted as done by the weighted progress of GET /stats (default: none, complete statuses weigh 1)
+//   - ARCHIVE_ON_COMPLETE: Move a top-level branch to the archive once its whole subtree is DONE (default: false)
+//   - ARCHIVE_PATH: Path to JSON file holding archived branches (default: DATA_PATH with .archive.json as extension)
+//   - REQUIRE_DECOMPOSITION: Reject completing a task tagged with a DECOMPOSITION_TAGS tag until it has enough children (default: false)
+//   - DECOMPOSITION_TAGS: Comma-separated tags of tasks that must be broken down before they are DONE (default: epic)
+//   - DECOMPOSITION_MIN_CHILDREN: Children a tagged task needs before it can be DONE (default: 1)
+//   - DECOMPOSITION_KINDS: Comma-separated kinds of tasks that must be broken down before they are DONE (default: none)
+//   - TASK_KINDS: Comma-separated kinds a task can be given; must include "task", the default kind (default: task,spike,milestone)
+//   - DELETE_GUARD: Deleting incomplete tasks - cascade, or block unless ?force=true (default: cascade)
+//   - STRICT_SINGLE_ROOT: Fail startup if the data file contains more than one root task (default: false)
+//   - PERSIST_MAX_RETRIES: Retries for transient write failures (default: 3)
+//   - PERSIST_RETRY_BACKOFF: Delay before the first write retry, doubled on each retry (default: 50ms)
+//   - MOVE_HISTORY_LIMIT: Moves kept in each task's history, 0 for unlimited (default: 50)
+//   - STATUS_HISTORY_LIMIT: Status changes kept in each task's timeline, 0 for unlimited (default: 50)
+//   - SWAGGER_HOST: Host served in the Swagger/OpenAPI document (default: localhost:8080)
+//   - SWAGGER_SCHEMES: Comma-separated schemes served in the Swagger/OpenAPI document (default: http,https)
+//   - AUTO_CREATE_ROOT: Description of a root task created on first GET /tasks/root if none exists (default: none)
+//   - TOGGLE_STATUSES: Comma-separated off,on status pair flipped by POST /tasks/{id}/toggle (default: TODO,DONE)
+//   - PERSIST_COALESCE: Batch concurrent writes into one background file write (default: false)
+//   - RESPONSE_ENVELOPE: Response shape - bare, wrapped in {data, meta, error} (default: bare)
+//   - ID_SCHEME: Format of new task IDs - uuid, short (default: uuid)
+//   - MAX_SUBTREE_OPERATION: Maximum tasks a subtree delete or tag may touch, 0 for unlimited (default: 10000)
+//   - FILE_LOCK: Lock the data file so a second server on the same DATA_PATH fails to start (default: true)
+//   - STARTUP_SELFTEST: Create, read back and delete a scratch task at startup, failing if any step errors (default: false)
+//   - HTTP_READ_TIMEOUT: Maximum time to read a whole request, headers and body, 0 for none (default: 15s)
+//   - HTTP_WRITE_TIMEOUT: Maximum time to write a response, 0 for none; the export stream is exempt (default: 15s)
+//   - HTTP_IDLE_TIMEOUT: Maximum time to keep an idle keep-alive connection open, 0 for none (default: 60s)
+//   - NORMALIZE_ON_WRITE: Renumber touched siblings to contiguous positions on every write (default: false)
+//   - TEMPLATE_DIR: Directory of nested-tree JSON templates for POST /tasks/from-template (default: none)
+//   - MAX_DESCRIPTION_LENGTH: Maximum description length in characters accepted by request bodies, 0 for unlimited (default: 0)
+//   - TRUSTED_PROXIES: Comma-separated proxy IPs/CIDRs whose X-Forwarded-For is trusted (default: 127.0.0.1/32,::1/128)
 //
 // Example usage:
 //   export PORT=3000
@@ -32,12 +89,18 @@ package main
 import (
 	"context"
 	"discovery-tree/api/container"
+	"discovery-tree/api/handlers"
+	"discovery-tree/api/middleware"
 	"discovery-tree/api/server"
+	"discovery-tree/domain"
+	"discovery-tree/infrastructure"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -71,6 +134,7 @@ func main() {
 		slog.String("log_level", config.LogLevel),
 		slog.Bool("cors_enabled", config.EnableCORS),
 		slog.Bool("swagger_enabled", config.EnableSwagger),
+		slog.String("trusted_proxies", strings.Join(config.TrustedProxies, ",")),
 	)
 	
 	if err := srv.Start(); err != nil {
@@ -118,6 +182,11 @@ func validateConfig(config *container.Config) error {
 		return fmt.Errorf("data path cannot be empty")
 	}
 	
+	// Validate archive path is not the data file
+	if config.ArchivePath != "" && filepath.Clean(config.ArchivePath) == filepath.Clean(config.DataPath) {
+		return fmt.Errorf("invalid archive path: %s (must differ from the data path)", config.ArchivePath)
+	}
+	
 	// Validate log level is valid
 	validLogLevels := map[string]bool{
 		"debug": true,
@@ -130,6 +199,204 @@ func validateConfig(config *container.Config) error {
 		return fmt.Errorf("invalid log level: %s (must be one of: debug, info, warn, error)", config.LogLevel)
 	}
 	
+	// Validate log format is valid (empty selects the mode-based default)
+	if config.LogFormat != "" && config.LogFormat != "text" && config.LogFormat != "json" {
+		return fmt.Errorf("invalid log format: %s (must be one of: text, json)", config.LogFormat)
+	}
+	
+	// Validate position base is 0 or 1
+	if config.PositionBase != 0 && config.PositionBase != 1 {
+		return fmt.Errorf("invalid position base: %d (must be 0 or 1)", config.PositionBase)
+	}
+	
+	// Validate done-parent policy is known
+	if !domain.DoneParentPolicy(config.DoneParentPolicy).IsValid() {
+		return fmt.Errorf("invalid done parent policy: %s (must be one of: reject, reopen)", config.DoneParentPolicy)
+	}
+	
+	// Validate delete guard is known
+	if !domain.DeleteGuard(config.DeleteGuard).IsValid() {
+		return fmt.Errorf("invalid delete guard: %s (must be one of: cascade, block)", config.DeleteGuard)
+	}
+	
+	// Validate child insert mode is known
+	if !domain.ChildInsertMode(config.ChildInsertMode).IsValid() {
+		return fmt.Errorf("invalid child insert mode: %s (must be one of: append, prepend)", config.ChildInsertMode)
+	}
+	
+	// Validate ancestor touching mode is known
+	if !domain.TouchAncestorsMode(config.TouchAncestors).IsValid() {
+		return fmt.Errorf("invalid touch ancestors mode: %s (must be one of: off, parent, all)", config.TouchAncestors)
+	}
+	
+	// Validate cross-root move policy is known
+	if !domain.CrossRootMovePolicy(config.CrossRootMoves).IsValid() {
+		return fmt.Errorf("invalid cross-root moves policy: %s (must be one of: allow, deny)", config.CrossRootMoves)
+	}
+	
+	// Validate in-flight limit is not negative
+	if config.MaxInFlight < 0 {
+		return fmt.Errorf("invalid max in-flight requests: %d (must not be negative, 0 for unlimited)", config.MaxInFlight)
+	}
+	
+	// Validate task kinds are valid names including the default kind
+	if err := validateKinds(config.TaskKinds, config.TaskKinds); err != nil {
+		return fmt.Errorf("invalid task kinds: %w", err)
+	}
+	if err := validateKinds([]string{domain.DefaultTaskKind}, config.TaskKinds); err != nil {
+		return fmt.Errorf("invalid task kinds: %w (the default kind of new tasks must be included)", err)
+	}
+	
+	// Validate decomposition tags are valid tags, decomposition kinds are task kinds, and the minimum is at least one child
+	if config.RequireDecomposition {
+		if len(config.DecompositionTags) == 0 && len(config.DecompositionKinds) == 0 {
+			return fmt.Errorf("decomposition tags and kinds cannot both be empty when decomposition is required")
+		}
+		if _, err := domain.NormalizeTags(config.DecompositionTags); err != nil {
+			return fmt.Errorf("invalid decomposition tags: %w", err)
+		}
+		if err := validateKinds(config.DecompositionKinds, config.TaskKinds); err != nil {
+			return fmt.Errorf("invalid decomposition kinds: %w", err)
+		}
+		if config.DecompositionMin < 1 {
+			return fmt.Errorf("invalid decomposition minimum children: %d (must be at least 1)", config.DecompositionMin)
+		}
+	}
+	
+	// Validate status weights name known statuses and weights from 0 to 1
+	if _, err := domain.ParseStatusWeights(config.StatusWeights); err != nil {
+		return err
+	}
+	
+	// Validate status colors name known statuses and hex colors
+	if _, err := handlers.ParseStatusColors(config.StatusColors); err != nil {
+		return err
+	}
+	
+	// Validate API base path is absolute
+	if !strings.HasPrefix(config.APIBasePath, "/") {
+		return fmt.Errorf("invalid API base path: %s (must start with /)", config.APIBasePath)
+	}
+	
+	// Validate task limit is not negative
+	if config.MaxTotalTasks < 0 {
+		return fmt.Errorf("invalid max total tasks: %d (must not be negative, 0 for unlimited)", config.MaxTotalTasks)
+	}
+	
+	// Validate description length limit is not negative
+	if config.MaxDescriptionLength < 0 {
+		return fmt.Errorf("invalid max description length: %d (must not be negative, 0 for unlimited)", config.MaxDescriptionLength)
+	}
+	if config.MaxBatchSize < 0 {
+		return fmt.Errorf("invalid max batch size: %d (must not be negative, 0 for unlimited)", config.MaxBatchSize)
+	}
+	
+	// Validate subtree operation limit is not negative
+	if config.MaxSubtreeOperation < 0 {
+		return fmt.Errorf("invalid max subtree operation: %d (must not be negative, 0 for unlimited)", config.MaxSubtreeOperation)
+	}
+	
+	// Validate move history limit is not negative
+	if config.MoveHistoryLimit < 0 {
+		return fmt.Errorf("invalid move history limit: %d (must not be negative, 0 for unlimited)", config.MoveHistoryLimit)
+	}
+	
+	// Validate status history limit is not negative
+	if config.StatusHistoryLimit < 0 {
+		return fmt.Errorf("invalid status history limit: %d (must not be negative, 0 for unlimited)", config.StatusHistoryLimit)
+	}
+	
+	// Validate persist retry settings
+	if config.PersistMaxRetries < 0 {
+		return fmt.Errorf("invalid persist max retries: %d (must not be negative)", config.PersistMaxRetries)
+	}
+	if config.PersistRetryBackoff < 0 {
+		return fmt.Errorf("invalid persist retry backoff: %s (must not be negative)", config.PersistRetryBackoff)
+	}
+	if config.PersistDebounce < 0 {
+		return fmt.Errorf("invalid persist debounce: %s (must not be negative, 0 for none)", config.PersistDebounce)
+	}
+	if config.PersistDebounce > 0 && !config.PersistCoalesce {
+		return fmt.Errorf("persist debounce %s requires PERSIST_COALESCE (the debounce only applies to the background writer)", config.PersistDebounce)
+	}
+	
+	// Validate HTTP timeouts are not negative
+	if config.HTTPReadTimeout < 0 {
+		return fmt.Errorf("invalid HTTP read timeout: %s (must not be negative, 0 for none)", config.HTTPReadTimeout)
+	}
+	if config.HTTPWriteTimeout < 0 {
+		return fmt.Errorf("invalid HTTP write timeout: %s (must not be negative, 0 for none)", config.HTTPWriteTimeout)
+	}
+	if config.HTTPIdleTimeout < 0 {
+		return fmt.Errorf("invalid HTTP idle timeout: %s (must not be negative, 0 for none)", config.HTTPIdleTimeout)
+	}
+	if config.HealthCheckTimeout < 0 {
+		return fmt.Errorf("invalid health check timeout: %s (must not be negative, 0 for none)", config.HealthCheckTimeout)
+	}
+	
+	// Validate toggle statuses are two distinct valid statuses
+	if len(config.ToggleStatuses) != 2 || config.ToggleStatuses[0] == config.ToggleStatuses[1] {
+		return fmt.Errorf("invalid toggle statuses: %s (must be two distinct statuses, off,on)", strings.Join(config.ToggleStatuses, ","))
+	}
+	for _, name := range config.ToggleStatuses {
+		if _, err := domain.NewStatus(name); err != nil {
+			return fmt.Errorf("invalid toggle status: %s", name)
+		}
+	}
+	
+	// Validate the complete statuses leave a status incomplete and exclude Root Work Item
+	complete, err := domain.ParseCompleteStatuses(config.CompleteStatuses)
+	if err != nil {
+		return fmt.Errorf("invalid complete statuses: %s: %w", strings.Join(config.CompleteStatuses, ","), err)
+	}
+	
+	// Validate the reopen status is a valid incomplete status a task can be set to
+	reopen, err := domain.NewStatus(config.ReopenStatus)
+	if err != nil || complete.Contains(reopen) || reopen == domain.StatusRootWorkItem {
+		return fmt.Errorf("invalid reopen status: %s (must be an incomplete status other than Root Work Item)", config.ReopenStatus)
+	}
+	
+	// Validate the child description template is not blank, if set
+	if config.ChildDescTemplate != "" && strings.TrimSpace(config.ChildDescTemplate) == "" {
+		return fmt.Errorf("invalid child description template: must not be blank")
+	}
+	
+	// Validate the encryption key decodes to an AES key, if set
+	if config.EncryptionKey != "" {
+		if _, err := infrastructure.ParseEncryptionKey(config.EncryptionKey); err != nil {
+			return fmt.Errorf("invalid encryption key: %w", err)
+		}
+	}
+	
+	// Validate response envelope mode is known
+	if config.ResponseEnvelope != middleware.EnvelopeBare && config.ResponseEnvelope != middleware.EnvelopeWrapped {
+		return fmt.Errorf("invalid response envelope: %s (must be one of: bare, wrapped)", config.ResponseEnvelope)
+	}
+	
+	// Validate ID scheme is known
+	if !domain.IDScheme(config.IDScheme).IsValid() {
+		return fmt.Errorf("invalid ID scheme: %s (must be one of: uuid, short)", config.IDScheme)
+	}
+	
+	// Validate Swagger host is a bare host[:port]
+	if strings.Contains(config.SwaggerHost, "/") {
+		return fmt.Errorf("invalid swagger host: %s (must be host[:port] without scheme or path)", config.SwaggerHost)
+	}
+	
+	// Validate Swagger schemes are supported by OpenAPI 2.0
+	for _, scheme := range config.SwaggerSchemes {
+		if scheme != "http" && scheme != "https" && scheme != "ws" && scheme != "wss" {
+			return fmt.Errorf("invalid swagger scheme: %s (must be one of: http, https, ws, wss)", scheme)
+		}
+	}
+	
+	// Validate trusted proxies are IPs or CIDRs
+	for _, proxy := range config.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid trusted proxy: %s (must be an IP or CIDR)", proxy)
+		}
+	}
+	
 	// Ensure data directory exists
 	if err := ensureDataDirectory(config.DataPath); err != nil {
 		return fmt.Errorf("failed to ensure data directory: %w", err)
@@ -138,6 +405,27 @@ func validateConfig(config *container.Config) error {
 	return nil
 }
 
+// validateKinds checks that every kind is a valid kind name listed in allowed
+func validateKinds(kinds, allowed []string) error {
+	for _, name := range kinds {
+		kind, err := domain.NormalizeKind(name)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, candidate := range allowed {
+			if strings.TrimSpace(candidate) == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q is not one of the task kinds %s", kind, strings.Join(allowed, ","))
+		}
+	}
+	return nil
+}
+
 // ensureDataDirectory creates the directory for the data path if it doesn't exist
 func ensureDataDirectory(dataPath string) error {
 	// Extract directory from file path
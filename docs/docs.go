@@ -24,27 +24,53 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/tasks": {
-            "get": {
-                "description": "Retrieves all tasks in the discovery tree",
-                "consumes": [
+        "/api/v1/admin/compact": {
+            "post": {
+                "description": "Renumbers the children of every task to contiguous positions, keeping their order, then rewrites the data file from the in-memory state in one atomic write, indented or minified per DATA_FILE_MINIFY. Reports the file size before and after and how many tasks were renumbered.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Compact the data file",
+                "responses": {
+                    "200": {
+                        "description": "Data file compacted",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompactResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The task store has no data file",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/diagnostics": {
+            "get": {
+                "description": "Read-only report for operators who suspect data corruption: checks every stored task against the tree invariants and lists the violations, plus the tasks whose parent is missing, one task on each cycle, every root when there is not exactly one, and sibling groups sharing a position. Nothing is changed; use it to decide whether to repair the data.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "admin"
                 ],
-                "summary": "Get all tasks",
+                "summary": "Diagnose the task store",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved all tasks",
+                        "description": "Diagnostics report",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.TaskResponse"
-                            }
+                            "$ref": "#/definitions/models.DiagnosticsResponse"
                         }
                     },
                     "500": {
@@ -54,9 +80,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/status/rename": {
             "post": {
-                "description": "Creates a new child task under the specified parent task",
+                "description": "Migration tool for workflow changes: sets every task in status from to status to, in one transaction, and reports how many tasks changed. Both must be valid statuses and differ; Root Work Item can be neither. The rename is rejected if it would leave a complete task with an incomplete child.",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,35 +92,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "admin"
                 ],
-                "summary": "Create child task",
+                "summary": "Rename a status across all tasks",
                 "parameters": [
                     {
-                        "description": "Child task creation request",
+                        "description": "Statuses to rename from and to",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateChildTaskRequest"
+                            "$ref": "#/definitions/models.RenameStatusRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully created child task",
+                    "200": {
+                        "description": "Status renamed",
                         "schema": {
-                            "$ref": "#/definitions/models.TaskResponse"
+                            "$ref": "#/definitions/models.RenameStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid or identical statuses, or Root Work Item",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Parent task not found",
+                    "409": {
+                        "description": "Rename would break bottom-to-top completion",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -106,28 +134,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/root": {
+        "/api/v1/stats": {
             "get": {
-                "description": "Retrieves the root task of the discovery tree",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Counts the tasks of the store, or of the subtree under taskId, per status and reports how much of them is done. percentComplete counts complete tasks; weightedPercentComplete credits each task with its status weight from STATUS_WEIGHTS, so In Progress can count partly. With the default weights both are equal. Root Work Item tasks are not counted, and no counted tasks is 0% done.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "stats"
+                ],
+                "summary": "Get progress statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Limit the statistics to this task's subtree (UUID format)",
+                        "name": "taskId",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get root task",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved root task",
+                        "description": "Successfully computed statistics",
                         "schema": {
-                            "$ref": "#/definitions/models.TaskResponse"
+                            "$ref": "#/definitions/models.StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Root task not found",
+                        "description": "Task not found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -139,61 +179,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new root task for the discovery tree. Only one root task can exist at a time.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/statuses": {
+            "get": {
+                "description": "Returns every valid task status in workflow order with its display name, its color (see STATUS_COLORS) and whether it counts as complete, so all clients render statuses alike. name is the value used in requests and responses.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
-                ],
-                "summary": "Create root task",
-                "parameters": [
-                    {
-                        "description": "Root task creation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateRootTaskRequest"
-                        }
-                    }
+                    "statuses"
                 ],
+                "summary": "List statuses",
                 "responses": {
-                    "201": {
-                        "description": "Successfully created root task",
-                        "schema": {
-                            "$ref": "#/definitions/models.TaskResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request data",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Root task already exists",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "200": {
+                        "description": "Successfully listed statuses",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StatusResponse"
+                            }
                         }
                     }
                 }
             }
         },
-        "/api/v1/tasks/{id}": {
+        "/api/v1/tasks": {
             "get": {
-                "description": "Retrieves a specific task by its unique identifier",
+                "description": "Retrieves all tasks in the discovery tree, optionally only those of one kind",
                 "consumes": [
                     "application/json"
                 ],
@@ -203,32 +216,27 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Get task by ID",
+                "summary": "Get all tasks",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Task ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only return tasks of this kind (e.g. spike)",
+                        "name": "kind",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved task",
+                        "description": "Successfully retrieved all tasks",
                         "schema": {
-                            "$ref": "#/definitions/models.TaskResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid task ID format",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Task not found",
+                        "description": "Invalid kind",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -241,8 +249,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Updates the description of an existing task",
+            "post": {
+                "description": "Creates a new child task under the specified parent task. It is appended after the last child (or placed first with CHILD_INSERT_MODE=prepend) unless a position is given, in which case children at or after it shift right.",
                 "consumes": [
                     "application/json"
                 ],
@@ -252,41 +260,45 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Update task description",
+                "summary": "Create child task",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Task ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Task update request",
+                        "description": "Child task creation request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateTaskRequest"
+                            "$ref": "#/definitions/models.CreateChildTaskRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully updated task",
+                    "201": {
+                        "description": "Successfully created child task",
                         "schema": {
                             "$ref": "#/definitions/models.TaskResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the created task"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request data or task ID format",
+                        "description": "Invalid request data or position out of range",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Parent task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No root task exists yet, or the parent is DONE",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -298,9 +310,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a task and all its descendants. Adjusts sibling positions automatically.",
+            }
+        },
+        "/api/v1/tasks/archive": {
+            "get": {
+                "description": "Lists the top-level branches archived when ARCHIVE_ON_COMPLETE moved them out of the tree after their whole subtree became DONE, oldest first. Archived tasks are not returned by the other task endpoints. The list is empty while archiving is off.",
                 "consumes": [
                     "application/json"
                 ],
@@ -310,31 +324,15 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Delete task",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Task ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "List archived trees",
                 "responses": {
-                    "204": {
-                        "description": "Successfully deleted task"
-                    },
-                    "400": {
-                        "description": "Invalid task ID format",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Task not found",
+                    "200": {
+                        "description": "Successfully retrieved the archive",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ArchivedTreeResponse"
+                            }
                         }
                     },
                     "500": {
@@ -346,9 +344,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/{id}/children": {
-            "get": {
-                "description": "Retrieves all child tasks of the specified parent task, ordered by position",
+        "/api/v1/tasks/archive/{id}/restore": {
+            "post": {
+                "description": "Moves an archived branch back under the root it was archived from (or the current root if that one is gone), after the root's last child, and removes it from the archive. Returns the restored tasks in depth-first order, the top task first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -358,12 +356,12 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Get task children",
+                "summary": "Restore archived tree",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Parent task ID (UUID format)",
+                        "description": "ID of the archived branch's top task (UUID format)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -371,7 +369,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved child tasks",
+                        "description": "Successfully restored the branch",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -386,7 +384,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Parent task not found",
+                        "description": "Archived tree not found, or no root to restore it under",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A task of the branch already exists in the tree, or MAX_TOTAL_TASKS would be exceeded",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -400,9 +404,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/{id}/move": {
-            "put": {
-                "description": "Moves a task to a new position or under a different parent task",
+        "/api/v1/tasks/by-description": {
+            "get": {
+                "description": "Retrieves every task whose description exactly matches value, oldest first. Descriptions are not unique, so the result is a list, empty when nothing matches. Matching is case-sensitive unless ci=true.",
                 "consumes": [
                     "application/json"
                 ],
@@ -412,41 +416,34 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Move task",
+                "summary": "Find tasks by description",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Task ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Description to match",
+                        "name": "value",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Move task request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.MoveTaskRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Match case-insensitively",
+                        "name": "ci",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully moved task",
+                        "description": "Successfully retrieved matching tasks",
                         "schema": {
-                            "$ref": "#/definitions/models.TaskResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request data, task ID format, or would create cycle",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Task or parent task not found",
+                        "description": "Missing value or invalid ci value",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -460,9 +457,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/{id}/status": {
-            "put": {
-                "description": "Updates the status of an existing task",
+        "/api/v1/tasks/changes": {
+            "get": {
+                "description": "Returns the tasks whose latest change has a sequence number above since, ordered by seq, for clients syncing without relying on timestamps. Every save assigns the task the store's next sequence number, which persists across restarts. Pass the returned seq as since on the next call. Deleted tasks are not listed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -472,41 +469,339 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Update task status",
+                "summary": "List changed tasks",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Task ID (UUID format)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Return tasks changed after this sequence number (default: 0, every task)",
+                        "name": "since",
+                        "in": "query"
                     },
                     {
-                        "description": "Status update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Maximum number of tasks to return (default: no limit)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved changed tasks",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateStatusRequest"
+                            "$ref": "#/definitions/models.TaskChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid since or limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/count": {
+            "get": {
+                "description": "Returns the number of tasks without fetching them, for \"N tasks\" labels. With byStatus=true, also returns the number of tasks in each status; every status is listed, with zero when no task has it. An empty store counts as zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Count tasks",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include the count per status",
+                        "name": "byStatus",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully counted tasks",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid byStatus value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/tasks/export": {
+            "get": {
+                "description": "Streams every task as one storage-format JSON object per line (JSON Lines). The tasks are copied in one read and encoded afterwards, so a slow client does not hold up changes to the store. Tasks are written in no particular order.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Export all tasks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated task status",
+                        "description": "One task JSON object per line",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported export format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/from-template": {
+            "post": {
+                "description": "Instantiates the named template as a subtree with fresh IDs under parentId, at position among its children (appended when omitted). Without parentId the template becomes the root task, which fails if a root already exists. A template whose top node is a Root Work Item can only be instantiated as the root. Returns the created tasks, top task first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create tasks from a template",
+                "parameters": [
+                    {
+                        "description": "Template instantiation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InstantiateTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or template",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template or parent task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Root task already exists or parent is DONE",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/graph": {
+            "get": {
+                "description": "Retrieves all tasks as nodes plus explicit parent-child edges, for graph-visualization clients. Nodes are ordered roots first, then grouped by parent in position order; edges follow their child nodes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task graph",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved task graph",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskGraphResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/group": {
+            "post": {
+                "description": "Creates a task with the given description under parentId at position, then moves the listed tasks, in order, to be its children. All moves are validated before any is applied.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Group tasks under a new task",
+                "parameters": [
+                    {
+                        "description": "Group request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupTasksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created group task",
                         "schema": {
                             "$ref": "#/definitions/models.TaskResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request data, task ID format, or status value",
+                        "description": "Invalid request data or task ID format",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Task or parent task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Grouping would create cycle, selection overlaps, or parent is DONE",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/import": {
+            "post": {
+                "description": "Reads one task JSON object per line (format=jsonl, the export format) or one task per CSV row (format=csv), validates the complete resulting tree (single root, no orphans, no cycles), then replaces or merges the store in a single operation. The CSV header names the columns: description is required; id, key, status, parentId and position are optional. Rows without an id get a generated one, and other rows may name them as parentId by their key. A parentId may refer to a row later in the file, or in merge mode to an existing task. An empty status is TODO and an empty position appends after the siblings listed before the row.",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Import tasks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "jsonl",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Import format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "merge"
+                        ],
+                        "type": "string",
+                        "description": "Replace the store or merge into it (default: merge)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "One task JSON object per line, or CSV with a header row",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully imported tasks",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported format or mode, or malformed record (message includes the line or row number)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Resulting tree violates an invariant",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -520,112 +815,2644 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Returns the health status of the Discovery Tree API",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "API is healthy",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+        "/api/v1/tasks/move-many": {
+            "post": {
+                "description": "Moves the listed tasks, in order, to sequential positions under the target parent. All moves are validated before any is applied.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Move multiple tasks",
+                "parameters": [
+                    {
+                        "description": "Bulk move request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MoveTasksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully moved tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or parent task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Move would create cycle or selection overlaps",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/nudge": {
+            "post": {
+                "description": "Shifts the listed siblings one position up or down within their shared parent, keeping their relative order; the selection need not be contiguous. Each unselected sibling the selection passes moves the other way. Rejected if the tasks do not share a parent or the selection already includes the first (up) or last (down) child.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Nudge tasks up or down",
+                "parameters": [
+                    {
+                        "description": "Nudge request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NudgeTasksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully nudged tasks, in the order requested",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tasks do not share a parent or the shift would go out of range",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/plan": {
+            "get": {
+                "description": "Returns the to-do list derived from the tree: every incomplete task without incomplete children, in the order it becomes ready under left-to-right, bottom-to-top completion (pre-order over incomplete branches, leftmost first). Each step carries its path of ancestors from the root down to its parent. An incomplete branch whose children are all complete is a step itself. A complete tree has an empty plan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get completion plan",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved plan",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PlanStepResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/root": {
+            "get": {
+                "description": "Retrieves the root task of the discovery tree. If AUTO_CREATE_ROOT is configured and no root exists, the root is created with the configured description and returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get root task",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved root task",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Root task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new root task for the discovery tree. Only one root task can exist at a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create root task",
+                "parameters": [
+                    {
+                        "description": "Root task creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRootTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created root task",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the created task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Root task already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/start-next": {
+            "post": {
+                "description": "Sets up to count ready TODO tasks to In Progress in one transaction, taken in the order of GET /tasks/plan, and returns them. Readiness is re-evaluated after each task is started. Fewer than count tasks are returned if fewer are ready.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Start the next ready tasks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of tasks to start (default 1)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully started tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid count",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/validate": {
+            "post": {
+                "description": "Checks a nested tree (same shape as a seed import) against the tree invariants: single root, Root Work Item only at the root, no cycles, contiguous sibling positions, and bottom-to-top completion. Nothing is created; all violations are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Validate a tree",
+                "parameters": [
+                    {
+                        "description": "Nested tree to validate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TreeNodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation result with any invariant violations",
+                        "schema": {
+                            "$ref": "#/definitions/models.TreeValidationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "get": {
+                "description": "Retrieves a specific task by its unique identifier. The include parameter embeds related data under dedicated keys: children and siblings (including the task itself) in position order, ancestors from the root down to the parent, and readiness, which also reports whether the task is on the completion frontier (the first open task in depth-first order, the next thing to do in its tree).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related data to embed: children, ancestors, siblings, readiness",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved task",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format or unknown include value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the description of an existing task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update task description",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated task",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data or task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a task and all its descendants (strategy=cascade, the default), or deletes only the task and moves its children up to take its place among its siblings (strategy=reparent). Adjusts sibling positions automatically. With DELETE_GUARD=block, deleting a task that is not DONE is rejected with 409 (code incomplete-delete) unless force=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "cascade",
+                            "reparent"
+                        ],
+                        "type": "string",
+                        "default": "cascade",
+                        "description": "What happens to the task's children",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete incomplete tasks even when DELETE_GUARD is block",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully deleted task"
+                    },
+                    "400": {
+                        "description": "Invalid task ID format, strategy or force value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot reparent the children of the root task, or incomplete tasks are guarded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Subtree exceeds MAX_SUBTREE_OPERATION",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/activity": {
+            "get": {
+                "description": "Summarizes a task and its descendants for a \"branch health\" card, in one pass over the subtree: the earliest createdAt, the latest updatedAt, how many tasks were updated in the last days days, and the number of tasks in each status; every status is listed, with zero when no task has it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get subtree activity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Length of the recent window in days (default 7)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully summarized activity",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskActivityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format or days",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Subtree exceeds MAX_SUBTREE_OPERATION",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/blockers": {
+            "get": {
+                "description": "Returns the incomplete tasks that keep the task from being ready, for a \"why can't I start this\" panel: its left sibling (type leftSibling), then its incomplete children in position order (type child). A ready task has an empty list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task blockers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved blockers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BlockerResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/children": {
+            "get": {
+                "description": "Retrieves all child tasks of the specified parent task, ordered by position unless sort names another order. Sorting only affects the response; stored positions are unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task children",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Parent task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "position",
+                            "status",
+                            "createdAt",
+                            "description"
+                        ],
+                        "type": "string",
+                        "description": "Order of the children (default: position); ties keep position order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved child tasks",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format or unknown sort",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Parent task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/export": {
+            "get": {
+                "description": "Returns the task and all its descendants, so a single branch can be shared without the whole tree. format=json nests the tasks as in API responses; jsonl writes one storage-format task per line (the import format); csv writes id, description, status, parentId and position columns (the CSV import format) with the exported task as a root; markdown writes a nested checklist with complete tasks checked. sort orders every task's children in the output, as on GET /tasks/{id}/children; stored positions, including the csv position column, are unchanged.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Export a subtree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "jsonl",
+                            "csv",
+                            "markdown"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "position",
+                            "status",
+                            "createdAt",
+                            "description"
+                        ],
+                        "type": "string",
+                        "description": "Order of each task's children (default: position); ties keep position order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The subtree in the requested format",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskTreeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format, unsupported export format or unknown sort",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Subtree exceeds MAX_SUBTREE_OPERATION",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/history": {
+            "get": {
+                "description": "Retrieves the explicit moves of the specified task, oldest first. Positions shifted only to make room for other tasks are not recorded, and only the most recent moves are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task move history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved move history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MoveRecordResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/kind": {
+            "put": {
+                "description": "Changes the kind of an existing task, e.g. to mark it a research spike or a milestone. The kind must be one of the configured TASK_KINDS.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Set task kind",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New kind",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetKindRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully changed the kind",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, task ID format, or unknown kind",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/move": {
+            "put": {
+                "description": "Moves a task to a new position or under a different parent task. Moving an incomplete task under a DONE parent is rejected with 409, unless reconcile=true, which reopens the parent and its DONE ancestors to the configured REOPEN_STATUS (In Progress by default) and returns them alongside the moved task.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Move task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reopen DONE ancestors at the destination instead of rejecting the move",
+                        "name": "reconcile",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Move task request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MoveTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully moved task (models.MoveTaskResponse with reconcile=true)",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, task ID format, or would create cycle",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task or parent task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Incomplete task moved under a DONE parent without reconcile",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/position": {
+            "get": {
+                "description": "Returns only the task's parent ID, its position among its siblings (using the configured position base) and the number of siblings, itself included, so drag-and-drop clients can check a cached position before moving the task without fetching the whole task.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task position",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved task position",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskPositionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/promote-root": {
+            "post": {
+                "description": "Makes the task the root of the tree, keeping its subtree: its parent is cleared, its status becomes Root Work Item and its former siblings close the gap. Rejected with 409 while a root exists, unless replaceRoot=true, which deletes the current root; that is only allowed when the task is the root's only child, so no other branch is lost. Promoting the root itself returns it unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Promote task to root",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete the current root, whose only child the task must be",
+                        "name": "replaceRoot",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully promoted task",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format or replaceRoot value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A root exists without replaceRoot, or the root has other branches",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/reset": {
+            "post": {
+                "description": "Sets the specified task and every descendant to TODO in a single transaction, clearing completedAt, to reopen a branch for rework. A Root Work Item keeps its status. The task's DONE ancestors are reopened to the configured REOPEN_STATUS (In Progress by default), since a DONE task cannot have incomplete children, and are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Reset a subtree to TODO",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully reset; returns the number of tasks set to TODO and the reopened ancestors",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResetSubtreeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Subtree exceeds MAX_SUBTREE_OPERATION",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/root": {
+            "get": {
+                "description": "Retrieves the topmost ancestor of the specified task (the task itself if it is a root)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get root of task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved root task",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Parent chain contains a cycle",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/siblings/count": {
+            "get": {
+                "description": "Returns how many siblings the task has (itself included) and its position among them, so clients can render \"2 of 5\" labels without fetching the sibling list. The index uses the configured position base.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get sibling count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved sibling count",
+                        "schema": {
+                            "$ref": "#/definitions/models.SiblingCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/status": {
+            "put": {
+                "description": "Updates the status of an existing task. With ARCHIVE_ON_COMPLETE, a change that leaves a whole top-level branch DONE moves the branch to the archive (see GET /tasks/archive); the task is still returned, read from the archive.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update task status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated task status",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, task ID format, or status value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/status/options": {
+            "get": {
+                "description": "Returns the statuses PUT /tasks/{id}/status would accept for the task right now, in status order, for context-aware status dropdowns. The current status is not included, and complete statuses are left out while the task has incomplete children.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get status options",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved status options",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/tags/subtree": {
+            "post": {
+                "description": "Adds and removes tags on the specified task and every descendant in a single write. Tags must be non-empty, at most 50 characters, and contain no whitespace or commas; a tag cannot be both added and removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Tag a subtree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags to add and remove",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagSubtreeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully tagged; returns the number of tasks whose tags changed",
+                        "schema": {
+                            "$ref": "#/definitions/models.TagSubtreeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data, task ID format, or tag",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Subtree exceeds MAX_SUBTREE_OPERATION",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/timeline": {
+            "get": {
+                "description": "Retrieves the explicit status changes of the specified task, oldest first, each with the previous status, the new status and when it changed. Changes made through the status, toggle, reset and status rename endpoints are recorded; the initial status and changes made as a side effect of other operations (such as reopening a DONE parent) are not. Only the most recent STATUS_HISTORY_LIMIT changes are kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task status timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved status timeline",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StatusRecordResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/toggle": {
+            "post": {
+                "description": "Flips the task between TODO and DONE (or the configured TOGGLE_STATUSES pair): a DONE task becomes TODO and any other task becomes DONE. Completing a task requires all its children to be DONE.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Toggle task status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully toggled task status",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Task has incomplete children",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/ungroup": {
+            "post": {
+                "description": "Moves all children of the specified task up to take its place among its siblings (later siblings shift right), then deletes the task. The root task cannot be ungrouped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Ungroup a task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Task ID (UUID format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully ungrouped; returns the promoted children",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TaskResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid task ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot ungroup the root task",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/templates": {
+            "get": {
+                "description": "Lists the named templates found in TEMPLATE_DIR, ordered by name. Each is a nested tree JSON file that POST /tasks/from-template instantiates. A file that cannot be loaded is listed with an error instead of failing the list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List task templates",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved templates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TemplateResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns the health status of the Discovery Tree API. Storage is probed by counting tasks; a failing probe, or one taking longer than HEALTH_CHECK_TIMEOUT, returns 503 with the reason in error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "API is healthy",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Storage check failed or timed out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Returns counters in the Prometheus text format. persist_requested_total counts changes that asked to be written to the data file, persist_flushed_total the file writes performed and persist_coalesced_total the changes written by a write shared with an earlier change (see PERSIST_COALESCE and PERSIST_DEBOUNCE). persist_pending is the number of changes not yet written.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Metrics",
+                "responses": {
+                    "200": {
+                        "description": "Metrics in the Prometheus text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ArchivedTreeResponse": {
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "description": "When the branch was archived",
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "Root task the branch was a child of",
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Position under that root when archived, using the configured position base",
+                    "type": "integer"
+                },
+                "rootId": {
+                    "description": "Top task of the branch; restore it with POST /tasks/archive/{id}/restore",
+                    "type": "string"
+                },
+                "tasks": {
+                    "description": "The branch in depth-first order, its top task first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                }
+            }
+        },
+        "models.BlockerResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "taskId": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"leftSibling\" or \"child\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CompactResponse": {
+            "type": "object",
+            "properties": {
+                "bytesAfter": {
+                    "description": "Size of the data file after compaction",
+                    "type": "integer"
+                },
+                "bytesBefore": {
+                    "description": "Size of the data file before compaction",
+                    "type": "integer"
+                },
+                "tasksRenumbered": {
+                    "description": "Tasks whose position changed to close gaps among their siblings",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateChildTaskRequest": {
+            "type": "object",
+            "required": [
+                "parentId"
+            ],
+            "properties": {
+                "description": {
+                    "description": "blank uses CHILD_DESCRIPTION_TEMPLATE, if set",
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "appends after the last child when omitted (see CHILD_INSERT_MODE)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateRootTaskRequest": {
+            "type": "object",
+            "required": [
+                "description"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "models.DiagnosticTaskResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "taskId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DiagnosticsResponse": {
+            "type": "object",
+            "properties": {
+                "cycles": {
+                    "description": "One task on each cycle",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiagnosticTaskResponse"
+                    }
+                },
+                "duplicatePositions": {
+                    "description": "Sibling groups sharing a position",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DuplicatePositionResponse"
+                    }
+                },
+                "healthy": {
+                    "description": "Whether no invariant is violated",
+                    "type": "boolean"
+                },
+                "orphans": {
+                    "description": "Tasks whose parent does not exist",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiagnosticTaskResponse"
+                    }
+                },
+                "roots": {
+                    "description": "Every root, when there is not exactly one",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiagnosticTaskResponse"
+                    }
+                },
+                "taskCount": {
+                    "description": "Number of tasks checked",
+                    "type": "integer"
+                },
+                "violations": {
+                    "description": "Every violated invariant",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvariantViolationResponse"
+                    }
+                }
+            }
+        },
+        "models.DuplicatePositionResponse": {
+            "type": "object",
+            "properties": {
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Stored 0-based position",
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiagnosticTaskResponse"
+                    }
+                }
+            }
+        },
+        "models.ErrorDetailResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "taskId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Tasks causing a constraint violation, when known",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ErrorDetailResponse"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Invalid field of a validation error",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupTasksRequest": {
+            "type": "object",
+            "required": [
+                "childIds",
+                "description",
+                "parentId"
+            ],
+            "properties": {
+                "childIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InstantiateTemplateRequest": {
+            "type": "object",
+            "required": [
+                "templateName"
+            ],
+            "properties": {
+                "parentId": {
+                    "description": "instantiates the template as the root when omitted",
+                    "type": "string"
+                },
+                "position": {
+                    "description": "appends after the last child when omitted",
+                    "type": "integer"
+                },
+                "templateName": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "models.InvariantViolationResponse": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoveRecordResponse": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "fromParentId": {
+                    "type": "string"
+                },
+                "fromPosition": {
+                    "type": "integer"
+                },
+                "toParentId": {
+                    "type": "string"
+                },
+                "toPosition": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MoveTaskRequest": {
+            "type": "object",
+            "properties": {
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.MoveTasksRequest": {
+            "type": "object",
+            "required": [
+                "ids",
+                "parentId"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "startPosition": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.NudgeTasksRequest": {
+            "type": "object",
+            "required": [
+                "direction",
+                "ids"
+            ],
+            "properties": {
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ]
+                },
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PlanStepResponse": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "description": "Ancestors from the root down to the task's parent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                },
+                "task": {
+                    "$ref": "#/definitions/models.TaskResponse"
+                }
+            }
+        },
+        "models.ReadinessResponse": {
+            "type": "object",
+            "properties": {
+                "allChildrenComplete": {
+                    "type": "boolean"
+                },
+                "frontier": {
+                    "description": "Whether the task is the next thing to do in its tree, not just allowed",
+                    "type": "boolean"
+                },
+                "leftSiblingComplete": {
+                    "type": "boolean"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RenameStatusRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "enum": [
+                        "TODO",
+                        "In Progress",
+                        "DONE",
+                        "Blocked",
+                        "Root Work Item"
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "enum": [
+                        "TODO",
+                        "In Progress",
+                        "DONE",
+                        "Blocked",
+                        "Root Work Item"
+                    ]
+                }
+            }
+        },
+        "models.RenameStatusResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "Status the tasks had",
+                    "type": "string"
+                },
+                "renamed": {
+                    "description": "Number of tasks changed",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "Status the tasks have now",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResetSubtreeResponse": {
+            "type": "object",
+            "properties": {
+                "reopened": {
+                    "description": "DONE ancestors reopened to the reopen status, nearest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                },
+                "reset": {
+                    "description": "Number of tasks set to TODO",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SetKindRequest": {
+            "type": "object",
+            "required": [
+                "kind"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SiblingCountResponse": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "description": "The task's position among them, using the configured position base",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Number of siblings, the task included",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "byStatus": {
+                    "description": "Counted tasks per status name, every counted status included",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "completed": {
+                    "description": "Counted tasks in a complete status",
+                    "type": "integer"
+                },
+                "percentComplete": {
+                    "description": "Completed tasks as a percentage of counted tasks",
+                    "type": "number"
+                },
+                "taskId": {
+                    "description": "Top task of the subtree, null for the whole store",
+                    "type": "string"
+                },
+                "tasks": {
+                    "description": "Tasks counted; Root Work Item tasks are not",
+                    "type": "integer"
+                },
+                "weightedPercentComplete": {
+                    "description": "Status weights (see STATUS_WEIGHTS) summed as a percentage of counted tasks",
+                    "type": "number"
+                }
+            }
+        },
+        "models.StatusRecordResponse": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "Hex color, from STATUS_COLORS or the default",
+                    "type": "string"
+                },
+                "complete": {
+                    "description": "Whether the status counts as complete",
+                    "type": "boolean"
+                },
+                "displayName": {
+                    "description": "Human-readable name",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Status value used in requests and responses",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagSubtreeRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CreateChildTaskRequest": {
+        },
+        "models.TagSubtreeResponse": {
             "type": "object",
-            "required": [
-                "description",
-                "parentId"
-            ],
             "properties": {
-                "description": {
-                    "type": "string",
-                    "minLength": 1
+                "affected": {
+                    "description": "Number of tasks whose tags changed",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TaskActivityResponse": {
+            "type": "object",
+            "properties": {
+                "byStatus": {
+                    "description": "Tasks per status name, every status included",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
                 },
-                "parentId": {
+                "changedRecently": {
+                    "description": "Tasks updated within the last days",
+                    "type": "integer"
+                },
+                "days": {
+                    "description": "Length of the recent window in days",
+                    "type": "integer"
+                },
+                "earliestCreatedAt": {
+                    "description": "Earliest CreatedAt in the subtree",
+                    "type": "string"
+                },
+                "latestUpdatedAt": {
+                    "description": "Latest UpdatedAt in the subtree",
+                    "type": "string"
+                },
+                "taskId": {
+                    "description": "Top task of the subtree",
                     "type": "string"
+                },
+                "total": {
+                    "description": "Tasks in the subtree, the top task included",
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateRootTaskRequest": {
+        "models.TaskChangesResponse": {
             "type": "object",
-            "required": [
-                "description"
-            ],
             "properties": {
-                "description": {
-                    "type": "string",
-                    "minLength": 1
+                "hasMore": {
+                    "description": "Whether limit cut the list short; call again with seq to continue",
+                    "type": "boolean"
+                },
+                "seq": {
+                    "description": "Cursor to pass as since on the next call",
+                    "type": "integer"
+                },
+                "tasks": {
+                    "description": "Changed tasks, ordered by seq",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.TaskCountResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "byStatus": {
+                    "description": "Tasks per status name, every status included; only with ?byStatus=true",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "description": "Number of tasks in the store",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TaskDetailResponse": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                },
+                "completedAt": {
                     "type": "string"
                 },
-                "error": {
+                "createdAt": {
                     "type": "string"
                 },
-                "message": {
+                "description": {
+                    "type": "string"
+                },
+                "hasChildren": {
+                    "description": "Whether the task has at least one child",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isLeaf": {
+                    "description": "Whether the task has no children",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "Classification such as task, spike or milestone; see TASK_KINDS",
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "readiness": {
+                    "$ref": "#/definitions/models.ReadinessResponse"
+                },
+                "seq": {
+                    "description": "Store sequence number of the task's latest change; see GET /tasks/changes",
+                    "type": "integer"
+                },
+                "siblings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "models.MoveTaskRequest": {
+        "models.TaskEdgeResponse": {
+            "type": "object",
+            "properties": {
+                "child": {
+                    "type": "string"
+                },
+                "parent": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaskGraphResponse": {
+            "type": "object",
+            "properties": {
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskEdgeResponse"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskResponse"
+                    }
+                }
+            }
+        },
+        "models.TaskPositionResponse": {
             "type": "object",
             "properties": {
                 "parentId": {
+                    "description": "Parent task ID, null for a root task",
                     "type": "string"
                 },
                 "position": {
-                    "type": "integer",
-                    "minimum": 0
+                    "description": "Position among its siblings, using the configured position base",
+                    "type": "integer"
+                },
+                "siblingCount": {
+                    "description": "Number of siblings, the task included",
+                    "type": "integer"
                 }
             }
         },
         "models.TaskResponse": {
             "type": "object",
             "properties": {
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "hasChildren": {
+                    "description": "Whether the task has at least one child",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isLeaf": {
+                    "description": "Whether the task has no children",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "Classification such as task, spike or milestone; see TASK_KINDS",
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "seq": {
+                    "description": "Store sequence number of the task's latest change; see GET /tasks/changes",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaskTreeResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Ordered by position; [] for a leaf",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaskTreeResponse"
+                    }
+                },
+                "completedAt": {
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
                 "description": {
                     "type": "string"
                 },
+                "hasChildren": {
+                    "description": "Whether the task has at least one child",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
+                "isLeaf": {
+                    "description": "Whether the task has no children",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "Classification such as task, spike or milestone; see TASK_KINDS",
+                    "type": "string"
+                },
                 "parentId": {
                     "type": "string"
                 },
                 "position": {
                     "type": "integer"
                 },
+                "seq": {
+                    "description": "Store sequence number of the task's latest change; see GET /tasks/changes",
+                    "type": "integer"
+                },
                 "status": {
                     "type": "string"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "updatedAt": {
                     "type": "string"
                 }
             }
         },
+        "models.TemplateResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Why the template file could not be loaded; such a template cannot be instantiated",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rootOnly": {
+                    "description": "Whether the template can only be instantiated as the root task",
+                    "type": "boolean"
+                },
+                "taskCount": {
+                    "description": "Number of tasks an instantiation creates",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TreeNodeRequest": {
+            "type": "object",
+            "required": [
+                "description"
+            ],
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TreeNodeRequest"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "TODO",
+                        "In Progress",
+                        "DONE",
+                        "Blocked",
+                        "Root Work Item"
+                    ]
+                }
+            }
+        },
+        "models.TreeValidationResponse": {
+            "type": "object",
+            "properties": {
+                "valid": {
+                    "type": "boolean"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvariantViolationResponse"
+                    }
+                }
+            }
+        },
         "models.UpdateStatusRequest": {
             "type": "object",
             "required": [
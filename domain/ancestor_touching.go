@@ -0,0 +1,208 @@
+package domain
+
+// ancestorTouchingRepository records the parent of every task an operation creates, deletes,
+// or moves (to another parent or position), so those parents can be touched once the
+// operation is done: their UpdatedAt is refreshed and, being saved, they get a new Seq
+// Tasks may be changed in place before they are saved, so a task's place is remembered when
+// it is first read; as for positionTrackingRepository, a task has to be read before it moves.
+// ReplaceAll is passed through untracked, as every task it writes is saved anyway
+type ancestorTouchingRepository struct {
+	TaskRepositoryTx
+	all     bool // touch every ancestor of a changed parent too, not just the parent
+	places  map[string]taskPlace
+	parents map[string]TaskID
+}
+
+// taskPlace is a task's parent and position as first read by an operation
+type taskPlace struct {
+	parentID *TaskID
+	position int
+}
+
+// newAncestorTouchingRepository wraps repo to record the parents whose children an operation changes
+func newAncestorTouchingRepository(repo TaskRepositoryTx, all bool) *ancestorTouchingRepository {
+	return &ancestorTouchingRepository{
+		TaskRepositoryTx: repo,
+		all:              all,
+		places:           make(map[string]taskPlace),
+		parents:          make(map[string]TaskID),
+	}
+}
+
+// touchParent records parentID; root tasks have no parent to touch
+func (r *ancestorTouchingRepository) touchParent(parentID *TaskID) {
+	if parentID != nil {
+		r.parents[parentID.String()] = *parentID
+	}
+}
+
+// remember records the place of tasks not seen before
+func (r *ancestorTouchingRepository) remember(tasks ...*Task) {
+	for _, task := range tasks {
+		if _, seen := r.places[task.ID().String()]; !seen {
+			r.places[task.ID().String()] = taskPlace{parentID: task.ParentID(), position: task.Position()}
+		}
+	}
+}
+
+// recordChange records the parents of a task about to be saved if it is new or has moved
+func (r *ancestorTouchingRepository) recordChange(task *Task) error {
+	place, seen := r.places[task.ID().String()]
+	if !seen {
+		if _, err := r.TaskRepositoryTx.FindByID(task.ID()); err == nil {
+			// Saved without being read first, so it cannot have moved
+			return nil
+		} else if _, ok := err.(NotFoundError); !ok {
+			return err
+		}
+		r.touchParent(task.ParentID())
+		r.remember(task)
+		return nil
+	}
+	if !sameParent(place.parentID, task.ParentID()) || place.position != task.Position() {
+		r.touchParent(place.parentID)
+		r.touchParent(task.ParentID())
+	}
+	return nil
+}
+
+// recordDelete records the parent of a task about to be deleted
+func (r *ancestorTouchingRepository) recordDelete(id TaskID) error {
+	task, err := r.TaskRepositoryTx.FindByID(id)
+	if err != nil {
+		return err
+	}
+	r.touchParent(task.ParentID())
+	return nil
+}
+
+// FindByID retrieves a task and remembers its place
+func (r *ancestorTouchingRepository) FindByID(id TaskID) (*Task, error) {
+	task, err := r.TaskRepositoryTx.FindByID(id)
+	if err == nil {
+		r.remember(task)
+	}
+	return task, err
+}
+
+// FindByParentID retrieves a parent's children and remembers their places
+func (r *ancestorTouchingRepository) FindByParentID(parentID *TaskID) ([]*Task, error) {
+	tasks, err := r.TaskRepositoryTx.FindByParentID(parentID)
+	if err == nil {
+		r.remember(tasks...)
+	}
+	return tasks, err
+}
+
+// FindRoot retrieves the root task and remembers its place
+func (r *ancestorTouchingRepository) FindRoot() (*Task, error) {
+	task, err := r.TaskRepositoryTx.FindRoot()
+	if err == nil {
+		r.remember(task)
+	}
+	return task, err
+}
+
+// FindAll retrieves all tasks and remembers their places
+func (r *ancestorTouchingRepository) FindAll() ([]*Task, error) {
+	tasks, err := r.TaskRepositoryTx.FindAll()
+	if err == nil {
+		r.remember(tasks...)
+	}
+	return tasks, err
+}
+
+// ForEach calls fn for every task and remembers their places
+func (r *ancestorTouchingRepository) ForEach(fn func(task *Task) error) error {
+	return r.TaskRepositoryTx.ForEach(func(task *Task) error {
+		r.remember(task)
+		return fn(task)
+	})
+}
+
+// FindSubtree retrieves a subtree and remembers the places of its tasks
+func (r *ancestorTouchingRepository) FindSubtree(rootID TaskID) ([]*Task, error) {
+	tasks, err := r.TaskRepositoryTx.FindSubtree(rootID)
+	if err == nil {
+		r.remember(tasks...)
+	}
+	return tasks, err
+}
+
+// Save records the task's parents if it is new or moved, then persists it
+func (r *ancestorTouchingRepository) Save(task *Task) error {
+	if err := r.recordChange(task); err != nil {
+		return err
+	}
+	return r.TaskRepositoryTx.Save(task)
+}
+
+// SaveAll records the parents of the new and moved tasks, then persists them
+func (r *ancestorTouchingRepository) SaveAll(tasks []*Task) error {
+	for _, task := range tasks {
+		if err := r.recordChange(task); err != nil {
+			return err
+		}
+	}
+	return r.TaskRepositoryTx.SaveAll(tasks)
+}
+
+// Delete records the task's parent, then removes the task
+func (r *ancestorTouchingRepository) Delete(id TaskID) error {
+	if err := r.recordDelete(id); err != nil {
+		return err
+	}
+	return r.TaskRepositoryTx.Delete(id)
+}
+
+// DeleteSubtree records the subtree root's parent, then removes the subtree
+func (r *ancestorTouchingRepository) DeleteSubtree(id TaskID) error {
+	if err := r.recordDelete(id); err != nil {
+		return err
+	}
+	return r.TaskRepositoryTx.DeleteSubtree(id)
+}
+
+// touchAncestors touches every recorded parent still in the store, and with all set their
+// ancestors too, saving each once; returns the number of tasks touched
+func (r *ancestorTouchingRepository) touchAncestors() (int, error) {
+	touched := make(map[string]bool)
+	var toSave []*Task
+	for _, parentID := range r.parents {
+		id := &parentID
+		for id != nil && !touched[id.String()] {
+			// A parent deleted by the operation has nothing left to touch
+			task, err := r.TaskRepositoryTx.FindByID(*id)
+			if err != nil {
+				if _, ok := err.(NotFoundError); ok {
+					break
+				}
+				return 0, err
+			}
+			touched[id.String()] = true
+			task.touch()
+			toSave = append(toSave, task)
+
+			if !r.all {
+				break
+			}
+			id = task.ParentID()
+		}
+	}
+
+	if len(toSave) == 0 {
+		return 0, nil
+	}
+	if err := r.TaskRepositoryTx.SaveAll(toSave); err != nil {
+		return 0, err
+	}
+	return len(toSave), nil
+}
+
+// sameParent reports whether two optional parent IDs refer to the same parent
+func sameParent(a, b *TaskID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -3,8 +3,11 @@ package domain
 import "fmt"
 
 // ValidationError represents an error when input validation fails
+// Rule names the check that failed (e.g. "negative-position"), so errors from different checks
+// on the same field can be told apart; it is the code of the error response
 type ValidationError struct {
 	Field   string
+	Rule    string
 	Message string
 }
 
@@ -15,10 +18,11 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation error: %s", e.Message)
 }
 
-// NewValidationError creates a new ValidationError
-func NewValidationError(field, message string) ValidationError {
+// NewValidationError creates a new ValidationError for the given field and failed rule
+func NewValidationError(field, rule, message string) ValidationError {
 	return ValidationError{
 		Field:   field,
+		Rule:    rule,
 		Message: message,
 	}
 }
@@ -45,6 +49,14 @@ func NewNotFoundError(entityType, id string) NotFoundError {
 type ConstraintViolationError struct {
 	Constraint string
 	Message    string
+	details    *[]ConstraintDetail // behind a pointer so the error stays comparable
+}
+
+// ConstraintDetail names a task that causes a constraint violation
+type ConstraintDetail struct {
+	TaskID      TaskID
+	Description string
+	Status      Status
 }
 
 func (e ConstraintViolationError) Error() string {
@@ -58,3 +70,48 @@ func NewConstraintViolationError(constraint, message string) ConstraintViolation
 		Message:    message,
 	}
 }
+
+// NewConstraintViolationErrorWithTasks creates a ConstraintViolationError naming the tasks that cause it
+func NewConstraintViolationErrorWithTasks(constraint, message string, tasks []*Task) ConstraintViolationError {
+	details := make([]ConstraintDetail, len(tasks))
+	for i, task := range tasks {
+		details[i] = ConstraintDetail{
+			TaskID:      task.ID(),
+			Description: task.Description(),
+			Status:      task.Status(),
+		}
+	}
+	return ConstraintViolationError{
+		Constraint: constraint,
+		Message:    message,
+		details:    &details,
+	}
+}
+
+// Details returns the tasks causing the violation, nil when the rule does not name them
+func (e ConstraintViolationError) Details() []ConstraintDetail {
+	if e.details == nil {
+		return nil
+	}
+	return *e.details
+}
+
+// SubtreeTooLargeError represents an error when an operation's subtree exceeds the configured size limit
+type SubtreeTooLargeError struct {
+	TaskID string
+	Size   int
+	Limit  int
+}
+
+func (e SubtreeTooLargeError) Error() string {
+	return fmt.Sprintf("subtree of task '%s' has %d tasks, more than the limit of %d; narrow the operation to a smaller subtree", e.TaskID, e.Size, e.Limit)
+}
+
+// NewSubtreeTooLargeError creates a new SubtreeTooLargeError
+func NewSubtreeTooLargeError(taskID string, size, limit int) SubtreeTooLargeError {
+	return SubtreeTooLargeError{
+		TaskID: taskID,
+		Size:   size,
+		Limit:  limit,
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// IDScheme selects the format of newly generated task IDs
+type IDScheme string
+
+const (
+	// IDSchemeUUID generates random UUIDs (the default)
+	IDSchemeUUID IDScheme = "uuid"
+	// IDSchemeShort generates ShortIDLength-character base32 IDs
+	IDSchemeShort IDScheme = "short"
+)
+
+// IsValid checks if the scheme value is valid (empty selects the default)
+func (s IDScheme) IsValid() bool {
+	return s == "" || s == IDSchemeUUID || s == IDSchemeShort
+}
+
+// ShortIDLength is the number of characters in a short ID (50 random bits)
+const ShortIDLength = 10
+
+// shortIDAlphabet is the lowercase RFC 4648 base32 alphabet
+const shortIDAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+// IDGenerator generates identifiers for new tasks
+type IDGenerator interface {
+	NewID() TaskID
+}
+
+// NewIDGenerator returns the generator for the given scheme, defaulting to UUIDs
+func NewIDGenerator(scheme IDScheme) IDGenerator {
+	if scheme == IDSchemeShort {
+		return ShortIDGenerator{}
+	}
+	return UUIDGenerator{}
+}
+
+// UUIDGenerator generates random UUIDs
+type UUIDGenerator struct{}
+
+// NewID returns a new random UUID task ID
+func (UUIDGenerator) NewID() TaskID {
+	return NewTaskID()
+}
+
+// ShortIDGenerator generates random, lowercase base32 IDs of ShortIDLength characters
+type ShortIDGenerator struct{}
+
+// NewID returns a new random short task ID
+func (ShortIDGenerator) NewID() TaskID {
+	random := make([]byte, ShortIDLength)
+	if _, err := rand.Read(random); err != nil {
+		panic("domain: reading random bytes for a short ID: " + err.Error())
+	}
+
+	// Each character takes 5 bits of its random byte
+	id := make([]byte, ShortIDLength)
+	for i, b := range random {
+		id[i] = shortIDAlphabet[b&31]
+	}
+	return TaskID{value: string(id)}
+}
+
+// IsShortID reports whether s has the short ID format, ignoring letter case
+func IsShortID(s string) bool {
+	if len(s) != ShortIDLength {
+		return false
+	}
+	for _, r := range strings.ToLower(s) {
+		if !strings.ContainsRune(shortIDAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestShortIDGenerator_NewID(t *testing.T) {
+	generator := NewIDGenerator(IDSchemeShort)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := generator.NewID()
+		if !IsShortID(id.String()) {
+			t.Fatalf("expected a short ID, got %q", id.String())
+		}
+		if !id.IsCanonical() {
+			t.Errorf("generated short ID %q should be canonical", id.String())
+		}
+		if seen[id.String()] {
+			t.Fatalf("duplicate short ID %q", id.String())
+		}
+		seen[id.String()] = true
+
+		// Round-trip through parsing
+		parsed, err := TaskIDFromString(id.String())
+		if err != nil {
+			t.Fatalf("TaskIDFromString(%q) unexpected error: %v", id.String(), err)
+		}
+		if !parsed.Equals(id) {
+			t.Errorf("round-tripped ID %q, want %q", parsed.String(), id.String())
+		}
+	}
+}
+
+func TestNewIDGenerator_DefaultsToUUID(t *testing.T) {
+	for _, scheme := range []IDScheme{"", IDSchemeUUID} {
+		id := NewIDGenerator(scheme).NewID()
+		if !HasCanonicalLayout(id.String()) || IsShortID(id.String()) {
+			t.Errorf("scheme %q: expected a UUID, got %q", scheme, id.String())
+		}
+	}
+}
+
+func TestIDScheme_IsValid(t *testing.T) {
+	for _, scheme := range []IDScheme{"", IDSchemeUUID, IDSchemeShort} {
+		if !scheme.IsValid() {
+			t.Errorf("expected scheme %q to be valid", scheme)
+		}
+	}
+	if IDScheme("ulid").IsValid() {
+		t.Error("expected unknown scheme to be invalid")
+	}
+}
+
+func TestTaskService_ShortIDsAlongsideLegacyUUIDs(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// A tree created with UUIDs, then extended after switching to short IDs
+	legacy := NewTaskService(repo)
+	root, _ := legacy.CreateRootTask("Root")
+
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{IDGenerator: ShortIDGenerator{}})
+	rootID, err := TaskIDFromString(root.ID().String())
+	if err != nil {
+		t.Fatalf("expected legacy UUID to parse, got %v", err)
+	}
+	child, err := service.CreateChildTask("Child", rootID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !IsShortID(child.ID().String()) {
+		t.Errorf("expected child to get a short ID, got %q", child.ID().String())
+	}
+
+	childID, err := TaskIDFromString(child.ID().String())
+	if err != nil {
+		t.Fatalf("expected short ID to parse, got %v", err)
+	}
+	retrieved, err := repo.FindByID(childID)
+	if err != nil {
+		t.Fatalf("expected to find child by short ID, got %v", err)
+	}
+	if !retrieved.ParentID().Equals(root.ID()) {
+		t.Errorf("expected parent %s, got %s", root.ID(), retrieved.ParentID())
+	}
+}
+
+// sequenceGenerator returns its IDs in order, repeating the last one once they run out
+type sequenceGenerator struct {
+	ids []TaskID
+}
+
+func (g *sequenceGenerator) NewID() TaskID {
+	id := g.ids[0]
+	if len(g.ids) > 1 {
+		g.ids = g.ids[1:]
+	}
+	return id
+}
+
+func TestTaskService_NewTaskIDSkipsStoredIDs(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	taken := TaskID{value: "aaaaaaaaaa"}
+	fresh := TaskID{value: "bbbbbbbbbb"}
+	generator := &sequenceGenerator{ids: []TaskID{taken, taken, fresh}}
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{IDGenerator: generator})
+
+	root, err := service.CreateRootTask("Root")
+	if err != nil {
+		t.Fatalf("CreateRootTask failed: %v", err)
+	}
+	if root.ID() != taken {
+		t.Fatalf("expected the root to get %s, got %s", taken, root.ID())
+	}
+
+	// A generated ID that is already stored is discarded instead of overwriting the root
+	child, err := service.CreateChildTask("Child", root.ID())
+	if err != nil {
+		t.Fatalf("CreateChildTask failed: %v", err)
+	}
+	if child.ID() != fresh {
+		t.Errorf("expected the child to get %s, got %s", fresh, child.ID())
+	}
+	stored, err := repo.FindByID(taken)
+	if err != nil || stored.Description() != "Root" {
+		t.Errorf("expected the root to be kept, got %v (%v)", stored, err)
+	}
+
+	// A generator that only repeats stored IDs fails instead of overwriting
+	if _, err := service.CreateChildTask("Another", root.ID()); err == nil {
+		t.Error("expected CreateChildTask to fail when every generated ID is taken")
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"sort"
+	"sync"
+)
+
+// InMemoryTaskArchive is an in-memory implementation of TaskArchive for testing
+type InMemoryTaskArchive struct {
+	trees map[string]ArchivedTree // keyed by top task ID string
+	mu    sync.RWMutex
+}
+
+// NewInMemoryTaskArchive creates a new in-memory task archive
+func NewInMemoryTaskArchive() *InMemoryTaskArchive {
+	return &InMemoryTaskArchive{
+		trees: make(map[string]ArchivedTree),
+	}
+}
+
+// Add stores an archived tree
+func (a *InMemoryTaskArchive) Add(tree ArchivedTree) error {
+	if len(tree.Tasks) == 0 {
+		return NewValidationError("tasks", "empty-archived-tree", "archived tree cannot be empty")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.trees[tree.Root().ID().String()] = tree
+	return nil
+}
+
+// FindAll retrieves all archived trees, ordered by archive time (tie-broken by top task ID)
+func (a *InMemoryTaskArchive) FindAll() ([]ArchivedTree, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	trees := make([]ArchivedTree, 0, len(a.trees))
+	for _, tree := range a.trees {
+		trees = append(trees, tree)
+	}
+	SortArchivedTrees(trees)
+	return trees, nil
+}
+
+// FindByRootID retrieves the archived tree whose top task has the given ID
+func (a *InMemoryTaskArchive) FindByRootID(rootID TaskID) (ArchivedTree, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	tree, ok := a.trees[rootID.String()]
+	if !ok {
+		return ArchivedTree{}, NewNotFoundError("Archived Tree", rootID.String())
+	}
+	return tree, nil
+}
+
+// FindTask retrieves an archived task from any archived tree
+func (a *InMemoryTaskArchive) FindTask(id TaskID) (*Task, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, tree := range a.trees {
+		for _, task := range tree.Tasks {
+			if task.ID().Equals(id) {
+				return task, nil
+			}
+		}
+	}
+	return nil, NewNotFoundError("Archived Task", id.String())
+}
+
+// Remove deletes the archived tree whose top task has the given ID
+func (a *InMemoryTaskArchive) Remove(rootID TaskID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.trees[rootID.String()]; !ok {
+		return NewNotFoundError("Archived Tree", rootID.String())
+	}
+	delete(a.trees, rootID.String())
+	return nil
+}
+
+// SortArchivedTrees orders archived trees by archive time, tie-broken by top task ID
+func SortArchivedTrees(trees []ArchivedTree) {
+	sort.Slice(trees, func(i, j int) bool {
+		if !trees[i].ArchivedAt.Equal(trees[j].ArchivedAt) {
+			return trees[i].ArchivedAt.Before(trees[j].ArchivedAt)
+		}
+		return trees[i].Root().ID().String() < trees[j].Root().ID().String()
+	})
+}
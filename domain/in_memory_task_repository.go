@@ -8,29 +8,129 @@ import (
 // InMemoryTaskRepository is an in-memory implementation of TaskRepository for testing
 type InMemoryTaskRepository struct {
 	tasks map[string]*Task
+	index *TaskIndex // tree queries over tasks, replaced whenever tasks changes
+	seq   uint64     // sequence number of the latest change
 	mu    sync.RWMutex
 }
 
 // NewInMemoryTaskRepository creates a new in-memory task repository
 func NewInMemoryTaskRepository() *InMemoryTaskRepository {
-	return &InMemoryTaskRepository{
-		tasks: make(map[string]*Task),
-	}
+	tasks := make(map[string]*Task)
+	return &InMemoryTaskRepository{tasks: tasks, index: NewTaskIndex(tasks)}
 }
 
 // Save persists a task (create or update)
 func (r *InMemoryTaskRepository) Save(task *Task) error {
 	if task == nil {
-		return NewValidationError("task", "task cannot be nil")
+		return NewValidationError("task", "nil-task", "task cannot be nil")
 	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.assignSeq(task)
 	r.tasks[task.ID().String()] = task
+	r.reindex()
+	return nil
+}
+
+// SaveAll persists multiple tasks in a single operation
+func (r *InMemoryTaskRepository) SaveAll(tasks []*Task) error {
+	for _, task := range tasks {
+		if task == nil {
+			return NewValidationError("task", "nil-task", "task cannot be nil")
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, task := range tasks {
+		r.assignSeq(task)
+		r.tasks[task.ID().String()] = task
+	}
+	r.reindex()
 	return nil
 }
 
+// ReplaceAll replaces the entire collection with the given tasks
+func (r *InMemoryTaskRepository) ReplaceAll(tasks []*Task) error {
+	for _, task := range tasks {
+		if task == nil {
+			return NewValidationError("task", "nil-task", "task cannot be nil")
+		}
+	}
+
+	replacement := make(map[string]*Task, len(tasks))
+	for _, task := range tasks {
+		replacement[task.ID().String()] = task
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, task := range tasks {
+		r.assignSeq(task)
+	}
+	r.tasks = replacement
+	r.reindex()
+	return nil
+}
+
+// reindex replaces the index after a change to the collection
+// Note: This method assumes the write lock is already held by the caller
+func (r *InMemoryTaskRepository) reindex() {
+	r.index = NewTaskIndex(r.tasks)
+}
+
+// assignSeq assigns the next sequence number to a task being saved
+// Note: This method assumes the write lock is already held by the caller
+func (r *InMemoryTaskRepository) assignSeq(task *Task) {
+	r.seq++
+	task.AssignSeq(r.seq)
+}
+
+// LastSeq returns the sequence number of the latest change
+func (r *InMemoryTaskRepository) LastSeq() (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.seq, nil
+}
+
+// WithTransaction runs fn against a staged copy of the collection and swaps it in if fn succeeds
+// The write lock is held throughout, so fn must only use tx, not the repository itself
+func (r *InMemoryTaskRepository) WithTransaction(fn func(tx TaskRepositoryTx) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tasks := make([]*Task, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		tasks = append(tasks, task)
+	}
+
+	staged := NewStagedTaskRepository(tasks, r.seq)
+	if err := fn(staged); err != nil {
+		return err
+	}
+
+	r.tasks = staged.tasks
+	r.index = staged.index
+	r.seq = staged.seq
+	return nil
+}
+
+// NewStagedTaskRepository creates an in-memory repository holding copies of the given tasks
+// whose sequence numbers continue from lastSeq
+// Repositories use it as the working set of a transaction, taking over its LastSeq on commit
+func NewStagedTaskRepository(tasks []*Task, lastSeq uint64) *InMemoryTaskRepository {
+	staged := make(map[string]*Task, len(tasks))
+	for _, task := range tasks {
+		staged[task.ID().String()] = task.Clone()
+	}
+	return &InMemoryTaskRepository{tasks: staged, index: NewTaskIndex(staged), seq: lastSeq}
+}
+
 // FindByID retrieves a task by its ID
 func (r *InMemoryTaskRepository) FindByID(id TaskID) (*Task, error) {
 	r.mu.RLock()
@@ -70,18 +170,40 @@ func (r *InMemoryTaskRepository) FindByParentID(parentID *TaskID) ([]*Task, erro
 	return result, nil
 }
 
+// CountByParentID returns the number of tasks with the given parent ID
+// Unlike FindByParentID, it neither collects nor sorts the children
+func (r *InMemoryTaskRepository) CountByParentID(parentID *TaskID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.index.CountChildren(parentID), nil
+}
+
+// NextChildPosition returns the position after the last child of the given parent
+func (r *InMemoryTaskRepository) NextChildPosition(parentID *TaskID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.index.NextChildPosition(parentID), nil
+}
+
 // FindRoot retrieves the root task (task with no parent)
+// If several tasks have no parent, the earliest created is returned (tie-broken by ID)
 func (r *InMemoryTaskRepository) FindRoot() (*Task, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	var root *Task
 	for _, task := range r.tasks {
-		if task.ParentID() == nil {
-			return task, nil
+		if task.ParentID() == nil && (root == nil || task.CreatedBefore(root)) {
+			root = task
 		}
 	}
 
-	return nil, NewNotFoundError("Root Task", "root")
+	if root == nil {
+		return nil, NewNotFoundError("Root Task", "root")
+	}
+	return root, nil
 }
 
 // FindAll retrieves all tasks
@@ -97,6 +219,67 @@ func (r *InMemoryTaskRepository) FindAll() ([]*Task, error) {
 	return result, nil
 }
 
+// FindByDescription retrieves all tasks whose description exactly matches (case-sensitive),
+// ordered by creation time (tie-broken by ID)
+func (r *InMemoryTaskRepository) FindByDescription(description string) ([]*Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.index.WithDescription(description), nil
+}
+
+// ForEach calls fn for every task under a single read lock, stopping at the first error
+func (r *InMemoryTaskRepository) ForEach(fn func(task *Task) error) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, task := range r.tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Count returns the total number of tasks
+func (r *InMemoryTaskRepository) Count() (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.tasks), nil
+}
+
+// CountByStatus returns the number of tasks in each status
+func (r *InMemoryTaskRepository) CountByStatus() (map[Status]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[Status]int)
+	for _, task := range r.tasks {
+		counts[task.Status()]++
+	}
+
+	return counts, nil
+}
+
+// FindSubtree retrieves the given task and all its descendants in depth-first order
+// The parent-to-children index is built once under a single read lock
+func (r *InMemoryTaskRepository) FindSubtree(rootID TaskID) ([]*Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.index.Subtree(rootID)
+}
+
+// CountSubtree returns the number of tasks in the given task's subtree (itself included)
+func (r *InMemoryTaskRepository) CountSubtree(rootID TaskID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.index.CountSubtree(rootID)
+}
+
 // Delete removes a task by its ID
 func (r *InMemoryTaskRepository) Delete(id TaskID) error {
 	r.mu.Lock()
@@ -107,6 +290,8 @@ func (r *InMemoryTaskRepository) Delete(id TaskID) error {
 	}
 
 	delete(r.tasks, id.String())
+	r.reindex()
+	r.seq++
 	return nil
 }
 
@@ -115,37 +300,18 @@ func (r *InMemoryTaskRepository) DeleteSubtree(id TaskID) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Check if the task exists
-	if _, exists := r.tasks[id.String()]; !exists {
-		return NewNotFoundError("Task", id.String())
+	// Collect the task and all its descendants, failing if the task does not exist
+	subtree, err := r.index.Subtree(id)
+	if err != nil {
+		return err
 	}
 
-	// Collect all tasks to delete (the task and all its descendants)
-	toDelete := r.collectDescendants(id)
-	toDelete = append(toDelete, id)
-
 	// Delete all collected tasks
-	for _, taskID := range toDelete {
-		delete(r.tasks, taskID.String())
+	for _, task := range subtree {
+		delete(r.tasks, task.ID().String())
 	}
+	r.reindex()
+	r.seq++
 
 	return nil
 }
-
-// collectDescendants recursively collects all descendant task IDs
-// Note: This method assumes the lock is already held by the caller
-func (r *InMemoryTaskRepository) collectDescendants(parentID TaskID) []TaskID {
-	var descendants []TaskID
-
-	// Find all direct children
-	for _, task := range r.tasks {
-		if task.ParentID() != nil && task.ParentID().Equals(parentID) {
-			descendants = append(descendants, task.ID())
-			// Recursively collect descendants of this child
-			childDescendants := r.collectDescendants(task.ID())
-			descendants = append(descendants, childDescendants...)
-		}
-	}
-
-	return descendants
-}
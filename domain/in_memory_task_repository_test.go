@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 )
 
 func TestInMemoryTaskRepository_Save(t *testing.T) {
@@ -274,3 +275,300 @@ func TestInMemoryTaskRepository_DeleteSubtreeNotFound(t *testing.T) {
 		t.Error("Expected NotFoundError when deleting non-existent subtree")
 	}
 }
+
+func TestInMemoryTaskRepository_SaveAll(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	root, _ := NewTask("Root", nil, 0)
+	child, _ := NewTask("Child", &root.id, 0)
+
+	if err := repo.SaveAll([]*Task{root, child}); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	all, _ := repo.FindAll()
+	if len(all) != 2 {
+		t.Errorf("Expected 2 tasks, got %d", len(all))
+	}
+}
+
+func TestInMemoryTaskRepository_SaveAllNil(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	root, _ := NewTask("Root", nil, 0)
+
+	err := repo.SaveAll([]*Task{root, nil})
+	if err == nil {
+		t.Error("Expected error when saving nil task")
+	}
+
+	// Verify nothing was saved
+	all, _ := repo.FindAll()
+	if len(all) != 0 {
+		t.Errorf("Expected 0 tasks, got %d", len(all))
+	}
+}
+
+func TestInMemoryTaskRepository_FindSubtree(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// Create tree: root -> a -> a1
+	//                   -> b
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 1)
+	a1, _ := NewTask("A1", &a.id, 0)
+	_ = repo.SaveAll([]*Task{b, a1, root, a})
+
+	subtree, err := repo.FindSubtree(root.ID())
+	if err != nil {
+		t.Fatalf("FindSubtree failed: %v", err)
+	}
+
+	// Depth-first order with siblings ordered by position
+	expected := []*Task{root, a, a1, b}
+	if len(subtree) != len(expected) {
+		t.Fatalf("Expected %d tasks, got %d", len(expected), len(subtree))
+	}
+	for i, task := range expected {
+		if !subtree[i].ID().Equals(task.ID()) {
+			t.Errorf("Expected %s at index %d, got %s", task.Description(), i, subtree[i].Description())
+		}
+	}
+
+	// Subtree of a branch excludes the rest of the tree
+	branch, _ := repo.FindSubtree(a.ID())
+	if len(branch) != 2 {
+		t.Errorf("Expected 2 tasks in branch, got %d", len(branch))
+	}
+}
+
+func TestInMemoryTaskRepository_FindSubtreeNotFound(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	_, err := repo.FindSubtree(NewTaskID())
+	if _, ok := err.(NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError, got %T", err)
+	}
+}
+
+func TestInMemoryTaskRepository_CountSubtree(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// Create tree: root -> a -> a1
+	//                   -> b
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 1)
+	a1, _ := NewTask("A1", &a.id, 0)
+	_ = repo.SaveAll([]*Task{b, a1, root, a})
+
+	for _, tt := range []struct {
+		task     *Task
+		expected int
+	}{{root, 4}, {a, 2}, {a1, 1}, {b, 1}} {
+		count, err := repo.CountSubtree(tt.task.ID())
+		if err != nil {
+			t.Fatalf("CountSubtree failed: %v", err)
+		}
+		if count != tt.expected {
+			t.Errorf("Expected %d tasks under %s, got %d", tt.expected, tt.task.Description(), count)
+		}
+	}
+
+	if _, err := repo.CountSubtree(NewTaskID()); err == nil {
+		t.Error("Expected error for non-existent task, got nil")
+	}
+}
+
+func TestInMemoryTaskRepository_CountByParentID(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// Create tree: root -> a -> a1
+	//                   -> b
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 1)
+	a1, _ := NewTask("A1", &a.id, 0)
+	_ = repo.SaveAll([]*Task{b, a1, root, a})
+
+	for _, tt := range []struct {
+		parentID *TaskID
+		expected int
+	}{{nil, 1}, {&root.id, 2}, {&a.id, 1}, {&b.id, 0}} {
+		count, err := repo.CountByParentID(tt.parentID)
+		if err != nil {
+			t.Fatalf("CountByParentID failed: %v", err)
+		}
+		if count != tt.expected {
+			t.Errorf("Expected %d children, got %d", tt.expected, count)
+		}
+	}
+}
+
+func TestInMemoryTaskRepository_NextChildPosition(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// Create tree: root -> a (0), b (2) with a gap at 1
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 2)
+	_ = repo.SaveAll([]*Task{root, a, b})
+
+	for _, tt := range []struct {
+		parentID *TaskID
+		expected int
+	}{{nil, 1}, {&root.id, 3}, {&a.id, 0}} {
+		next, err := repo.NextChildPosition(tt.parentID)
+		if err != nil {
+			t.Fatalf("NextChildPosition failed: %v", err)
+		}
+		if next != tt.expected {
+			t.Errorf("Expected next position %d, got %d", tt.expected, next)
+		}
+	}
+}
+
+func TestInMemoryTaskRepository_Seq(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	_ = repo.Save(root)
+	_ = repo.Save(a)
+	if root.Seq() != 1 || a.Seq() != 2 {
+		t.Fatalf("Expected seqs 1 and 2, got %d and %d", root.Seq(), a.Seq())
+	}
+
+	// A failed transaction consumes no numbers; a committed one continues the sequence
+	_ = repo.WithTransaction(func(tx TaskRepositoryTx) error {
+		staged, _ := tx.FindByID(a.ID())
+		_ = tx.Save(staged)
+		return NewValidationError("test", "rolled-back", "rolled back")
+	})
+	_ = repo.WithTransaction(func(tx TaskRepositoryTx) error {
+		staged, _ := tx.FindByID(root.ID())
+		return tx.Save(staged)
+	})
+	if stored, _ := repo.FindByID(root.ID()); stored.Seq() != 3 {
+		t.Errorf("Expected root at seq 3, got %d", stored.Seq())
+	}
+
+	// Deletes consume a number too
+	_ = repo.Delete(a.ID())
+	if seq, _ := repo.LastSeq(); seq != 4 {
+		t.Errorf("Expected last seq 4, got %d", seq)
+	}
+}
+
+func TestInMemoryTaskRepository_FindByDescription(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// Two tasks share a description; created times order them
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	root := ReconstructTask(TaskFields{ID: NewTaskID(), Description: "Root", Status: StatusRootWorkItem, CreatedAt: created, UpdatedAt: created})
+	later := ReconstructTask(TaskFields{ID: NewTaskID(), Description: "Deploy", Status: StatusTODO, ParentID: &root.id, CreatedAt: created.Add(time.Hour), UpdatedAt: created})
+	earlier := ReconstructTask(TaskFields{ID: NewTaskID(), Description: "Deploy", Status: StatusTODO, ParentID: &root.id, Position: 1, CreatedAt: created.Add(time.Minute), UpdatedAt: created})
+	other := ReconstructTask(TaskFields{ID: NewTaskID(), Description: "deploy", Status: StatusTODO, ParentID: &root.id, Position: 2, CreatedAt: created, UpdatedAt: created})
+	_ = repo.SaveAll([]*Task{root, later, earlier, other})
+
+	matches, err := repo.FindByDescription("Deploy")
+	if err != nil {
+		t.Fatalf("FindByDescription failed: %v", err)
+	}
+	if len(matches) != 2 || !matches[0].ID().Equals(earlier.ID()) || !matches[1].ID().Equals(later.ID()) {
+		t.Errorf("Expected both Deploy tasks, oldest first, got %v", matches)
+	}
+
+	if matches, _ := repo.FindByDescription("Root"); len(matches) != 1 {
+		t.Errorf("Expected 1 match, got %d", len(matches))
+	}
+	if matches, _ := repo.FindByDescription("Missing"); matches == nil || len(matches) != 0 {
+		t.Errorf("Expected an empty list, got %v", matches)
+	}
+}
+
+func TestInMemoryTaskRepository_FindRootMultipleRootsTieBreak(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	// Same creation time, so the lower ID wins
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lowID, _ := TaskIDFromString("00000000-0000-4000-8000-000000000000")
+	highID, _ := TaskIDFromString("ffffffff-ffff-4fff-bfff-ffffffffffff")
+	low := ReconstructTask(TaskFields{ID: lowID, Description: "Low", Status: StatusRootWorkItem, CreatedAt: created, UpdatedAt: created})
+	high := ReconstructTask(TaskFields{ID: highID, Description: "High", Status: StatusRootWorkItem, CreatedAt: created, UpdatedAt: created})
+	_ = repo.SaveAll([]*Task{high, low})
+
+	for i := 0; i < 10; i++ {
+		root, err := repo.FindRoot()
+		if err != nil {
+			t.Fatalf("FindRoot failed: %v", err)
+		}
+		if !root.ID().Equals(lowID) {
+			t.Fatalf("expected root with lowest ID, got %s", root.ID())
+		}
+	}
+}
+
+func TestInMemoryTaskRepository_WithTransactionCommits(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	root, _ := NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+
+	err := repo.WithTransaction(func(tx TaskRepositoryTx) error {
+		child, _ := NewTask("Child", &root.id, 0)
+		if err := tx.Save(child); err != nil {
+			return err
+		}
+		staged, err := tx.FindByID(root.ID())
+		if err != nil {
+			return err
+		}
+		return staged.UpdateDescription("Renamed")
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+
+	if count, _ := repo.Count(); count != 2 {
+		t.Errorf("Expected 2 tasks after commit, got %d", count)
+	}
+	stored, _ := repo.FindByID(root.ID())
+	if stored.Description() != "Renamed" {
+		t.Errorf("Expected committed description 'Renamed', got %q", stored.Description())
+	}
+}
+
+func TestInMemoryTaskRepository_WithTransactionRollsBack(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+
+	root, _ := NewTask("Root", nil, 0)
+	child, _ := NewTask("Child", &root.id, 0)
+	_ = repo.SaveAll([]*Task{root, child})
+
+	failure := NewConstraintViolationError("test", "abort")
+	err := repo.WithTransaction(func(tx TaskRepositoryTx) error {
+		// Change a task in place, add one and delete one before failing
+		staged, _ := tx.FindByID(root.ID())
+		_ = staged.UpdateDescription("Renamed")
+		extra, _ := NewTask("Extra", &root.id, 1)
+		_ = tx.Save(extra)
+		_ = tx.Delete(child.ID())
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("Expected the transaction's error, got %v", err)
+	}
+
+	if count, _ := repo.Count(); count != 2 {
+		t.Errorf("Expected 2 tasks after rollback, got %d", count)
+	}
+	if _, err := repo.FindByID(child.ID()); err != nil {
+		t.Errorf("Expected deleted child to be restored, got %v", err)
+	}
+	stored, _ := repo.FindByID(root.ID())
+	if stored.Description() != "Root" {
+		t.Errorf("Expected in-place change to be discarded, got %q", stored.Description())
+	}
+}
@@ -0,0 +1,139 @@
+package domain
+
+import "sort"
+
+// positionTrackingRepository records the parent of every task an operation reads or writes,
+// so the children of those parents can be renumbered once the operation is done
+// Any task whose position or parent changes has to be read first, so the recorded parents
+// cover every sibling list the operation may have left with gaps or duplicates
+type positionTrackingRepository struct {
+	TaskRepositoryTx
+	parents map[string]TaskID
+}
+
+// newPositionTrackingRepository wraps repo to record the parents an operation touches
+func newPositionTrackingRepository(repo TaskRepositoryTx) *positionTrackingRepository {
+	return &positionTrackingRepository{
+		TaskRepositoryTx: repo,
+		parents:          make(map[string]TaskID),
+	}
+}
+
+// touchParent records parentID; root tasks have no siblings to renumber
+func (r *positionTrackingRepository) touchParent(parentID *TaskID) {
+	if parentID != nil {
+		r.parents[parentID.String()] = *parentID
+	}
+}
+
+// touchTasks records the parents of tasks
+func (r *positionTrackingRepository) touchTasks(tasks []*Task) {
+	for _, task := range tasks {
+		r.touchParent(task.ParentID())
+	}
+}
+
+// Save persists a task and records its parent
+func (r *positionTrackingRepository) Save(task *Task) error {
+	r.touchParent(task.ParentID())
+	return r.TaskRepositoryTx.Save(task)
+}
+
+// SaveAll persists tasks and records their parents
+func (r *positionTrackingRepository) SaveAll(tasks []*Task) error {
+	r.touchTasks(tasks)
+	return r.TaskRepositoryTx.SaveAll(tasks)
+}
+
+// ReplaceAll replaces the collection and records the parents of the new tasks
+func (r *positionTrackingRepository) ReplaceAll(tasks []*Task) error {
+	r.touchTasks(tasks)
+	return r.TaskRepositoryTx.ReplaceAll(tasks)
+}
+
+// FindByID retrieves a task and records its parent
+func (r *positionTrackingRepository) FindByID(id TaskID) (*Task, error) {
+	task, err := r.TaskRepositoryTx.FindByID(id)
+	if err == nil {
+		r.touchParent(task.ParentID())
+	}
+	return task, err
+}
+
+// FindByParentID retrieves a parent's children and records the parent
+func (r *positionTrackingRepository) FindByParentID(parentID *TaskID) ([]*Task, error) {
+	r.touchParent(parentID)
+	return r.TaskRepositoryTx.FindByParentID(parentID)
+}
+
+// NextChildPosition returns the parent's next free position and records the parent
+func (r *positionTrackingRepository) NextChildPosition(parentID *TaskID) (int, error) {
+	r.touchParent(parentID)
+	return r.TaskRepositoryTx.NextChildPosition(parentID)
+}
+
+// FindAll retrieves all tasks and records their parents
+func (r *positionTrackingRepository) FindAll() ([]*Task, error) {
+	tasks, err := r.TaskRepositoryTx.FindAll()
+	if err == nil {
+		r.touchTasks(tasks)
+	}
+	return tasks, err
+}
+
+// ForEach calls fn for every task and records their parents
+func (r *positionTrackingRepository) ForEach(fn func(task *Task) error) error {
+	return r.TaskRepositoryTx.ForEach(func(task *Task) error {
+		r.touchParent(task.ParentID())
+		return fn(task)
+	})
+}
+
+// FindSubtree retrieves a subtree and records the parents of its tasks
+func (r *positionTrackingRepository) FindSubtree(rootID TaskID) ([]*Task, error) {
+	tasks, err := r.TaskRepositoryTx.FindSubtree(rootID)
+	if err == nil {
+		r.touchTasks(tasks)
+	}
+	return tasks, err
+}
+
+// normalizePositions renumbers the children of every recorded parent to 0..n-1,
+// keeping their current order, persists the tasks whose position changed and returns their number
+// Renumbering is not a move, so it is not recorded in the tasks' move history
+func (r *positionTrackingRepository) normalizePositions() (int, error) {
+	var renumbered []*Task
+	for _, parentID := range r.parents {
+		// A parent deleted by the operation has no children left to find
+		children, err := r.TaskRepositoryTx.FindByParentID(&parentID)
+		if err != nil {
+			return 0, err
+		}
+
+		// Break position ties by ID so duplicates are renumbered deterministically
+		sort.SliceStable(children, func(i, j int) bool {
+			if children[i].Position() != children[j].Position() {
+				return children[i].Position() < children[j].Position()
+			}
+			return children[i].ID().String() < children[j].ID().String()
+		})
+
+		for i, child := range children {
+			if child.Position() == i {
+				continue
+			}
+			if err := child.Move(child.ParentID(), i); err != nil {
+				return 0, err
+			}
+			renumbered = append(renumbered, child)
+		}
+	}
+
+	if len(renumbered) == 0 {
+		return 0, nil
+	}
+	if err := r.TaskRepositoryTx.SaveAll(renumbered); err != nil {
+		return 0, err
+	}
+	return len(renumbered), nil
+}
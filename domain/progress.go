@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusWeights maps statuses to the share of a task, from 0 to 1, that counts as done
+// Statuses left out weigh 1 if they count as complete and 0 otherwise
+type StatusWeights map[Status]float64
+
+// ParseStatusWeights parses Name=weight entries, such as "In Progress=0.5"
+// Each weight must be between 0 and 1; Root Work Item is not counted in progress, so it
+// cannot be weighted
+func ParseStatusWeights(entries []string) (StatusWeights, error) {
+	weights := make(StatusWeights, len(entries))
+	for _, entry := range entries {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid status weight %q (must be Name=weight)", entry)
+		}
+		status, err := NewStatus(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status weight %q: unknown status", entry)
+		}
+		if status == StatusRootWorkItem {
+			return nil, fmt.Errorf("invalid status weight %q: Root Work Item is not counted in progress", entry)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 || weight > 1 {
+			return nil, fmt.Errorf("invalid status weight %q: weight must be a number from 0 to 1", entry)
+		}
+		weights[status] = weight
+	}
+	return weights, nil
+}
+
+// Progress reports how much of a set of tasks is done
+// Root Work Item tasks are not counted
+type Progress struct {
+	Tasks           int            // Tasks counted
+	Completed       int            // Counted tasks in a complete status
+	ByStatus        map[Status]int // Counted tasks per status; statuses no task has are absent
+	Percent         float64        // Completed tasks as a percentage of counted tasks
+	WeightedPercent float64        // Sum of the tasks' status weights as a percentage of counted tasks
+}
+
+// ProgressCalculator computes progress, crediting each task with the weight of its status
+type ProgressCalculator struct {
+	weights  StatusWeights
+	complete CompleteStatuses
+}
+
+// NewProgressCalculator creates a ProgressCalculator with the given weights, counting tasks in
+// one of the complete statuses as completed
+// Nil weights credit complete tasks fully and others not at all, so the weighted
+// percentage equals the binary one
+func NewProgressCalculator(weights StatusWeights, complete CompleteStatuses) *ProgressCalculator {
+	return &ProgressCalculator{weights: weights, complete: complete}
+}
+
+// Weight returns the share of a task in the given status that counts as done
+func (p *ProgressCalculator) Weight(status Status) float64 {
+	if weight, ok := p.weights[status]; ok {
+		return weight
+	}
+	if p.complete.Contains(status) {
+		return 1
+	}
+	return 0
+}
+
+// Calculate computes the progress of tasks; no counted tasks is 0% done
+func (p *ProgressCalculator) Calculate(tasks []*Task) Progress {
+	progress := Progress{ByStatus: make(map[Status]int)}
+	credit := 0.0
+	for _, task := range tasks {
+		if task.Status() == StatusRootWorkItem {
+			continue
+		}
+		progress.Tasks++
+		progress.ByStatus[task.Status()]++
+		if p.complete.Contains(task.Status()) {
+			progress.Completed++
+		}
+		credit += p.Weight(task.Status())
+	}
+
+	if progress.Tasks > 0 {
+		progress.Percent = 100 * float64(progress.Completed) / float64(progress.Tasks)
+		progress.WeightedPercent = 100 * credit / float64(progress.Tasks)
+	}
+	return progress
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// buildProgressSubtree builds root -> A (DONE), B (In Progress), C (In Progress), D (TODO)
+// and returns the whole tree as from FindSubtree
+func buildProgressSubtree(t *testing.T) []*Task {
+	t.Helper()
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+	statuses := []Status{StatusDONE, StatusInProgress, StatusInProgress, StatusTODO}
+	for _, status := range statuses {
+		child, err := service.CreateChildTask("Child", root.ID())
+		if err != nil {
+			t.Fatalf("failed to create child: %v", err)
+		}
+		if err := service.ChangeTaskStatus(child.ID(), status); err != nil {
+			t.Fatalf("failed to set status: %v", err)
+		}
+	}
+	subtree, err := repo.FindSubtree(root.ID())
+	if err != nil {
+		t.Fatalf("failed to read subtree: %v", err)
+	}
+	return subtree
+}
+
+func TestProgressCalculator_BinaryAndWeighted(t *testing.T) {
+	subtree := buildProgressSubtree(t)
+
+	tests := []struct {
+		name     string
+		weights  StatusWeights
+		percent  float64
+		weighted float64
+	}{
+		{"default reproduces binary progress", nil, 25, 25},
+		{"in progress counts half", StatusWeights{StatusInProgress: 0.5}, 25, 50},
+		{"done can weigh less than complete", StatusWeights{StatusDONE: 0.5, StatusTODO: 0.25}, 25, 18.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := NewProgressCalculator(tt.weights, CompleteStatuses{}).Calculate(subtree)
+
+			// The root is not counted
+			if progress.Tasks != 4 || progress.Completed != 1 {
+				t.Errorf("expected 1 of 4 tasks completed, got %d of %d", progress.Completed, progress.Tasks)
+			}
+			if progress.ByStatus[StatusInProgress] != 2 {
+				t.Errorf("expected 2 tasks in progress, got %d", progress.ByStatus[StatusInProgress])
+			}
+			if math.Abs(progress.Percent-tt.percent) > 1e-9 {
+				t.Errorf("expected binary progress %v%%, got %v%%", tt.percent, progress.Percent)
+			}
+			if math.Abs(progress.WeightedPercent-tt.weighted) > 1e-9 {
+				t.Errorf("expected weighted progress %v%%, got %v%%", tt.weighted, progress.WeightedPercent)
+			}
+		})
+	}
+}
+
+func TestProgressCalculator_NoTasks(t *testing.T) {
+	progress := NewProgressCalculator(StatusWeights{StatusInProgress: 0.5}, CompleteStatuses{}).Calculate(nil)
+	if progress.Tasks != 0 || progress.Percent != 0 || progress.WeightedPercent != 0 {
+		t.Errorf("expected empty progress, got %+v", progress)
+	}
+}
+
+func TestParseStatusWeights(t *testing.T) {
+	weights, err := ParseStatusWeights([]string{"In Progress = 0.5", "Blocked=0"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if weights[StatusInProgress] != 0.5 || weights[StatusBlocked] != 0 || len(weights) != 2 {
+		t.Errorf("unexpected weights %v", weights)
+	}
+
+	for _, entries := range [][]string{
+		{"In Progress"},
+		{"Doing=0.5"},
+		{"In Progress=1.5"},
+		{"In Progress=-0.1"},
+		{"In Progress=half"},
+		{"Root Work Item=0.5"},
+	} {
+		if _, err := ParseStatusWeights(entries); err == nil {
+			t.Errorf("expected error parsing %v", entries)
+		}
+	}
+}
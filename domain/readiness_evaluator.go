@@ -1,49 +1,247 @@
 package domain
 
+import "sync"
+
 // ReadinessEvaluator evaluates whether a task is ready to be worked on
 type ReadinessEvaluator interface {
 	// EvaluateReadiness evaluates the readiness state of a task based on ordering constraints
 	EvaluateReadiness(taskID TaskID) (ReadinessState, error)
+
+	// FindBlockers returns the incomplete tasks that keep a task from being ready
+	FindBlockers(taskID TaskID) ([]Blocker, error)
+
+	// IsFrontier reports whether a task is the next thing to do in its tree
+	IsFrontier(taskID TaskID) (bool, error)
+}
+
+// BlockerType identifies how a blocker keeps a task from being ready
+type BlockerType string
+
+const (
+	// BlockerLeftSibling is an incomplete left sibling, which has to be completed first
+	BlockerLeftSibling BlockerType = "leftSibling"
+	// BlockerChild is an incomplete child, which has to be completed before its parent
+	BlockerChild BlockerType = "child"
+)
+
+// Blocker is an incomplete task that keeps another task from being ready
+type Blocker struct {
+	Type BlockerType
+	Task *Task
 }
 
 // ReadinessEvaluatorService implements ReadinessEvaluator
 type ReadinessEvaluatorService struct {
-	repo      TaskRepository
+	repo      TaskRepositoryTx
 	navigator TreeNavigator
+	complete  CompleteStatuses
+
+	// Cache of evaluated states, only used when created with NewCachedReadinessEvaluatorService
+	cacheEnabled bool
+	cacheMu      sync.Mutex
+	cache        map[TaskID]readinessCacheEntry
+	dependents   map[TaskID]map[TaskID]bool // task -> cached tasks whose state was computed from it
+	generation   uint64                     // bumped on every invalidation to discard in-flight results
+}
+
+// readinessCacheEntry is a cached state together with the tasks it was computed from
+type readinessCacheEntry struct {
+	state     ReadinessState
+	dependsOn []TaskID // left sibling and children at evaluation time
 }
 
 // NewReadinessEvaluatorService creates a new ReadinessEvaluatorService
-func NewReadinessEvaluatorService(repo TaskRepository, navigator TreeNavigator) *ReadinessEvaluatorService {
+// Evaluation only reads, so it also works on a transaction's repository
+// Tasks in one of the complete statuses count as complete
+func NewReadinessEvaluatorService(repo TaskRepositoryTx, navigator TreeNavigator, complete CompleteStatuses) *ReadinessEvaluatorService {
 	return &ReadinessEvaluatorService{
 		repo:      repo,
 		navigator: navigator,
+		complete:  complete,
+	}
+}
+
+// NewCachedReadinessEvaluatorService creates a ReadinessEvaluatorService that caches states by task ID
+// Entries are invalidated through change events from repo when the task, its parent's children,
+// its left sibling, or its children are saved or deleted
+func NewCachedReadinessEvaluatorService(repo *ObservableTaskRepository, navigator TreeNavigator, complete CompleteStatuses) *ReadinessEvaluatorService {
+	s := &ReadinessEvaluatorService{
+		repo:         repo,
+		navigator:    navigator,
+		complete:     complete,
+		cacheEnabled: true,
+		cache:        make(map[TaskID]readinessCacheEntry),
+		dependents:   make(map[TaskID]map[TaskID]bool),
 	}
+	repo.Subscribe(s.handleTaskChange)
+	return s
 }
 
 // EvaluateReadiness evaluates the readiness state of a task
 // A task is ready if:
-// 1. Its left sibling is DONE (or it has no left sibling)
-// 2. All its children are DONE (or it has no children)
+// 1. Its left sibling is complete (or it has no left sibling)
+// 2. All its children are complete (or it has no children)
+// A status is complete if it is one of the evaluator's complete statuses, by default just DONE
+// When caching is enabled, a cached state is returned if one is still valid
 func (s *ReadinessEvaluatorService) EvaluateReadiness(taskID TaskID) (ReadinessState, error) {
+	if !s.cacheEnabled {
+		state, _, err := s.evaluate(taskID)
+		return state, err
+	}
+
+	s.cacheMu.Lock()
+	entry, ok := s.cache[taskID]
+	generation := s.generation
+	s.cacheMu.Unlock()
+	if ok {
+		return entry.state, nil
+	}
+
+	state, dependsOn, err := s.evaluate(taskID)
+	if err != nil {
+		return ReadinessState{}, err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	// Skip storing a result that a concurrent change may already have made stale
+	if s.generation == generation {
+		s.storeEntry(taskID, readinessCacheEntry{state: state, dependsOn: dependsOn})
+	}
+	return state, nil
+}
+
+// cachedState returns the cached state of a task, if caching is enabled and one is still valid
+// It never evaluates, so it does not read the repository
+func (s *ReadinessEvaluatorService) cachedState(taskID TaskID) (ReadinessState, bool) {
+	if s == nil || !s.cacheEnabled {
+		return ReadinessState{}, false
+	}
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	entry, ok := s.cache[taskID]
+	return entry.state, ok
+}
+
+// EvaluateReadinessUncached evaluates the readiness state of a task, bypassing the cache
+func (s *ReadinessEvaluatorService) EvaluateReadinessUncached(taskID TaskID) (ReadinessState, error) {
+	state, _, err := s.evaluate(taskID)
+	return state, err
+}
+
+// ClearCache drops every cached state
+func (s *ReadinessEvaluatorService) ClearCache() {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.generation++
+	s.cache = make(map[TaskID]readinessCacheEntry)
+	s.dependents = make(map[TaskID]map[TaskID]bool)
+}
+
+// FindBlockers returns the incomplete tasks that keep a task from being ready: its left
+// sibling, then its children in position order. A ready task has no blockers
+// Blockers are always computed from the current tree, bypassing the cache
+func (s *ReadinessEvaluatorService) FindBlockers(taskID TaskID) ([]Blocker, error) {
+	if _, err := s.repo.FindByID(taskID); err != nil {
+		return nil, err
+	}
+
+	blockers := make([]Blocker, 0)
+
+	leftSibling, err := s.navigator.GetLeftSibling(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if leftSibling != nil && !s.complete.Contains(leftSibling.Status()) {
+		blockers = append(blockers, Blocker{Type: BlockerLeftSibling, Task: leftSibling})
+	}
+
+	children, err := s.navigator.GetChildren(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if !s.complete.Contains(child.Status()) {
+			blockers = append(blockers, Blocker{Type: BlockerChild, Task: child})
+		}
+	}
+
+	return blockers, nil
+}
+
+// IsFrontier reports whether a task is on the completion frontier: the next thing to do in
+// its tree. That is the case when the task is incomplete, all its children are complete, and
+// so are all its left siblings and all the left siblings of each of its ancestors. Where
+// readiness only asks whether a task may be worked on, the frontier is the first incomplete
+// task in depth-first post-order, so a tree has at most one
+// The frontier is always computed from the current tree, bypassing the cache
+func (s *ReadinessEvaluatorService) IsFrontier(taskID TaskID) (bool, error) {
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return false, err
+	}
+	if s.complete.Contains(task.Status()) {
+		return false, nil
+	}
+
+	children, err := s.navigator.GetChildren(taskID)
+	if err != nil {
+		return false, err
+	}
+	for _, child := range children {
+		if !s.complete.Contains(child.Status()) {
+			return false, nil
+		}
+	}
+
+	// Walk up to the root; other roots are separate trees, not left siblings
+	for current := task; current.ParentID() != nil; {
+		siblings, err := s.navigator.GetSiblings(current.ID())
+		if err != nil {
+			return false, err
+		}
+		for _, sibling := range siblings {
+			if sibling.ID() == current.ID() {
+				break
+			}
+			if !s.complete.Contains(sibling.Status()) {
+				return false, nil
+			}
+		}
+
+		current, err = s.navigator.GetParent(current.ID())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// evaluate computes the readiness state of a task along with the tasks it was computed from
+func (s *ReadinessEvaluatorService) evaluate(taskID TaskID) (ReadinessState, []TaskID, error) {
 	// First, verify the task exists
 	_, err := s.repo.FindByID(taskID)
 	if err != nil {
-		return ReadinessState{}, err
+		return ReadinessState{}, nil, err
 	}
 
 	var reasons []string
+	var dependsOn []TaskID
 	leftSiblingComplete := true
 	allChildrenComplete := true
 
 	// Check left sibling completion status
 	leftSibling, err := s.navigator.GetLeftSibling(taskID)
 	if err != nil {
-		return ReadinessState{}, err
+		return ReadinessState{}, nil, err
 	}
 
 	if leftSibling != nil {
-		// Left sibling exists, check if it's DONE
-		if leftSibling.Status() != StatusDONE {
+		dependsOn = append(dependsOn, leftSibling.ID())
+
+		// Left sibling exists, check if it's complete
+		if !s.complete.Contains(leftSibling.Status()) {
 			leftSiblingComplete = false
 			reasons = append(reasons, "left sibling is not complete")
 		}
@@ -53,13 +251,17 @@ func (s *ReadinessEvaluatorService) EvaluateReadiness(taskID TaskID) (ReadinessS
 	// Check all children completion status
 	children, err := s.navigator.GetChildren(taskID)
 	if err != nil {
-		return ReadinessState{}, err
+		return ReadinessState{}, nil, err
+	}
+
+	for _, child := range children {
+		dependsOn = append(dependsOn, child.ID())
 	}
 
 	if len(children) > 0 {
-		// Task has children, check if all are DONE
+		// Task has children, check if all are complete
 		for _, child := range children {
-			if child.Status() != StatusDONE {
+			if !s.complete.Contains(child.Status()) {
 				allChildrenComplete = false
 				reasons = append(reasons, "not all children are complete")
 				break // Only need to find one incomplete child
@@ -69,5 +271,71 @@ func (s *ReadinessEvaluatorService) EvaluateReadiness(taskID TaskID) (ReadinessS
 	// If no children, allChildrenComplete remains true
 
 	// Create and return the readiness state
-	return NewReadinessState(leftSiblingComplete, allChildrenComplete, reasons), nil
+	return NewReadinessState(leftSiblingComplete, allChildrenComplete, reasons), dependsOn, nil
+}
+
+// storeEntry caches an entry and indexes it under the tasks it depends on
+// The caller must hold cacheMu
+func (s *ReadinessEvaluatorService) storeEntry(taskID TaskID, entry readinessCacheEntry) {
+	s.removeEntry(taskID)
+	s.cache[taskID] = entry
+	for _, dependency := range entry.dependsOn {
+		if s.dependents[dependency] == nil {
+			s.dependents[dependency] = make(map[TaskID]bool)
+		}
+		s.dependents[dependency][taskID] = true
+	}
+}
+
+// removeEntry drops the cached entry for a task and its index records
+// The caller must hold cacheMu
+func (s *ReadinessEvaluatorService) removeEntry(taskID TaskID) {
+	entry, ok := s.cache[taskID]
+	if !ok {
+		return
+	}
+	delete(s.cache, taskID)
+	for _, dependency := range entry.dependsOn {
+		delete(s.dependents[dependency], taskID)
+		if len(s.dependents[dependency]) == 0 {
+			delete(s.dependents, dependency)
+		}
+	}
+}
+
+// invalidate drops the cached entries affected by a change to the given task:
+// its own entry (it may have moved), every entry computed from it (as left sibling or child),
+// and the entry of its current parent (it may have just become a child there)
+// The caller must hold cacheMu
+func (s *ReadinessEvaluatorService) invalidate(taskID TaskID, parentID *TaskID) {
+	s.removeEntry(taskID)
+	for dependent := range s.dependents[taskID] {
+		s.removeEntry(dependent)
+	}
+	if parentID != nil {
+		s.removeEntry(*parentID)
+	}
+}
+
+// handleTaskChange invalidates the cache in response to a repository change event
+func (s *ReadinessEvaluatorService) handleTaskChange(event TaskChangeEvent) {
+	if event.All {
+		s.ClearCache()
+		return
+	}
+
+	// Look up current parents before locking; deleted tasks no longer have one
+	parents := make([]*TaskID, len(event.TaskIDs))
+	for i, taskID := range event.TaskIDs {
+		if task, err := s.repo.FindByID(taskID); err == nil {
+			parents[i] = task.ParentID()
+		}
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.generation++
+	for i, taskID := range event.TaskIDs {
+		s.invalidate(taskID, parents[i])
+	}
 }
@@ -202,7 +202,7 @@ func TestReadinessEvaluatorService_EvaluateReadiness(t *testing.T) {
 			// Setup
 			repo := NewInMemoryTaskRepository()
 			navigator := NewTreeNavigatorService(repo)
-			evaluator := NewReadinessEvaluatorService(repo, navigator)
+			evaluator := NewReadinessEvaluatorService(repo, navigator, CompleteStatuses{})
 
 			// Create the tree and get the task ID to evaluate
 			taskID := tt.setupTree(repo)
@@ -234,11 +234,57 @@ func TestReadinessEvaluatorService_EvaluateReadiness(t *testing.T) {
 	}
 }
 
+func TestReadinessEvaluatorService_EvaluateReadiness_CustomCompleteStatus(t *testing.T) {
+	// Treat Blocked as an additional terminal status, standing in for a custom workflow state
+	complete, err := NewCompleteStatuses(StatusDONE, StatusBlocked)
+	if err != nil {
+		t.Fatalf("failed to configure complete statuses: %v", err)
+	}
+
+	// Setup: root -> a (Blocked), b (TODO)
+	repo := NewInMemoryTaskRepository()
+	navigator := NewTreeNavigatorService(repo)
+	evaluator := NewReadinessEvaluatorService(repo, navigator, complete)
+
+	root, _ := NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+	rootID := root.ID()
+	a, _ := NewTask("A", &rootID, 0)
+	_ = a.ChangeStatus(StatusBlocked)
+	_ = repo.Save(a)
+	b, _ := NewTask("B", &rootID, 1)
+	_ = repo.Save(b)
+
+	// The custom complete left sibling makes b ready, exactly as DONE would
+	state, err := evaluator.EvaluateReadiness(b.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !state.IsReady() || !state.LeftSiblingComplete() {
+		t.Errorf("expected task after a custom complete sibling to be ready, got reasons %v", state.Reasons())
+	}
+
+	// The custom complete child makes the root's children complete
+	state, err = evaluator.EvaluateReadiness(root.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if state.AllChildrenComplete() {
+		t.Errorf("expected root children incomplete while b is TODO")
+	}
+	_ = b.ChangeStatus(StatusDONE)
+	_ = repo.Save(b)
+	state, _ = evaluator.EvaluateReadiness(root.ID())
+	if !state.AllChildrenComplete() {
+		t.Errorf("expected root children complete with a Blocked and b DONE, got reasons %v", state.Reasons())
+	}
+}
+
 func TestReadinessEvaluatorService_EvaluateReadiness_NonExistentTask(t *testing.T) {
 	// Setup
 	repo := NewInMemoryTaskRepository()
 	navigator := NewTreeNavigatorService(repo)
-	evaluator := NewReadinessEvaluatorService(repo, navigator)
+	evaluator := NewReadinessEvaluatorService(repo, navigator, CompleteStatuses{})
 
 	// Create a task ID that doesn't exist
 	nonExistentID := NewTaskID()
@@ -260,7 +306,7 @@ func TestReadinessEvaluatorService_EvaluateReadiness_ComplexTree(t *testing.T) {
 	// Setup a more complex tree structure
 	repo := NewInMemoryTaskRepository()
 	navigator := NewTreeNavigatorService(repo)
-	evaluator := NewReadinessEvaluatorService(repo, navigator)
+	evaluator := NewReadinessEvaluatorService(repo, navigator, CompleteStatuses{})
 
 	// Create tree:
 	//        Root
@@ -327,3 +373,200 @@ func TestReadinessEvaluatorService_EvaluateReadiness_ComplexTree(t *testing.T) {
 		})
 	}
 }
+
+func TestReadinessEvaluatorService_FindBlockers_MultipleReasons(t *testing.T) {
+	// Setup: root -> a, b; b -> b1 (DONE), b2, b3
+	// b is blocked by its left sibling a and by its incomplete children b2 and b3
+	repo := NewInMemoryTaskRepository()
+	navigator := NewTreeNavigatorService(repo)
+	evaluator := NewReadinessEvaluatorService(repo, navigator, CompleteStatuses{})
+
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 1)
+	b1, _ := NewTask("B1", &b.id, 0)
+	_ = b1.ChangeStatus(StatusDONE)
+	b2, _ := NewTask("B2", &b.id, 1)
+	b3, _ := NewTask("B3", &b.id, 2)
+	for _, task := range []*Task{root, a, b, b1, b2, b3} {
+		_ = repo.Save(task)
+	}
+
+	blockers, err := evaluator.FindBlockers(b.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Blocker{
+		{Type: BlockerLeftSibling, Task: a},
+		{Type: BlockerChild, Task: b2},
+		{Type: BlockerChild, Task: b3},
+	}
+	if len(blockers) != len(expected) {
+		t.Fatalf("expected %d blockers, got %d: %v", len(expected), len(blockers), blockers)
+	}
+	for i := range expected {
+		if blockers[i].Type != expected[i].Type || blockers[i].Task.ID() != expected[i].Task.ID() {
+			t.Errorf("blocker %d: expected %s %s, got %s %s", i,
+				expected[i].Type, expected[i].Task.Description(), blockers[i].Type, blockers[i].Task.Description())
+		}
+	}
+
+	// A ready task has no blockers
+	blockers, err = evaluator.FindBlockers(b1.ID())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blockers == nil || len(blockers) != 0 {
+		t.Errorf("expected an empty blocker list for a ready task, got %v", blockers)
+	}
+
+	// A missing task is not found
+	if _, err := evaluator.FindBlockers(NewTaskID()); err == nil {
+		t.Error("expected an error for a missing task")
+	}
+}
+
+func TestReadinessEvaluatorService_Cache_StatusChangeInvalidatesAffectedEntries(t *testing.T) {
+	// Setup: root -> a, b, c with every change published to the cache
+	repo := NewObservableTaskRepository(NewInMemoryTaskRepository())
+	service := NewTaskService(repo)
+	evaluator := NewCachedReadinessEvaluatorService(repo, NewTreeNavigatorService(repo), CompleteStatuses{})
+
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+
+	// Warm the cache
+	for _, task := range []*Task{root, a, b, c} {
+		if _, err := evaluator.EvaluateReadiness(task.ID()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	state, _ := evaluator.EvaluateReadiness(b.ID())
+	if state.IsReady() {
+		t.Fatal("expected b not ready while a is TODO")
+	}
+
+	// Completing a affects b (its right sibling) and root (its parent) only
+	if err := service.ChangeTaskStatus(a.ID(), StatusDONE); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, task := range []*Task{root, b} {
+		if _, ok := evaluator.cache[task.ID()]; ok {
+			t.Errorf("expected cache entry for %s to be invalidated", task.Description())
+		}
+	}
+	if _, ok := evaluator.cache[c.ID()]; !ok {
+		t.Error("expected cache entry for c to survive")
+	}
+
+	state, _ = evaluator.EvaluateReadiness(b.ID())
+	if !state.IsReady() {
+		t.Errorf("expected b ready after a is DONE, got reasons %v", state.Reasons())
+	}
+}
+
+func TestReadinessEvaluatorService_Cache_MoveAndBypass(t *testing.T) {
+	// Setup: root -> a, b with every change published to the cache
+	repo := NewObservableTaskRepository(NewInMemoryTaskRepository())
+	service := NewTaskService(repo)
+	evaluator := NewCachedReadinessEvaluatorService(repo, NewTreeNavigatorService(repo), CompleteStatuses{})
+
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+
+	state, _ := evaluator.EvaluateReadiness(b.ID())
+	if state.IsReady() {
+		t.Fatal("expected b not ready while a is TODO")
+	}
+
+	// Moving b in front of a gives it no left sibling
+	rootID := root.ID()
+	if err := service.MoveTask(b.ID(), &rootID, 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	state, _ = evaluator.EvaluateReadiness(b.ID())
+	if !state.IsReady() {
+		t.Errorf("expected b ready after moving first, got reasons %v", state.Reasons())
+	}
+
+	// A change made without publishing is only seen when bypassing or clearing the cache
+	stale, _ := evaluator.EvaluateReadiness(a.ID())
+	stored, _ := repo.FindByID(b.ID())
+	_ = stored.ChangeStatus(StatusDONE)
+	if state, _ := evaluator.EvaluateReadiness(a.ID()); state.IsReady() != stale.IsReady() {
+		t.Error("expected the cached state without a change event")
+	}
+	if state, _ := evaluator.EvaluateReadinessUncached(a.ID()); !state.IsReady() {
+		t.Errorf("expected uncached evaluation to see b DONE, got reasons %v", state.Reasons())
+	}
+	evaluator.ClearCache()
+	if state, _ := evaluator.EvaluateReadiness(a.ID()); !state.IsReady() {
+		t.Errorf("expected a ready after clearing the cache, got reasons %v", state.Reasons())
+	}
+}
+
+func TestReadinessEvaluatorService_IsFrontier(t *testing.T) {
+	// On the plan fixture, F is the first open task in depth-first order. H and J are ready
+	// too (H's left sibling G is DONE, J has no left sibling), but other work comes first
+	repo := NewInMemoryTaskRepository()
+	fixture := newPlanFixture()
+	for _, task := range fixture {
+		if err := repo.Save(task); err != nil {
+			t.Fatalf("failed to save %s: %v", task.Description(), err)
+		}
+	}
+	evaluator := NewReadinessEvaluatorService(repo, NewTreeNavigatorService(repo), CompleteStatuses{})
+
+	tests := []struct {
+		name             string
+		expectedReady    bool
+		expectedFrontier bool
+	}{
+		{"F", true, true},
+		{"H", true, false},
+		{"J", true, false},
+		{"D", true, false}, // ready but already DONE
+		{"B", false, false},
+		{"I", false, false},
+		{"Root", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := fixture[tt.name].ID()
+			state, err := evaluator.EvaluateReadiness(id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if state.IsReady() != tt.expectedReady {
+				t.Errorf("expected IsReady=%v, got %v", tt.expectedReady, state.IsReady())
+			}
+
+			frontier, err := evaluator.IsFrontier(id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if frontier != tt.expectedFrontier {
+				t.Errorf("expected IsFrontier=%v, got %v", tt.expectedFrontier, frontier)
+			}
+		})
+	}
+
+	// Completing F moves the frontier to its right sibling H, past the DONE G
+	f := fixture["F"]
+	_ = f.ChangeStatus(StatusDONE)
+	_ = repo.Save(f)
+	if frontier, _ := evaluator.IsFrontier(fixture["H"].ID()); !frontier {
+		t.Error("expected H to be the frontier once F is DONE")
+	}
+
+	// Unknown tasks are not found
+	if _, err := evaluator.IsFrontier(NewTaskID()); err == nil {
+		t.Error("expected an error for an unknown task")
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Status represents the current state of a task
 type Status int
@@ -36,6 +39,65 @@ func (s Status) IsValid() bool {
 	return s >= StatusTODO && s <= StatusRootWorkItem
 }
 
+// IsComplete reports whether the status counts as complete by default, where only DONE does
+// Services that accept a configured set check it with CompleteStatuses.Contains instead
+func (s Status) IsComplete() bool {
+	return s == StatusDONE
+}
+
+// CompleteStatuses is the set of statuses that count as complete for readiness,
+// bottom-to-top completion, and reopening
+// The zero value holds only DONE, per Status.IsComplete
+type CompleteStatuses struct {
+	statuses map[Status]bool
+}
+
+// NewCompleteStatuses creates the set of the given statuses
+// The set must be non-empty, may not include Root Work Item, which is never complete,
+// and must leave another status incomplete for tasks to be reopened to
+func NewCompleteStatuses(statuses ...Status) (CompleteStatuses, error) {
+	if len(statuses) == 0 {
+		return CompleteStatuses{}, NewValidationError("status", "no-complete-status", "at least one status must count as complete")
+	}
+
+	set := make(map[Status]bool, len(statuses))
+	for _, status := range statuses {
+		if !status.IsValid() {
+			return CompleteStatuses{}, NewValidationError("status", "invalid-status", "invalid status value")
+		}
+		if status == StatusRootWorkItem {
+			return CompleteStatuses{}, NewValidationError("status", "root-work-item-complete", "Root Work Item cannot count as complete")
+		}
+		set[status] = true
+	}
+	if set[StatusTODO] && set[StatusInProgress] && set[StatusDONE] && set[StatusBlocked] {
+		return CompleteStatuses{}, NewValidationError("status", "no-incomplete-status", "at least one status other than Root Work Item must stay incomplete")
+	}
+
+	return CompleteStatuses{statuses: set}, nil
+}
+
+// ParseCompleteStatuses parses status names, such as "DONE" or "Blocked", into a CompleteStatuses
+func ParseCompleteStatuses(names []string) (CompleteStatuses, error) {
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		status, err := NewStatus(strings.TrimSpace(name))
+		if err != nil {
+			return CompleteStatuses{}, err
+		}
+		statuses = append(statuses, status)
+	}
+	return NewCompleteStatuses(statuses...)
+}
+
+// Contains reports whether the status counts as complete
+func (c CompleteStatuses) Contains(s Status) bool {
+	if c.statuses == nil {
+		return s.IsComplete()
+	}
+	return c.statuses[s]
+}
+
 // NewStatus creates a Status from a string value with validation
 func NewStatus(s string) (Status, error) {
 	switch s {
@@ -50,6 +112,6 @@ func NewStatus(s string) (Status, error) {
 	case "Root Work Item":
 		return StatusRootWorkItem, nil
 	default:
-		return Status(-1), NewValidationError("status", fmt.Sprintf("invalid status value: %s", s))
+		return Status(-1), NewValidationError("status", "invalid-status", fmt.Sprintf("invalid status value: %s", s))
 	}
 }
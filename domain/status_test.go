@@ -51,6 +51,69 @@ func TestStatus_IsValid(t *testing.T) {
 	}
 }
 
+func TestStatus_IsComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   Status
+		expected bool
+	}{
+		{"TODO is not complete", StatusTODO, false},
+		{"InProgress is not complete", StatusInProgress, false},
+		{"DONE is complete", StatusDONE, true},
+		{"Blocked is not complete", StatusBlocked, false},
+		{"RootWorkItem is not complete", StatusRootWorkItem, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.status.IsComplete()
+			if result != tt.expected {
+				t.Errorf("Status.IsComplete() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewCompleteStatuses(t *testing.T) {
+	complete, err := NewCompleteStatuses(StatusDONE, StatusBlocked)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !complete.Contains(StatusBlocked) || !complete.Contains(StatusDONE) || complete.Contains(StatusTODO) {
+		t.Error("expected exactly DONE and Blocked to be complete")
+	}
+	if StatusBlocked.IsComplete() {
+		t.Error("expected a configured set to leave the default unchanged")
+	}
+
+	// The zero value holds only DONE
+	var defaults CompleteStatuses
+	if !defaults.Contains(StatusDONE) || defaults.Contains(StatusBlocked) {
+		t.Error("expected the zero value to hold only DONE")
+	}
+
+	invalid := [][]Status{nil, {Status(100)}, {StatusRootWorkItem}, {StatusTODO, StatusInProgress, StatusDONE, StatusBlocked}}
+	for _, statuses := range invalid {
+		if _, err := NewCompleteStatuses(statuses...); err == nil {
+			t.Errorf("expected error for complete statuses %v", statuses)
+		}
+	}
+}
+
+func TestParseCompleteStatuses(t *testing.T) {
+	complete, err := ParseCompleteStatuses([]string{"DONE", " Blocked "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !complete.Contains(StatusBlocked) || complete.Contains(StatusInProgress) {
+		t.Error("expected DONE and Blocked to be complete")
+	}
+
+	if _, err := ParseCompleteStatuses([]string{"DONE", "Shipped"}); err == nil {
+		t.Error("expected error for an unknown status")
+	}
+}
+
 func TestNewStatus(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"sort"
 	"strings"
 	"time"
 )
@@ -14,23 +15,50 @@ type Task struct {
 	position    int     // position among siblings (0-indexed)
 	createdAt   time.Time
 	updatedAt   time.Time
+	completedAt *time.Time     // nil unless the task is complete
+	moveHistory []MoveRecord   // explicit moves of this task, oldest first
+	transitions []StatusRecord // explicit status changes of this task, oldest first
+	tags        []string       // labels, sorted and unique
+	kind        string         // classification, e.g. "task" or "milestone"; see DefaultTaskKind
+	seq         uint64         // store sequence number of the task's latest save, 0 until saved
+}
+
+// MoveRecord describes one explicit move of a task to a new parent and/or position
+type MoveRecord struct {
+	FromParentID *TaskID
+	ToParentID   *TaskID
+	FromPosition int
+	ToPosition   int
+	At           time.Time
+}
+
+// StatusRecord describes one explicit status change of a task
+type StatusRecord struct {
+	From Status
+	To   Status
+	At   time.Time
 }
 
 // NewTask creates a new Task with validation
 // For root tasks, parentID should be nil
 // For child tasks, parentID should point to the parent task
 func NewTask(description string, parentID *TaskID, position int) (*Task, error) {
+	return newTaskWithID(NewTaskID(), description, parentID, position)
+}
+
+// newTaskWithID creates a new Task like NewTask, using the given ID
+func newTaskWithID(id TaskID, description string, parentID *TaskID, position int) (*Task, error) {
 	// Validate description is not empty or whitespace-only
 	if strings.TrimSpace(description) == "" {
-		return nil, NewValidationError("description", "description cannot be empty")
+		return nil, NewValidationError("description", "empty-description", "description cannot be empty")
 	}
 
 	// Validate position is non-negative
 	if position < 0 {
-		return nil, NewValidationError("position", "position must be non-negative")
+		return nil, NewValidationError("position", "negative-position", "position must be non-negative")
 	}
 
-	now := time.Now()
+	now := currentTimestamp()
 	
 	// Determine initial status based on whether this is a root task
 	initialStatus := StatusTODO
@@ -39,13 +67,14 @@ func NewTask(description string, parentID *TaskID, position int) (*Task, error)
 	}
 
 	task := &Task{
-		id:          NewTaskID(),
+		id:          id,
 		description: description,
 		status:      initialStatus,
 		parentID:    parentID,
 		position:    position,
 		createdAt:   now,
 		updatedAt:   now,
+		kind:        DefaultTaskKind,
 	}
 
 	return task, nil
@@ -81,28 +110,125 @@ func (t *Task) CreatedAt() time.Time {
 	return t.createdAt
 }
 
+// CreatedBefore reports whether the task was created before other, tie-broken by ID
+// It gives a stable order for tasks that should be unique but may not be (e.g. multiple roots)
+func (t *Task) CreatedBefore(other *Task) bool {
+	if !t.createdAt.Equal(other.createdAt) {
+		return t.createdAt.Before(other.createdAt)
+	}
+	return t.id.String() < other.id.String()
+}
+
 // UpdatedAt returns the task's last update timestamp
 func (t *Task) UpdatedAt() time.Time {
 	return t.updatedAt
 }
 
+// CompletedAt returns when the task was last marked complete (nil if not complete)
+func (t *Task) CompletedAt() *time.Time {
+	if t.completedAt == nil {
+		return nil
+	}
+	// Return a copy to prevent external mutation
+	completedAt := *t.completedAt
+	return &completedAt
+}
+
+// MoveHistory returns the task's explicit moves, oldest first
+// Siblings renumbered to make room for or close the gap after another task's move are not recorded
+func (t *Task) MoveHistory() []MoveRecord {
+	// Return a copy to prevent external mutation
+	history := make([]MoveRecord, len(t.moveHistory))
+	copy(history, t.moveHistory)
+	return history
+}
+
+// StatusHistory returns the task's explicit status changes, oldest first
+// The initial status and changes made as a side effect of other operations are not recorded
+func (t *Task) StatusHistory() []StatusRecord {
+	// Return a copy to prevent external mutation
+	history := make([]StatusRecord, len(t.transitions))
+	copy(history, t.transitions)
+	return history
+}
+
+// Tags returns the task's tags in sorted order
+func (t *Task) Tags() []string {
+	// Return a copy to prevent external mutation
+	tags := make([]string, len(t.tags))
+	copy(tags, t.tags)
+	return tags
+}
+
+// HasTag returns true if the task carries the given tag
+func (t *Task) HasTag(tag string) bool {
+	i := sort.SearchStrings(t.tags, tag)
+	return i < len(t.tags) && t.tags[i] == tag
+}
+
+// Kind returns the task's kind (DefaultTaskKind unless changed)
+func (t *Task) Kind() string {
+	return t.kind
+}
+
+// Seq returns the store sequence number of the task's latest save (0 if never saved)
+// Sequence numbers grow with every change to the store, so they order changes across clients
+func (t *Task) Seq() uint64 {
+	return t.seq
+}
+
+// AssignSeq sets the task's sequence number
+// It is used by repositories when they save the task or load it from persistent storage
+func (t *Task) AssignSeq(seq uint64) {
+	t.seq = seq
+}
+
 // IsRoot returns true if this is a root task (no parent)
 func (t *Task) IsRoot() bool {
 	return t.parentID == nil
 }
 
+// Clone returns an independent copy of the task; changes to either do not affect the other
+func (t *Task) Clone() *Task {
+	clone := *t
+	clone.parentID = copyTaskID(t.parentID)
+	clone.completedAt = t.CompletedAt()
+	clone.moveHistory = t.MoveHistory()
+	clone.transitions = t.StatusHistory()
+	clone.tags = t.Tags()
+	return &clone
+}
+
 // ChangeStatus updates the task's status with validation
 // This method performs basic status validation (checking if the status is valid)
 // More complex validation (e.g., bottom-to-top enforcement) will be handled by TaskValidator
+// Only DONE counts as complete for CompletedAt; see ChangeStatusWith
 func (t *Task) ChangeStatus(newStatus Status) error {
+	return t.ChangeStatusWith(newStatus, CompleteStatuses{})
+}
+
+// ChangeStatusWith updates the task's status like ChangeStatus, setting CompletedAt when it
+// enters one of the complete statuses and clearing it when it leaves them
+func (t *Task) ChangeStatusWith(newStatus Status, complete CompleteStatuses) error {
 	// Validate that the new status is a valid status value
 	if !newStatus.IsValid() {
-		return NewValidationError("status", "invalid status value")
+		return NewValidationError("status", "invalid-status", "invalid status value")
+	}
+
+	now := currentTimestamp()
+
+	// Track completion separately from updates: set on entering a complete status, clear on leaving it
+	if complete.Contains(newStatus) {
+		if !complete.Contains(t.status) || t.completedAt == nil {
+			t.completedAt = &now
+		}
+	} else {
+		t.completedAt = nil
 	}
 
 	// Update the status and timestamp
 	t.status = newStatus
-	t.updatedAt = time.Now()
+	t.updatedAt = now
 
 	return nil
 }
@@ -111,16 +237,54 @@ func (t *Task) ChangeStatus(newStatus Status) error {
 func (t *Task) UpdateDescription(description string) error {
 	// Validate description is not empty or whitespace-only
 	if strings.TrimSpace(description) == "" {
-		return NewValidationError("description", "description cannot be empty")
+		return NewValidationError("description", "empty-description", "description cannot be empty")
 	}
 
 	// Update the description and timestamp
 	t.description = description
-	t.updatedAt = time.Now()
+	t.updatedAt = currentTimestamp()
 
 	return nil
 }
 
+// UpdateTags adds and removes tags, which must already be valid (see NormalizeTags)
+// Returns true if the task's tags changed, in which case the update timestamp is refreshed
+func (t *Task) UpdateTags(add, remove []string) bool {
+	removing := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removing[tag] = true
+	}
+
+	tags := make([]string, 0, len(t.tags)+len(add))
+	for _, tag := range t.tags {
+		if !removing[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	tags = append(tags, add...)
+	tags = sortedUniqueTags(tags)
+
+	if equalTags(tags, t.tags) {
+		return false
+	}
+
+	t.tags = tags
+	t.updatedAt = currentTimestamp()
+	return true
+}
+
+// UpdateKind sets the task's kind, which must already be valid (see TaskServiceConfig.Kinds)
+// Returns true if the kind changed, in which case the update timestamp is refreshed
+func (t *Task) UpdateKind(kind string) bool {
+	if kind == t.kind {
+		return false
+	}
+
+	t.kind = kind
+	t.updatedAt = currentTimestamp()
+	return true
+}
+
 // Move updates the task's parent and position
 // This method only updates the task's internal state
 // Position adjustments for siblings should be handled by the caller (e.g., TaskService)
@@ -128,13 +292,13 @@ func (t *Task) UpdateDescription(description string) error {
 func (t *Task) Move(newParentID *TaskID, newPosition int) error {
 	// Validate position is non-negative
 	if newPosition < 0 {
-		return NewValidationError("position", "position must be non-negative")
+		return NewValidationError("position", "negative-position", "position must be non-negative")
 	}
 
 	// Update parent and position
 	t.parentID = newParentID
 	t.position = newPosition
-	t.updatedAt = time.Now()
+	t.updatedAt = currentTimestamp()
 
 	// Update status if moving to/from root
 	if newParentID == nil {
@@ -151,25 +315,87 @@ func (t *Task) Move(newParentID *TaskID, newPosition int) error {
 	return nil
 }
 
+// touch refreshes the task's update timestamp without changing anything else
+func (t *Task) touch() {
+	t.updatedAt = currentTimestamp()
+}
+
+// recordMove appends a move from the given parent and position to the task's current place
+// When limit is positive, only the most recent limit entries are kept
+func (t *Task) recordMove(fromParentID *TaskID, fromPosition int, limit int) {
+	t.moveHistory = append(t.moveHistory, MoveRecord{
+		FromParentID: copyTaskID(fromParentID),
+		ToParentID:   copyTaskID(t.parentID),
+		FromPosition: fromPosition,
+		ToPosition:   t.position,
+		At:           t.updatedAt,
+	})
+
+	if limit > 0 && len(t.moveHistory) > limit {
+		t.moveHistory = append([]MoveRecord(nil), t.moveHistory[len(t.moveHistory)-limit:]...)
+	}
+}
+
+// recordStatusChange appends a change from the given status to the task's current status
+// When limit is positive, only the most recent limit entries are kept
+func (t *Task) recordStatusChange(from Status, limit int) {
+	t.transitions = append(t.transitions, StatusRecord{
+		From: from,
+		To:   t.status,
+		At:   t.updatedAt,
+	})
+
+	if limit > 0 && len(t.transitions) > limit {
+		t.transitions = append([]StatusRecord(nil), t.transitions[len(t.transitions)-limit:]...)
+	}
+}
+
+// copyTaskID returns a copy of the referenced ID (nil stays nil)
+func copyTaskID(id *TaskID) *TaskID {
+	if id == nil {
+		return nil
+	}
+	idCopy := *id
+	return &idCopy
+}
+
+// TaskFields holds every field of a task as persisted, for ReconstructTask
+type TaskFields struct {
+	ID            TaskID
+	Description   string
+	Status        Status
+	ParentID      *TaskID
+	Position      int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CompletedAt   *time.Time
+	MoveHistory   []MoveRecord
+	StatusHistory []StatusRecord
+	Tags          []string
+	Kind          string
+}
+
 // ReconstructTask creates a Task with all fields specified
 // This is used by the infrastructure layer to deserialize tasks from persistent storage
 // Unlike NewTask, this does not generate new IDs or timestamps
-func ReconstructTask(
-	id TaskID,
-	description string,
-	status Status,
-	parentID *TaskID,
-	position int,
-	createdAt time.Time,
-	updatedAt time.Time,
-) *Task {
+// An empty kind, as in legacy data, becomes DefaultTaskKind
+func ReconstructTask(fields TaskFields) *Task {
+	kind := fields.Kind
+	if kind == "" {
+		kind = DefaultTaskKind
+	}
 	return &Task{
-		id:          id,
-		description: description,
-		status:      status,
-		parentID:    parentID,
-		position:    position,
-		createdAt:   createdAt,
-		updatedAt:   updatedAt,
+		id:          fields.ID,
+		description: fields.Description,
+		status:      fields.Status,
+		parentID:    fields.ParentID,
+		position:    fields.Position,
+		createdAt:   fields.CreatedAt,
+		updatedAt:   fields.UpdatedAt,
+		completedAt: fields.CompletedAt,
+		moveHistory: fields.MoveHistory,
+		transitions: fields.StatusHistory,
+		tags:        sortedUniqueTags(fields.Tags),
+		kind:        kind,
 	}
 }
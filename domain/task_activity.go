@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// SubtreeActivity summarizes when the tasks of a subtree were created and changed
+type SubtreeActivity struct {
+	Total             int            // Number of tasks in the subtree, its top task included
+	EarliestCreatedAt time.Time      // Earliest CreatedAt in the subtree
+	LatestUpdatedAt   time.Time      // Latest UpdatedAt in the subtree
+	ChangedSince      int            // Tasks whose UpdatedAt is at or after the cutoff
+	ByStatus          map[Status]int // Tasks per status; statuses no task has are absent
+}
+
+// SummarizeActivity summarizes a subtree, as from FindSubtree, in a single pass
+// Tasks updated at or after since count as changed
+func SummarizeActivity(subtree []*Task, since time.Time) SubtreeActivity {
+	activity := SubtreeActivity{ByStatus: make(map[Status]int)}
+	for i, task := range subtree {
+		if i == 0 || task.CreatedAt().Before(activity.EarliestCreatedAt) {
+			activity.EarliestCreatedAt = task.CreatedAt()
+		}
+		if task.UpdatedAt().After(activity.LatestUpdatedAt) {
+			activity.LatestUpdatedAt = task.UpdatedAt()
+		}
+		if !task.UpdatedAt().Before(since) {
+			activity.ChangedSince++
+		}
+		activity.ByStatus[task.Status()]++
+	}
+	activity.Total = len(subtree)
+	return activity
+}
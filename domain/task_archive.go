@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ArchivedTree is a completed top-level branch moved out of the active tree
+type ArchivedTree struct {
+	ParentID   TaskID    // Root task the branch was a child of
+	Position   int       // Position of the branch under its parent when it was archived
+	ArchivedAt time.Time // When the branch was archived
+	Tasks      []*Task   // The branch in depth-first order, its top task first
+}
+
+// Root returns the top task of the archived branch
+func (a ArchivedTree) Root() *Task {
+	return a.Tasks[0]
+}
+
+// TaskArchive stores completed branches outside the active tree, where normal reads do not see them
+type TaskArchive interface {
+	// Add stores an archived tree
+	Add(tree ArchivedTree) error
+
+	// FindAll retrieves all archived trees, ordered by archive time (tie-broken by top task ID)
+	FindAll() ([]ArchivedTree, error)
+
+	// FindByRootID retrieves the archived tree whose top task has the given ID, or returns a NotFoundError
+	FindByRootID(rootID TaskID) (ArchivedTree, error)
+
+	// FindTask retrieves an archived task from any archived tree, or returns a NotFoundError
+	FindTask(id TaskID) (*Task, error)
+
+	// Remove deletes the archived tree whose top task has the given ID, or returns a NotFoundError
+	Remove(rootID TaskID) error
+}
+
+// ArchivedTrees retrieves the archived trees, ordered by archive time; there are none without an Archive
+func (s *TaskService) ArchivedTrees() ([]ArchivedTree, error) {
+	if s.config.Archive == nil {
+		return []ArchivedTree{}, nil
+	}
+	return s.config.Archive.FindAll()
+}
+
+// FindArchivedTask retrieves a task from the archive, or returns a NotFoundError
+func (s *TaskService) FindArchivedTask(taskID TaskID) (*Task, error) {
+	if s.config.Archive == nil {
+		return nil, NewNotFoundError("Archived Task", taskID.String())
+	}
+	return s.config.Archive.FindTask(taskID)
+}
+
+// findActiveOrArchived retrieves a task from the repository, falling back to the archive
+func (s *TaskService) findActiveOrArchived(taskID TaskID) (*Task, error) {
+	task, err := s.repo.FindByID(taskID)
+	if _, ok := err.(NotFoundError); ok && s.config.Archive != nil {
+		return s.config.Archive.FindTask(taskID)
+	}
+	return task, err
+}
+
+// archiveCompletedBranch moves the top-level branch holding task to the archive if its whole
+// subtree is complete; it does nothing without ArchiveOnComplete, or for an incomplete or root task
+// It runs within a transaction: the branch is added to the archive, then deleted from the
+// staged tree, and the added tree is returned, even if the delete fails, so the caller can
+// take it out of the archive again if the transaction does not commit
+func (s *TaskService) archiveCompletedBranch(task *Task) (*ArchivedTree, error) {
+	if !s.config.ArchiveOnComplete || s.config.Archive == nil || !s.config.CompleteStatuses.Contains(task.Status()) || task.ParentID() == nil {
+		return nil, nil
+	}
+
+	// Walk up to the ancestor whose parent is a root task
+	top := task
+	for {
+		parent, err := s.repo.FindByID(*top.ParentID())
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID() == nil {
+			break
+		}
+		top = parent
+	}
+
+	subtree, err := s.repo.FindSubtree(top.ID())
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range subtree {
+		if !s.config.CompleteStatuses.Contains(member.Status()) {
+			return nil, nil
+		}
+	}
+
+	archived := make([]*Task, len(subtree))
+	for i, member := range subtree {
+		archived[i] = member.Clone()
+	}
+	tree := ArchivedTree{
+		ParentID:   *top.ParentID(),
+		Position:   top.Position(),
+		ArchivedAt: currentTimestamp(),
+		Tasks:      archived,
+	}
+	if err := s.config.Archive.Add(tree); err != nil {
+		return nil, err
+	}
+
+	return &tree, s.deleteSubtree(top.ID())
+}
+
+// RestoreArchivedTree moves an archived branch back into the tree and removes it from the archive
+// The branch goes back under the root it was archived from, or the current root if that one is
+// gone, after the root's last child. A branch whose tasks already exist in the tree is rejected
+// with a ConstraintViolationError
+// The branch is removed from the archive within the restoring transaction and put back if the
+// transaction does not commit, so it ends up in exactly one of the two
+// Returns the restored tasks in depth-first order, the top task first
+func (s *TaskService) RestoreArchivedTree(rootID TaskID) ([]*Task, error) {
+	if s.config.Archive == nil {
+		return nil, NewNotFoundError("Archived Tree", rootID.String())
+	}
+
+	tree, err := s.config.Archive.FindByRootID(rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore copies, so a failed restore leaves the archived tree as it was
+	tasks := make([]*Task, len(tree.Tasks))
+	for i, task := range tree.Tasks {
+		tasks[i] = task.Clone()
+	}
+
+	removed := false
+	err = s.inTransaction(func(tx *TaskService) error {
+		if err := tx.restoreTasks(tasks, tree.ParentID); err != nil {
+			return err
+		}
+		// Removed last, so only a failed commit has to put it back
+		if err := s.config.Archive.Remove(rootID); err != nil {
+			return err
+		}
+		removed = true
+		return nil
+	})
+	if err != nil {
+		if removed {
+			if addErr := s.config.Archive.Add(tree); addErr != nil {
+				return nil, errors.Join(err, fmt.Errorf("archived tree %s was lost from the archive: %w", rootID, addErr))
+			}
+		}
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// restoreTasks saves an archived branch, its top task first, as the last child of parentID,
+// or of the root task if parentID no longer exists
+func (s *TaskService) restoreTasks(tasks []*Task, parentID TaskID) error {
+	if err := s.ensureCapacity(len(tasks)); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		_, err := s.repo.FindByID(task.ID())
+		if err == nil {
+			return NewConstraintViolationError(
+				"archived-task-exists",
+				fmt.Sprintf("cannot restore archived tree: task %s already exists", task.ID()),
+			)
+		}
+		if _, ok := err.(NotFoundError); !ok {
+			return err
+		}
+	}
+
+	if _, err := s.repo.FindByID(parentID); err != nil {
+		if _, ok := err.(NotFoundError); !ok {
+			return err
+		}
+		root, err := s.repo.FindRoot()
+		if err != nil {
+			return err
+		}
+		parentID = root.ID()
+	}
+
+	position, err := s.repo.NextChildPosition(&parentID)
+	if err != nil {
+		return err
+	}
+	if err := tasks[0].Move(&parentID, position); err != nil {
+		return err
+	}
+
+	return s.repo.SaveAll(tasks)
+}
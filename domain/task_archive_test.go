@@ -0,0 +1,291 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTaskService_ArchiveOnComplete tests that completing a whole top-level branch moves it to the archive
+func TestTaskService_ArchiveOnComplete(t *testing.T) {
+	// setup builds root -> A (A1, A2) and root -> B
+	setup := func(archiveOnComplete bool) (*InMemoryTaskRepository, *InMemoryTaskArchive, *TaskService, *Task, *Task, *Task, *Task, *Task) {
+		repo := NewInMemoryTaskRepository()
+		archive := NewInMemoryTaskArchive()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{Archive: archive, ArchiveOnComplete: archiveOnComplete})
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		a1, _ := service.CreateChildTask("A1", a.ID())
+		a2, _ := service.CreateChildTask("A2", a.ID())
+		b, _ := service.CreateChildTask("B", root.ID())
+		return repo, archive, service, root, a, a1, a2, b
+	}
+	complete := func(t *testing.T, service *TaskService, tasks ...*Task) {
+		t.Helper()
+		for _, task := range tasks {
+			if err := service.ChangeTaskStatus(task.ID(), StatusDONE); err != nil {
+				t.Fatalf("expected no error completing %q, got %v", task.Description(), err)
+			}
+		}
+	}
+
+	t.Run("archives a fully DONE branch", func(t *testing.T) {
+		repo, archive, service, root, a, a1, a2, b := setup(true)
+		complete(t, service, a1, a2)
+		if count, _ := repo.Count(); count != 5 {
+			t.Fatalf("expected the branch to stay while A is open, got %d tasks", count)
+		}
+
+		complete(t, service, a)
+
+		all, _ := repo.FindAll()
+		if len(all) != 2 {
+			t.Fatalf("expected only root and B to stay active, got %d tasks", len(all))
+		}
+		for _, task := range []*Task{a, a1, a2} {
+			if _, err := repo.FindByID(task.ID()); err == nil {
+				t.Errorf("expected %q to leave the active tree", task.Description())
+			}
+		}
+		if found, err := repo.FindByID(b.ID()); err != nil || found.Position() != 0 {
+			t.Errorf("expected B to close the gap at position 0, got %v", err)
+		}
+
+		tree, err := archive.FindByRootID(a.ID())
+		if err != nil {
+			t.Fatalf("expected A's branch in the archive, got %v", err)
+		}
+		if len(tree.Tasks) != 3 || !tree.Root().ID().Equals(a.ID()) {
+			t.Errorf("expected the archived branch A, A1, A2, got %d tasks", len(tree.Tasks))
+		}
+		if !tree.ParentID.Equals(root.ID()) || tree.Position != 0 {
+			t.Errorf("expected A archived from root at position 0, got position %d", tree.Position)
+		}
+		if archived, err := service.FindArchivedTask(a1.ID()); err != nil || archived.Status() != StatusDONE {
+			t.Errorf("expected A1 retrievable from the archive, got %v", err)
+		}
+	})
+
+	t.Run("keeps a branch that is not DONE throughout", func(t *testing.T) {
+		repo, archive, service, _, _, a1, _, _ := setup(true)
+		complete(t, service, a1)
+		if count, _ := repo.Count(); count != 5 {
+			t.Errorf("expected all 5 tasks to stay active, got %d", count)
+		}
+		if trees, _ := archive.FindAll(); len(trees) != 0 {
+			t.Errorf("expected an empty archive, got %d trees", len(trees))
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		repo, archive, service, _, a, a1, a2, _ := setup(false)
+		complete(t, service, a1, a2, a)
+		if count, _ := repo.Count(); count != 5 {
+			t.Errorf("expected all 5 tasks to stay active, got %d", count)
+		}
+		if trees, _ := archive.FindAll(); len(trees) != 0 {
+			t.Errorf("expected an empty archive, got %d trees", len(trees))
+		}
+	})
+
+	t.Run("toggle returns the archived task", func(t *testing.T) {
+		_, _, service, _, a, a1, a2, _ := setup(true)
+		complete(t, service, a1, a2)
+		task, err := service.ToggleTaskStatus(a.ID())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !task.ID().Equals(a.ID()) || task.Status() != StatusDONE {
+			t.Errorf("expected A returned as DONE, got %q", task.Status())
+		}
+	})
+
+	t.Run("restores a branch after the root's last child", func(t *testing.T) {
+		repo, archive, service, root, a, a1, a2, b := setup(true)
+		complete(t, service, a1, a2, a)
+
+		restored, err := service.RestoreArchivedTree(a.ID())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(restored) != 3 {
+			t.Errorf("expected 3 restored tasks, got %d", len(restored))
+		}
+		if count, _ := repo.Count(); count != 5 {
+			t.Errorf("expected all 5 tasks active again, got %d", count)
+		}
+		found, err := repo.FindByID(a.ID())
+		if err != nil {
+			t.Fatalf("expected A to be active again, got %v", err)
+		}
+		if !found.ParentID().Equals(root.ID()) || found.Position() != 1 {
+			t.Errorf("expected A under root after B at position 1, got %d", found.Position())
+		}
+		if found, _ := repo.FindByID(b.ID()); found.Position() != 0 {
+			t.Errorf("expected B to stay at 0, got %d", found.Position())
+		}
+		if trees, _ := archive.FindAll(); len(trees) != 0 {
+			t.Errorf("expected the archive to be empty after restoring, got %d trees", len(trees))
+		}
+	})
+
+	t.Run("restoring an unknown tree is not found", func(t *testing.T) {
+		_, _, service, _, _, _, _, b := setup(true)
+		_, err := service.RestoreArchivedTree(b.ID())
+		if _, ok := err.(NotFoundError); !ok {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("rejects restoring tasks that exist", func(t *testing.T) {
+		repo, archive, service, root, a, a1, a2, _ := setup(true)
+		complete(t, service, a1, a2, a)
+
+		// A copy of A1 reappears in the tree, e.g. through an import
+		repo.Save(a1.Clone())
+
+		_, err := service.RestoreArchivedTree(a.ID())
+		if _, ok := err.(ConstraintViolationError); !ok {
+			t.Fatalf("expected constraint violation, got %v", err)
+		}
+		if _, err := archive.FindByRootID(a.ID()); err != nil {
+			t.Errorf("expected the branch to stay archived, got %v", err)
+		}
+		if children, _ := repo.FindByParentID(&root.id); len(children) != 1 {
+			t.Errorf("expected only B under root, got %d children", len(children))
+		}
+	})
+}
+
+// failingArchive is an in-memory archive whose Add or Remove can be made to fail
+type failingArchive struct {
+	*InMemoryTaskArchive
+	failAdd    bool
+	failRemove bool
+}
+
+func (a *failingArchive) Add(tree ArchivedTree) error {
+	if a.failAdd {
+		return NewValidationError("archive", "unavailable", "archive unavailable")
+	}
+	return a.InMemoryTaskArchive.Add(tree)
+}
+
+func (a *failingArchive) Remove(rootID TaskID) error {
+	if a.failRemove {
+		return NewValidationError("archive", "unavailable", "archive unavailable")
+	}
+	return a.InMemoryTaskArchive.Remove(rootID)
+}
+
+// failingCommitRepository runs transactions but fails to commit them while failCommit is set
+type failingCommitRepository struct {
+	*InMemoryTaskRepository
+	failCommit bool
+}
+
+func (r *failingCommitRepository) WithTransaction(fn func(tx TaskRepositoryTx) error) error {
+	return r.InMemoryTaskRepository.WithTransaction(func(tx TaskRepositoryTx) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if r.failCommit {
+			return NewValidationError("commit", "failed", "commit failed")
+		}
+		return nil
+	})
+}
+
+// TestTaskService_ArchiveIsOneUnit tests that archiving and restoring a branch either happen
+// completely or leave both the tree and the archive as they were
+func TestTaskService_ArchiveIsOneUnit(t *testing.T) {
+	// setup builds root -> A (A1), with A1 DONE, so completing A archives A's branch
+	setup := func() (*failingCommitRepository, *failingArchive, *TaskService, *Task) {
+		repo := &failingCommitRepository{InMemoryTaskRepository: NewInMemoryTaskRepository()}
+		archive := &failingArchive{InMemoryTaskArchive: NewInMemoryTaskArchive()}
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{Archive: archive, ArchiveOnComplete: true})
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		a1, _ := service.CreateChildTask("A1", a.ID())
+		if err := service.ChangeTaskStatus(a1.ID(), StatusDONE); err != nil {
+			t.Fatalf("expected no error completing A1, got %v", err)
+		}
+		return repo, archive, service, a
+	}
+	expectActive := func(t *testing.T, repo TaskRepository, task *Task, status Status) {
+		t.Helper()
+		found, err := repo.FindByID(task.ID())
+		if err != nil {
+			t.Fatalf("expected %q to stay in the tree, got %v", task.Description(), err)
+		}
+		if found.Status() != status {
+			t.Errorf("expected %q to stay %s, got %s", task.Description(), status, found.Status())
+		}
+	}
+
+	t.Run("a failed archive leaves the status unchanged", func(t *testing.T) {
+		repo, archive, service, a := setup()
+		archive.failAdd = true
+
+		if err := service.ChangeTaskStatus(a.ID(), StatusDONE); err == nil {
+			t.Fatal("expected the archive failure to be returned")
+		}
+		expectActive(t, repo, a, StatusTODO)
+	})
+
+	t.Run("a failed commit takes the branch out of the archive", func(t *testing.T) {
+		repo, archive, service, a := setup()
+		repo.failCommit = true
+
+		if err := service.ChangeTaskStatus(a.ID(), StatusDONE); err == nil {
+			t.Fatal("expected the commit failure to be returned")
+		}
+		expectActive(t, repo, a, StatusTODO)
+		if trees, _ := archive.FindAll(); len(trees) != 0 {
+			t.Errorf("expected the archive to be empty, got %d trees", len(trees))
+		}
+	})
+
+	t.Run("a failed cleanup is reported", func(t *testing.T) {
+		repo, archive, service, a := setup()
+		repo.failCommit = true
+		archive.failRemove = true
+
+		err := service.ChangeTaskStatus(a.ID(), StatusDONE)
+		if err == nil || !strings.Contains(err.Error(), "still in the archive") {
+			t.Fatalf("expected the failed cleanup to be reported, got %v", err)
+		}
+	})
+
+	t.Run("a failed restore commit keeps the branch archived", func(t *testing.T) {
+		repo, archive, service, a := setup()
+		if err := service.ChangeTaskStatus(a.ID(), StatusDONE); err != nil {
+			t.Fatalf("expected no error completing A, got %v", err)
+		}
+		repo.failCommit = true
+
+		if _, err := service.RestoreArchivedTree(a.ID()); err == nil {
+			t.Fatal("expected the commit failure to be returned")
+		}
+		if _, err := archive.FindByRootID(a.ID()); err != nil {
+			t.Errorf("expected the branch to stay archived, got %v", err)
+		}
+		if _, err := repo.FindByID(a.ID()); err == nil {
+			t.Error("expected A to stay out of the tree")
+		}
+	})
+
+	t.Run("a failed removal from the archive keeps the branch archived", func(t *testing.T) {
+		repo, archive, service, a := setup()
+		if err := service.ChangeTaskStatus(a.ID(), StatusDONE); err != nil {
+			t.Fatalf("expected no error completing A, got %v", err)
+		}
+		archive.failRemove = true
+
+		if _, err := service.RestoreArchivedTree(a.ID()); err == nil {
+			t.Fatal("expected the archive failure to be returned")
+		}
+		if _, err := repo.FindByID(a.ID()); err == nil {
+			t.Error("expected A to stay out of the tree")
+		}
+	})
+}
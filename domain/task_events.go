@@ -0,0 +1,179 @@
+package domain
+
+import "sync"
+
+// TaskChangeEvent describes tasks that were saved or deleted
+type TaskChangeEvent struct {
+	TaskIDs []TaskID // IDs of the saved or deleted tasks
+	All     bool     // The whole collection was replaced, so any task may have changed
+}
+
+// TaskChangeListener is called after a change has been persisted
+// It runs outside the repository's locks and may read from the repository
+type TaskChangeListener func(event TaskChangeEvent)
+
+// ObservableTaskRepository wraps a TaskRepository and publishes a TaskChangeEvent
+// to its listeners after every successful save or delete
+type ObservableTaskRepository struct {
+	TaskRepository
+
+	mu        sync.RWMutex
+	listeners []TaskChangeListener
+}
+
+// NewObservableTaskRepository creates an ObservableTaskRepository around repo
+func NewObservableTaskRepository(repo TaskRepository) *ObservableTaskRepository {
+	return &ObservableTaskRepository{TaskRepository: repo}
+}
+
+// Subscribe registers a listener for all future changes
+func (r *ObservableTaskRepository) Subscribe(listener TaskChangeListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// publish notifies every listener of the event
+func (r *ObservableTaskRepository) publish(event TaskChangeEvent) {
+	r.mu.RLock()
+	listeners := make([]TaskChangeListener, len(r.listeners))
+	copy(listeners, r.listeners)
+	r.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// Save persists a task and publishes the change
+func (r *ObservableTaskRepository) Save(task *Task) error {
+	if err := r.TaskRepository.Save(task); err != nil {
+		return err
+	}
+	r.publish(TaskChangeEvent{TaskIDs: []TaskID{task.ID()}})
+	return nil
+}
+
+// SaveAll persists multiple tasks and publishes the change
+func (r *ObservableTaskRepository) SaveAll(tasks []*Task) error {
+	if err := r.TaskRepository.SaveAll(tasks); err != nil {
+		return err
+	}
+	r.publish(TaskChangeEvent{TaskIDs: taskIDsOf(tasks)})
+	return nil
+}
+
+// ReplaceAll replaces the entire collection and publishes a change to all tasks
+func (r *ObservableTaskRepository) ReplaceAll(tasks []*Task) error {
+	if err := r.TaskRepository.ReplaceAll(tasks); err != nil {
+		return err
+	}
+	r.publish(TaskChangeEvent{TaskIDs: taskIDsOf(tasks), All: true})
+	return nil
+}
+
+// Delete removes a task and publishes the change
+func (r *ObservableTaskRepository) Delete(id TaskID) error {
+	if err := r.TaskRepository.Delete(id); err != nil {
+		return err
+	}
+	r.publish(TaskChangeEvent{TaskIDs: []TaskID{id}})
+	return nil
+}
+
+// DeleteSubtree removes a task and its descendants and publishes the change
+func (r *ObservableTaskRepository) DeleteSubtree(id TaskID) error {
+	// Collect the IDs first, since they cannot be looked up once deleted
+	ids := []TaskID{id}
+	if subtree, err := r.TaskRepository.FindSubtree(id); err == nil {
+		ids = taskIDsOf(subtree)
+	}
+
+	if err := r.TaskRepository.DeleteSubtree(id); err != nil {
+		return err
+	}
+	r.publish(TaskChangeEvent{TaskIDs: ids})
+	return nil
+}
+
+// WithTransaction runs a transaction and publishes a single change for everything it committed
+func (r *ObservableTaskRepository) WithTransaction(fn func(tx TaskRepositoryTx) error) error {
+	var recorder *observedTx
+	err := r.TaskRepository.WithTransaction(func(tx TaskRepositoryTx) error {
+		recorder = &observedTx{TaskRepositoryTx: tx}
+		return fn(recorder)
+	})
+	if err != nil {
+		return err
+	}
+	if len(recorder.event.TaskIDs) > 0 || recorder.event.All {
+		r.publish(recorder.event)
+	}
+	return nil
+}
+
+// observedTx records the tasks saved or deleted within a transaction
+type observedTx struct {
+	TaskRepositoryTx
+	event TaskChangeEvent
+}
+
+// Save persists a task and records the change
+func (t *observedTx) Save(task *Task) error {
+	if err := t.TaskRepositoryTx.Save(task); err != nil {
+		return err
+	}
+	t.event.TaskIDs = append(t.event.TaskIDs, task.ID())
+	return nil
+}
+
+// SaveAll persists multiple tasks and records the change
+func (t *observedTx) SaveAll(tasks []*Task) error {
+	if err := t.TaskRepositoryTx.SaveAll(tasks); err != nil {
+		return err
+	}
+	t.event.TaskIDs = append(t.event.TaskIDs, taskIDsOf(tasks)...)
+	return nil
+}
+
+// ReplaceAll replaces the entire collection and records a change to all tasks
+func (t *observedTx) ReplaceAll(tasks []*Task) error {
+	if err := t.TaskRepositoryTx.ReplaceAll(tasks); err != nil {
+		return err
+	}
+	t.event.TaskIDs = append(t.event.TaskIDs, taskIDsOf(tasks)...)
+	t.event.All = true
+	return nil
+}
+
+// Delete removes a task and records the change
+func (t *observedTx) Delete(id TaskID) error {
+	if err := t.TaskRepositoryTx.Delete(id); err != nil {
+		return err
+	}
+	t.event.TaskIDs = append(t.event.TaskIDs, id)
+	return nil
+}
+
+// DeleteSubtree removes a task and its descendants and records the change
+func (t *observedTx) DeleteSubtree(id TaskID) error {
+	ids := []TaskID{id}
+	if subtree, err := t.TaskRepositoryTx.FindSubtree(id); err == nil {
+		ids = taskIDsOf(subtree)
+	}
+
+	if err := t.TaskRepositoryTx.DeleteSubtree(id); err != nil {
+		return err
+	}
+	t.event.TaskIDs = append(t.event.TaskIDs, ids...)
+	return nil
+}
+
+// taskIDsOf returns the IDs of the given tasks in order
+func taskIDsOf(tasks []*Task) []TaskID {
+	ids := make([]TaskID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID()
+	}
+	return ids
+}
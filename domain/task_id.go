@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"strings"
+
 	"github.com/google/uuid"
 )
 
@@ -17,17 +19,22 @@ func NewTaskID() TaskID {
 }
 
 // TaskIDFromString creates a TaskID from a string value with validation
+// Both UUIDs and short IDs are accepted, whichever scheme generates new IDs
+// The TaskID is returned in canonical form, so an ID given in another case or UUID layout
+// finds the same task (see Canonical)
 func TaskIDFromString(s string) (TaskID, error) {
 	if s == "" {
-		return TaskID{}, NewValidationError("taskID", "task ID cannot be empty")
+		return TaskID{}, NewValidationError("taskID", "empty-task-id", "task ID cannot be empty")
 	}
 	
-	// Validate that the string is a valid UUID format
-	if _, err := uuid.Parse(s); err != nil {
-		return TaskID{}, NewValidationError("taskID", "task ID must be a valid UUID")
+	// Validate that the string is a valid UUID or short ID format
+	if !IsShortID(s) {
+		if _, err := uuid.Parse(s); err != nil {
+			return TaskID{}, NewValidationError("taskID", "invalid-task-id", "task ID must be a valid UUID or short ID")
+		}
 	}
 	
-	return TaskID{value: s}, nil
+	return TaskID{value: s}.Canonical(), nil
 }
 
 // String returns the string representation of the TaskID
@@ -35,6 +42,48 @@ func (t TaskID) String() string {
 	return t.value
 }
 
+// IsCanonical reports whether the TaskID is in canonical form: a lowercase short ID,
+// or a UUID in lowercase, hyphenated 8-4-4-4-12 layout
+func (t TaskID) IsCanonical() bool {
+	if IsShortID(t.value) {
+		return t.value == strings.ToLower(t.value)
+	}
+	parsed, err := uuid.Parse(t.value)
+	if err != nil {
+		return false
+	}
+	return t.value == parsed.String()
+}
+
+// Canonical returns the TaskID in canonical form (see IsCanonical)
+// IDs that are neither short IDs nor UUIDs are returned unchanged
+func (t TaskID) Canonical() TaskID {
+	if t.IsCanonical() {
+		return t
+	}
+	if IsShortID(t.value) {
+		return TaskID{value: strings.ToLower(t.value)}
+	}
+	parsed, err := uuid.Parse(t.value)
+	if err != nil {
+		return t
+	}
+	return TaskID{value: parsed.String()}
+}
+
+// HasCanonicalLayout reports whether s is a short ID or uses the hyphenated 8-4-4-4-12 UUID
+// layout, ignoring letter case. Braced, URN-prefixed, or unhyphenated UUIDs do not qualify
+func HasCanonicalLayout(s string) bool {
+	if IsShortID(s) {
+		return true
+	}
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(s, parsed.String())
+}
+
 // Equals checks if two TaskIDs are equal
 func (t TaskID) Equals(other TaskID) bool {
 	return t.value == other.value
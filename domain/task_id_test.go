@@ -44,6 +44,16 @@ func TestTaskIDFromString(t *testing.T) {
 			input:   "abc123",
 			wantErr: true,
 		},
+		{
+			name:    "valid short ID",
+			input:   "k3xq7mzp2a",
+			wantErr: false,
+		},
+		{
+			name:    "short ID with characters outside base32",
+			input:   "k3xq7mzp1a",
+			wantErr: true,
+		},
 	}
 	
 	for _, tt := range tests {
@@ -91,3 +101,78 @@ func TestTaskIDEquals(t *testing.T) {
 		t.Error("TaskIDs with different values should not be equal")
 	}
 }
+
+func TestTaskIDIsCanonical(t *testing.T) {
+	lower, _ := TaskIDFromString("550e8400-e29b-41d4-a716-446655440000")
+	upper := TaskID{value: "550E8400-E29B-41D4-A716-446655440000"}
+
+	if !lower.IsCanonical() {
+		t.Error("lowercase hyphenated UUID should be canonical")
+	}
+	if upper.IsCanonical() {
+		t.Error("uppercase UUID should not be canonical")
+	}
+	if !NewTaskID().IsCanonical() {
+		t.Error("generated TaskID should be canonical")
+	}
+}
+
+func TestTaskIDCanonical(t *testing.T) {
+	upper := TaskID{value: "550E8400-E29B-41D4-A716-446655440000"}
+	braced := TaskID{value: "{550e8400-e29b-41d4-a716-446655440000}"}
+	want := "550e8400-e29b-41d4-a716-446655440000"
+
+	if got := upper.Canonical().String(); got != want {
+		t.Errorf("Canonical() = %q, want %q", got, want)
+	}
+	if got := braced.Canonical().String(); got != want {
+		t.Errorf("Canonical() = %q, want %q", got, want)
+	}
+
+	short := TaskID{value: "K3XQ7MZP2A"}
+	if short.IsCanonical() {
+		t.Error("uppercase short ID should not be canonical")
+	}
+	if got := short.Canonical().String(); got != "k3xq7mzp2a" {
+		t.Errorf("Canonical() = %q, want %q", got, "k3xq7mzp2a")
+	}
+}
+
+func TestHasCanonicalLayout(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"550e8400-e29b-41d4-a716-446655440000", true},
+		{"550E8400-E29B-41D4-A716-446655440000", true},
+		{"{550e8400-e29b-41d4-a716-446655440000}", false},
+		{"urn:uuid:550e8400-e29b-41d4-a716-446655440000", false},
+		{"550e8400e29b41d4a716446655440000", false},
+		{"not-a-uuid", false},
+		{"k3xq7mzp2a", true},
+		{"K3XQ7MZP2A", true},
+	}
+
+	for _, tt := range tests {
+		if got := HasCanonicalLayout(tt.input); got != tt.want {
+			t.Errorf("HasCanonicalLayout(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTaskIDFromString_Canonicalizes(t *testing.T) {
+	tests := map[string]string{
+		"550E8400-E29B-41D4-A716-446655440000":   "550e8400-e29b-41d4-a716-446655440000",
+		"{550e8400-e29b-41d4-a716-446655440000}": "550e8400-e29b-41d4-a716-446655440000",
+		"K3XQ7MZP2A":                             "k3xq7mzp2a",
+	}
+	for input, want := range tests {
+		id, err := TaskIDFromString(input)
+		if err != nil {
+			t.Fatalf("TaskIDFromString(%q) unexpected error: %v", input, err)
+		}
+		if id.String() != want {
+			t.Errorf("TaskIDFromString(%q).String() = %q, want %q", input, id.String(), want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package domain
+
+import "fmt"
+
+// ImportMode selects how ImportTasks combines imported tasks with the existing store
+type ImportMode string
+
+const (
+	// ImportModeReplace discards the existing tasks and stores only the imported ones
+	ImportModeReplace ImportMode = "replace"
+	// ImportModeMerge adds the imported tasks to the store, overwriting tasks with the same ID
+	ImportModeMerge ImportMode = "merge"
+)
+
+// IsValid checks if the import mode is a known mode
+func (m ImportMode) IsValid() bool {
+	return m == ImportModeReplace || m == ImportModeMerge
+}
+
+// ImportTasks stores a flat collection of fully-formed tasks (e.g. from an export)
+// The resulting tree is checked with CheckTreeInvariants before anything is persisted,
+// and the store is replaced or merged in a single transaction
+func (s *TaskService) ImportTasks(tasks []*Task, mode ImportMode) error {
+	return s.inTransaction(func(tx *TaskService) error {
+		return tx.importTasks(tasks, mode)
+	})
+}
+
+// TaskReader reads a flat collection of tasks for ImportTasksFrom, taking the ID of each
+// task that does not bring its own from newID. kept reports whether a stored task the
+// tasks may refer to survives the import; it is nil when the import keeps no stored task
+type TaskReader func(newID func() (TaskID, error), kept func(TaskID) bool) ([]*Task, error)
+
+// ImportTasksFrom reads tasks with read and stores them as ImportTasks does, returning them
+// New IDs come from the configured IDGenerator and are checked against the stored tasks, and
+// the read runs in the same transaction as the import
+func (s *TaskService) ImportTasksFrom(read TaskReader, mode ImportMode) ([]*Task, error) {
+	var tasks []*Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		// Only a merge keeps the stored tasks
+		var kept func(TaskID) bool
+		if mode == ImportModeMerge {
+			kept = func(id TaskID) bool {
+				_, err := tx.repo.FindByID(id)
+				return err == nil
+			}
+		}
+
+		var err error
+		if tasks, err = read(tx.newTaskID, kept); err != nil {
+			return err
+		}
+		return tx.importTasks(tasks, mode)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// importTasks implements ImportTasks within a transaction
+func (s *TaskService) importTasks(tasks []*Task, mode ImportMode) error {
+	if !mode.IsValid() {
+		return NewValidationError("mode", "invalid-import-mode", fmt.Sprintf("invalid import mode %q (must be one of: replace, merge)", mode))
+	}
+	if len(tasks) == 0 {
+		return NewValidationError("tasks", "empty-import", "import must contain at least one task")
+	}
+
+	// Reject duplicate IDs within the import itself
+	seen := make(map[TaskID]bool, len(tasks))
+	for _, task := range tasks {
+		if seen[task.ID()] {
+			return NewValidationError("id", "duplicate-task-id", fmt.Sprintf("duplicate task ID %s in import", task.ID()))
+		}
+		seen[task.ID()] = true
+	}
+
+	// Assemble the resulting tree: imported tasks overwrite existing ones with the same ID
+	result := tasks
+	if mode == ImportModeMerge {
+		existing, err := s.repo.FindAll()
+		if err != nil {
+			return err
+		}
+		result = make([]*Task, 0, len(existing)+len(tasks))
+		for _, task := range existing {
+			if !seen[task.ID()] {
+				result = append(result, task)
+			}
+		}
+		result = append(result, tasks...)
+	}
+
+	// Validate the complete resulting tree before persisting anything
+	if violations := CheckTreeInvariants(result, s.config.CompleteStatuses); len(violations) > 0 {
+		return violations[0].Err()
+	}
+
+	// The limit applies to the resulting store, not to the tasks added
+	if s.config.MaxTotalTasks > 0 && len(result) > s.config.MaxTotalTasks {
+		return NewConstraintViolationError(
+			"max-total-tasks",
+			fmt.Sprintf("import would leave %d tasks in the store, more than the limit of %d", len(result), s.config.MaxTotalTasks),
+		)
+	}
+
+	if mode == ImportModeReplace {
+		return s.repo.ReplaceAll(tasks)
+	}
+	return s.repo.SaveAll(tasks)
+}
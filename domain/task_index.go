@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"sort"
+	"sync"
+)
+
+// TaskIndex answers the tree queries the repositories share over their collection of tasks,
+// which they keep in a map keyed by task ID string
+// The parent-to-children index is built on first use and kept, so an index must not be used
+// after the collection changes; the map is never modified
+type TaskIndex struct {
+	tasks    map[string]*Task
+	once     sync.Once
+	children map[string][]*Task // children by parent ID string, "" for roots, ordered by position
+}
+
+// NewTaskIndex creates a TaskIndex over tasks, keyed by task ID string
+func NewTaskIndex(tasks map[string]*Task) *TaskIndex {
+	return &TaskIndex{tasks: tasks}
+}
+
+// childrenOf returns the children of the given parent (the roots for nil), ordered by position
+// The returned slice is shared with the index and must not be modified
+func (x *TaskIndex) childrenOf(parentID *TaskID) []*Task {
+	x.once.Do(func() {
+		x.children = make(map[string][]*Task)
+		for _, task := range x.tasks {
+			key := parentKey(task.ParentID())
+			x.children[key] = append(x.children[key], task)
+		}
+		for _, siblings := range x.children {
+			sort.Slice(siblings, func(i, j int) bool {
+				return siblings[i].Position() < siblings[j].Position()
+			})
+		}
+	})
+	return x.children[parentKey(parentID)]
+}
+
+// parentKey is the index key for a parent ID
+func parentKey(parentID *TaskID) string {
+	if parentID == nil {
+		return ""
+	}
+	return parentID.String()
+}
+
+// CountChildren returns the number of tasks with the given parent ID (roots for nil)
+func (x *TaskIndex) CountChildren(parentID *TaskID) int {
+	return len(x.childrenOf(parentID))
+}
+
+// NextChildPosition returns the position after the last child of the given parent
+func (x *TaskIndex) NextChildPosition(parentID *TaskID) int {
+	next := 0
+	for _, child := range x.childrenOf(parentID) {
+		if child.Position() >= next {
+			next = child.Position() + 1
+		}
+	}
+	return next
+}
+
+// Subtree returns the given task and all its descendants in depth-first order, or a
+// NotFoundError if the task does not exist
+func (x *TaskIndex) Subtree(rootID TaskID) ([]*Task, error) {
+	root, exists := x.tasks[rootID.String()]
+	if !exists {
+		return nil, NewNotFoundError("Task", rootID.String())
+	}
+
+	// Walk the index depth-first, guarding against cycles in corrupted data
+	result := []*Task{}
+	visited := make(map[string]bool)
+	var walk func(task *Task)
+	walk = func(task *Task) {
+		if visited[task.ID().String()] {
+			return
+		}
+		visited[task.ID().String()] = true
+		result = append(result, task)
+		taskID := task.ID()
+		for _, child := range x.childrenOf(&taskID) {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return result, nil
+}
+
+// CountSubtree returns the number of tasks in the given task's subtree (itself included),
+// or a NotFoundError if the task does not exist; no tasks are collected
+func (x *TaskIndex) CountSubtree(rootID TaskID) (int, error) {
+	if _, exists := x.tasks[rootID.String()]; !exists {
+		return 0, NewNotFoundError("Task", rootID.String())
+	}
+
+	// Walk the index, guarding against cycles in corrupted data
+	visited := map[string]bool{rootID.String(): true}
+	pending := []TaskID{rootID}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, child := range x.childrenOf(&id) {
+			if !visited[child.ID().String()] {
+				visited[child.ID().String()] = true
+				pending = append(pending, child.ID())
+			}
+		}
+	}
+
+	return len(visited), nil
+}
+
+// WithDescription returns the tasks whose description exactly matches (case-sensitive),
+// ordered by creation time (tie-broken by ID)
+func (x *TaskIndex) WithDescription(description string) []*Task {
+	result := []*Task{}
+	for _, task := range x.tasks {
+		if task.Description() == description {
+			result = append(result, task)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedBefore(result[j])
+	})
+
+	return result
+}
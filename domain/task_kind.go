@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultTaskKind is the kind of new tasks and of tasks loaded from data without a kind
+const DefaultTaskKind = "task"
+
+// NormalizeKind validates a kind name and returns it trimmed
+// A kind follows the rules for tags: non-empty, at most MaxTagLength characters, and no whitespace or commas
+func NormalizeKind(kind string) (string, error) {
+	kind = strings.TrimSpace(kind)
+	if kind == "" {
+		return "", NewValidationError("kind", "empty-kind", "kind cannot be empty")
+	}
+	if len([]rune(kind)) > MaxTagLength {
+		return "", NewValidationError("kind", "kind-too-long", fmt.Sprintf("kind %q exceeds %d characters", kind, MaxTagLength))
+	}
+	if strings.ContainsAny(kind, ", \t\r\n") {
+		return "", NewValidationError("kind", "invalid-kind-characters", fmt.Sprintf("kind %q must not contain whitespace or commas", kind))
+	}
+	return kind, nil
+}
+
+// kinds returns the kinds tasks may be given, in configured order
+func (c TaskServiceConfig) kinds() []string {
+	if len(c.Kinds) == 0 {
+		return []string{DefaultTaskKind}
+	}
+	return c.Kinds
+}
+
+// allowsKind reports whether kind is one of the configured kinds
+func (c TaskServiceConfig) allowsKind(kind string) bool {
+	for _, allowed := range c.kinds() {
+		if allowed == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTaskKind changes the kind of a task, which must be one of the configured Kinds
+// Setting the kind a task already has leaves it unchanged
+func (s *TaskService) SetTaskKind(taskID TaskID, kind string) (*Task, error) {
+	kind, err := NormalizeKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	if !s.config.allowsKind(kind) {
+		return nil, NewValidationError("kind", "unknown-kind", fmt.Sprintf("unknown kind %q; must be one of %s", kind, strings.Join(s.config.kinds(), ", ")))
+	}
+
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.UpdateKind(kind) {
+		return task, nil
+	}
+
+	if err := s.repo.Save(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// FilterByKind returns the tasks of the given kind, preserving order
+func FilterByKind(tasks []*Task, kind string) []*Task {
+	filtered := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Kind() == kind {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeKind(t *testing.T) {
+	kind, err := NormalizeKind(" spike ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if kind != "spike" {
+		t.Errorf("expected spike, got %q", kind)
+	}
+
+	for _, invalid := range []string{"", "  ", "two words", "a,b", strings.Repeat("x", MaxTagLength+1)} {
+		if _, err := NormalizeKind(invalid); err == nil {
+			t.Errorf("expected error for kind %q, got nil", invalid)
+		} else if _, ok := err.(ValidationError); !ok {
+			t.Errorf("expected ValidationError for kind %q, got %T", invalid, err)
+		}
+	}
+}
+
+func TestTaskService_SetTaskKind(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{Kinds: []string{"task", "spike", "milestone"}})
+	root, _ := service.CreateRootTask("Root")
+	task, _ := service.CreateChildTask("Try the new parser", root.ID())
+
+	if task.Kind() != DefaultTaskKind {
+		t.Errorf("expected new tasks to be of kind %q, got %q", DefaultTaskKind, task.Kind())
+	}
+
+	updated, err := service.SetTaskKind(task.ID(), " spike ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Kind() != "spike" {
+		t.Errorf("expected kind spike, got %q", updated.Kind())
+	}
+	if stored, _ := repo.FindByID(task.ID()); stored.Kind() != "spike" {
+		t.Errorf("expected the kind to be saved, got %q", stored.Kind())
+	}
+
+	// Setting the same kind again leaves the task unchanged
+	seq := updated.Seq()
+	if again, err := service.SetTaskKind(task.ID(), "spike"); err != nil || again.Seq() != seq {
+		t.Errorf("expected an unchanged task, got %v", err)
+	}
+
+	// Unknown and malformed kinds are rejected without changing the task
+	for _, kind := range []string{"bug", "two words"} {
+		_, err := service.SetTaskKind(task.ID(), kind)
+		if _, ok := err.(ValidationError); !ok {
+			t.Errorf("expected ValidationError for kind %q, got %v", kind, err)
+		}
+	}
+	if stored, _ := repo.FindByID(task.ID()); stored.Kind() != "spike" {
+		t.Errorf("expected the kind to stay spike, got %q", stored.Kind())
+	}
+
+	if _, err := service.SetTaskKind(NewTaskID(), "spike"); err == nil {
+		t.Errorf("expected an error for an unknown task")
+	} else if _, ok := err.(NotFoundError); !ok {
+		t.Errorf("expected NotFoundError, got %T", err)
+	}
+}
+
+func TestTaskService_SetTaskKind_DefaultKinds(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+
+	// Without configured kinds only the default kind is allowed
+	if _, err := service.SetTaskKind(root.ID(), DefaultTaskKind); err != nil {
+		t.Errorf("expected the default kind to be allowed, got %v", err)
+	}
+	if _, err := service.SetTaskKind(root.ID(), "spike"); err == nil {
+		t.Errorf("expected spike to be rejected without configured kinds")
+	}
+}
+
+func TestFilterByKind(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{Kinds: []string{"task", "milestone"}})
+	root, _ := service.CreateRootTask("Root")
+	beta, _ := service.CreateChildTask("Beta", root.ID())
+	_, _ = service.CreateChildTask("Write docs", root.ID())
+	release, _ := service.CreateChildTask("Release", root.ID())
+	for _, task := range []*Task{beta, release} {
+		if _, err := service.SetTaskKind(task.ID(), "milestone"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	all, _ := repo.FindByParentID(&root.id)
+	milestones := FilterByKind(all, "milestone")
+	if len(milestones) != 2 || milestones[0].Description() != "Beta" || milestones[1].Description() != "Release" {
+		t.Errorf("expected [Beta Release] in order, got %d tasks", len(milestones))
+	}
+	if tasks := FilterByKind(all, "spike"); len(tasks) != 0 {
+		t.Errorf("expected no spikes, got %d", len(tasks))
+	}
+}
@@ -0,0 +1,129 @@
+package domain
+
+import "sort"
+
+// PlanStep is a task that remains to be done, together with the path leading to it
+type PlanStep struct {
+	Task *Task
+	Path []*Task // ancestors from the root down to the task's parent
+}
+
+// BuildPlan derives a to-do list from a complete collection of tasks: the incomplete tasks
+// without incomplete children, in the order they become ready under left-to-right,
+// bottom-to-top completion
+// The tree is walked in pre-order, leftmost child first, descending only into incomplete
+// tasks, since a complete task has only complete children. An incomplete task whose
+// children are all complete is a step of its own: it is the remaining work of its branch.
+// Several roots are walked in creation order; complete means one of the given complete statuses
+func BuildPlan(tasks []*Task, complete CompleteStatuses) []PlanStep {
+	children := make(map[TaskID][]*Task)
+	var roots []*Task
+	for _, task := range tasks {
+		if task.parentID == nil {
+			roots = append(roots, task)
+		} else {
+			children[*task.parentID] = append(children[*task.parentID], task)
+		}
+	}
+
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].CreatedBefore(roots[j])
+	})
+	for _, siblings := range children {
+		sort.Slice(siblings, func(i, j int) bool {
+			if siblings[i].position != siblings[j].position {
+				return siblings[i].position < siblings[j].position
+			}
+			return siblings[i].CreatedBefore(siblings[j])
+		})
+	}
+
+	steps := make([]PlanStep, 0)
+	var visit func(task *Task, path []*Task)
+	visit = func(task *Task, path []*Task) {
+		if complete.Contains(task.status) {
+			return
+		}
+
+		var incomplete []*Task
+		for _, child := range children[task.id] {
+			if !complete.Contains(child.status) {
+				incomplete = append(incomplete, child)
+			}
+		}
+		if len(incomplete) == 0 {
+			steps = append(steps, PlanStep{Task: task, Path: path})
+			return
+		}
+
+		// Copy the path so sibling branches do not share its backing array
+		childPath := append(append(make([]*Task, 0, len(path)+1), path...), task)
+		for _, child := range incomplete {
+			visit(child, childPath)
+		}
+	}
+	for _, root := range roots {
+		visit(root, []*Task{})
+	}
+	return steps
+}
+
+// StartNextTasks sets up to count ready TODO tasks to In Progress in one transaction, taking
+// them in plan order (see BuildPlan), and returns the started tasks
+// Readiness is evaluated against the transaction's current state, so tasks started earlier in
+// the batch are taken into account, reusing the states cached by the configured Readiness
+// evaluator where they still hold. Fewer than count tasks are started if fewer are ready
+func (s *TaskService) StartNextTasks(count int) ([]*Task, error) {
+	if count <= 0 {
+		return nil, NewValidationError("count", "invalid-count", "count must be positive")
+	}
+
+	started := make([]*Task, 0, count)
+	err := s.inTransaction(func(tx *TaskService) error {
+		tasks, err := tx.repo.FindAll()
+		if err != nil {
+			return err
+		}
+
+		evaluator := NewReadinessEvaluatorService(tx.repo, NewTreeNavigatorService(tx.repo), tx.config.CompleteStatuses)
+
+		// Cached states describe the store as committed, which starting tasks leaves alone
+		// unless TODO and In Progress differ in whether they count as complete
+		cache := tx.config.Readiness
+		if tx.config.CompleteStatuses.Contains(StatusTODO) != tx.config.CompleteStatuses.Contains(StatusInProgress) {
+			cache = nil
+		}
+		for _, step := range BuildPlan(tasks, tx.config.CompleteStatuses) {
+			if len(started) == count {
+				break
+			}
+			if step.Task.Status() != StatusTODO {
+				continue
+			}
+
+			state, ok := cache.cachedState(step.Task.ID())
+			if !ok {
+				if state, err = evaluator.EvaluateReadiness(step.Task.ID()); err != nil {
+					return err
+				}
+			}
+			if !state.IsReady() {
+				continue
+			}
+
+			if err := tx.ChangeTaskStatus(step.Task.ID(), StatusInProgress); err != nil {
+				return err
+			}
+			task, err := tx.repo.FindByID(step.Task.ID())
+			if err != nil {
+				return err
+			}
+			started = append(started, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return started, nil
+}
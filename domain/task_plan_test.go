@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"testing"
+)
+
+// planDescriptions returns the task description and path of each step as "A/B/Task"
+func planDescriptions(steps []PlanStep) []string {
+	descriptions := make([]string, len(steps))
+	for i, step := range steps {
+		for _, ancestor := range step.Path {
+			descriptions[i] += ancestor.Description() + "/"
+		}
+		descriptions[i] += step.Task.Description()
+	}
+	return descriptions
+}
+
+// newPlanFixture creates the complex tree used by the plan tests, keyed by description:
+//
+//	      Root
+//	   /   |    \
+//	  A    B     C
+//	 /|   /|\    |
+//	D E  F G H   I
+//	             |
+//	             J
+//
+// Status: A, D, E, G = DONE; I = IN PROGRESS; everything else TODO (Root is the root work item)
+// Tasks are created out of position order
+func newPlanFixture() map[string]*Task {
+	root, _ := NewTask("Root", nil, 0)
+	c, _ := NewTask("C", &root.id, 2)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 1)
+	d, _ := NewTask("D", &a.id, 0)
+	e, _ := NewTask("E", &a.id, 1)
+	h, _ := NewTask("H", &b.id, 2)
+	g, _ := NewTask("G", &b.id, 1)
+	f, _ := NewTask("F", &b.id, 0)
+	i, _ := NewTask("I", &c.id, 0)
+	j, _ := NewTask("J", &i.id, 0)
+	for _, task := range []*Task{a, d, e, g} {
+		_ = task.ChangeStatus(StatusDONE)
+	}
+	_ = i.ChangeStatus(StatusInProgress)
+
+	tasks := make(map[string]*Task)
+	for _, task := range []*Task{root, a, b, c, d, e, f, g, h, i, j} {
+		tasks[task.Description()] = task
+	}
+	return tasks
+}
+
+func TestBuildPlan_ComplexTree(t *testing.T) {
+	fixture := newPlanFixture()
+
+	// Pass the tasks in scrambled order
+	var tasks []*Task
+	for _, name := range []string{"J", "H", "Root", "E", "C", "G", "A", "I", "D", "F", "B"} {
+		tasks = append(tasks, fixture[name])
+	}
+	steps := BuildPlan(tasks, CompleteStatuses{})
+
+	expected := []string{"Root/B/F", "Root/B/H", "Root/C/I/J"}
+	actual := planDescriptions(steps)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected plan %v, got %v", expected, actual)
+	}
+	for k := range expected {
+		if actual[k] != expected[k] {
+			t.Errorf("expected plan %v, got %v", expected, actual)
+			break
+		}
+	}
+}
+
+func TestBuildPlan_IncompleteBranchWithCompleteChildren(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	a1, _ := NewTask("A1", &a.id, 0)
+	_ = a1.ChangeStatus(StatusDONE)
+
+	steps := BuildPlan([]*Task{root, a, a1}, CompleteStatuses{})
+	if actual := planDescriptions(steps); len(actual) != 1 || actual[0] != "Root/A" {
+		t.Errorf("expected the branch itself as the only step, got %v", actual)
+	}
+}
+
+func TestBuildPlan_CompleteTree(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	_ = a.ChangeStatus(StatusDONE)
+	_ = root.ChangeStatus(StatusDONE)
+
+	if steps := BuildPlan([]*Task{root, a}, CompleteStatuses{}); len(steps) != 0 {
+		t.Errorf("expected an empty plan for a complete tree, got %v", planDescriptions(steps))
+	}
+	if steps := BuildPlan(nil, CompleteStatuses{}); steps == nil || len(steps) != 0 {
+		t.Errorf("expected an empty, non-nil plan for no tasks, got %v", steps)
+	}
+}
+
+// newStartNextService saves the plan fixture and returns a service over it
+func newStartNextService(t *testing.T) (*TaskService, *InMemoryTaskRepository, map[string]*Task) {
+	repo := NewInMemoryTaskRepository()
+	fixture := newPlanFixture()
+	for _, task := range fixture {
+		if err := repo.Save(task); err != nil {
+			t.Fatalf("failed to save %s: %v", task.Description(), err)
+		}
+	}
+	return NewTaskService(repo), repo, fixture
+}
+
+// taskDescriptions returns the descriptions of tasks, in order
+func taskDescriptions(tasks []*Task) []string {
+	descriptions := make([]string, len(tasks))
+	for i, task := range tasks {
+		descriptions[i] = task.Description()
+	}
+	return descriptions
+}
+
+func TestTaskService_StartNextTasks(t *testing.T) {
+	service, repo, fixture := newStartNextService(t)
+
+	// The first two ready tasks in plan order are started and persisted
+	started, err := service.StartNextTasks(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := taskDescriptions(started); len(actual) != 2 || actual[0] != "F" || actual[1] != "H" {
+		t.Fatalf("expected F and H to be started, got %v", actual)
+	}
+	for _, name := range []string{"F", "H"} {
+		stored, _ := repo.FindByID(fixture[name].ID())
+		if stored.Status() != StatusInProgress {
+			t.Errorf("expected %s to be In Progress, got %s", name, stored.Status())
+		}
+	}
+
+	// Asking for more than are ready starts what is left; started tasks are not picked again
+	started, err = service.StartNextTasks(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := taskDescriptions(started); len(actual) != 1 || actual[0] != "J" {
+		t.Fatalf("expected only J to be started, got %v", actual)
+	}
+
+	started, err = service.StartNextTasks(1)
+	if err != nil || len(started) != 0 {
+		t.Errorf("expected nothing left to start, got %v (%v)", taskDescriptions(started), err)
+	}
+
+	if _, err := service.StartNextTasks(0); err == nil {
+		t.Error("expected an error for a non-positive count")
+	}
+}
+
+func TestTaskService_StartNextTasks_ReevaluatesReadinessWithinBatch(t *testing.T) {
+	// With In Progress counting as complete, starting a task makes its right sibling ready
+	complete, err := NewCompleteStatuses(StatusDONE, StatusInProgress)
+	if err != nil {
+		t.Fatalf("failed to configure complete statuses: %v", err)
+	}
+
+	repo := NewInMemoryTaskRepository()
+	root, _ := NewTask("Root", nil, 0)
+	x, _ := NewTask("X", &root.id, 0)
+	y, _ := NewTask("Y", &root.id, 1)
+	z, _ := NewTask("Z", &root.id, 2)
+	for _, task := range []*Task{root, x, y, z} {
+		_ = repo.Save(task)
+	}
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{CompleteStatuses: complete})
+
+	// Y and Z only become ready once their left sibling is started in the same batch
+	started, err := service.StartNextTasks(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := taskDescriptions(started); len(actual) != 3 || actual[0] != "X" || actual[1] != "Y" || actual[2] != "Z" {
+		t.Errorf("expected X, Y and Z to be started, got %v", actual)
+	}
+}
+
+func TestTaskService_StartNextTasks_WithCachedReadiness(t *testing.T) {
+	repo := NewObservableTaskRepository(NewInMemoryTaskRepository())
+	root, _ := NewTask("Root", nil, 0)
+	x, _ := NewTask("X", &root.id, 0)
+	y, _ := NewTask("Y", &root.id, 1)
+	for _, task := range []*Task{root, x, y} {
+		_ = repo.Save(task)
+	}
+	readiness := NewCachedReadinessEvaluatorService(repo, NewTreeNavigatorService(repo), CompleteStatuses{})
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{Readiness: readiness})
+
+	// Cache Y as blocked by X, then complete X
+	if state, err := readiness.EvaluateReadiness(y.ID()); err != nil || state.IsReady() {
+		t.Fatalf("expected Y to be blocked, got %v (%v)", state, err)
+	}
+	if err := service.ChangeTaskStatus(x.ID(), StatusDONE); err != nil {
+		t.Fatalf("failed to complete X: %v", err)
+	}
+
+	// A stale cached state would skip Y
+	started, err := service.StartNextTasks(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := taskDescriptions(started); len(actual) != 1 || actual[0] != "Y" {
+		t.Errorf("expected Y to be started, got %v", actual)
+	}
+}
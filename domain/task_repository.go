@@ -1,10 +1,16 @@
 package domain
 
-// TaskRepository provides persistence operations for Task aggregates
-type TaskRepository interface {
+// TaskRepositoryTx provides the task operations available inside a transaction
+type TaskRepositoryTx interface {
 	// Save persists a task (create or update)
 	Save(task *Task) error
 
+	// SaveAll persists multiple tasks in a single operation
+	SaveAll(tasks []*Task) error
+
+	// ReplaceAll replaces the entire collection with the given tasks in a single operation
+	ReplaceAll(tasks []*Task) error
+
 	// FindByID retrieves a task by its ID
 	FindByID(id TaskID) (*Task, error)
 
@@ -12,14 +18,64 @@ type TaskRepository interface {
 	FindByParentID(parentID *TaskID) ([]*Task, error)
 
 	// FindRoot retrieves the root task (task with no parent)
+	// If several tasks have no parent, the earliest created is returned (tie-broken by ID)
 	FindRoot() (*Task, error)
 
 	// FindAll retrieves all tasks
 	FindAll() ([]*Task, error)
 
+	// FindByDescription retrieves all tasks whose description exactly matches (case-sensitive),
+	// ordered by creation time (tie-broken by ID)
+	FindByDescription(description string) ([]*Task, error)
+
+	// ForEach calls fn for every task without copying the collection, stopping at the first error
+	// fn runs while the repository is read-locked and must not call back into the repository
+	ForEach(fn func(task *Task) error) error
+
+	// Count returns the total number of tasks
+	Count() (int, error)
+
+	// CountByStatus returns the number of tasks in each status without collecting them
+	// Statuses no task has are absent from the map
+	CountByStatus() (map[Status]int, error)
+
+	// CountByParentID returns the number of tasks with the given parent ID without collecting them
+	CountByParentID(parentID *TaskID) (int, error)
+
+	// NextChildPosition returns the position after the parent's last child (0 when it has none)
+	// It is a plain read: the position stays free only inside a transaction, whose staged copy
+	// no other writer sees; outside one a concurrent save may take it
+	// Gaps among the positions are kept, so an appended child sorts after every existing one;
+	// NORMALIZE_ON_WRITE or a compact closes them
+	NextChildPosition(parentID *TaskID) (int, error)
+
+	// FindSubtree retrieves the given task and all its descendants in depth-first order,
+	// with siblings ordered by position
+	FindSubtree(rootID TaskID) ([]*Task, error)
+
+	// CountSubtree returns the number of tasks in the given task's subtree (itself included)
+	// without collecting them, so callers can check a subtree's size before materializing it
+	CountSubtree(rootID TaskID) (int, error)
+
 	// Delete removes a task by its ID (should only be used for leaf tasks)
 	Delete(id TaskID) error
 
 	// DeleteSubtree removes a task and all its descendants
 	DeleteSubtree(id TaskID) error
+
+	// LastSeq returns the sequence number of the latest change to the store (0 before any)
+	// Every saved task is assigned the next number and every delete consumes one, so the
+	// numbers only grow; persistent stores keep the latest across restarts
+	LastSeq() (uint64, error)
+}
+
+// TaskRepository provides persistence operations for Task aggregates
+type TaskRepository interface {
+	TaskRepositoryTx
+
+	// WithTransaction runs fn against a staged copy of the collection and applies its changes
+	// all at once if fn returns nil, or discards them if fn (or applying them) fails
+	// Tasks read through tx are copies, so changing them in place does not affect the store
+	// until commit. Transactions are serialized with each other and with other writes
+	WithTransaction(fn func(tx TaskRepositoryTx) error) error
 }
@@ -1,22 +1,474 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DeleteStrategy selects what DeleteTaskWithStrategy does with the deleted task's children
+type DeleteStrategy string
+
+const (
+	// DeleteCascade deletes the task together with all its descendants
+	DeleteCascade DeleteStrategy = "cascade"
+	// DeleteReparent promotes the task's children to its parent, at its position, and deletes only the task
+	DeleteReparent DeleteStrategy = "reparent"
+)
+
+// IsValid checks if the strategy value is valid (empty selects the default)
+func (d DeleteStrategy) IsValid() bool {
+	return d == "" || d == DeleteCascade || d == DeleteReparent
+}
+
+// DeleteGuard selects whether DeleteTaskWithStrategy may delete incomplete tasks
+type DeleteGuard string
+
+const (
+	// DeleteGuardCascade deletes tasks regardless of their status
+	DeleteGuardCascade DeleteGuard = "cascade"
+	// DeleteGuardBlock refuses to delete incomplete tasks unless the delete is forced
+	DeleteGuardBlock DeleteGuard = "block"
+)
+
+// IsValid checks if the guard value is valid (empty selects the default)
+func (g DeleteGuard) IsValid() bool {
+	return g == "" || g == DeleteGuardCascade || g == DeleteGuardBlock
+}
+
+// DoneParentPolicy selects how CreateChildTask handles a parent that is already DONE
+type DoneParentPolicy string
+
+const (
+	// DoneParentReject rejects creating a child under a DONE parent
+	DoneParentReject DoneParentPolicy = "reject"
+	// DoneParentReopen reopens the DONE parent (and any DONE ancestors) to the reopen status
+	DoneParentReopen DoneParentPolicy = "reopen"
+)
+
+// IsValid checks if the policy value is valid (empty selects the default)
+func (p DoneParentPolicy) IsValid() bool {
+	return p == "" || p == DoneParentReject || p == DoneParentReopen
+}
+
+// ChildInsertMode selects where CreateChildTask places a child when no position is given
+type ChildInsertMode string
+
+const (
+	// ChildInsertAppend places the new child after its last sibling
+	ChildInsertAppend ChildInsertMode = "append"
+	// ChildInsertPrepend places the new child at position 0, shifting its siblings right
+	ChildInsertPrepend ChildInsertMode = "prepend"
+)
+
+// IsValid checks if the mode value is valid (empty selects the default)
+func (m ChildInsertMode) IsValid() bool {
+	return m == "" || m == ChildInsertAppend || m == ChildInsertPrepend
+}
+
+// TouchAncestorsMode selects which tasks are touched when an operation creates, deletes,
+// or moves their children, so clients syncing by UpdatedAt or Seq see structural changes
+type TouchAncestorsMode string
+
+const (
+	// TouchAncestorsOff touches no task beyond the ones an operation changes
+	TouchAncestorsOff TouchAncestorsMode = "off"
+	// TouchAncestorsParent touches the parent of every created, deleted or moved task
+	TouchAncestorsParent TouchAncestorsMode = "parent"
+	// TouchAncestorsAll touches the parent and every further ancestor
+	TouchAncestorsAll TouchAncestorsMode = "all"
+)
+
+// IsValid checks if the mode value is valid (empty selects the default)
+func (m TouchAncestorsMode) IsValid() bool {
+	return m == "" || m == TouchAncestorsOff || m == TouchAncestorsParent || m == TouchAncestorsAll
+}
+
+// NudgeDirection selects which way NudgeTasks shifts a selection among its siblings
+type NudgeDirection string
+
+const (
+	// NudgeUp shifts each selected task one position towards the first sibling
+	NudgeUp NudgeDirection = "up"
+	// NudgeDown shifts each selected task one position towards the last sibling
+	NudgeDown NudgeDirection = "down"
+)
+
+// IsValid checks if the direction value is valid
+func (d NudgeDirection) IsValid() bool {
+	return d == NudgeUp || d == NudgeDown
+}
+
+// TaskServiceConfig holds optional behaviors for TaskService
+// The zero value preserves the default behavior
+type TaskServiceConfig struct {
+	// TrimDescriptions trims leading and trailing whitespace from descriptions before validation
+	TrimDescriptions bool
+
+	// CollapseWhitespace replaces each run of whitespace inside a description with a single
+	// space before validation; leading and trailing whitespace is left to TrimDescriptions
+	CollapseWhitespace bool
+
+	// DoneParentPolicy selects how a child created under a DONE parent is handled
+	// Defaults to DoneParentReject
+	DoneParentPolicy DoneParentPolicy
+
+	// MaxTotalTasks caps the number of tasks in the store; 0 means unlimited
+	MaxTotalTasks int
+
+	// MoveHistoryLimit caps each task's move history to its most recent entries; 0 means unlimited
+	MoveHistoryLimit int
+
+	// StatusHistoryLimit caps each task's status history to its most recent entries; 0 means unlimited
+	StatusHistoryLimit int
+
+	// ToggleStatuses is the {off, on} pair flipped by ToggleTaskStatus
+	// Defaults to {TODO, DONE}
+	ToggleStatuses []Status
+
+	// MaxSubtreeOperation caps the number of tasks a subtree operation (delete, tag) may touch;
+	// 0 means unlimited
+	MaxSubtreeOperation int
+
+	// IDGenerator generates the IDs of new tasks
+	// Defaults to UUIDGenerator
+	IDGenerator IDGenerator
+
+	// NormalizeOnWrite renumbers the children of every parent a mutating operation touched to
+	// 0..n-1 before persisting, so positions are contiguous at rest at the cost of extra work per write
+	NormalizeOnWrite bool
+
+	// CrossRootMoves selects whether moves may cross from one root's tree into another's
+	// Defaults to CrossRootMovesAllow
+	CrossRootMoves CrossRootMovePolicy
+
+	// ChildInsertMode selects where CreateChildTask places a child when no position is given
+	// Defaults to ChildInsertAppend
+	ChildInsertMode ChildInsertMode
+
+	// ReopenStatus is the status a DONE task is set to when it is reopened, by creating a
+	// child under it, moving a task under it with reconcile, or importing beneath it
+	// Defaults to In Progress; nil, or a complete or Root Work Item status, selects the default
+	ReopenStatus *Status
+
+	// TouchAncestors selects whether the parents (or all ancestors) of tasks created, deleted or
+	// moved in a transaction get a refreshed UpdatedAt and a new Seq, at the cost of extra writes
+	// Defaults to TouchAncestorsOff
+	TouchAncestors TouchAncestorsMode
+
+	// ChildDescriptionTemplate names children created with a blank description; each {parent}
+	// placeholder is replaced with the parent's description
+	// Empty leaves blank descriptions to be rejected
+	ChildDescriptionTemplate string
+
+	// ClampMovePositions moves a task given a position past the end of its new siblings to the
+	// last valid position instead of rejecting the move; see TaskValidatorConfig.ClampMovePositions
+	ClampMovePositions bool
+
+	// DecompositionTags and MinDecomposedChildren keep tasks carrying one of the tags from being
+	// completed until they have enough children; see TaskValidatorConfig.DecompositionTags
+	DecompositionTags     []string
+	MinDecomposedChildren int
+
+	// DecompositionKinds applies the same rule to tasks of these kinds; see
+	// TaskValidatorConfig.DecompositionKinds
+	DecompositionKinds []string
+
+	// Kinds are the kinds SetTaskKind accepts, e.g. "task", "spike" and "milestone"
+	// Defaults to just DefaultTaskKind
+	Kinds []string
+
+	// Archive holds the completed branches moved out of the tree, for ArchivedTrees and
+	// RestoreArchivedTree; nil means there is no archive
+	Archive TaskArchive
+
+	// DeleteGuard selects whether DeleteTaskWithStrategy refuses to delete incomplete tasks
+	// without force, protecting active work from accidental deletes
+	// Defaults to DeleteGuardCascade
+	DeleteGuard DeleteGuard
+
+	// ArchiveOnComplete moves a top-level branch (a child of a root task) to Archive once a status
+	// change leaves its whole subtree complete; it requires Archive
+	ArchiveOnComplete bool
+
+	// CompleteStatuses are the statuses that count as complete for readiness, bottom-to-top
+	// completion, and reopening
+	// Defaults to just DONE
+	CompleteStatuses CompleteStatuses
+
+	// Readiness is a cached evaluator over the service's repository, from
+	// NewCachedReadinessEvaluatorService, whose still-valid states StartNextTasks reuses
+	// nil evaluates every candidate
+	Readiness *ReadinessEvaluatorService
+}
+
+// reopenStatus returns the status complete tasks are reopened to
+// Without a valid configured status it is the first incomplete of In Progress, TODO and Blocked
+func (c TaskServiceConfig) reopenStatus() Status {
+	if c.ReopenStatus != nil && c.ReopenStatus.IsValid() && !c.CompleteStatuses.Contains(*c.ReopenStatus) && *c.ReopenStatus != StatusRootWorkItem {
+		return *c.ReopenStatus
+	}
+	for _, status := range []Status{StatusInProgress, StatusTODO, StatusBlocked} {
+		if !c.CompleteStatuses.Contains(status) {
+			return status
+		}
+	}
+	return StatusDONE // CompleteStatuses leaves at least one of the four incomplete
+}
+
+// validatorConfig returns the validator rules this configuration selects
+func (c TaskServiceConfig) validatorConfig() TaskValidatorConfig {
+	return TaskValidatorConfig{
+		CrossRootMoves:        c.CrossRootMoves,
+		ClampMovePositions:    c.ClampMovePositions,
+		DecompositionTags:     c.DecompositionTags,
+		MinDecomposedChildren: c.MinDecomposedChildren,
+		DecompositionKinds:    c.DecompositionKinds,
+		CompleteStatuses:      c.CompleteStatuses,
+	}
+}
+
+// newValidator creates the TaskValidator for repo with the rules this configuration selects
+func (c TaskServiceConfig) newValidator(repo TaskRepositoryTx) TaskValidator {
+	return NewTaskValidatorWithConfig(repo, c.validatorConfig())
+}
+
 // TaskService provides domain logic for task operations that require repository access
 type TaskService struct {
-	repo      TaskRepository
+	store     TaskRepository   // repository transactions are started on, nil inside a transaction
+	repo      TaskRepositoryTx // repository operations go through, the transaction's inside one
 	validator TaskValidator
+	config    TaskServiceConfig
 }
 
-// NewTaskService creates a new TaskService
+// NewTaskService creates a new TaskService with default configuration
 func NewTaskService(repo TaskRepository) *TaskService {
+	return NewTaskServiceWithConfig(repo, TaskServiceConfig{})
+}
+
+// NewTaskServiceWithConfig creates a new TaskService with the given configuration
+func NewTaskServiceWithConfig(repo TaskRepository, config TaskServiceConfig) *TaskService {
 	return &TaskService{
+		store:     repo,
 		repo:      repo,
-		validator: NewTaskValidator(repo),
+		validator: config.newValidator(repo),
+		config:    config,
+	}
+}
+
+// inTransaction runs fn with a copy of the service whose repository operations belong to a
+// single transaction: they are persisted together if fn succeeds and discarded if it fails
+// Inside a transaction, fn simply runs as part of the enclosing one
+func (s *TaskService) inTransaction(fn func(tx *TaskService) error) error {
+	if s.store == nil {
+		return fn(s)
+	}
+
+	return s.store.WithTransaction(func(repo TaskRepositoryTx) error {
+		var touching *ancestorTouchingRepository
+		if s.config.TouchAncestors == TouchAncestorsParent || s.config.TouchAncestors == TouchAncestorsAll {
+			touching = newAncestorTouchingRepository(repo, s.config.TouchAncestors == TouchAncestorsAll)
+			repo = touching
+		}
+		var tracking *positionTrackingRepository
+		if s.config.NormalizeOnWrite {
+			tracking = newPositionTrackingRepository(repo)
+			repo = tracking
+		}
+
+		if err := fn(&TaskService{
+			repo:      repo,
+			validator: s.config.newValidator(repo),
+			config:    s.config,
+		}); err != nil {
+			return err
+		}
+
+		// Renumber the siblings the operation touched, then touch the parents whose children
+		// changed, before the operation's changes are committed
+		if tracking != nil {
+			if _, err := tracking.normalizePositions(); err != nil {
+				return err
+			}
+		}
+		if touching != nil {
+			if _, err := touching.touchAncestors(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// write runs a mutating operation, in a transaction when NormalizeOnWrite needs one
+// to renumber the touched siblings before persisting
+func (s *TaskService) write(fn func(tx *TaskService) error) error {
+	if !s.config.NormalizeOnWrite {
+		return fn(s)
+	}
+	return s.inTransaction(fn)
+}
+
+// normalizeDescription applies the configured description policy before validation
+func (s *TaskService) normalizeDescription(description string) string {
+	if s.config.CollapseWhitespace {
+		description = collapseWhitespace(description)
+	}
+	if s.config.TrimDescriptions {
+		return strings.TrimSpace(description)
+	}
+	return description
+}
+
+// collapseWhitespace replaces each run of whitespace between words with a single space,
+// keeping any leading and trailing whitespace as it is
+func collapseWhitespace(description string) string {
+	words := strings.TrimSpace(description)
+	if words == "" {
+		return description
+	}
+	leading := len(description) - len(strings.TrimLeftFunc(description, unicode.IsSpace))
+	trailing := description[leading+len(words):]
+	return description[:leading] + strings.Join(strings.Fields(words), " ") + trailing
+}
+
+// maxNewIDAttempts bounds how many IDs newTaskID generates before giving up on finding an unused one
+const maxNewIDAttempts = 10
+
+// newTaskID returns a new ID from the configured IDGenerator that no stored task already has
+// Short IDs carry about 50 random bits, so a collision is unlikely but would make Save overwrite
+// the existing task; a colliding ID is discarded and another generated
+func (s *TaskService) newTaskID() (TaskID, error) {
+	generator := s.config.IDGenerator
+	if generator == nil {
+		generator = UUIDGenerator{}
+	}
+
+	for attempt := 0; attempt < maxNewIDAttempts; attempt++ {
+		id := generator.NewID()
+		_, err := s.repo.FindByID(id)
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(NotFoundError); !ok {
+			return TaskID{}, err
+		}
+		return id, nil
+	}
+	return TaskID{}, fmt.Errorf("no unused task ID after %d attempts", maxNewIDAttempts)
+}
+
+// newTask creates a new Task with an unused ID from the configured IDGenerator
+func (s *TaskService) newTask(description string, parentID *TaskID, position int) (*Task, error) {
+	id, err := s.newTaskID()
+	if err != nil {
+		return nil, err
+	}
+	return newTaskWithID(id, s.normalizeDescription(description), parentID, position)
+}
+
+// ensureCapacity returns a ConstraintViolationError if adding n tasks would exceed MaxTotalTasks
+func (s *TaskService) ensureCapacity(n int) error {
+	if s.config.MaxTotalTasks <= 0 {
+		return nil
+	}
+
+	count, err := s.repo.Count()
+	if err != nil {
+		return err
+	}
+
+	if count+n > s.config.MaxTotalTasks {
+		return NewConstraintViolationError(
+			"max-total-tasks",
+			fmt.Sprintf("cannot add %d task(s): store holds %d of at most %d tasks", n, count, s.config.MaxTotalTasks),
+		)
+	}
+
+	return nil
+}
+
+// ensureSubtreeWithinLimit returns a SubtreeTooLargeError if the task's subtree holds more
+// than MaxSubtreeOperation tasks; the subtree is counted, not collected
+// Subtree changes call it before starting their transaction, so an oversized subtree is
+// rejected without staging the store, and again inside it, where the count is authoritative
+func (s *TaskService) ensureSubtreeWithinLimit(taskID TaskID) error {
+	if s.config.MaxSubtreeOperation <= 0 {
+		return nil
+	}
+
+	size, err := s.repo.CountSubtree(taskID)
+	if err != nil {
+		return err
+	}
+
+	if size > s.config.MaxSubtreeOperation {
+		return NewSubtreeTooLargeError(taskID.String(), size, s.config.MaxSubtreeOperation)
+	}
+
+	return nil
+}
+
+// FindSubtree retrieves the given task and all its descendants in depth-first order
+// Subtrees larger than MaxSubtreeOperation are rejected with a SubtreeTooLargeError before
+// any task is collected
+func (s *TaskService) FindSubtree(taskID TaskID) ([]*Task, error) {
+	if err := s.ensureSubtreeWithinLimit(taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindSubtree(taskID)
+}
+
+// moveAndRecord moves a task that is itself being moved and appends the move to its history
+// Siblings that are only renumbered around it use Task.Move directly so their history stays clean
+func (s *TaskService) moveAndRecord(task *Task, newParentID *TaskID, newPosition int) error {
+	fromParentID := task.ParentID()
+	fromPosition := task.Position()
+
+	if err := task.Move(newParentID, newPosition); err != nil {
+		return err
+	}
+
+	task.recordMove(fromParentID, fromPosition, s.config.MoveHistoryLimit)
+	return nil
+}
+
+// changeStatusAndRecord changes the status of a task whose status is itself being changed and
+// appends the change to its history; setting the status it already has records nothing
+// Tasks changed only as a side effect (e.g. reopened ancestors) use Task.ChangeStatusWith directly
+// so their history stays clean
+func (s *TaskService) changeStatusAndRecord(task *Task, newStatus Status) error {
+	from := task.Status()
+	if err := task.ChangeStatusWith(newStatus, s.config.CompleteStatuses); err != nil {
+		return err
+	}
+
+	if from != newStatus {
+		task.recordStatusChange(from, s.config.StatusHistoryLimit)
 	}
+	return nil
 }
 
 // CreateRootTask creates a new root task with validation
 // Ensures only one root task exists in the tree
+// The root and capacity checks and the write run in one transaction, so concurrent creates
+// cannot both pass them
 func (s *TaskService) CreateRootTask(description string) (*Task, error) {
+	var task *Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		task, err = tx.createRootTask(description)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// createRootTask implements CreateRootTask within a transaction
+func (s *TaskService) createRootTask(description string) (*Task, error) {
 	// Check if a root task already exists
 	existingRoot, err := s.repo.FindRoot()
 	if err == nil && existingRoot != nil {
@@ -24,242 +476,1179 @@ func (s *TaskService) CreateRootTask(description string) (*Task, error) {
 	}
 	// If error is NotFoundError, that's expected and we can proceed
 	if err != nil {
-		if _, ok := err.(NotFoundError); !ok {
-			// Some other error occurred
-			return nil, err
-		}
+		if _, ok := err.(NotFoundError); !ok {
+			// Some other error occurred
+			return nil, err
+		}
+	}
+
+	// Ensure the store has room for the new task
+	if err := s.ensureCapacity(1); err != nil {
+		return nil, err
+	}
+
+	// Create the root task with position 0
+	task, err := s.newTask(description, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the task
+	err = s.repo.Save(task)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// CreateChildTask creates a new child task under the specified parent
+// Automatically calculates the position based on existing children: after the last child,
+// or first when ChildInsertMode is ChildInsertPrepend
+// Validates that the parent exists
+// Runs in a transaction so concurrent creates under one parent get distinct positions
+func (s *TaskService) CreateChildTask(description string, parentID TaskID) (*Task, error) {
+	var task *Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		task, err = tx.createChildTask(description, parentID, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// CreateChildTaskAt creates a new child task at the given position among the parent's children
+// Existing children at or after the position shift right; position must be within [0, childCount]
+// Like CreateChildTask, it runs in a transaction so the shift cannot race another create
+func (s *TaskService) CreateChildTaskAt(description string, parentID TaskID, position int) (*Task, error) {
+	var task *Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		task, err = tx.createChildTask(description, parentID, &position)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// createChildTask creates a child task at position, or appends it when position is nil
+func (s *TaskService) createChildTask(description string, parentID TaskID, position *int) (*Task, error) {
+	// Validate that the parent exists
+	parent, err := s.repo.FindByID(parentID)
+	if err != nil {
+		// In an empty store the real mistake is the missing root, not the parent ID
+		if _, ok := err.(NotFoundError); ok {
+			if count, countErr := s.repo.Count(); countErr == nil && count == 0 {
+				return nil, NewConstraintViolationError("no-root", "no root task exists; create a root first")
+			}
+		}
+		return nil, err
+	}
+
+	// A new child is incomplete, so a DONE parent would violate bottom-to-top completion
+	if s.config.CompleteStatuses.Contains(parent.Status()) && s.config.DoneParentPolicy != DoneParentReopen {
+		return nil, NewConstraintViolationError(
+			"done-parent",
+			"cannot create a child under a task that is DONE",
+		)
+	}
+
+	// Ensure the store has room for the new task
+	if err := s.ensureCapacity(1); err != nil {
+		return nil, err
+	}
+
+	// Find existing children to make room among them
+	children, err := s.repo.FindByParentID(&parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Append after the last child (or prepend, if configured) unless a position was requested
+	var nextPosition int
+	if position != nil {
+		if *position < 0 {
+			return nil, NewValidationError("position", "negative-position", "position must be non-negative")
+		}
+		if *position > len(children) {
+			return nil, NewValidationError("position", "position-out-of-range", "position exceeds valid range")
+		}
+		nextPosition = *position
+	} else if s.config.ChildInsertMode == ChildInsertPrepend {
+		nextPosition = 0
+	} else {
+		// Called in a transaction, so no concurrent create can take this position before commit
+		nextPosition, err = s.repo.NextChildPosition(&parentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Name a blank child after its parent, if configured
+	if strings.TrimSpace(description) == "" && s.config.ChildDescriptionTemplate != "" {
+		description = strings.ReplaceAll(s.config.ChildDescriptionTemplate, "{parent}", parent.Description())
+	}
+
+	// Create the child task
+	task, err := s.newTask(description, &parentID, nextPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make room among the existing children
+	shifted, err := shiftSiblingsRight(children, nextPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reopen the DONE parent and any DONE ancestors so completion stays bottom-to-top
+	reopened, err := s.reopenDoneAncestors(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the task together with any shifted siblings and reopened ancestors
+	toSave := append([]*Task{task}, shifted...)
+	err = s.repo.SaveAll(append(toSave, reopened...))
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// reopenDoneAncestors changes the given task and its DONE ancestors to the reopen status,
+// clearing their CompletedAt. Stops at the first ancestor that is not DONE and returns the changed tasks
+func (s *TaskService) reopenDoneAncestors(task *Task) ([]*Task, error) {
+	var reopened []*Task
+
+	for task != nil && s.config.CompleteStatuses.Contains(task.Status()) {
+		if err := task.ChangeStatusWith(s.config.reopenStatus(), s.config.CompleteStatuses); err != nil {
+			return nil, err
+		}
+		reopened = append(reopened, task)
+
+		if task.ParentID() == nil {
+			break
+		}
+		parent, err := s.repo.FindByID(*task.ParentID())
+		if err != nil {
+			return nil, err
+		}
+		task = parent
+	}
+
+	return reopened, nil
+}
+
+// UpdateTaskDescription updates the description of a task
+// Applies the configured description policy before validation
+func (s *TaskService) UpdateTaskDescription(taskID TaskID, description string) (*Task, error) {
+	// Retrieve the task first
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the description (validates it is not empty)
+	err = task.UpdateDescription(s.normalizeDescription(description))
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the updated task
+	err = s.repo.Save(task)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// ChangeTaskStatus changes the status of a task with validation
+// Enforces bottom-to-top completion: a task can only be marked complete if all children are complete
+// Incomplete statuses are allowed regardless of children status
+// With ArchiveOnComplete, a change that completes a whole top-level branch archives the branch;
+// the status change and the branch's removal from the tree commit in one transaction, and the
+// branch is taken out of the archive again if they do not
+func (s *TaskService) ChangeTaskStatus(taskID TaskID, newStatus Status) error {
+	var archived *ArchivedTree
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		archived, err = tx.changeTaskStatus(taskID, newStatus)
+		return err
+	})
+	if err != nil && archived != nil {
+		if removeErr := s.config.Archive.Remove(archived.Root().ID()); removeErr != nil {
+			return errors.Join(err, fmt.Errorf("archived tree %s is still in the archive: %w", archived.Root().ID(), removeErr))
+		}
+	}
+	return err
+}
+
+// changeTaskStatus implements ChangeTaskStatus within a transaction
+// Returns the tree added to the archive, if any, even when a later step fails
+func (s *TaskService) changeTaskStatus(taskID TaskID, newStatus Status) (*ArchivedTree, error) {
+	// Retrieve the task first
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the status change using the validator
+	// This enforces bottom-to-top completion for complete statuses
+	err = s.validator.ValidateStatusChange(task, newStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	// Change the status on the task entity and record it in the task's status history
+	// This performs basic validation (checking if status is valid)
+	err = s.changeStatusAndRecord(task, newStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the updated task
+	err = s.repo.Save(task)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the task's branch to the archive if this completed it
+	return s.archiveCompletedBranch(task)
+}
+
+// StatusOptions returns the statuses the task can move to right now, in status order
+// A status is an option when ChangeTaskStatus would accept it, so complete statuses are
+// left out while the task has incomplete children; the current status is never an option
+func (s *TaskService) StatusOptions(taskID TaskID) ([]Status, error) {
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []Status
+	for status := StatusTODO; status.IsValid(); status++ {
+		if status == task.Status() {
+			continue
+		}
+		err := s.validator.ValidateStatusChange(task, status)
+		if _, ok := err.(ConstraintViolationError); ok {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, status)
+	}
+	return options, nil
+}
+
+// ToggleTaskStatus flips a task between the configured off and on statuses (TODO and DONE by default)
+// A task in the on status is set to off; any other status is set to on
+// Moving to a complete status enforces bottom-to-top completion like ChangeTaskStatus
+// Returns the updated task, read from the archive if the toggle archived its branch
+func (s *TaskService) ToggleTaskStatus(taskID TaskID) (*Task, error) {
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	off, on := StatusTODO, StatusDONE
+	if len(s.config.ToggleStatuses) == 2 {
+		off, on = s.config.ToggleStatuses[0], s.config.ToggleStatuses[1]
+	}
+
+	newStatus := on
+	if task.Status() == on {
+		newStatus = off
+	}
+
+	if err := s.ChangeTaskStatus(taskID, newStatus); err != nil {
+		return nil, err
+	}
+
+	return s.findActiveOrArchived(taskID)
+}
+
+// ResetSubtree sets a task and all its descendants to TODO in one transaction, clearing their
+// CompletedAt, to reopen a branch for rework; a Root Work Item keeps its status
+// A DONE parent cannot keep an incomplete child, so the task's DONE ancestors are reopened to
+// the reopen status, as by MoveTaskAndReconcile
+// Subtrees larger than MaxSubtreeOperation are rejected with a SubtreeTooLargeError
+// Returns the number of tasks set to TODO and the reopened ancestors, nearest first
+func (s *TaskService) ResetSubtree(taskID TaskID) (int, []*Task, error) {
+	if err := s.ensureSubtreeWithinLimit(taskID); err != nil {
+		return 0, nil, err
+	}
+
+	reset := 0
+	var reopened []*Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		if err := tx.ensureSubtreeWithinLimit(taskID); err != nil {
+			return err
+		}
+
+		subtree, err := tx.repo.FindSubtree(taskID)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]*Task, 0, len(subtree))
+		for _, task := range subtree {
+			if task.Status() == StatusTODO || task.Status() == StatusRootWorkItem {
+				continue
+			}
+			if err := tx.changeStatusAndRecord(task, StatusTODO); err != nil {
+				return err
+			}
+			changed = append(changed, task)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		reset = len(changed)
+
+		if parentID := subtree[0].ParentID(); parentID != nil {
+			parent, err := tx.repo.FindByID(*parentID)
+			if err != nil {
+				return err
+			}
+			if reopened, err = tx.reopenDoneAncestors(parent); err != nil {
+				return err
+			}
+			changed = append(changed, reopened...)
+		}
+
+		return tx.repo.SaveAll(changed)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return reset, reopened, nil
+}
+
+// MoveTask moves a task to a new parent and position
+// Handles position adjustments for both old and new siblings
+// Validates the move operation (prevents cycles)
+// The entire subtree moves with the task, and all changes are persisted in one transaction
+// An incomplete task cannot be moved under a DONE parent; see MoveTaskAndReconcile
+func (s *TaskService) MoveTask(taskID TaskID, newParentID *TaskID, newPosition int) error {
+	return s.inTransaction(func(tx *TaskService) error {
+		_, err := tx.moveTask(taskID, newParentID, newPosition, false)
+		return err
+	})
+}
+
+// MoveTaskAndReconcile moves a task like MoveTask, but instead of rejecting the move of an
+// incomplete task under a DONE parent, reopens the new parent and its DONE ancestors to the reopen status
+// Returns the reopened ancestors, nearest first
+func (s *TaskService) MoveTaskAndReconcile(taskID TaskID, newParentID *TaskID, newPosition int) ([]*Task, error) {
+	var reopened []*Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		reopened, err = tx.moveTask(taskID, newParentID, newPosition, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reopened, nil
+}
+
+// PromoteToRoot makes a task the root of the tree: its parent is cleared, its status becomes
+// Root Work Item and it takes position 0, keeping its subtree. Its former siblings close the gap
+// Promoting is rejected while a root exists unless replaceRoot is set, in which case the current
+// root is detached and deleted; that is only allowed when nothing but the promoted subtree is
+// left under it, so no other branch is lost. Exactly one root remains. Promoting a root is a no-op
+func (s *TaskService) PromoteToRoot(taskID TaskID, replaceRoot bool) (*Task, error) {
+	var task *Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		task, err = tx.promoteToRoot(taskID, replaceRoot)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// promoteToRoot implements PromoteToRoot inside a transaction
+func (s *TaskService) promoteToRoot(taskID TaskID, replaceRoot bool) (*Task, error) {
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.IsRoot() {
+		return task, nil
+	}
+
+	roots, err := s.repo.FindByParentID(nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) > 0 && !replaceRoot {
+		return nil, NewConstraintViolationError("single-root", "cannot promote task to root: a root task already exists")
+	}
+
+	// Every current root goes, so it may hold nothing but the promoted subtree
+	promoted, err := s.repo.CountSubtree(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range roots {
+		size, err := s.repo.CountSubtree(root.ID())
+		if err != nil {
+			return nil, err
+		}
+		if size != promoted+1 || !task.ParentID().Equals(root.ID()) {
+			return nil, NewConstraintViolationError(
+				"root-has-branches",
+				"cannot replace the root: it has other tasks besides the promoted one; delete or move them first",
+			)
+		}
+	}
+
+	// Close the gap among the task's former siblings
+	oldParentID, oldPosition := task.ParentID(), task.Position()
+	siblings, err := s.repo.FindByParentID(oldParentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sibling := range siblings {
+		if sibling.ID().Equals(taskID) || sibling.Position() <= oldPosition {
+			continue
+		}
+		if err := sibling.Move(sibling.ParentID(), sibling.Position()-1); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Save(sibling); err != nil {
+			return nil, err
+		}
+	}
+
+	// Moving to no parent makes the task a Root Work Item
+	if err := s.moveAndRecord(task, nil, 0); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(task); err != nil {
+		return nil, err
+	}
+
+	for _, root := range roots {
+		if err := s.repo.Delete(root.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+// moveTask implements MoveTask within a transaction
+// When reconcile is set, DONE ancestors at the destination are reopened and returned
+func (s *TaskService) moveTask(taskID TaskID, newParentID *TaskID, newPosition int, reconcile bool) ([]*Task, error) {
+	// Retrieve the task being moved
+	task, err := s.repo.FindByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clamp an over-range position first, if configured, so the move uses the clamped one
+	newPosition, err = s.validator.ClampMovePosition(taskID, newParentID, newPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the move operation (cycle detection, parent exists, etc.)
+	err = s.validator.ValidateMove(taskID, newParentID, newPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// An incomplete task under a new, DONE parent would violate bottom-to-top completion
+	var doneParent *Task
+	changesParent := newParentID != nil && (task.ParentID() == nil || !task.ParentID().Equals(*newParentID))
+	if changesParent && !s.config.CompleteStatuses.Contains(task.Status()) {
+		newParent, err := s.repo.FindByID(*newParentID)
+		if err != nil {
+			return nil, err
+		}
+		if s.config.CompleteStatuses.Contains(newParent.Status()) {
+			if !reconcile {
+				return nil, NewConstraintViolationError(
+					"done-parent",
+					"cannot move an incomplete task under a task that is DONE",
+				)
+			}
+			doneParent = newParent
+		}
+	}
+
+	oldParentID := task.ParentID()
+	oldPosition := task.Position()
+
+	// Check if this is actually a move (parent or position changed)
+	isSameParent := (oldParentID == nil && newParentID == nil) ||
+		(oldParentID != nil && newParentID != nil && oldParentID.Equals(*newParentID))
+
+	if isSameParent && oldPosition == newPosition {
+		// No actual move needed
+		return nil, nil
+	}
+
+	if !isSameParent {
+		// Moving to a different parent
+		
+		// Step 1: Adjust positions of old siblings (close the gap)
+		oldSiblings, err := s.repo.FindByParentID(oldParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sibling := range oldSiblings {
+			// Skip the task being moved
+			if sibling.ID().Equals(taskID) {
+				continue
+			}
+
+			// Shift left siblings that are to the right of the old position
+			if sibling.Position() > oldPosition {
+				err = sibling.Move(sibling.ParentID(), sibling.Position()-1)
+				if err != nil {
+					return nil, err
+				}
+				err = s.repo.Save(sibling)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// Step 2: Adjust positions of new siblings (make room)
+		newSiblings, err := s.repo.FindByParentID(newParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		shifted, err := shiftSiblingsRight(newSiblings, newPosition)
+		if err != nil {
+			return nil, err
+		}
+		for _, sibling := range shifted {
+			err = s.repo.Save(sibling)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// Moving within the same parent (reordering)
+		siblings, err := s.repo.FindByParentID(newParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if newPosition > oldPosition {
+			// Moving right: shift siblings between old and new position left
+			for _, sibling := range siblings {
+				if sibling.ID().Equals(taskID) {
+					continue
+				}
+				if sibling.Position() > oldPosition && sibling.Position() <= newPosition {
+					err = sibling.Move(sibling.ParentID(), sibling.Position()-1)
+					if err != nil {
+						return nil, err
+					}
+					err = s.repo.Save(sibling)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+		} else {
+			// Moving left: shift siblings between new and old position right
+			for _, sibling := range siblings {
+				if sibling.ID().Equals(taskID) {
+					continue
+				}
+				if sibling.Position() >= newPosition && sibling.Position() < oldPosition {
+					err = sibling.Move(sibling.ParentID(), sibling.Position()+1)
+					if err != nil {
+						return nil, err
+					}
+					err = s.repo.Save(sibling)
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	// Step 3: Move the task itself
+	err = s.moveAndRecord(task, newParentID, newPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Save(task)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: In this in-memory implementation, no explicit subtree traversal is needed when moving a task.
+	// Child tasks reference their parent by ID, so when a task's parent or position changes,
+	// its subtree remains attached via these references; only the parent and position are updated.
+
+	// Reopen the DONE parent and any DONE ancestors so completion stays bottom-to-top
+	if doneParent == nil {
+		return nil, nil
+	}
+	reopened, err := s.reopenDoneAncestors(doneParent)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveAll(reopened); err != nil {
+		return nil, err
+	}
+
+	return reopened, nil
+}
+
+// shiftSiblingsRight moves every sibling at or after position one place right to make room
+// Returns the shifted siblings, which the caller persists
+func shiftSiblingsRight(siblings []*Task, position int) ([]*Task, error) {
+	var shifted []*Task
+	for _, sibling := range siblings {
+		// Shift right siblings that are at or after the new position
+		if sibling.Position() >= position {
+			if err := sibling.Move(sibling.ParentID(), sibling.Position()+1); err != nil {
+				return nil, err
+			}
+			shifted = append(shifted, sibling)
+		}
+	}
+	return shifted, nil
+}
+
+// MoveTasks moves several tasks under a single new parent
+// The tasks are placed at sequential positions starting at startPosition, in the order given
+// All moves are validated before any is applied, and every changed task is persisted at once
+func (s *TaskService) MoveTasks(taskIDs []TaskID, newParentID TaskID, startPosition int) error {
+	return s.inTransaction(func(tx *TaskService) error {
+		return tx.moveTasks(taskIDs, newParentID, startPosition)
+	})
+}
+
+// moveTasks implements MoveTasks within a transaction
+func (s *TaskService) moveTasks(taskIDs []TaskID, newParentID TaskID, startPosition int) error {
+	// Validate the whole selection before touching any task
+	err := s.validator.ValidateBulkMove(taskIDs, newParentID, startPosition)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool, len(taskIDs))
+	moving := make([]*Task, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		task, err := s.repo.FindByID(taskID)
+		if err != nil {
+			return err
+		}
+		selected[taskID.String()] = true
+		moving = append(moving, task)
+	}
+
+	changed := make(map[string]*Task)
+
+	// Step 1: Close the gaps left behind under each old parent
+	if err := s.closeSelectionGaps(moving, selected, newParentID, changed); err != nil {
+		return err
+	}
+
+	// Step 2: Rebuild the new parent's children with the moved tasks inserted at startPosition
+	newSiblings, err := s.repo.FindByParentID(&newParentID)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*Task, 0, len(newSiblings))
+	for _, sibling := range newSiblings {
+		if !selected[sibling.ID().String()] {
+			remaining = append(remaining, sibling)
+		}
+	}
+
+	ordered := make([]*Task, 0, len(remaining)+len(moving))
+	ordered = append(ordered, remaining[:startPosition]...)
+	ordered = append(ordered, moving...)
+	ordered = append(ordered, remaining[startPosition:]...)
+
+	for position, task := range ordered {
+		isSameParent := task.ParentID() != nil && task.ParentID().Equals(newParentID)
+		if isSameParent && task.Position() == position {
+			continue
+		}
+		if selected[task.ID().String()] {
+			err = s.moveAndRecord(task, &newParentID, position)
+		} else {
+			err = task.Move(&newParentID, position)
+		}
+		if err != nil {
+			return err
+		}
+		changed[task.ID().String()] = task
+	}
+
+	if len(changed) == 0 {
+		// No actual move needed
+		return nil
+	}
+
+	// Step 3: Persist every changed task in a single operation
+	toSave := make([]*Task, 0, len(changed))
+	for _, task := range changed {
+		toSave = append(toSave, task)
+	}
+
+	return s.repo.SaveAll(toSave)
+}
+
+// NudgeTasks shifts the selected siblings one position up or down within their parent,
+// keeping their relative order; each unselected sibling they pass shifts the other way
+// The selection need not be contiguous, but must share a parent and must not already
+// include the first child (up) or the last child (down)
+func (s *TaskService) NudgeTasks(taskIDs []TaskID, direction NudgeDirection) error {
+	return s.inTransaction(func(tx *TaskService) error {
+		return tx.nudgeTasks(taskIDs, direction)
+	})
+}
+
+// nudgeTasks implements NudgeTasks within a transaction
+func (s *TaskService) nudgeTasks(taskIDs []TaskID, direction NudgeDirection) error {
+	if !direction.IsValid() {
+		return NewValidationError("direction", "invalid-direction", fmt.Sprintf("direction must be %s or %s", NudgeUp, NudgeDown))
+	}
+	if len(taskIDs) == 0 {
+		return NewValidationError("ids", "missing-ids", "at least one task ID is required")
+	}
+
+	// Load the selection and check it shares a parent
+	var parentID *TaskID
+	selected := make(map[string]bool, len(taskIDs))
+	for i, taskID := range taskIDs {
+		if selected[taskID.String()] {
+			return NewValidationError("ids", "duplicate-ids", "task IDs must be unique")
+		}
+		selected[taskID.String()] = true
+
+		task, err := s.repo.FindByID(taskID)
+		if err != nil {
+			return err
+		}
+		if task.ParentID() == nil {
+			return NewConstraintViolationError("shared-parent", "cannot nudge a root task")
+		}
+		if i == 0 {
+			parentID = task.ParentID()
+		} else if !parentID.Equals(*task.ParentID()) {
+			return NewConstraintViolationError("shared-parent", "cannot nudge tasks that do not share a parent")
+		}
+	}
+
+	ordered, err := s.repo.FindByParentID(parentID)
+	if err != nil {
+		return err
+	}
+
+	// Swap each selected task with its neighbor, starting from the end the selection moves towards,
+	// so a run of selected tasks moves as a block past the neighbor in front of it
+	last := len(ordered) - 1
+	if direction == NudgeUp {
+		if selected[ordered[0].ID().String()] {
+			return NewConstraintViolationError("nudge-range", "cannot nudge up: the selection includes the first child")
+		}
+		for i := 1; i <= last; i++ {
+			if selected[ordered[i].ID().String()] {
+				ordered[i-1], ordered[i] = ordered[i], ordered[i-1]
+			}
+		}
+	} else {
+		if selected[ordered[last].ID().String()] {
+			return NewConstraintViolationError("nudge-range", "cannot nudge down: the selection includes the last child")
+		}
+		for i := last - 1; i >= 0; i-- {
+			if selected[ordered[i].ID().String()] {
+				ordered[i], ordered[i+1] = ordered[i+1], ordered[i]
+			}
+		}
+	}
+
+	// Renumber the siblings in their new order; moves of the selected tasks are recorded
+	var changed []*Task
+	for position, task := range ordered {
+		if task.Position() == position {
+			continue
+		}
+		if selected[task.ID().String()] {
+			err = s.moveAndRecord(task, parentID, position)
+		} else {
+			err = task.Move(parentID, position)
+		}
+		if err != nil {
+			return err
+		}
+		changed = append(changed, task)
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	return s.repo.SaveAll(changed)
+}
+
+// closeSelectionGaps renumbers the remaining children of every old parent of the moving tasks
+// The parent the tasks move to is skipped since the caller rebuilds its children; renumbered tasks are added to changed
+func (s *TaskService) closeSelectionGaps(moving []*Task, selected map[string]bool, newParentID TaskID, changed map[string]*Task) error {
+	visitedParents := make(map[string]bool)
+	for _, task := range moving {
+		oldParentID := task.ParentID()
+		if oldParentID == nil || oldParentID.Equals(newParentID) || visitedParents[oldParentID.String()] {
+			continue
+		}
+		visitedParents[oldParentID.String()] = true
+
+		oldSiblings, err := s.repo.FindByParentID(oldParentID)
+		if err != nil {
+			return err
+		}
+
+		position := 0
+		for _, sibling := range oldSiblings {
+			if selected[sibling.ID().String()] {
+				continue
+			}
+			if sibling.Position() != position {
+				if err := sibling.Move(sibling.ParentID(), position); err != nil {
+					return err
+				}
+				changed[sibling.ID().String()] = sibling
+			}
+			position++
+		}
+	}
+
+	return nil
+}
+
+// GroupTasks creates a new task under parentID at position and moves the listed tasks,
+// in order, to be its children
+// All moves are validated before any is applied, and every change is persisted at once
+func (s *TaskService) GroupTasks(childIDs []TaskID, description string, parentID TaskID, position int) (*Task, error) {
+	var group *Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		group, err = tx.groupTasks(childIDs, description, parentID, position)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return group, nil
+}
 
-	// Create the root task with position 0
-	task, err := NewTask(description, nil, 0)
+// groupTasks implements GroupTasks within a transaction
+func (s *TaskService) groupTasks(childIDs []TaskID, description string, parentID TaskID, position int) (*Task, error) {
+	// The group takes the children's place under the parent, so the selection must be movable
+	// there: no cycles, no overlapping selection, and a position within the remaining siblings
+	err := s.validator.ValidateBulkMove(childIDs, parentID, position)
 	if err != nil {
 		return nil, err
 	}
 
-	// Save the task
-	err = s.repo.Save(task)
+	parent, err := s.repo.FindByID(parentID)
 	if err != nil {
 		return nil, err
 	}
 
-	return task, nil
-}
+	// The group is incomplete, so a DONE parent would violate bottom-to-top completion
+	if s.config.CompleteStatuses.Contains(parent.Status()) && s.config.DoneParentPolicy != DoneParentReopen {
+		return nil, NewConstraintViolationError(
+			"done-parent",
+			"cannot create a group under a task that is DONE",
+		)
+	}
 
-// CreateChildTask creates a new child task under the specified parent
-// Automatically calculates the position based on existing children
-// Validates that the parent exists
-func (s *TaskService) CreateChildTask(description string, parentID TaskID) (*Task, error) {
-	// Validate that the parent exists
-	_, err := s.repo.FindByID(parentID)
-	if err != nil {
+	// Ensure the store has room for the group task
+	if err := s.ensureCapacity(1); err != nil {
 		return nil, err
 	}
 
-	// Find existing children to calculate the next position
-	children, err := s.repo.FindByParentID(&parentID)
+	group, err := s.newTask(description, &parentID, position)
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate the next position (number of existing children)
-	nextPosition := len(children)
+	selected := make(map[string]bool, len(childIDs))
+	moving := make([]*Task, 0, len(childIDs))
+	for _, childID := range childIDs {
+		child, err := s.repo.FindByID(childID)
+		if err != nil {
+			return nil, err
+		}
+		selected[childID.String()] = true
+		moving = append(moving, child)
+	}
+
+	changed := make(map[string]*Task)
 
-	// Create the child task
-	task, err := NewTask(description, &parentID, nextPosition)
-	if err != nil {
+	// Step 1: Close the gaps left behind under each old parent other than the target
+	if err := s.closeSelectionGaps(moving, selected, parentID, changed); err != nil {
 		return nil, err
 	}
 
-	// Save the task
-	err = s.repo.Save(task)
+	// Step 2: Rebuild the parent's children with the group inserted at position
+	siblings, err := s.repo.FindByParentID(&parentID)
 	if err != nil {
 		return nil, err
 	}
 
-	return task, nil
-}
+	ordered := make([]*Task, 0, len(siblings)+1)
+	for _, sibling := range siblings {
+		if !selected[sibling.ID().String()] {
+			ordered = append(ordered, sibling)
+		}
+	}
+	ordered = append(ordered[:position], append([]*Task{group}, ordered[position:]...)...)
 
-// ChangeTaskStatus changes the status of a task with validation
-// Enforces bottom-to-top completion: a task can only be marked DONE if all children are DONE
-// Non-DONE statuses are allowed regardless of children status
-func (s *TaskService) ChangeTaskStatus(taskID TaskID, newStatus Status) error {
-	// Retrieve the task first
-	task, err := s.repo.FindByID(taskID)
-	if err != nil {
-		return err
+	for i, task := range ordered {
+		if task == group || task.Position() == i {
+			continue
+		}
+		if err := task.Move(&parentID, i); err != nil {
+			return nil, err
+		}
+		changed[task.ID().String()] = task
 	}
 
-	// Validate the status change using the validator
-	// This enforces bottom-to-top completion for DONE status
-	err = s.validator.ValidateStatusChange(task, newStatus)
-	if err != nil {
-		return err
+	// Step 3: Move the selected tasks under the group, preserving the requested order
+	groupID := group.ID()
+	for i, child := range moving {
+		if err := s.moveAndRecord(child, &groupID, i); err != nil {
+			return nil, err
+		}
+		changed[child.ID().String()] = child
 	}
 
-	// Change the status on the task entity
-	// This performs basic validation (checking if status is valid)
-	err = task.ChangeStatus(newStatus)
+	// Reopen the DONE parent and any DONE ancestors so completion stays bottom-to-top
+	reopened, err := s.reopenDoneAncestors(parent)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for _, task := range reopened {
+		changed[task.ID().String()] = task
 	}
 
-	// Save the updated task
-	err = s.repo.Save(task)
-	if err != nil {
-		return err
+	// Step 4: Persist the group and every changed task in a single operation
+	toSave := make([]*Task, 0, len(changed)+1)
+	toSave = append(toSave, group)
+	for _, task := range changed {
+		toSave = append(toSave, task)
 	}
 
-	return nil
+	if err := s.repo.SaveAll(toSave); err != nil {
+		return nil, err
+	}
+
+	return group, nil
 }
 
-// MoveTask moves a task to a new parent and position
-// Handles position adjustments for both old and new siblings
-// Validates the move operation (prevents cycles)
-// The entire subtree moves with the task
-func (s *TaskService) MoveTask(taskID TaskID, newParentID *TaskID, newPosition int) error {
-	// Retrieve the task being moved
-	task, err := s.repo.FindByID(taskID)
-	if err != nil {
+// UngroupTask moves the children of a task up to take its place among its siblings, then deletes it
+// Later siblings shift right to make room. The root task cannot be ungrouped
+// Returns the promoted children in their new order. All changes are persisted in one transaction
+func (s *TaskService) UngroupTask(taskID TaskID) ([]*Task, error) {
+	var children []*Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		children, err = tx.ungroupTask(taskID)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return children, nil
+}
 
-	// Validate the move operation (cycle detection, parent exists, etc.)
-	err = s.validator.ValidateMove(taskID, newParentID, newPosition)
+// ungroupTask implements UngroupTask within a transaction
+func (s *TaskService) ungroupTask(taskID TaskID) ([]*Task, error) {
+	task, err := s.repo.FindByID(taskID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	oldParentID := task.ParentID()
-	oldPosition := task.Position()
+	if task.IsRoot() {
+		return nil, NewConstraintViolationError("root-ungroup", "cannot ungroup the root task")
+	}
 
-	// Check if this is actually a move (parent or position changed)
-	isSameParent := (oldParentID == nil && newParentID == nil) ||
-		(oldParentID != nil && newParentID != nil && oldParentID.Equals(*newParentID))
+	parentID := *task.ParentID()
+	siblings, err := s.repo.FindByParentID(&parentID)
+	if err != nil {
+		return nil, err
+	}
 
-	if isSameParent && oldPosition == newPosition {
-		// No actual move needed
-		return nil
+	children, err := s.repo.FindByParentID(&taskID)
+	if err != nil {
+		return nil, err
 	}
 
-	if !isSameParent {
-		// Moving to a different parent
-		
-		// Step 1: Adjust positions of old siblings (close the gap)
-		oldSiblings, err := s.repo.FindByParentID(oldParentID)
+	// Splice the children in at the task's place; the emptied task goes last so the
+	// tree stays valid between saving the moves and deleting it
+	ordered := make([]*Task, 0, len(siblings)+len(children))
+	for _, sibling := range siblings {
+		if sibling.ID().Equals(taskID) {
+			ordered = append(ordered, children...)
+			continue
+		}
+		ordered = append(ordered, sibling)
+	}
+	ordered = append(ordered, task)
+
+	changed := make([]*Task, 0, len(ordered))
+	for position, t := range ordered {
+		isSameParent := t.ParentID() != nil && t.ParentID().Equals(parentID)
+		if isSameParent && t.Position() == position {
+			continue
+		}
+		// Promoted children change parent and are recorded; shifted siblings are not
+		if !isSameParent {
+			err = s.moveAndRecord(t, &parentID, position)
+		} else {
+			err = t.Move(&parentID, position)
+		}
 		if err != nil {
-			return err
+			return nil, err
 		}
+		changed = append(changed, t)
+	}
 
-		for _, sibling := range oldSiblings {
-			// Skip the task being moved
-			if sibling.ID().Equals(taskID) {
-				continue
-			}
+	// Persist every move in a single operation
+	if err := s.repo.SaveAll(changed); err != nil {
+		return nil, err
+	}
 
-			// Shift left siblings that are to the right of the old position
-			if sibling.Position() > oldPosition {
-				err = sibling.Move(sibling.ParentID(), sibling.Position()-1)
-				if err != nil {
-					return err
-				}
-				err = s.repo.Save(sibling)
-				if err != nil {
-					return err
-				}
-			}
-		}
+	// The task is now a childless last sibling, so removing it leaves no gap
+	if err := s.repo.Delete(taskID); err != nil {
+		return nil, err
+	}
 
-		// Step 2: Adjust positions of new siblings (make room)
-		newSiblings, err := s.repo.FindByParentID(newParentID)
-		if err != nil {
+	return children, nil
+}
+
+// DeleteTask deletes a task and adjusts sibling positions
+// If the task has children, it performs cascading deletion
+// If the task is the root, it removes the entire tree
+// The deletion and the sibling adjustments are persisted in one transaction
+// Subtrees larger than MaxSubtreeOperation are rejected with a SubtreeTooLargeError
+func (s *TaskService) DeleteTask(taskID TaskID) error {
+	if err := s.ensureSubtreeWithinLimit(taskID); err != nil {
+		return err
+	}
+
+	return s.inTransaction(func(tx *TaskService) error {
+		return tx.deleteTask(taskID)
+	})
+}
+
+// DeleteTaskWithStrategy deletes a task, handling its children according to strategy
+// DeleteCascade (the default) behaves like DeleteTask. DeleteReparent moves the children up to
+// take the task's place among its siblings, like UngroupTask, and is rejected for the root task
+// With DeleteGuardBlock, deleting any incomplete task is rejected with a ConstraintViolationError
+// naming those tasks unless force is set
+// All changes are persisted in one transaction
+func (s *TaskService) DeleteTaskWithStrategy(taskID TaskID, strategy DeleteStrategy, force bool) error {
+	switch strategy {
+	case "", DeleteCascade:
+		if err := s.ensureSubtreeWithinLimit(taskID); err != nil {
 			return err
 		}
-
-		for _, sibling := range newSiblings {
-			// Shift right siblings that are at or after the new position
-			if sibling.Position() >= newPosition {
-				err = sibling.Move(sibling.ParentID(), sibling.Position()+1)
+		return s.inTransaction(func(tx *TaskService) error {
+			if !force {
+				subtree, err := tx.repo.FindSubtree(taskID)
 				if err != nil {
 					return err
 				}
-				err = s.repo.Save(sibling)
-				if err != nil {
+				if err := tx.guardDelete(subtree); err != nil {
 					return err
 				}
 			}
-		}
-	} else {
-		// Moving within the same parent (reordering)
-		siblings, err := s.repo.FindByParentID(newParentID)
-		if err != nil {
-			return err
-		}
-
-		if newPosition > oldPosition {
-			// Moving right: shift siblings between old and new position left
-			for _, sibling := range siblings {
-				if sibling.ID().Equals(taskID) {
-					continue
-				}
-				if sibling.Position() > oldPosition && sibling.Position() <= newPosition {
-					err = sibling.Move(sibling.ParentID(), sibling.Position()-1)
-					if err != nil {
-						return err
-					}
-					err = s.repo.Save(sibling)
-					if err != nil {
-						return err
-					}
-				}
+			return tx.deleteTask(taskID)
+		})
+	case DeleteReparent:
+		return s.inTransaction(func(tx *TaskService) error {
+			task, err := tx.repo.FindByID(taskID)
+			if err != nil {
+				return err
 			}
-		} else {
-			// Moving left: shift siblings between new and old position right
-			for _, sibling := range siblings {
-				if sibling.ID().Equals(taskID) {
-					continue
-				}
-				if sibling.Position() >= newPosition && sibling.Position() < oldPosition {
-					err = sibling.Move(sibling.ParentID(), sibling.Position()+1)
-					if err != nil {
-						return err
-					}
-					err = s.repo.Save(sibling)
-					if err != nil {
-						return err
-					}
+			if task.IsRoot() {
+				return NewConstraintViolationError("root-reparent", "cannot reparent the children of the root task")
+			}
+			if !force {
+				// Only the task itself is deleted; its children move up
+				if err := tx.guardDelete([]*Task{task}); err != nil {
+					return err
 				}
 			}
-		}
+			_, err = tx.ungroupTask(taskID)
+			return err
+		})
+	default:
+		return NewValidationError("strategy", "invalid-delete-strategy", fmt.Sprintf("invalid delete strategy %q (must be one of: cascade, reparent)", strategy))
 	}
+}
 
-	// Step 3: Move the task itself
-	err = task.Move(newParentID, newPosition)
-	if err != nil {
-		return err
+// guardDelete rejects deleting the given tasks under DeleteGuardBlock if any of them is incomplete
+// Root Work Items are containers rather than work, so they never block a delete
+func (s *TaskService) guardDelete(tasks []*Task) error {
+	if s.config.DeleteGuard != DeleteGuardBlock {
+		return nil
 	}
 
-	err = s.repo.Save(task)
-	if err != nil {
-		return err
+	var incomplete []*Task
+	for _, task := range tasks {
+		if task.Status() != StatusRootWorkItem && !s.config.CompleteStatuses.Contains(task.Status()) {
+			incomplete = append(incomplete, task)
+		}
 	}
-
-	// Note: In this in-memory implementation, no explicit subtree traversal is needed when moving a task.
-	// Child tasks reference their parent by ID, so when a task's parent or position changes,
-	// its subtree remains attached via these references; only the parent and position are updated.
-
-	return nil
+	if len(incomplete) == 0 {
+		return nil
+	}
+	return NewConstraintViolationErrorWithTasks(
+		"incomplete-delete",
+		fmt.Sprintf("cannot delete %d incomplete task(s) while deletes are guarded; complete them first or force the delete", len(incomplete)),
+		incomplete,
+	)
 }
 
-// DeleteTask deletes a task and adjusts sibling positions
-// If the task has children, it performs cascading deletion
-// If the task is the root, it removes the entire tree
-func (s *TaskService) DeleteTask(taskID TaskID) error {
+// deleteTask implements DeleteTask within a transaction
+func (s *TaskService) deleteTask(taskID TaskID) error {
 	// Retrieve the task to be deleted
 	task, err := s.repo.FindByID(taskID)
 	if err != nil {
 		return err
 	}
 
+	// Refuse to delete more tasks than a single operation may touch
+	if err := s.ensureSubtreeWithinLimit(taskID); err != nil {
+		return err
+	}
+
 	// Check if this is the root task
 	if task.IsRoot() {
 		// Root deletion removes the entire tree
@@ -367,3 +1756,129 @@ func (s *TaskService) deleteEntireTree() error {
 	// Delete the root and all its descendants
 	return s.repo.DeleteSubtree(root.ID())
 }
+
+// Diagnose reports the invariant violations of the stored tasks and the tasks involved,
+// without changing anything
+func (s *TaskService) Diagnose() (TreeDiagnostics, error) {
+	tasks, err := s.repo.FindAll()
+	if err != nil {
+		return TreeDiagnostics{}, err
+	}
+	return DiagnoseTree(tasks, s.config.CompleteStatuses), nil
+}
+
+// RenameStatus moves every task in status from to status to, in one transaction, and
+// returns the number of tasks changed
+// Root Work Item is neither renamed nor assigned, and the rename is rejected if it would
+// leave a complete task with an incomplete child
+func (s *TaskService) RenameStatus(from, to Status) (int, error) {
+	if !from.IsValid() {
+		return 0, NewValidationError("from", "invalid-status", "invalid status value")
+	}
+	if !to.IsValid() {
+		return 0, NewValidationError("to", "invalid-status", "invalid status value")
+	}
+	if from == to {
+		return 0, NewValidationError("to", "same-status", "must differ from the status being renamed")
+	}
+	if from == StatusRootWorkItem || to == StatusRootWorkItem {
+		return 0, NewValidationError("status", "root-work-item-rename", "Root Work Item cannot be renamed or assigned")
+	}
+
+	renamed := 0
+	err := s.inTransaction(func(tx *TaskService) error {
+		var all []*Task
+		if err := tx.repo.ForEach(func(task *Task) error {
+			all = append(all, task)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		// Check completion against the statuses after the rename before changing anything
+		before := make(map[TaskID]Status, len(all))
+		after := make(map[TaskID]Status, len(all))
+		for _, task := range all {
+			before[task.ID()] = task.Status()
+			after[task.ID()] = task.Status()
+			if task.Status() == from {
+				after[task.ID()] = to
+			}
+		}
+		for _, task := range all {
+			parentID := task.ParentID()
+			if parentID == nil {
+				continue
+			}
+			if task.Status() != from && before[*parentID] != from {
+				continue
+			}
+			if s.config.CompleteStatuses.Contains(after[*parentID]) && !s.config.CompleteStatuses.Contains(after[task.ID()]) {
+				return NewConstraintViolationError(
+					"bottom-to-top-completion",
+					fmt.Sprintf("renaming %s to %s would leave a complete task with incomplete child %q", from, to, task.Description()),
+				)
+			}
+		}
+
+		// Tasks that must be broken down cannot be completed by a rename either
+		if s.config.CompleteStatuses.Contains(to) && !s.config.CompleteStatuses.Contains(from) {
+			rules := s.config.validatorConfig()
+			children := make(map[TaskID]int, len(all))
+			for _, task := range all {
+				if task.ParentID() != nil {
+					children[*task.ParentID()]++
+				}
+			}
+			for _, task := range all {
+				if task.Status() != from {
+					continue
+				}
+				if reason, ok := rules.decompositionReason(task); ok && children[task.ID()] < rules.minDecomposedChildren() {
+					return NewConstraintViolationError(
+						"decomposition-required",
+						fmt.Sprintf("renaming %s to %s would complete task %q %s before it is broken down into at least %d", from, to, task.Description(), reason, rules.minDecomposedChildren()),
+					)
+				}
+			}
+		}
+
+		var changed []*Task
+		for _, task := range all {
+			if task.Status() != from {
+				continue
+			}
+			if err := tx.changeStatusAndRecord(task, to); err != nil {
+				return err
+			}
+			changed = append(changed, task)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := tx.repo.SaveAll(changed); err != nil {
+			return err
+		}
+		renamed = len(changed)
+		return nil
+	})
+	return renamed, err
+}
+
+// NormalizePositions renumbers the children of every task to 0..n-1, keeping their order,
+// closing the gaps and duplicates left by earlier writes; returns the number of tasks renumbered
+func (s *TaskService) NormalizePositions() (int, error) {
+	renumbered := 0
+	err := s.inTransaction(func(tx *TaskService) error {
+		// Reading every task records every parent for renumbering
+		tracking := newPositionTrackingRepository(tx.repo)
+		if err := tracking.ForEach(func(*Task) error { return nil }); err != nil {
+			return err
+		}
+
+		var err error
+		renumbered, err = tracking.normalizePositions()
+		return err
+	})
+	return renumbered, err
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -211,10 +212,112 @@ func TestTaskService_CreateChildTask_AutomaticPositionCalculation(t *testing.T)
 	}
 }
 
+
+func TestTaskService_CreateChildTask_Prepend(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{ChildInsertMode: ChildInsertPrepend})
+
+	root, _ := service.CreateRootTask("Root")
+	first, _ := service.CreateChildTask("First", root.ID())
+	second, _ := service.CreateChildTask("Second", root.ID())
+	third, _ := service.CreateChildTask("Third", root.ID())
+
+	// The newest child comes first, and positions stay contiguous
+	rootID := root.ID()
+	children, err := repo.FindByParentID(&rootID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []*Task{third, second, first}
+	if len(children) != len(expected) {
+		t.Fatalf("expected %d children, got %d", len(expected), len(children))
+	}
+	for i, child := range children {
+		if !child.ID().Equals(expected[i].ID()) {
+			t.Errorf("expected %q at index %d, got %q", expected[i].Description(), i, child.Description())
+		}
+		if child.Position() != i {
+			t.Errorf("expected position %d for %q, got %d", i, child.Description(), child.Position())
+		}
+	}
+}
+
+func TestTaskService_CreateChildTaskAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		expected []string
+	}{
+		{"front", 0, []string{"New", "A", "B", "C"}},
+		{"middle", 2, []string{"A", "B", "New", "C"}},
+		{"end", 3, []string{"A", "B", "C", "New"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryTaskRepository()
+			service := NewTaskService(repo)
+
+			// Create parent with 3 children
+			parent, _ := service.CreateRootTask("Parent")
+			for _, description := range []string{"A", "B", "C"} {
+				if _, err := service.CreateChildTask(description, parent.ID()); err != nil {
+					t.Fatalf("failed to create child %s: %v", description, err)
+				}
+			}
+
+			child, err := service.CreateChildTaskAt("New", parent.ID(), tt.position)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if child.Position() != tt.position {
+				t.Errorf("expected new child at position %d, got %d", tt.position, child.Position())
+			}
+
+			// Verify order and contiguous positions
+			children, _ := repo.FindByParentID(&parent.id)
+			if len(children) != len(tt.expected) {
+				t.Fatalf("expected %d children, got %d", len(tt.expected), len(children))
+			}
+			for i, description := range tt.expected {
+				if children[i].Description() != description || children[i].Position() != i {
+					t.Errorf("expected %s at position %d, got %s at position %d", description, i, children[i].Description(), children[i].Position())
+				}
+			}
+		})
+	}
+}
+
+func TestTaskService_CreateChildTaskAt_OutOfRange(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	parent, _ := service.CreateRootTask("Parent")
+	_, _ = service.CreateChildTask("A", parent.ID())
+
+	for _, position := range []int{-1, 2} {
+		_, err := service.CreateChildTaskAt("New", parent.ID(), position)
+		if _, ok := err.(ValidationError); !ok {
+			t.Errorf("expected ValidationError for position %d, got %v", position, err)
+		}
+	}
+
+	// Nothing was created
+	children, _ := repo.FindByParentID(&parent.id)
+	if len(children) != 1 {
+		t.Errorf("expected 1 child, got %d", len(children))
+	}
+}
+
 func TestTaskService_CreateChildTask_NonExistentParent(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
 	service := NewTaskService(repo)
 
+	// A root exists, so the missing parent is reported as such
+	if _, err := service.CreateRootTask("Root"); err != nil {
+		t.Fatalf("failed to create root task: %v", err)
+	}
+
 	// Try to create child with non-existent parent
 	nonExistentParentID := NewTaskID()
 	child, err := service.CreateChildTask("Child task", nonExistentParentID)
@@ -233,6 +336,27 @@ func TestTaskService_CreateChildTask_NonExistentParent(t *testing.T) {
 	}
 }
 
+func TestTaskService_CreateChildTask_EmptyStore(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	child, err := service.CreateChildTask("Child task", NewTaskID())
+	if child != nil {
+		t.Errorf("expected nil task in an empty store, got %v", child)
+	}
+
+	constraintErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T (%v)", err, err)
+	}
+	if constraintErr.Constraint != "no-root" {
+		t.Errorf("expected no-root constraint, got %s", constraintErr.Constraint)
+	}
+	if constraintErr.Message != "no root task exists; create a root first" {
+		t.Errorf("unexpected message %q", constraintErr.Message)
+	}
+}
+
 func TestTaskService_CreateChildTask_EmptyDescription(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
 	service := NewTaskService(repo)
@@ -988,6 +1112,101 @@ func TestTaskService_MoveTask_NegativePosition(t *testing.T) {
 	}
 }
 
+// setupDoneBranch creates root -> a -> b with a and b DONE, plus an incomplete task c under root
+func setupDoneBranch(t *testing.T, service *TaskService) (a, b, c *Task) {
+	t.Helper()
+	root, _ := service.CreateRootTask("Root")
+	a, _ = service.CreateChildTask("A", root.ID())
+	b, _ = service.CreateChildTask("B", a.ID())
+	c, _ = service.CreateChildTask("C", root.ID())
+	if err := service.ChangeTaskStatus(b.ID(), StatusDONE); err != nil {
+		t.Fatalf("failed to complete B: %v", err)
+	}
+	if err := service.ChangeTaskStatus(a.ID(), StatusDONE); err != nil {
+		t.Fatalf("failed to complete A: %v", err)
+	}
+	return a, b, c
+}
+
+func TestTaskService_MoveTask_IncompleteUnderDoneParentRejected(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	a, b, c := setupDoneBranch(t, service)
+
+	err := service.MoveTask(c.ID(), &b.id, 0)
+	cvErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %v", err)
+	}
+	if cvErr.Constraint != "done-parent" {
+		t.Errorf("expected done-parent constraint, got %q", cvErr.Constraint)
+	}
+
+	// Nothing changed
+	moved, _ := repo.FindByID(c.ID())
+	if moved.ParentID().Equals(b.ID()) {
+		t.Error("expected C to stay under the root")
+	}
+	for _, id := range []TaskID{a.ID(), b.ID()} {
+		task, _ := repo.FindByID(id)
+		if task.Status() != StatusDONE {
+			t.Errorf("expected %s to stay DONE, got %v", task.Description(), task.Status())
+		}
+	}
+}
+
+func TestTaskService_MoveTaskAndReconcile_ReopensAncestors(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	a, b, c := setupDoneBranch(t, service)
+
+	reopened, err := service.MoveTaskAndReconcile(c.ID(), &b.id, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// B and A are reopened, nearest first
+	if len(reopened) != 2 || !reopened[0].ID().Equals(b.ID()) || !reopened[1].ID().Equals(a.ID()) {
+		t.Fatalf("expected [B A] to be reopened, got %d tasks", len(reopened))
+	}
+	for _, id := range []TaskID{a.ID(), b.ID()} {
+		task, _ := repo.FindByID(id)
+		if task.Status() != StatusInProgress {
+			t.Errorf("expected %s to be In Progress, got %v", task.Description(), task.Status())
+		}
+	}
+
+	moved, _ := repo.FindByID(c.ID())
+	if !moved.ParentID().Equals(b.ID()) {
+		t.Errorf("expected C under B, got parent %v", moved.ParentID())
+	}
+
+	all, _ := repo.FindAll()
+	if violations := CheckTreeInvariants(all, CompleteStatuses{}); len(violations) != 0 {
+		t.Errorf("expected a valid tree, got %v", violations)
+	}
+}
+
+func TestTaskService_MoveTaskAndReconcile_NothingToReopen(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	a, b, _ := setupDoneBranch(t, service)
+
+	// A DONE task may move under a DONE parent without reopening anything
+	root, _ := repo.FindRoot()
+	reopened, err := service.MoveTaskAndReconcile(b.ID(), &root.id, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(reopened) != 0 {
+		t.Errorf("expected nothing reopened, got %d tasks", len(reopened))
+	}
+	retrieved, _ := repo.FindByID(a.ID())
+	if retrieved.Status() != StatusDONE {
+		t.Errorf("expected A to stay DONE, got %v", retrieved.Status())
+	}
+}
+
 func TestTaskService_MoveTask_ComplexReordering(t *testing.T) {
 	repo := NewInMemoryTaskRepository()
 	service := NewTaskService(repo)
@@ -1351,3 +1570,1877 @@ func TestTaskService_DeleteTask_SubtreeWithSiblingAdjustment(t *testing.T) {
 		t.Errorf("expected child3 to still exist, got error: %v", err)
 	}
 }
+
+func TestTaskService_MoveTasks_Success(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> source -> a, b, c
+	//                   -> target -> x
+	root, _ := service.CreateRootTask("Root")
+	source, _ := service.CreateChildTask("Source", root.ID())
+	target, _ := service.CreateChildTask("Target", root.ID())
+	a, _ := service.CreateChildTask("A", source.ID())
+	b, _ := service.CreateChildTask("B", source.ID())
+	c, _ := service.CreateChildTask("C", source.ID())
+	x, _ := service.CreateChildTask("X", target.ID())
+
+	// Move c and a (in that order) under target, starting at position 0
+	err := service.MoveTasks([]TaskID{c.ID(), a.ID()}, target.ID(), 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Verify target children are c, a, x in order
+	targetChildren, _ := repo.FindByParentID(&target.id)
+	expected := []TaskID{c.ID(), a.ID(), x.ID()}
+	if len(targetChildren) != len(expected) {
+		t.Fatalf("expected %d children under target, got %d", len(expected), len(targetChildren))
+	}
+	for i, id := range expected {
+		if !targetChildren[i].ID().Equals(id) {
+			t.Errorf("expected %v at position %d, got %v", id, i, targetChildren[i].ID())
+		}
+		if targetChildren[i].Position() != i {
+			t.Errorf("expected position %d, got %d", i, targetChildren[i].Position())
+		}
+	}
+
+	// Verify the gap under source was closed
+	sourceChildren, _ := repo.FindByParentID(&source.id)
+	if len(sourceChildren) != 1 {
+		t.Fatalf("expected 1 child under source, got %d", len(sourceChildren))
+	}
+	if !sourceChildren[0].ID().Equals(b.ID()) || sourceChildren[0].Position() != 0 {
+		t.Errorf("expected b at position 0 under source, got %v at %d", sourceChildren[0].ID(), sourceChildren[0].Position())
+	}
+}
+
+func TestTaskService_MoveTasks_PreventCycle(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> parent -> child
+	//                   -> other
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	other, _ := service.CreateChildTask("Other", root.ID())
+	child, _ := service.CreateChildTask("Child", parent.ID())
+
+	// Try to move other and parent under parent's own child (creates cycle)
+	err := service.MoveTasks([]TaskID{other.ID(), parent.ID()}, child.ID(), 0)
+	if err == nil {
+		t.Fatal("expected error when creating cycle, got nil")
+	}
+
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Errorf("expected ConstraintViolationError, got %T", err)
+	}
+
+	// Verify nothing was moved, including the valid part of the selection
+	retrievedOther, _ := repo.FindByID(other.ID())
+	if !retrievedOther.ParentID().Equals(root.ID()) || retrievedOther.Position() != 1 {
+		t.Errorf("expected other still under root at position 1 after failed move")
+	}
+	retrievedParent, _ := repo.FindByID(parent.ID())
+	if !retrievedParent.ParentID().Equals(root.ID()) {
+		t.Errorf("expected parent still under root after failed move")
+	}
+}
+
+func TestTaskService_MoveTasks_OverlappingSelection(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> parent -> child
+	//                   -> target
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	target, _ := service.CreateChildTask("Target", root.ID())
+	child, _ := service.CreateChildTask("Child", parent.ID())
+
+	// Try to move an ancestor together with its descendant
+	err := service.MoveTasks([]TaskID{child.ID(), parent.ID()}, target.ID(), 0)
+	if err == nil {
+		t.Fatal("expected error for overlapping selection, got nil")
+	}
+
+	cvErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if cvErr.Constraint != "overlapping-selection" {
+		t.Errorf("expected overlapping-selection constraint, got %q", cvErr.Constraint)
+	}
+}
+
+func TestTaskService_NudgeTasks(t *testing.T) {
+	// setup builds root -> A, B, C, D, E
+	setup := func() (*InMemoryTaskRepository, *TaskService, TaskID, []*Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskService(repo)
+		root, _ := service.CreateRootTask("Root")
+		var children []*Task
+		for _, description := range []string{"A", "B", "C", "D", "E"} {
+			child, _ := service.CreateChildTask(description, root.ID())
+			children = append(children, child)
+		}
+		return repo, service, root.ID(), children
+	}
+
+	orderOf := func(repo *InMemoryTaskRepository, parentID TaskID) string {
+		children, _ := repo.FindByParentID(&parentID)
+		order := ""
+		for i, child := range children {
+			if child.Position() != i {
+				t.Errorf("expected %s at position %d, got %d", child.Description(), i, child.Position())
+			}
+			order += child.Description()
+		}
+		return order
+	}
+
+	tests := []struct {
+		name      string
+		selection []int // indexes into A..E
+		direction NudgeDirection
+		want      string
+	}{
+		{"two adjacent up", []int{2, 3}, NudgeUp, "ACDBE"},
+		{"two adjacent down", []int{1, 2}, NudgeDown, "ADBCE"},
+		{"two apart up", []int{1, 3}, NudgeUp, "BADCE"},
+		{"two apart down", []int{3, 1}, NudgeDown, "ACBED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, service, rootID, children := setup()
+			var ids []TaskID
+			for _, i := range tt.selection {
+				ids = append(ids, children[i].ID())
+			}
+
+			if err := service.NudgeTasks(ids, tt.direction); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := orderOf(repo, rootID); got != tt.want {
+				t.Errorf("expected order %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		repo, service, rootID, children := setup()
+
+		for _, tc := range []struct {
+			ids       []TaskID
+			direction NudgeDirection
+		}{
+			{[]TaskID{children[0].ID(), children[2].ID()}, NudgeUp},
+			{[]TaskID{children[2].ID(), children[4].ID()}, NudgeDown},
+		} {
+			err := service.NudgeTasks(tc.ids, tc.direction)
+			cvErr, ok := err.(ConstraintViolationError)
+			if !ok {
+				t.Fatalf("expected ConstraintViolationError, got %T", err)
+			}
+			if cvErr.Constraint != "nudge-range" {
+				t.Errorf("expected nudge-range constraint, got %q", cvErr.Constraint)
+			}
+		}
+
+		// Nothing moved
+		if got := orderOf(repo, rootID); got != "ABCDE" {
+			t.Errorf("expected order ABCDE, got %s", got)
+		}
+	})
+
+	t.Run("different parents", func(t *testing.T) {
+		_, service, _, children := setup()
+		nested, _ := service.CreateChildTask("Nested", children[0].ID())
+
+		err := service.NudgeTasks([]TaskID{children[1].ID(), nested.ID()}, NudgeUp)
+		cvErr, ok := err.(ConstraintViolationError)
+		if !ok {
+			t.Fatalf("expected ConstraintViolationError, got %T", err)
+		}
+		if cvErr.Constraint != "shared-parent" {
+			t.Errorf("expected shared-parent constraint, got %q", cvErr.Constraint)
+		}
+	})
+}
+
+func TestTaskService_TrimDescriptions_Disabled(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Padded descriptions are stored as-is by default
+	root, err := service.CreateRootTask("  Root  ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if root.Description() != "  Root  " {
+		t.Errorf("expected description to be preserved, got %q", root.Description())
+	}
+
+	updated, err := service.UpdateTaskDescription(root.ID(), "\tUpdated\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Description() != "\tUpdated\n" {
+		t.Errorf("expected description to be preserved, got %q", updated.Description())
+	}
+}
+
+func TestTaskService_TrimDescriptions_Enabled(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{TrimDescriptions: true})
+
+	// Padded-but-nonempty descriptions are accepted and stored trimmed
+	root, err := service.CreateRootTask("  Root  ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if root.Description() != "Root" {
+		t.Errorf("expected trimmed description, got %q", root.Description())
+	}
+
+	child, err := service.CreateChildTask(" Child ", root.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if child.Description() != "Child" {
+		t.Errorf("expected trimmed description, got %q", child.Description())
+	}
+
+	updated, err := service.UpdateTaskDescription(child.ID(), "\tUpdated\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Description() != "Updated" {
+		t.Errorf("expected trimmed description, got %q", updated.Description())
+	}
+
+	// Whitespace-only descriptions are still rejected
+	if _, err := service.UpdateTaskDescription(child.ID(), "   "); err == nil {
+		t.Error("expected error for whitespace-only description, got nil")
+	}
+}
+
+func TestTaskService_CollapseWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   TaskServiceConfig
+		input    string
+		expected string
+	}{
+		{"disabled preserves runs", TaskServiceConfig{}, "foo\t\tbar", "foo\t\tbar"},
+		{"enabled collapses runs", TaskServiceConfig{CollapseWhitespace: true}, "foo\t\tbar", "foo bar"},
+		{"enabled collapses every run", TaskServiceConfig{CollapseWhitespace: true}, "a  b\n\nc \t d", "a b c d"},
+		{"enabled keeps the edges", TaskServiceConfig{CollapseWhitespace: true}, "  foo  bar\t", "  foo bar\t"},
+		{"enabled with trim", TaskServiceConfig{CollapseWhitespace: true, TrimDescriptions: true}, "  foo  bar\t", "foo bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryTaskRepository()
+			service := NewTaskServiceWithConfig(repo, tt.config)
+
+			// Both creates and updates are canonicalized
+			root, err := service.CreateRootTask(tt.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if root.Description() != tt.expected {
+				t.Errorf("expected created description %q, got %q", tt.expected, root.Description())
+			}
+
+			updated, err := service.UpdateTaskDescription(root.ID(), tt.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if updated.Description() != tt.expected {
+				t.Errorf("expected updated description %q, got %q", tt.expected, updated.Description())
+			}
+		})
+	}
+
+	// Whitespace-only descriptions are still rejected
+	service := NewTaskServiceWithConfig(NewInMemoryTaskRepository(), TaskServiceConfig{CollapseWhitespace: true})
+	if _, err := service.CreateRootTask(" \t\t "); err == nil {
+		t.Error("expected error for whitespace-only description, got nil")
+	}
+}
+
+func TestTaskService_CreateChildTask_DoneParentRejected(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	if err := service.ChangeTaskStatus(parent.ID(), StatusDONE); err != nil {
+		t.Fatalf("failed to mark parent DONE: %v", err)
+	}
+
+	_, err := service.CreateChildTask("Child", parent.ID())
+	if err == nil {
+		t.Fatal("expected error when creating child under DONE parent, got nil")
+	}
+
+	cvErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if cvErr.Constraint != "done-parent" {
+		t.Errorf("expected done-parent constraint, got %q", cvErr.Constraint)
+	}
+
+	// Verify no child was created and parent is still DONE
+	children, _ := repo.FindByParentID(&parent.id)
+	if len(children) != 0 {
+		t.Errorf("expected no children, got %d", len(children))
+	}
+	retrieved, _ := repo.FindByID(parent.ID())
+	if retrieved.Status() != StatusDONE {
+		t.Errorf("expected parent to remain DONE, got %v", retrieved.Status())
+	}
+}
+
+func TestTaskService_CreateChildTask_DoneParentReopened(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{DoneParentPolicy: DoneParentReopen})
+
+	// Create tree: root -> grandparent (DONE) -> parent (DONE)
+	root, _ := service.CreateRootTask("Root")
+	grandparent, _ := service.CreateChildTask("Grandparent", root.ID())
+	parent, _ := service.CreateChildTask("Parent", grandparent.ID())
+	_ = service.ChangeTaskStatus(parent.ID(), StatusDONE)
+	_ = service.ChangeTaskStatus(grandparent.ID(), StatusDONE)
+
+	child, err := service.CreateChildTask("Child", parent.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if child.Status() != StatusTODO {
+		t.Errorf("expected child status TODO, got %v", child.Status())
+	}
+
+	// Both DONE ancestors are reopened so completion stays bottom-to-top
+	for _, id := range []TaskID{parent.ID(), grandparent.ID()} {
+		retrieved, _ := repo.FindByID(id)
+		if retrieved.Status() != StatusInProgress {
+			t.Errorf("expected %s to be reopened to In Progress, got %v", retrieved.Description(), retrieved.Status())
+		}
+		if retrieved.CompletedAt() != nil {
+			t.Errorf("expected %s CompletedAt to be cleared", retrieved.Description())
+		}
+	}
+
+	// The root was never DONE and is untouched
+	retrievedRoot, _ := repo.FindByID(root.ID())
+	if retrievedRoot.Status() != StatusRootWorkItem {
+		t.Errorf("expected root status unchanged, got %v", retrievedRoot.Status())
+	}
+}
+
+func TestTaskService_ReopenStatus(t *testing.T) {
+	reopenTo := StatusBlocked
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{DoneParentPolicy: DoneParentReopen, ReopenStatus: &reopenTo})
+
+	// Create tree: root -> parent (DONE), root -> other (DONE) -> leaf (DONE)
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	other, _ := service.CreateChildTask("Other", root.ID())
+	leaf, _ := service.CreateChildTask("Leaf", other.ID())
+	_ = service.ChangeTaskStatus(parent.ID(), StatusDONE)
+	_ = service.ChangeTaskStatus(leaf.ID(), StatusDONE)
+	_ = service.ChangeTaskStatus(other.ID(), StatusDONE)
+
+	// Creating a child under a DONE parent flips the parent to the configured reopen status
+	if _, err := service.CreateChildTask("Child", parent.ID()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	retrieved, _ := repo.FindByID(parent.ID())
+	if retrieved.Status() != StatusBlocked {
+		t.Errorf("expected parent to be reopened to Blocked, got %v", retrieved.Status())
+	}
+	if retrieved.CompletedAt() != nil {
+		t.Error("expected parent CompletedAt to be cleared")
+	}
+
+	// So does a reconciling move of an incomplete task under a DONE task
+	reopened, err := service.MoveTaskAndReconcile(parent.ID(), &other.id, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(reopened) != 1 || reopened[0].Status() != StatusBlocked || reopened[0].CompletedAt() != nil {
+		t.Errorf("expected Other to be reopened to Blocked with CompletedAt cleared, got %v", reopened)
+	}
+
+	// A complete reopen status would leave the task DONE, so the default is used instead
+	done := StatusDONE
+	config := TaskServiceConfig{ReopenStatus: &done}
+	if config.reopenStatus() != StatusInProgress {
+		t.Errorf("expected a complete reopen status to fall back to In Progress, got %v", config.reopenStatus())
+	}
+}
+
+func TestTaskService_CompleteStatuses(t *testing.T) {
+	complete, err := NewCompleteStatuses(StatusDONE, StatusBlocked)
+	if err != nil {
+		t.Fatalf("failed to configure complete statuses: %v", err)
+	}
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{CompleteStatuses: complete})
+	other := NewTaskService(repo)
+
+	// Create tree: root -> parent -> child
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	child, _ := service.CreateChildTask("Child", parent.ID())
+
+	// Blocked counts as complete for this service only, so the incomplete child blocks it here
+	if err := service.ChangeTaskStatus(parent.ID(), StatusBlocked); err == nil {
+		t.Error("expected completing a parent with a TODO child to be rejected")
+	}
+	if err := other.ChangeTaskStatus(parent.ID(), StatusBlocked); err != nil {
+		t.Errorf("expected Blocked to be incomplete for a default service, got %v", err)
+	}
+
+	// A Blocked child lets its parent be completed, and completing sets CompletedAt
+	if err := service.ChangeTaskStatus(child.ID(), StatusBlocked); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.ChangeTaskStatus(parent.ID(), StatusDONE); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	retrieved, _ := repo.FindByID(child.ID())
+	if retrieved.CompletedAt() == nil {
+		t.Error("expected a child set to a configured complete status to have CompletedAt")
+	}
+
+	// With In Progress complete, reopening falls back to TODO
+	complete, _ = NewCompleteStatuses(StatusDONE, StatusInProgress)
+	if status := (TaskServiceConfig{CompleteStatuses: complete}).reopenStatus(); status != StatusTODO {
+		t.Errorf("expected reopening to fall back to TODO, got %v", status)
+	}
+}
+
+func TestTaskService_PromoteToRoot(t *testing.T) {
+	// setup builds root -> A -> A1, A2 and optionally root -> B
+	setup := func(withB bool) (*InMemoryTaskRepository, *TaskService, *Task, *Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskService(repo)
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		service.CreateChildTask("A1", a.ID())
+		service.CreateChildTask("A2", a.ID())
+		if withB {
+			service.CreateChildTask("B", root.ID())
+		}
+		return repo, service, root, a
+	}
+
+	t.Run("rejected while a root exists", func(t *testing.T) {
+		_, service, _, a := setup(false)
+		_, err := service.PromoteToRoot(a.ID(), false)
+		constraintErr, ok := err.(ConstraintViolationError)
+		if !ok || constraintErr.Constraint != "single-root" {
+			t.Fatalf("expected single-root violation, got %v", err)
+		}
+	})
+
+	t.Run("replacing a root with other branches is rejected", func(t *testing.T) {
+		repo, service, root, a := setup(true)
+		_, err := service.PromoteToRoot(a.ID(), true)
+		constraintErr, ok := err.(ConstraintViolationError)
+		if !ok || constraintErr.Constraint != "root-has-branches" {
+			t.Fatalf("expected root-has-branches violation, got %v", err)
+		}
+		// Nothing changed
+		if _, err := repo.FindByID(root.ID()); err != nil {
+			t.Errorf("expected the root to remain, got %v", err)
+		}
+	})
+
+	t.Run("replacing with a grandchild is rejected", func(t *testing.T) {
+		repo, service, _, a := setup(false)
+		children, _ := repo.FindByParentID(&a.id)
+		_, err := service.PromoteToRoot(children[0].ID(), true)
+		if _, ok := err.(ConstraintViolationError); !ok {
+			t.Fatalf("expected a constraint violation, got %v", err)
+		}
+	})
+
+	t.Run("replacing the root", func(t *testing.T) {
+		repo, service, root, a := setup(false)
+		_ = service.ChangeTaskStatus(a.ID(), StatusInProgress)
+
+		promoted, err := service.PromoteToRoot(a.ID(), true)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !promoted.IsRoot() || promoted.Status() != StatusRootWorkItem || promoted.Position() != 0 {
+			t.Errorf("expected a root work item at position 0, got parent %v status %v position %d",
+				promoted.ParentID(), promoted.Status(), promoted.Position())
+		}
+
+		// Exactly one root results, the old one is gone and the subtree is intact
+		roots, _ := repo.FindByParentID(nil)
+		if len(roots) != 1 || roots[0].ID() != a.ID() {
+			t.Errorf("expected A to be the only root, got %d roots", len(roots))
+		}
+		if _, err := repo.FindByID(root.ID()); err == nil {
+			t.Error("expected the old root to be deleted")
+		}
+		if children, _ := repo.FindByParentID(&a.id); len(children) != 2 {
+			t.Errorf("expected A to keep its 2 children, got %d", len(children))
+		}
+	})
+
+	t.Run("promoting the root is a no-op", func(t *testing.T) {
+		_, service, root, _ := setup(false)
+		promoted, err := service.PromoteToRoot(root.ID(), false)
+		if err != nil || promoted.ID() != root.ID() {
+			t.Fatalf("expected the root back unchanged, got %v, %v", promoted, err)
+		}
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		_, service, _, _ := setup(false)
+		if _, err := service.PromoteToRoot(NewTaskID(), true); err == nil {
+			t.Error("expected an error for an unknown task")
+		}
+	})
+}
+
+func TestTaskService_MaxTotalTasks(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{MaxTotalTasks: 3})
+
+	// Fill the store to the limit
+	root, err := service.CreateRootTask("Root")
+	if err != nil {
+		t.Fatalf("failed to create root: %v", err)
+	}
+	if _, err := service.CreateChildTask("Child 1", root.ID()); err != nil {
+		t.Fatalf("failed to create child 1: %v", err)
+	}
+	child2, err := service.CreateChildTask("Child 2", root.ID())
+	if err != nil {
+		t.Fatalf("failed to create child 2: %v", err)
+	}
+
+	// The next create fails
+	_, err = service.CreateChildTask("Child 3", root.ID())
+	constraintErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if constraintErr.Constraint != "max-total-tasks" {
+		t.Errorf("expected max-total-tasks constraint, got %s", constraintErr.Constraint)
+	}
+
+	// Deleting frees capacity
+	if err := service.DeleteTask(child2.ID()); err != nil {
+		t.Fatalf("failed to delete child 2: %v", err)
+	}
+	if _, err := service.CreateChildTask("Child 3", root.ID()); err != nil {
+		t.Errorf("expected create to succeed after delete, got %v", err)
+	}
+}
+
+func TestTaskService_MaxTotalTasks_ConcurrentRootCreates(t *testing.T) {
+	repo := &stagingCountingRepository{InMemoryTaskRepository: NewInMemoryTaskRepository()}
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{MaxTotalTasks: 1})
+
+	// Concurrent creates check and write in one transaction each, so only one gets in
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.CreateRootTask("Root"); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	count, _ := repo.Count()
+	if created != 1 || count != 1 {
+		t.Errorf("expected one root to be created, got %d created and %d stored", created, count)
+	}
+	if repo.transactions != 20 {
+		t.Errorf("expected every create to run in a transaction, got %d transactions", repo.transactions)
+	}
+}
+
+func TestTaskService_MaxTotalTasks_ImportTree(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{MaxTotalTasks: 2})
+
+	root := TreeImportNode{
+		Description: "Root",
+		Children:    []TreeImportNode{{Description: "A"}, {Description: "B"}},
+	}
+
+	_, err := service.ImportTree(root)
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+
+	count, _ := repo.Count()
+	if count != 0 {
+		t.Errorf("expected no tasks to be created, got %d", count)
+	}
+}
+
+func TestTaskService_ImportTasks_Merge(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root, _ := service.CreateRootTask("Root")
+	existing, _ := service.CreateChildTask("Existing", root.ID())
+
+	// Import a second child and an updated copy of the existing one
+	added, _ := NewTask("Added", &root.id, 1)
+	updated := ReconstructTask(TaskFields{ID: existing.ID(), Description: "Renamed", Status: existing.Status(), ParentID: existing.ParentID(), CreatedAt: existing.CreatedAt(), UpdatedAt: existing.UpdatedAt()})
+
+	if err := service.ImportTasks([]*Task{added, updated}, ImportModeMerge); err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+
+	all, _ := repo.FindAll()
+	if len(all) != 3 {
+		t.Errorf("expected 3 tasks after merge, got %d", len(all))
+	}
+	retrieved, _ := repo.FindByID(existing.ID())
+	if retrieved.Description() != "Renamed" {
+		t.Errorf("expected merged task to be overwritten, got %q", retrieved.Description())
+	}
+}
+
+func TestTaskService_ImportTasks_MergeSecondRootRejected(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	_, _ = service.CreateRootTask("Root")
+	otherRoot, _ := NewTask("Other root", nil, 0)
+
+	err := service.ImportTasks([]*Task{otherRoot}, ImportModeMerge)
+	constraintErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if constraintErr.Constraint != "single-root" {
+		t.Errorf("expected single-root constraint, got %s", constraintErr.Constraint)
+	}
+}
+
+func TestTaskService_GroupTasks_ThreeSiblings(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> a, b, c, d, e
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+	d, _ := service.CreateChildTask("D", root.ID())
+	e, _ := service.CreateChildTask("E", root.ID())
+
+	// Group d, b, c (in that order) into a new task at position 1
+	group, err := service.GroupTasks([]TaskID{d.ID(), b.ID(), c.ID()}, "Group", root.ID(), 1)
+	if err != nil {
+		t.Fatalf("GroupTasks failed: %v", err)
+	}
+
+	// Root now has: a, group, e
+	rootChildren, _ := repo.FindByParentID(&root.id)
+	expectedRoot := []TaskID{a.ID(), group.ID(), e.ID()}
+	if len(rootChildren) != len(expectedRoot) {
+		t.Fatalf("expected %d root children, got %d", len(expectedRoot), len(rootChildren))
+	}
+	for i, id := range expectedRoot {
+		if !rootChildren[i].ID().Equals(id) || rootChildren[i].Position() != i {
+			t.Errorf("expected %s at position %d, got %s at %d", id, i, rootChildren[i].Description(), rootChildren[i].Position())
+		}
+	}
+
+	// Group holds d, b, c in the requested order
+	groupChildren, _ := repo.FindByParentID(&group.id)
+	expectedGroup := []TaskID{d.ID(), b.ID(), c.ID()}
+	if len(groupChildren) != len(expectedGroup) {
+		t.Fatalf("expected %d group children, got %d", len(expectedGroup), len(groupChildren))
+	}
+	for i, id := range expectedGroup {
+		if !groupChildren[i].ID().Equals(id) || groupChildren[i].Position() != i {
+			t.Errorf("expected %s at position %d, got %s at %d", id, i, groupChildren[i].Description(), groupChildren[i].Position())
+		}
+	}
+
+	if violations := CheckTreeInvariants(mustFindAll(t, repo), CompleteStatuses{}); len(violations) != 0 {
+		t.Errorf("expected a valid tree, got %v", violations)
+	}
+}
+
+func TestTaskService_GroupTasks_PreventCycle(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> a -> a1
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	a1, _ := service.CreateChildTask("A1", a.ID())
+
+	// Grouping a under its own descendant would create a cycle
+	_, err := service.GroupTasks([]TaskID{a.ID()}, "Group", a1.ID(), 0)
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+
+	// Nothing was created or moved
+	all := mustFindAll(t, repo)
+	if len(all) != 3 {
+		t.Errorf("expected 3 tasks, got %d", len(all))
+	}
+	retrieved, _ := repo.FindByID(a.ID())
+	if !retrieved.ParentID().Equals(root.ID()) {
+		t.Error("expected a to remain under root")
+	}
+}
+
+// mustFindAll returns every task in the repository or fails the test
+func mustFindAll(t *testing.T, repo TaskRepository) []*Task {
+	t.Helper()
+	all, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	return all
+}
+
+func TestTaskService_UngroupTask(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> a, b (-> b1, b2), c
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+	b1, _ := service.CreateChildTask("B1", b.ID())
+	b2, _ := service.CreateChildTask("B2", b.ID())
+
+	promoted, err := service.UngroupTask(b.ID())
+	if err != nil {
+		t.Fatalf("UngroupTask failed: %v", err)
+	}
+	if len(promoted) != 2 {
+		t.Errorf("expected 2 promoted children, got %d", len(promoted))
+	}
+
+	// Root now has: a, b1, b2, c
+	children, _ := repo.FindByParentID(&root.id)
+	expected := []TaskID{a.ID(), b1.ID(), b2.ID(), c.ID()}
+	if len(children) != len(expected) {
+		t.Fatalf("expected %d root children, got %d", len(expected), len(children))
+	}
+	for i, id := range expected {
+		if !children[i].ID().Equals(id) || children[i].Position() != i {
+			t.Errorf("expected %s at position %d, got %s at %d", id, i, children[i].Description(), children[i].Position())
+		}
+	}
+
+	// The ungrouped task is gone
+	if _, err := repo.FindByID(b.ID()); err == nil {
+		t.Error("expected ungrouped task to be deleted")
+	}
+
+	if violations := CheckTreeInvariants(mustFindAll(t, repo), CompleteStatuses{}); len(violations) != 0 {
+		t.Errorf("expected a valid tree, got %v", violations)
+	}
+}
+
+func TestTaskService_UngroupTask_RootRejected(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root, _ := service.CreateRootTask("Root")
+	_, _ = service.CreateChildTask("A", root.ID())
+
+	_, err := service.UngroupTask(root.ID())
+	constraintErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if constraintErr.Constraint != "root-ungroup" {
+		t.Errorf("expected root-ungroup constraint, got %s", constraintErr.Constraint)
+	}
+}
+
+func TestTaskService_MoveTask_RecordsMoveHistory(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> a, b, c
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	c, _ := service.CreateChildTask("C", root.ID())
+
+	// Move a to the end, then under b
+	if err := service.MoveTask(a.ID(), &root.id, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.MoveTask(a.ID(), &b.id, 0); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	retrieved, _ := repo.FindByID(a.ID())
+	history := retrieved.MoveHistory()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+
+	first := history[0]
+	if !first.FromParentID.Equals(root.ID()) || !first.ToParentID.Equals(root.ID()) {
+		t.Errorf("expected first move within root, got %v -> %v", first.FromParentID, first.ToParentID)
+	}
+	if first.FromPosition != 0 || first.ToPosition != 2 {
+		t.Errorf("expected first move 0 -> 2, got %d -> %d", first.FromPosition, first.ToPosition)
+	}
+
+	second := history[1]
+	if !second.FromParentID.Equals(root.ID()) || !second.ToParentID.Equals(b.ID()) {
+		t.Errorf("expected second move from root to B, got %v -> %v", second.FromParentID, second.ToParentID)
+	}
+	if second.FromPosition != 2 || second.ToPosition != 0 {
+		t.Errorf("expected second move 2 -> 0, got %d -> %d", second.FromPosition, second.ToPosition)
+	}
+	if second.At.Before(first.At) {
+		t.Errorf("expected history in chronological order")
+	}
+
+	// Siblings shifted to make room are not recorded
+	for _, sibling := range []*Task{b, c} {
+		retrieved, _ := repo.FindByID(sibling.ID())
+		if len(retrieved.MoveHistory()) != 0 {
+			t.Errorf("expected no history for shifted sibling %s, got %d entries", retrieved.Description(), len(retrieved.MoveHistory()))
+		}
+	}
+}
+
+func TestTaskService_MoveTask_MoveHistoryLimit(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{MoveHistoryLimit: 2})
+
+	// Create tree: root -> a, b
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	_, _ = service.CreateChildTask("B", root.ID())
+
+	// Swap a back and forth three times
+	for _, position := range []int{1, 0, 1} {
+		if err := service.MoveTask(a.ID(), &root.id, position); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// Only the two most recent moves are kept
+	retrieved, _ := repo.FindByID(a.ID())
+	history := retrieved.MoveHistory()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+	if history[0].ToPosition != 0 || history[1].ToPosition != 1 {
+		t.Errorf("expected the last two moves (-> 0, -> 1), got -> %d, -> %d", history[0].ToPosition, history[1].ToPosition)
+	}
+}
+
+func TestTaskService_ChangeTaskStatus_RecordsStatusHistory(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> parent -> leaf
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	leaf, _ := service.CreateChildTask("Leaf", parent.ID())
+
+	// Creation is not a status change
+	if history := leaf.StatusHistory(); len(history) != 0 {
+		t.Fatalf("expected no history for a new task, got %d entries", len(history))
+	}
+
+	// TODO -> In Progress -> DONE, then reopened; setting the same status again records nothing
+	for _, status := range []Status{StatusInProgress, StatusDONE, StatusDONE, StatusInProgress} {
+		if err := service.ChangeTaskStatus(leaf.ID(), status); err != nil {
+			t.Fatalf("expected no error changing to %s, got %v", status, err)
+		}
+	}
+
+	retrieved, _ := repo.FindByID(leaf.ID())
+	history := retrieved.StatusHistory()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 status changes, got %d", len(history))
+	}
+	expected := []struct{ from, to Status }{
+		{StatusTODO, StatusInProgress},
+		{StatusInProgress, StatusDONE},
+		{StatusDONE, StatusInProgress},
+	}
+	for i, want := range expected {
+		if history[i].From != want.from || history[i].To != want.to {
+			t.Errorf("entry %d: expected %s -> %s, got %s -> %s", i, want.from, want.to, history[i].From, history[i].To)
+		}
+		if history[i].At.IsZero() || (i > 0 && history[i].At.Before(history[i-1].At)) {
+			t.Errorf("entry %d: expected a timestamp in order, got %v", i, history[i].At)
+		}
+	}
+
+	// Reopening the DONE parent as a side effect of resetting its child is not recorded for it
+	if err := service.ChangeTaskStatus(leaf.ID(), StatusDONE); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.ChangeTaskStatus(parent.ID(), StatusDONE); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, _, err := service.ResetSubtree(leaf.ID()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	retrievedParent, _ := repo.FindByID(parent.ID())
+	if retrievedParent.Status() != StatusInProgress || len(retrievedParent.StatusHistory()) != 1 {
+		t.Errorf("expected only the parent's explicit change to be recorded, got %d entries", len(retrievedParent.StatusHistory()))
+	}
+	retrieved, _ = repo.FindByID(leaf.ID())
+	if history := retrieved.StatusHistory(); len(history) != 5 || history[4].To != StatusTODO {
+		t.Errorf("expected the reset to be recorded for the leaf, got %d entries", len(history))
+	}
+}
+
+func TestTaskService_ChangeTaskStatus_StatusHistoryLimit(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{StatusHistoryLimit: 2})
+
+	root, _ := service.CreateRootTask("Root")
+	leaf, _ := service.CreateChildTask("Leaf", root.ID())
+
+	for _, status := range []Status{StatusInProgress, StatusBlocked, StatusDONE} {
+		if err := service.ChangeTaskStatus(leaf.ID(), status); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// Only the two most recent changes are kept
+	retrieved, _ := repo.FindByID(leaf.ID())
+	history := retrieved.StatusHistory()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+	if history[0].To != StatusBlocked || history[1].To != StatusDONE {
+		t.Errorf("expected the last two changes (-> Blocked, -> DONE), got -> %s, -> %s", history[0].To, history[1].To)
+	}
+}
+
+func TestTaskService_ToggleTaskStatus_Leaf(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root, _ := service.CreateRootTask("Root")
+	leaf, _ := service.CreateChildTask("Leaf", root.ID())
+
+	// TODO -> DONE
+	toggled, err := service.ToggleTaskStatus(leaf.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if toggled.Status() != StatusDONE {
+		t.Errorf("expected DONE, got %v", toggled.Status())
+	}
+
+	// DONE -> TODO
+	toggled, err = service.ToggleTaskStatus(leaf.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if toggled.Status() != StatusTODO {
+		t.Errorf("expected TODO, got %v", toggled.Status())
+	}
+}
+
+func TestTaskService_ToggleTaskStatus_ParentWithIncompleteChildren(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	_, _ = service.CreateChildTask("Child", parent.ID())
+
+	_, err := service.ToggleTaskStatus(parent.ID())
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Fatalf("expected ConstraintViolationError, got %v", err)
+	}
+
+	retrieved, _ := repo.FindByID(parent.ID())
+	if retrieved.Status() != StatusTODO {
+		t.Errorf("expected parent to stay TODO, got %v", retrieved.Status())
+	}
+}
+
+func TestTaskService_StatusOptions(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	child, _ := service.CreateChildTask("Child", parent.ID())
+
+	options, err := service.StatusOptions(parent.ID())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, status := range options {
+		if status == StatusDONE || status == StatusTODO {
+			t.Errorf("expected options without DONE and the current TODO, got %v", options)
+		}
+	}
+
+	// Once the child is DONE, the parent may be DONE too
+	if err := service.ChangeTaskStatus(child.ID(), StatusDONE); err != nil {
+		t.Fatalf("failed to complete child: %v", err)
+	}
+	options, _ = service.StatusOptions(parent.ID())
+	found := false
+	for _, status := range options {
+		found = found || status == StatusDONE
+	}
+	if !found {
+		t.Errorf("expected DONE among the options, got %v", options)
+	}
+}
+
+func TestTaskService_ToggleTaskStatus_ConfiguredPair(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{ToggleStatuses: []Status{StatusTODO, StatusInProgress}})
+
+	root, _ := service.CreateRootTask("Root")
+	leaf, _ := service.CreateChildTask("Leaf", root.ID())
+
+	toggled, _ := service.ToggleTaskStatus(leaf.ID())
+	if toggled.Status() != StatusInProgress {
+		t.Errorf("expected In Progress, got %v", toggled.Status())
+	}
+	toggled, _ = service.ToggleTaskStatus(leaf.ID())
+	if toggled.Status() != StatusTODO {
+		t.Errorf("expected TODO, got %v", toggled.Status())
+	}
+}
+
+// failingDeleteRepository fails every Delete made inside a transaction
+type failingDeleteRepository struct {
+	*InMemoryTaskRepository
+}
+
+func (r failingDeleteRepository) WithTransaction(fn func(tx TaskRepositoryTx) error) error {
+	return r.InMemoryTaskRepository.WithTransaction(func(tx TaskRepositoryTx) error {
+		return fn(failingDeleteTx{tx})
+	})
+}
+
+type failingDeleteTx struct {
+	TaskRepositoryTx
+}
+
+func (failingDeleteTx) Delete(id TaskID) error {
+	return NewConstraintViolationError("test", "delete failed")
+}
+
+func TestTaskService_UngroupTask_FailureLeavesStoreUnchanged(t *testing.T) {
+	repo := failingDeleteRepository{NewInMemoryTaskRepository()}
+	service := NewTaskService(repo)
+
+	// Create tree: root -> a, b (-> b1, b2)
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+	b1, _ := service.CreateChildTask("B1", b.ID())
+	b2, _ := service.CreateChildTask("B2", b.ID())
+	before := mustFindAll(t, repo)
+
+	// The children are promoted before the group is deleted, so the failure comes mid-way
+	if _, err := service.UngroupTask(b.ID()); err == nil {
+		t.Fatal("expected UngroupTask to fail")
+	}
+
+	after := mustFindAll(t, repo)
+	if len(after) != len(before) {
+		t.Fatalf("expected %d tasks, got %d", len(before), len(after))
+	}
+	expected := map[TaskID]struct {
+		parent   TaskID
+		position int
+	}{
+		a.ID():  {root.ID(), 0},
+		b.ID():  {root.ID(), 1},
+		b1.ID(): {b.ID(), 0},
+		b2.ID(): {b.ID(), 1},
+	}
+	for id, want := range expected {
+		task, err := repo.FindByID(id)
+		if err != nil {
+			t.Fatalf("expected task %s to remain, got %v", id, err)
+		}
+		if !task.ParentID().Equals(want.parent) || task.Position() != want.position {
+			t.Errorf("expected %s under %s at %d, got %s at %d", task.Description(), want.parent, want.position, task.ParentID(), task.Position())
+		}
+		if len(task.MoveHistory()) != 0 {
+			t.Errorf("expected no recorded moves for %s", task.Description())
+		}
+	}
+}
+
+func TestTaskService_MaxSubtreeOperation(t *testing.T) {
+	// Create tree: root -> parent -> (a, b), so parent's subtree holds 3 tasks
+	setup := func(limit int) (*TaskService, TaskRepository, *Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{MaxSubtreeOperation: limit})
+		root, _ := service.CreateRootTask("Root")
+		parent, _ := service.CreateChildTask("Parent", root.ID())
+		_, _ = service.CreateChildTask("A", parent.ID())
+		_, _ = service.CreateChildTask("B", parent.ID())
+		return service, repo, parent
+	}
+
+	tests := []struct {
+		name    string
+		limit   int
+		allowed bool
+	}{
+		{"unlimited", 0, true},
+		{"at the limit", 3, true},
+		{"one over the limit", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/tag", func(t *testing.T) {
+			service, _, parent := setup(tt.limit)
+			_, err := service.TagSubtree(parent.ID(), []string{"ux"}, nil)
+			checkSubtreeLimitError(t, err, tt.allowed)
+		})
+		t.Run(tt.name+"/delete", func(t *testing.T) {
+			service, repo, parent := setup(tt.limit)
+			err := service.DeleteTask(parent.ID())
+			checkSubtreeLimitError(t, err, tt.allowed)
+
+			// A rejected delete leaves the subtree in place
+			count, _ := repo.Count()
+			expected := 1
+			if !tt.allowed {
+				expected = 4
+			}
+			if count != expected {
+				t.Errorf("expected %d tasks after delete, got %d", expected, count)
+			}
+		})
+		t.Run(tt.name+"/find", func(t *testing.T) {
+			service, _, parent := setup(tt.limit)
+			subtree, err := service.FindSubtree(parent.ID())
+			checkSubtreeLimitError(t, err, tt.allowed)
+			if tt.allowed && len(subtree) != 3 {
+				t.Errorf("expected 3 tasks in the subtree, got %d", len(subtree))
+			}
+		})
+	}
+}
+
+// stagingCountingRepository counts the transactions run on an in-memory repository
+type stagingCountingRepository struct {
+	*InMemoryTaskRepository
+	transactions int
+}
+
+func (r *stagingCountingRepository) WithTransaction(fn func(tx TaskRepositoryTx) error) error {
+	return r.InMemoryTaskRepository.WithTransaction(func(tx TaskRepositoryTx) error {
+		// Counted under the repository's write lock, so concurrent transactions count safely
+		r.transactions++
+		return fn(tx)
+	})
+}
+
+func TestTaskService_MaxSubtreeOperation_RejectsBeforeStaging(t *testing.T) {
+	repo := &stagingCountingRepository{InMemoryTaskRepository: NewInMemoryTaskRepository()}
+	service := NewTaskServiceWithConfig(repo, TaskServiceConfig{MaxSubtreeOperation: 2})
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	_, _ = service.CreateChildTask("A", parent.ID())
+	_, _ = service.CreateChildTask("B", parent.ID())
+
+	before := repo.transactions
+	checkSubtreeLimitError(t, service.DeleteTask(parent.ID()), false)
+	checkSubtreeLimitError(t, service.DeleteTaskWithStrategy(parent.ID(), DeleteCascade, true), false)
+	_, err := service.TagSubtree(parent.ID(), []string{"ux"}, nil)
+	checkSubtreeLimitError(t, err, false)
+	_, _, err = service.ResetSubtree(parent.ID())
+	checkSubtreeLimitError(t, err, false)
+
+	if repo.transactions != before {
+		t.Errorf("expected no transaction for a rejected operation, got %d", repo.transactions-before)
+	}
+}
+
+// checkSubtreeLimitError asserts err is nil when allowed and a SubtreeTooLargeError otherwise
+func checkSubtreeLimitError(t *testing.T, err error, allowed bool) {
+	t.Helper()
+	if allowed {
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return
+	}
+	tooLarge, ok := err.(SubtreeTooLargeError)
+	if !ok {
+		t.Fatalf("expected SubtreeTooLargeError, got %v", err)
+	}
+	if tooLarge.Size != 3 || tooLarge.Limit != 2 {
+		t.Errorf("expected size 3 over limit 2, got %d over %d", tooLarge.Size, tooLarge.Limit)
+	}
+}
+
+func TestTaskService_DeleteTaskWithStrategy(t *testing.T) {
+	// Create tree: root -> a, b (-> b1 (-> b1x), b2), c
+	setup := func() (*TaskService, TaskRepository, map[string]*Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskService(repo)
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		b, _ := service.CreateChildTask("B", root.ID())
+		c, _ := service.CreateChildTask("C", root.ID())
+		b1, _ := service.CreateChildTask("B1", b.ID())
+		b2, _ := service.CreateChildTask("B2", b.ID())
+		b1x, _ := service.CreateChildTask("B1x", b1.ID())
+		return service, repo, map[string]*Task{"Root": root, "A": a, "B": b, "C": c, "B1": b1, "B2": b2, "B1x": b1x}
+	}
+
+	tests := []struct {
+		name         string
+		strategy     DeleteStrategy
+		rootChildren []string
+		remaining    int
+	}{
+		{"default cascades", "", []string{"A", "C"}, 3},
+		{"cascade", DeleteCascade, []string{"A", "C"}, 3},
+		{"reparent", DeleteReparent, []string{"A", "B1", "B2", "C"}, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, tasks := setup()
+
+			if err := service.DeleteTaskWithStrategy(tasks["B"].ID(), tt.strategy, false); err != nil {
+				t.Fatalf("DeleteTaskWithStrategy failed: %v", err)
+			}
+
+			rootID := tasks["Root"].ID()
+			children, _ := repo.FindByParentID(&rootID)
+			if len(children) != len(tt.rootChildren) {
+				t.Fatalf("expected %d root children, got %d", len(tt.rootChildren), len(children))
+			}
+			for i, description := range tt.rootChildren {
+				if children[i].Description() != description || children[i].Position() != i {
+					t.Errorf("expected %s at position %d, got %s at %d", description, i, children[i].Description(), children[i].Position())
+				}
+			}
+
+			if count, _ := repo.Count(); count != tt.remaining {
+				t.Errorf("expected %d remaining tasks, got %d", tt.remaining, count)
+			}
+			if _, err := repo.FindByID(tasks["B"].ID()); err == nil {
+				t.Error("expected deleted task to be gone")
+			}
+
+			// Reparenting keeps grandchildren under their own parent
+			if tt.strategy == DeleteReparent {
+				b1x, err := repo.FindByID(tasks["B1x"].ID())
+				if err != nil || !b1x.ParentID().Equals(tasks["B1"].ID()) {
+					t.Errorf("expected B1x to stay under B1, got %v", err)
+				}
+			}
+
+			if violations := CheckTreeInvariants(mustFindAll(t, repo), CompleteStatuses{}); len(violations) != 0 {
+				t.Errorf("expected a valid tree, got %v", violations)
+			}
+		})
+	}
+}
+
+func TestTaskService_DeleteTaskWithStrategy_Rejections(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+
+	err := service.DeleteTaskWithStrategy(root.ID(), DeleteReparent, false)
+	constraintErr, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if constraintErr.Constraint != "root-reparent" {
+		t.Errorf("expected root-reparent constraint, got %s", constraintErr.Constraint)
+	}
+
+	if err := service.DeleteTaskWithStrategy(a.ID(), DeleteStrategy("orphan"), false); err == nil {
+		t.Error("expected error for unknown strategy, got nil")
+	} else if _, ok := err.(ValidationError); !ok {
+		t.Errorf("expected ValidationError, got %T", err)
+	}
+
+	// Nothing was deleted
+	if count, _ := repo.Count(); count != 2 {
+		t.Errorf("expected 2 tasks, got %d", count)
+	}
+}
+
+func TestTaskService_DeleteTaskWithStrategy_Guard(t *testing.T) {
+	// Create tree: root -> a (DONE, -> a1 DONE), b (In Progress, -> b1 TODO)
+	setup := func(guard DeleteGuard) (*TaskService, TaskRepository, map[string]*Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{DeleteGuard: guard})
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		a1, _ := service.CreateChildTask("A1", a.ID())
+		b, _ := service.CreateChildTask("B", root.ID())
+		b1, _ := service.CreateChildTask("B1", b.ID())
+		for _, task := range []*Task{a1, a} {
+			if err := service.ChangeTaskStatus(task.ID(), StatusDONE); err != nil {
+				t.Fatalf("expected no error completing %q, got %v", task.Description(), err)
+			}
+		}
+		if err := service.ChangeTaskStatus(b.ID(), StatusInProgress); err != nil {
+			t.Fatalf("expected no error starting B, got %v", err)
+		}
+		return service, repo, map[string]*Task{"Root": root, "A": a, "A1": a1, "B": b, "B1": b1}
+	}
+
+	t.Run("blocks deleting an in-progress branch", func(t *testing.T) {
+		service, repo, tasks := setup(DeleteGuardBlock)
+
+		err := service.DeleteTaskWithStrategy(tasks["B"].ID(), DeleteCascade, false)
+		violation, ok := err.(ConstraintViolationError)
+		if !ok || violation.Constraint != "incomplete-delete" {
+			t.Fatalf("expected incomplete-delete violation, got %v", err)
+		}
+		if details := violation.Details(); len(details) != 2 || details[0].Description != "B" || details[1].Description != "B1" {
+			t.Errorf("expected B and B1 to be named, got %d tasks", len(details))
+		}
+		if count, _ := repo.Count(); count != 5 {
+			t.Errorf("expected nothing deleted, got %d tasks", count)
+		}
+
+		// The root's subtree holds the same work
+		if err := service.DeleteTaskWithStrategy(tasks["Root"].ID(), DeleteCascade, false); err == nil {
+			t.Errorf("expected deleting the root to be blocked")
+		}
+		// Reparenting deletes only the task, which is in progress too
+		if err := service.DeleteTaskWithStrategy(tasks["B"].ID(), DeleteReparent, false); err == nil {
+			t.Errorf("expected reparenting B to be blocked")
+		}
+	})
+
+	t.Run("allows deleting a DONE branch", func(t *testing.T) {
+		service, repo, tasks := setup(DeleteGuardBlock)
+		if err := service.DeleteTaskWithStrategy(tasks["A"].ID(), DeleteCascade, false); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if count, _ := repo.Count(); count != 3 {
+			t.Errorf("expected 3 remaining tasks, got %d", count)
+		}
+	})
+
+	t.Run("forced delete succeeds", func(t *testing.T) {
+		service, repo, tasks := setup(DeleteGuardBlock)
+		if err := service.DeleteTaskWithStrategy(tasks["B"].ID(), DeleteCascade, true); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if count, _ := repo.Count(); count != 3 {
+			t.Errorf("expected 3 remaining tasks, got %d", count)
+		}
+		if violations := CheckTreeInvariants(mustFindAll(t, repo), CompleteStatuses{}); len(violations) != 0 {
+			t.Errorf("expected a valid tree, got %v", violations)
+		}
+	})
+
+	t.Run("cascade guard deletes active work", func(t *testing.T) {
+		service, repo, tasks := setup(DeleteGuardCascade)
+		if err := service.DeleteTaskWithStrategy(tasks["B"].ID(), DeleteCascade, false); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if count, _ := repo.Count(); count != 3 {
+			t.Errorf("expected 3 remaining tasks, got %d", count)
+		}
+	})
+}
+
+func TestTaskService_NormalizeOnWrite(t *testing.T) {
+	// setupDrifted builds root -> A, B, C, D and then deletes B and C behind the service's back,
+	// leaving A and D at positions 0 and 3
+	setupDrifted := func(t *testing.T, normalize bool) (*InMemoryTaskRepository, *TaskService, *Task, *Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{NormalizeOnWrite: normalize})
+		root, _ := service.CreateRootTask("Root")
+		other, _ := service.CreateChildTask("Other", root.ID())
+		a, _ := service.CreateChildTask("A", other.ID())
+		b, _ := service.CreateChildTask("B", other.ID())
+		c, _ := service.CreateChildTask("C", other.ID())
+		service.CreateChildTask("D", other.ID())
+		if err := repo.Delete(b.ID()); err != nil {
+			t.Fatalf("failed to delete B: %v", err)
+		}
+		if err := repo.Delete(c.ID()); err != nil {
+			t.Fatalf("failed to delete C: %v", err)
+		}
+		return repo, service, other, a
+	}
+
+	positionsOf := func(repo *InMemoryTaskRepository, parentID TaskID) []int {
+		children, _ := repo.FindByParentID(&parentID)
+		positions := make([]int, len(children))
+		for i, child := range children {
+			positions[i] = child.Position()
+		}
+		return positions
+	}
+
+	tests := []struct {
+		name      string
+		normalize bool
+		want      []int
+	}{
+		// Appending after the last child (D at 3) keeps the gaps at 1 and 2
+		{"disabled keeps the drift", false, []int{0, 3, 4}},
+		{"enabled renumbers the siblings", true, []int{0, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" on create", func(t *testing.T) {
+			repo, service, parent, _ := setupDrifted(t, tt.normalize)
+
+			if _, err := service.CreateChildTask("E", parent.ID()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			got := positionsOf(repo, parent.ID())
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected positions %v, got %v", tt.want, got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected positions %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	t.Run("enabled renumbers the old parent on move", func(t *testing.T) {
+		repo, service, parent, a := setupDrifted(t, true)
+		root, _ := repo.FindRoot()
+
+		if err := service.MoveTask(a.ID(), &root.id, 0); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// D was left alone at position 3 without normalization
+		if got := positionsOf(repo, parent.ID()); len(got) != 1 || got[0] != 0 {
+			t.Errorf("expected the remaining child at position 0, got %v", got)
+		}
+		if got := positionsOf(repo, root.ID()); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+			t.Errorf("expected root children at 0 and 1, got %v", got)
+		}
+
+		// Renumbering is not a move, so D's history stays empty
+		children, _ := repo.FindByParentID(&parent.id)
+		if len(children[0].MoveHistory()) != 0 {
+			t.Errorf("expected no move history for a renumbered sibling, got %v", children[0].MoveHistory())
+		}
+	})
+}
+
+func TestTaskService_TouchAncestors(t *testing.T) {
+	// setup builds root -> A -> B and returns the repository, service, A and B
+	setup := func(mode TouchAncestorsMode) (*InMemoryTaskRepository, *TaskService, *Task, *Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{TouchAncestors: mode})
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		b, _ := service.CreateChildTask("B", a.ID())
+		return repo, service, a, b
+	}
+	seqOf := func(repo *InMemoryTaskRepository, id TaskID) uint64 {
+		task, err := repo.FindByID(id)
+		if err != nil {
+			t.Fatalf("failed to find task: %v", err)
+		}
+		return task.Seq()
+	}
+
+	tests := []struct {
+		mode               TouchAncestorsMode
+		parentTouched      bool
+		grandparentTouched bool
+	}{
+		{TouchAncestorsOff, false, false},
+		{TouchAncestorsParent, true, false},
+		{TouchAncestorsAll, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+" on create", func(t *testing.T) {
+			repo, service, a, b := setup(tt.mode)
+			parentSeq, grandparentSeq := seqOf(repo, b.ID()), seqOf(repo, a.ID())
+
+			child, err := service.CreateChildTask("C", b.ID())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if got := seqOf(repo, b.ID()) > parentSeq; got != tt.parentTouched {
+				t.Errorf("expected parent Seq advanced to be %v, got %v", tt.parentTouched, got)
+			}
+			if got := seqOf(repo, a.ID()) > grandparentSeq; got != tt.grandparentTouched {
+				t.Errorf("expected grandparent Seq advanced to be %v, got %v", tt.grandparentTouched, got)
+			}
+			if tt.parentTouched {
+				parent, _ := repo.FindByID(b.ID())
+				if parent.UpdatedAt().Before(child.CreatedAt()) {
+					t.Errorf("expected parent UpdatedAt refreshed, got %v before %v", parent.UpdatedAt(), child.CreatedAt())
+				}
+			}
+		})
+	}
+
+	t.Run("parent on delete and move", func(t *testing.T) {
+		repo, service, a, b := setup(TouchAncestorsParent)
+		c, _ := service.CreateChildTask("C", b.ID())
+		d, _ := service.CreateChildTask("D", b.ID())
+
+		// Deleting C touches its parent B
+		parentSeq := seqOf(repo, b.ID())
+		if err := service.DeleteTask(c.ID()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if seqOf(repo, b.ID()) <= parentSeq {
+			t.Error("expected delete to advance the parent's Seq")
+		}
+
+		// Moving D from B to A touches both its old and its new parent
+		oldParentSeq, newParentSeq := seqOf(repo, b.ID()), seqOf(repo, a.ID())
+		if err := service.MoveTask(d.ID(), &a.id, 0); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if seqOf(repo, b.ID()) <= oldParentSeq || seqOf(repo, a.ID()) <= newParentSeq {
+			t.Error("expected move to advance the Seq of the old and new parent")
+		}
+
+		// Changing a child's status is not structural and touches nothing
+		parentSeq = seqOf(repo, a.ID())
+		if err := service.ChangeTaskStatus(d.ID(), StatusInProgress); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if seqOf(repo, a.ID()) != parentSeq {
+			t.Error("expected a status change to leave the parent's Seq alone")
+		}
+	})
+}
+
+func TestTaskService_RenameStatus(t *testing.T) {
+	// setup builds root -> A (In Progress) -> A1 (In Progress), A2 (TODO), and root -> B (TODO)
+	setup := func() (*InMemoryTaskRepository, *TaskService, []*Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskService(repo)
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		a1, _ := service.CreateChildTask("A1", a.ID())
+		a2, _ := service.CreateChildTask("A2", a.ID())
+		b, _ := service.CreateChildTask("B", root.ID())
+		service.ChangeTaskStatus(a.ID(), StatusInProgress)
+		service.ChangeTaskStatus(a1.ID(), StatusInProgress)
+		return repo, service, []*Task{root, a, a1, a2, b}
+	}
+	statusOf := func(repo *InMemoryTaskRepository, task *Task) Status {
+		found, err := repo.FindByID(task.ID())
+		if err != nil {
+			t.Fatalf("expected task %q to exist, got %v", task.Description(), err)
+		}
+		return found.Status()
+	}
+
+	t.Run("renames every task in the status", func(t *testing.T) {
+		repo, service, tasks := setup()
+		renamed, err := service.RenameStatus(StatusInProgress, StatusBlocked)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if renamed != 2 {
+			t.Errorf("expected 2 tasks renamed, got %d", renamed)
+		}
+		want := []Status{StatusRootWorkItem, StatusBlocked, StatusBlocked, StatusTODO, StatusTODO}
+		for i, task := range tasks {
+			if got := statusOf(repo, task); got != want[i] {
+				t.Errorf("expected %q to be %s, got %s", task.Description(), want[i], got)
+			}
+		}
+	})
+
+	t.Run("no task in the status", func(t *testing.T) {
+		_, service, _ := setup()
+		renamed, err := service.RenameStatus(StatusDONE, StatusTODO)
+		if err != nil || renamed != 0 {
+			t.Errorf("expected nothing renamed, got %d, %v", renamed, err)
+		}
+	})
+
+	t.Run("completing a task with incomplete children is rejected", func(t *testing.T) {
+		repo, service, tasks := setup()
+		_, err := service.RenameStatus(StatusInProgress, StatusDONE)
+		constraintErr, ok := err.(ConstraintViolationError)
+		if !ok || constraintErr.Constraint != "bottom-to-top-completion" {
+			t.Fatalf("expected bottom-to-top-completion violation, got %v", err)
+		}
+		// Nothing changed
+		if got := statusOf(repo, tasks[2]); got != StatusInProgress {
+			t.Errorf("expected A1 to stay In Progress, got %s", got)
+		}
+	})
+
+	t.Run("invalid renames are rejected", func(t *testing.T) {
+		_, service, _ := setup()
+		for _, pair := range [][2]Status{
+			{StatusTODO, StatusTODO},
+			{StatusRootWorkItem, StatusTODO},
+			{StatusTODO, StatusRootWorkItem},
+			{Status(99), StatusTODO},
+		} {
+			_, err := service.RenameStatus(pair[0], pair[1])
+			if _, ok := err.(ValidationError); !ok {
+				t.Errorf("expected validation error renaming %v to %v, got %v", pair[0], pair[1], err)
+			}
+		}
+	})
+
+	t.Run("cannot complete a task that must be broken down", func(t *testing.T) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{DecompositionTags: []string{"epic"}})
+		root, _ := service.CreateRootTask("Root")
+		epic, _ := service.CreateChildTask("Epic", root.ID())
+		if _, err := service.TagSubtree(epic.ID(), []string{"epic"}, nil); err != nil {
+			t.Fatalf("expected no error tagging, got %v", err)
+		}
+
+		_, err := service.RenameStatus(StatusTODO, StatusDONE)
+		if violation, ok := err.(ConstraintViolationError); !ok || violation.Constraint != "decomposition-required" {
+			t.Fatalf("expected decomposition-required violation, got %v", err)
+		}
+		if got := statusOf(repo, epic); got != StatusTODO {
+			t.Errorf("expected the epic to stay TODO, got %s", got)
+		}
+	})
+}
+
+func TestTaskService_ChildDescriptionTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"unset rejects blank", "", "", "", true},
+		{"renders the parent", "Investigate {parent}", "", "Investigate Root", false},
+		{"whitespace counts as blank", "Investigate {parent}", "   ", "Investigate Root", false},
+		{"every placeholder is replaced", "{parent}: follow up on {parent}", "", "Root: follow up on Root", false},
+		{"given description wins", "Investigate {parent}", "Child", "Child", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryTaskRepository()
+			service := NewTaskServiceWithConfig(repo, TaskServiceConfig{ChildDescriptionTemplate: tt.template})
+			root, _ := service.CreateRootTask("Root")
+
+			child, err := service.CreateChildTask(tt.input, root.ID())
+			if tt.wantErr {
+				if _, ok := err.(ValidationError); !ok {
+					t.Fatalf("expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if child.Description() != tt.expected {
+				t.Errorf("expected description %q, got %q", tt.expected, child.Description())
+			}
+		})
+	}
+}
+
+func TestTaskService_ClampMovePositions(t *testing.T) {
+	// setup builds root -> A, B, C and root -> D, so C can move within its siblings or into D's
+	setup := func(clamp bool) (*InMemoryTaskRepository, *TaskService, *Task, *Task, *Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskServiceWithConfig(repo, TaskServiceConfig{ClampMovePositions: clamp})
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		service.CreateChildTask("B", root.ID())
+		service.CreateChildTask("C", root.ID())
+		d, _ := service.CreateChildTask("D", root.ID())
+		return repo, service, root, a, d
+	}
+	positionOf := func(repo *InMemoryTaskRepository, task *Task) int {
+		found, err := repo.FindByID(task.ID())
+		if err != nil {
+			t.Fatalf("expected task %q to exist, got %v", task.Description(), err)
+		}
+		return found.Position()
+	}
+
+	t.Run("strict by default", func(t *testing.T) {
+		repo, service, root, a, _ := setup(false)
+		err := service.MoveTask(a.ID(), &root.id, 99)
+		if _, ok := err.(ValidationError); !ok {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+		if got := positionOf(repo, a); got != 0 {
+			t.Errorf("expected A to stay at 0, got %d", got)
+		}
+	})
+
+	t.Run("clamps within the same parent", func(t *testing.T) {
+		repo, service, root, a, _ := setup(true)
+		if err := service.MoveTask(a.ID(), &root.id, 99); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := positionOf(repo, a); got != 3 {
+			t.Errorf("expected A at the last position 3, got %d", got)
+		}
+	})
+
+	t.Run("clamps under a new parent", func(t *testing.T) {
+		repo, service, _, a, d := setup(true)
+		// D has three children, so position 99 becomes 3
+		for _, description := range []string{"D1", "D2", "D3"} {
+			service.CreateChildTask(description, d.ID())
+		}
+		if err := service.MoveTask(a.ID(), &d.id, 99); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got := positionOf(repo, a); got != 3 {
+			t.Errorf("expected A appended at 3, got %d", got)
+		}
+	})
+
+	t.Run("negative positions stay rejected", func(t *testing.T) {
+		_, service, root, a, _ := setup(true)
+		if _, ok := service.MoveTask(a.ID(), &root.id, -1).(ValidationError); !ok {
+			t.Error("expected validation error for a negative position")
+		}
+	})
+}
+
+func TestTaskService_ResetSubtree(t *testing.T) {
+	// setup builds root -> A -> A1, A2 -> A2a and root -> B, with everything but the root DONE
+	setup := func() (*InMemoryTaskRepository, *TaskService, map[string]*Task) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskService(repo)
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		a1, _ := service.CreateChildTask("A1", a.ID())
+		a2, _ := service.CreateChildTask("A2", a.ID())
+		a2a, _ := service.CreateChildTask("A2a", a2.ID())
+		b, _ := service.CreateChildTask("B", root.ID())
+		for _, task := range []*Task{a1, a2a, a2, a, b} {
+			if err := service.ChangeTaskStatus(task.ID(), StatusDONE); err != nil {
+				t.Fatalf("expected no error completing %q, got %v", task.Description(), err)
+			}
+		}
+		return repo, service, map[string]*Task{"Root": root, "A": a, "A1": a1, "A2": a2, "A2a": a2a, "B": b}
+	}
+	find := func(repo *InMemoryTaskRepository, task *Task) *Task {
+		found, err := repo.FindByID(task.ID())
+		if err != nil {
+			t.Fatalf("expected task %q to exist, got %v", task.Description(), err)
+		}
+		return found
+	}
+
+	t.Run("resets a fully DONE tree from the root", func(t *testing.T) {
+		repo, service, tasks := setup()
+		reset, reopened, err := service.ResetSubtree(tasks["Root"].ID())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if reset != 5 || len(reopened) != 0 {
+			t.Errorf("expected 5 tasks reset and none reopened, got %d and %d", reset, len(reopened))
+		}
+		if got := find(repo, tasks["Root"]).Status(); got != StatusRootWorkItem {
+			t.Errorf("expected the root to stay Root Work Item, got %s", got)
+		}
+		for _, name := range []string{"A", "A1", "A2", "A2a", "B"} {
+			task := find(repo, tasks[name])
+			if task.Status() != StatusTODO || task.CompletedAt() != nil {
+				t.Errorf("expected %s to be TODO without completedAt, got %s", name, task.Status())
+			}
+		}
+	})
+
+	t.Run("reopens DONE ancestors of a reset branch", func(t *testing.T) {
+		repo, service, tasks := setup()
+		reset, reopened, err := service.ResetSubtree(tasks["A2"].ID())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if reset != 2 {
+			t.Errorf("expected A2 and A2a reset, got %d", reset)
+		}
+		if len(reopened) != 1 || !reopened[0].ID().Equals(tasks["A"].ID()) {
+			t.Fatalf("expected A to be reopened, got %d tasks", len(reopened))
+		}
+		if got := find(repo, tasks["A"]).Status(); got != StatusInProgress {
+			t.Errorf("expected A In Progress, got %s", got)
+		}
+		for _, name := range []string{"A1", "B"} {
+			if got := find(repo, tasks[name]).Status(); got != StatusDONE {
+				t.Errorf("expected %s to stay DONE, got %s", name, got)
+			}
+		}
+	})
+
+	t.Run("leaves TODO tasks unchanged", func(t *testing.T) {
+		repo := NewInMemoryTaskRepository()
+		service := NewTaskService(repo)
+		root, _ := service.CreateRootTask("Root")
+		a, _ := service.CreateChildTask("A", root.ID())
+		before := find(repo, a).Seq()
+
+		reset, _, err := service.ResetSubtree(a.ID())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if reset != 0 || find(repo, a).Seq() != before {
+			t.Errorf("expected nothing to change, got %d reset", reset)
+		}
+	})
+
+	t.Run("unknown task is not found", func(t *testing.T) {
+		_, service, _ := setup()
+		_, _, err := service.ResetSubtree(NewTaskID())
+		if _, ok := err.(NotFoundError); !ok {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+}
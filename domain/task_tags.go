@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxTagLength is the maximum length of a tag in characters
+const MaxTagLength = 50
+
+// NormalizeTags validates tags and returns them trimmed, sorted and without duplicates
+// A tag must be non-empty, at most MaxTagLength characters, and contain no whitespace or commas
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, NewValidationError("tags", "empty-tag", "tag cannot be empty")
+		}
+		if len([]rune(tag)) > MaxTagLength {
+			return nil, NewValidationError("tags", "tag-too-long", fmt.Sprintf("tag %q exceeds %d characters", tag, MaxTagLength))
+		}
+		if strings.ContainsAny(tag, ", \t\r\n") {
+			return nil, NewValidationError("tags", "invalid-tag-characters", fmt.Sprintf("tag %q must not contain whitespace or commas", tag))
+		}
+		normalized = append(normalized, tag)
+	}
+	return sortedUniqueTags(normalized), nil
+}
+
+// sortedUniqueTags returns the tags sorted and without duplicates (nil for no tags)
+func sortedUniqueTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	sorted := make([]string, len(tags))
+	copy(sorted, tags)
+	sort.Strings(sorted)
+
+	unique := sorted[:1]
+	for _, tag := range sorted[1:] {
+		if tag != unique[len(unique)-1] {
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
+
+// equalTags reports whether two sorted tag lists are identical
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TagSubtree adds and removes tags on a task and all its descendants in one transaction
+// Tags are validated with NormalizeTags, and a tag may not be both added and removed
+// Subtrees larger than MaxSubtreeOperation are rejected with a SubtreeTooLargeError
+// Returns the number of tasks whose tags changed
+func (s *TaskService) TagSubtree(taskID TaskID, add, remove []string) (int, error) {
+	add, err := NormalizeTags(add)
+	if err != nil {
+		return 0, err
+	}
+	remove, err = NormalizeTags(remove)
+	if err != nil {
+		return 0, err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, NewValidationError("tags", "no-tag-changes", "at least one tag must be added or removed")
+	}
+	for _, tag := range add {
+		if i := sort.SearchStrings(remove, tag); i < len(remove) && remove[i] == tag {
+			return 0, NewValidationError("tags", "conflicting-tag-change", fmt.Sprintf("tag %q cannot be both added and removed", tag))
+		}
+	}
+
+	if err := s.ensureSubtreeWithinLimit(taskID); err != nil {
+		return 0, err
+	}
+
+	affected := 0
+	err = s.inTransaction(func(tx *TaskService) error {
+		if err := tx.ensureSubtreeWithinLimit(taskID); err != nil {
+			return err
+		}
+
+		subtree, err := tx.repo.FindSubtree(taskID)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]*Task, 0, len(subtree))
+		for _, task := range subtree {
+			if task.UpdateTags(add, remove) {
+				changed = append(changed, task)
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		affected = len(changed)
+		return tx.repo.SaveAll(changed)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" ux ", "backend", "ux"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "backend" || tags[1] != "ux" {
+		t.Errorf("expected [backend ux], got %v", tags)
+	}
+
+	for _, invalid := range []string{"", "  ", "two words", "a,b", strings.Repeat("x", MaxTagLength+1)} {
+		if _, err := NormalizeTags([]string{invalid}); err == nil {
+			t.Errorf("expected error for tag %q, got nil", invalid)
+		} else if _, ok := err.(ValidationError); !ok {
+			t.Errorf("expected ValidationError for tag %q, got %T", invalid, err)
+		}
+	}
+}
+
+func TestTaskService_TagSubtree(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> parent -> (a, b), plus an untouched sibling of parent
+	root, _ := service.CreateRootTask("Root")
+	parent, _ := service.CreateChildTask("Parent", root.ID())
+	a, _ := service.CreateChildTask("A", parent.ID())
+	b, _ := service.CreateChildTask("B", parent.ID())
+	other, _ := service.CreateChildTask("Other", root.ID())
+
+	affected, err := service.TagSubtree(parent.ID(), []string{"backend", "ux"}, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if affected != 3 {
+		t.Errorf("expected 3 affected tasks, got %d", affected)
+	}
+	for _, id := range []TaskID{parent.ID(), a.ID(), b.ID()} {
+		task, _ := repo.FindByID(id)
+		if !task.HasTag("backend") || !task.HasTag("ux") {
+			t.Errorf("expected task %s to carry both tags, got %v", task.Description(), task.Tags())
+		}
+	}
+	for _, id := range []TaskID{root.ID(), other.ID()} {
+		task, _ := repo.FindByID(id)
+		if len(task.Tags()) != 0 {
+			t.Errorf("expected task %s to be untagged, got %v", task.Description(), task.Tags())
+		}
+	}
+
+	// Removing a tag from a subtree only counts tasks that carried it
+	affected, err = service.TagSubtree(a.ID(), nil, []string{"ux"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if affected != 1 {
+		t.Errorf("expected 1 affected task, got %d", affected)
+	}
+
+	// Re-applying the same change affects nothing
+	affected, err = service.TagSubtree(a.ID(), nil, []string{"ux"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if affected != 0 {
+		t.Errorf("expected 0 affected tasks, got %d", affected)
+	}
+}
+
+func TestTaskService_TagSubtree_InvalidRequests(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+
+	tests := []struct {
+		name   string
+		add    []string
+		remove []string
+	}{
+		{"nothing to do", nil, nil},
+		{"invalid tag", []string{"has space"}, nil},
+		{"added and removed", []string{"ux"}, []string{"ux"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.TagSubtree(root.ID(), tt.add, tt.remove)
+			if _, ok := err.(ValidationError); !ok {
+				t.Errorf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+
+	// Unknown task
+	if _, err := service.TagSubtree(NewTaskID(), []string{"ux"}, nil); err == nil {
+		t.Error("expected error for unknown task, got nil")
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+// TaskTemplate is a named nested tree that can be instantiated repeatedly, each time with fresh IDs
+// A template whose top node is a Root Work Item can only be instantiated as the root task
+type TaskTemplate struct {
+	Name string
+	Root TreeImportNode
+	Err  error // set by FindAll when the template could not be loaded; Root is then empty
+}
+
+// TemplateRepository provides read access to named task templates
+type TemplateRepository interface {
+	// FindAll retrieves all templates, ordered by name
+	// A template that cannot be loaded is listed with Err set instead of failing the whole list
+	FindAll() ([]TaskTemplate, error)
+
+	// FindByName retrieves a template by its name, or returns a NotFoundError
+	FindByName(name string) (TaskTemplate, error)
+}
@@ -326,6 +326,47 @@ func TestTask_ChangeStatus_InvalidStatus(t *testing.T) {
 	}
 }
 
+func TestTask_ChangeStatus_SetsAndClearsCompletedAt(t *testing.T) {
+	parentID := NewTaskID()
+	task, err := NewTask("Test task", &parentID, 0)
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	if task.CompletedAt() != nil {
+		t.Fatal("expected new task to have nil CompletedAt")
+	}
+
+	// Entering DONE sets CompletedAt
+	if err := task.ChangeStatus(StatusDONE); err != nil {
+		t.Fatalf("failed to change status: %v", err)
+	}
+	completedAt := task.CompletedAt()
+	if completedAt == nil {
+		t.Fatal("expected CompletedAt to be set after marking DONE")
+	}
+
+	// Re-marking DONE keeps the original completion time
+	time.Sleep(2 * time.Millisecond)
+	if err := task.ChangeStatus(StatusDONE); err != nil {
+		t.Fatalf("failed to change status: %v", err)
+	}
+	if !task.CompletedAt().Equal(*completedAt) {
+		t.Errorf("expected CompletedAt to stay %v, got %v", *completedAt, *task.CompletedAt())
+	}
+	if !task.UpdatedAt().After(*completedAt) {
+		t.Error("expected UpdatedAt to move independently of CompletedAt")
+	}
+
+	// Leaving DONE clears CompletedAt
+	if err := task.ChangeStatus(StatusInProgress); err != nil {
+		t.Fatalf("failed to change status: %v", err)
+	}
+	if task.CompletedAt() != nil {
+		t.Errorf("expected CompletedAt to be cleared after leaving DONE, got %v", *task.CompletedAt())
+	}
+}
+
 func TestTask_UpdateDescription_ValidDescription(t *testing.T) {
 	task, err := NewTask("Initial description", nil, 0)
 	if err != nil {
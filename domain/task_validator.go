@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+)
+
 // TaskValidator validates operations that span multiple tasks or require tree-wide knowledge
 type TaskValidator interface {
 	// ValidateStatusChange validates whether a status change is allowed
@@ -10,51 +15,157 @@ type TaskValidator interface {
 	// Returns an error if the move would create a cycle or violate constraints
 	ValidateMove(taskID TaskID, newParentID *TaskID, newPosition int) error
 
+	// ClampMovePosition returns the position a move will use: with ClampMovePositions, a position
+	// past the last valid one for the move becomes the last valid one; otherwise it is unchanged
+	ClampMovePosition(taskID TaskID, newParentID *TaskID, newPosition int) (int, error)
+
+	// ValidateBulkMove validates whether several tasks can be moved together under one parent
+	// Returns an error if any single move would be invalid or the selection overlaps itself
+	ValidateBulkMove(taskIDs []TaskID, newParentID TaskID, startPosition int) error
+
 	// ValidateDelete validates whether a delete operation is allowed
 	// Returns an error if the delete violates constraints
 	ValidateDelete(taskID TaskID) error
 }
 
+// CrossRootMovePolicy selects whether a task may be moved into the tree of another root
+type CrossRootMovePolicy string
+
+const (
+	// CrossRootMovesAllow allows moving a task under a parent in any tree
+	CrossRootMovesAllow CrossRootMovePolicy = "allow"
+	// CrossRootMovesDeny rejects moving a task under a parent in another root's tree
+	CrossRootMovesDeny CrossRootMovePolicy = "deny"
+)
+
+// IsValid checks if the policy value is valid (empty selects the default)
+func (p CrossRootMovePolicy) IsValid() bool {
+	return p == "" || p == CrossRootMovesAllow || p == CrossRootMovesDeny
+}
+
+// TaskValidatorConfig holds optional rules for TaskValidator
+// The zero value preserves the default behavior
+type TaskValidatorConfig struct {
+	// CrossRootMoves selects whether moves may cross from one root's tree into another's
+	// Defaults to CrossRootMovesAllow
+	CrossRootMoves CrossRootMovePolicy
+
+	// ClampMovePositions makes ValidateMove accept a position past the end of the new siblings,
+	// which ClampMovePosition turns into the last valid position, instead of rejecting it
+	// Negative positions, bulk moves, positioned creates and imports stay strict
+	ClampMovePositions bool
+
+	// DecompositionTags makes ValidateStatusChange reject completing a task carrying any of these
+	// tags until it has at least MinDecomposedChildren children, so tagged items such as epics
+	// cannot be done without being broken down; untagged tasks, leaves included, are unaffected
+	// Empty turns the rule off
+	DecompositionTags []string
+
+	// MinDecomposedChildren is the number of children a task carrying a DecompositionTags tag
+	// needs before it can be completed; values below 1 mean 1
+	MinDecomposedChildren int
+
+	// DecompositionKinds applies the DecompositionTags rule to tasks of any of these kinds
+	// Empty leaves the rule to the tags alone
+	DecompositionKinds []string
+
+	// CompleteStatuses are the statuses ValidateStatusChange holds to bottom-to-top completion
+	// Defaults to just DONE
+	CompleteStatuses CompleteStatuses
+}
+
+// decompositionReason describes why the task must be broken down before it is completed
+// (e.g. `tagged "epic"`), reporting false if neither its tags nor its kind require it
+func (c TaskValidatorConfig) decompositionReason(task *Task) (string, bool) {
+	for _, kind := range c.DecompositionKinds {
+		if task.Kind() == kind {
+			return fmt.Sprintf("of kind %q", kind), true
+		}
+	}
+	for _, tag := range c.DecompositionTags {
+		if task.HasTag(tag) {
+			return fmt.Sprintf("tagged %q", tag), true
+		}
+	}
+	return "", false
+}
+
+// minDecomposedChildren returns the number of children a decomposed task needs
+func (c TaskValidatorConfig) minDecomposedChildren() int {
+	if c.MinDecomposedChildren < 1 {
+		return 1
+	}
+	return c.MinDecomposedChildren
+}
+
 // taskValidator is the concrete implementation of TaskValidator
 type taskValidator struct {
-	repo TaskRepository
+	repo      TaskRepositoryTx
+	navigator TreeNavigator
+	config    TaskValidatorConfig
+}
+
+// NewTaskValidator creates a new TaskValidator instance with default configuration
+func NewTaskValidator(repo TaskRepositoryTx) TaskValidator {
+	return NewTaskValidatorWithConfig(repo, TaskValidatorConfig{})
 }
 
-// NewTaskValidator creates a new TaskValidator instance
-func NewTaskValidator(repo TaskRepository) TaskValidator {
+// NewTaskValidatorWithConfig creates a new TaskValidator instance with the given configuration
+func NewTaskValidatorWithConfig(repo TaskRepositoryTx, config TaskValidatorConfig) TaskValidator {
 	return &taskValidator{
-		repo: repo,
+		repo:      repo,
+		navigator: NewTreeNavigatorService(repo),
+		config:    config,
 	}
 }
 
 // ValidateStatusChange validates whether a status change is allowed
-// Enforces bottom-to-top completion: a task can only be marked DONE if all children are DONE
-// Non-DONE statuses are allowed regardless of children status
+// Enforces bottom-to-top completion: a task can only be marked complete (see CompleteStatuses)
+// if all children are complete. Incomplete statuses are allowed regardless of children status
+// With DecompositionTags or DecompositionKinds, a tagged task or one of those kinds also needs
+// MinDecomposedChildren children to be completed
 func (v *taskValidator) ValidateStatusChange(task *Task, newStatus Status) error {
-	// Only enforce constraints when changing to DONE status
-	if newStatus != StatusDONE {
-		// Non-DONE statuses (TODO, In Progress, Blocked, Root Work Item) are always allowed
+	// Only enforce constraints when changing to a complete status
+	if !v.config.CompleteStatuses.Contains(newStatus) {
+		// Incomplete statuses (by default TODO, In Progress, Blocked, Root Work Item) are always allowed
 		return nil
 	}
 
-	// For DONE status, check that all children are DONE
+	// For a complete status, check that all children are complete
 	// Get all children of this task
 	children, err := v.repo.FindByParentID(&task.id)
 	if err != nil {
 		return err
 	}
 
-	// Check if any child is not DONE
+	// Collect every incomplete child, so the rejection says which ones block completion
+	var incomplete []*Task
 	for _, child := range children {
-		if child.Status() != StatusDONE {
-			return NewConstraintViolationError(
-				"bottom-to-top-completion",
-				"cannot mark task as DONE when children are not all DONE",
-			)
+		if !v.config.CompleteStatuses.Contains(child.Status()) {
+			incomplete = append(incomplete, child)
 		}
 	}
+	if len(incomplete) > 0 {
+		descriptions := make([]string, len(incomplete))
+		for i, child := range incomplete {
+			descriptions[i] = fmt.Sprintf("%q", child.Description())
+		}
+		return NewConstraintViolationErrorWithTasks(
+			"bottom-to-top-completion",
+			fmt.Sprintf("cannot mark task as DONE when children are not all DONE; incomplete: %s", strings.Join(descriptions, ", ")),
+			incomplete,
+		)
+	}
 
-	// All children are DONE (or task has no children), so DONE status is allowed
+	// A task that must be broken down needs enough children, complete as checked above
+	if reason, ok := v.config.decompositionReason(task); ok && len(children) < v.config.minDecomposedChildren() {
+		return NewConstraintViolationError(
+			"decomposition-required",
+			fmt.Sprintf("cannot mark task %s as DONE with %d children; it must be broken down into at least %d", reason, len(children), v.config.minDecomposedChildren()),
+		)
+	}
+
+	// All children are complete (or task has no children), so the status is allowed
 	return nil
 }
 
@@ -63,7 +174,7 @@ func (v *taskValidator) ValidateStatusChange(task *Task, newStatus Status) error
 func (v *taskValidator) ValidateMove(taskID TaskID, newParentID *TaskID, newPosition int) error {
 	// Validate position is non-negative
 	if newPosition < 0 {
-		return NewValidationError("position", "position must be non-negative")
+		return NewValidationError("position", "negative-position", "position must be non-negative")
 	}
 
 	// Verify the task being moved exists
@@ -109,23 +220,168 @@ func (v *taskValidator) ValidateMove(taskID TaskID, newParentID *TaskID, newPosi
 		)
 	}
 
-	// Validate position is within valid range for the new parent
-	siblings, err := v.repo.FindByParentID(newParentID)
+	if err := v.validateSameRoot(taskID, *newParentID); err != nil {
+		return err
+	}
+
+	// Validate position is within valid range for the new parent, unless it will be clamped
+	if v.config.ClampMovePositions {
+		return nil
+	}
+	maxPosition, err := v.maxMovePosition(task, newParent.ID())
 	if err != nil {
 		return err
 	}
+	if newPosition > maxPosition {
+		return NewValidationError("position", "position-out-of-range", "position exceeds valid range")
+	}
+
+	return nil
+}
+
+// ClampMovePosition returns the position a move will use, clamped to the last valid one
+// when ClampMovePositions is set
+func (v *taskValidator) ClampMovePosition(taskID TaskID, newParentID *TaskID, newPosition int) (int, error) {
+	if !v.config.ClampMovePositions || newParentID == nil {
+		return newPosition, nil
+	}
+
+	task, err := v.repo.FindByID(taskID)
+	if err != nil {
+		return 0, err
+	}
+	maxPosition, err := v.maxMovePosition(task, *newParentID)
+	if err != nil {
+		return 0, err
+	}
+	if newPosition > maxPosition {
+		return maxPosition, nil
+	}
+	return newPosition, nil
+}
+
+// maxMovePosition returns the last valid position for moving task under newParentID
+// Within the same parent it is len(siblings) - 1; under a different parent it is len(siblings)
+func (v *taskValidator) maxMovePosition(task *Task, newParentID TaskID) (int, error) {
+	siblings, err := v.repo.FindByParentID(&newParentID)
+	if err != nil {
+		return 0, err
+	}
 
-	// If moving within the same parent, max position is len(siblings) - 1
-	// If moving to a different parent, max position is len(siblings)
 	maxPosition := len(siblings)
-	if task.ParentID() != nil && task.ParentID().Equals(newParent.ID()) {
+	if task.ParentID() != nil && task.ParentID().Equals(newParentID) {
 		maxPosition = len(siblings) - 1
 	}
+	return maxPosition, nil
+}
 
-	if newPosition > maxPosition {
-		return NewValidationError("position", "position exceeds valid range")
+// ValidateBulkMove validates whether several tasks can be moved together under one parent
+// Every task must exist, no task may be the new parent or one of its ancestors,
+// and no selected task may be a descendant of another selected task
+func (v *taskValidator) ValidateBulkMove(taskIDs []TaskID, newParentID TaskID, startPosition int) error {
+	if len(taskIDs) == 0 {
+		return NewValidationError("ids", "missing-ids", "at least one task ID is required")
+	}
+
+	// Validate position is non-negative
+	if startPosition < 0 {
+		return NewValidationError("startPosition", "negative-position", "position must be non-negative")
+	}
+
+	// Verify the new parent exists
+	if _, err := v.repo.FindByID(newParentID); err != nil {
+		return err
+	}
+
+	selected := make(map[string]bool, len(taskIDs))
+	for _, taskID := range taskIDs {
+		if selected[taskID.String()] {
+			return NewValidationError("ids", "duplicate-ids", "task IDs must be unique")
+		}
+		selected[taskID.String()] = true
+
+		// Verify the task being moved exists
+		if _, err := v.repo.FindByID(taskID); err != nil {
+			return err
+		}
+
+		// Prevent moving a task to itself
+		if taskID.Equals(newParentID) {
+			return NewConstraintViolationError(
+				"cycle-prevention",
+				"cannot move task to itself",
+			)
+		}
+
+		// Prevent creating a cycle: check if newParent is a descendant of task
+		if v.isDescendant(taskID, newParentID) {
+			return NewConstraintViolationError(
+				"cycle-prevention",
+				"cannot move task to its own descendant",
+			)
+		}
+
+		if err := v.validateSameRoot(taskID, newParentID); err != nil {
+			return err
+		}
+	}
+
+	// Prevent overlapping selections: a task moves with its subtree, so selecting
+	// both an ancestor and one of its descendants is ambiguous
+	for _, taskID := range taskIDs {
+		for _, other := range taskIDs {
+			if !taskID.Equals(other) && v.isDescendant(other, taskID) {
+				return NewConstraintViolationError(
+					"overlapping-selection",
+					"cannot move a task together with one of its ancestors",
+				)
+			}
+		}
+	}
+
+	// Validate start position is within valid range for the new parent
+	// Selected tasks already under the new parent do not count towards the range
+	siblings, err := v.repo.FindByParentID(&newParentID)
+	if err != nil {
+		return err
+	}
+
+	remaining := 0
+	for _, sibling := range siblings {
+		if !selected[sibling.ID().String()] {
+			remaining++
+		}
+	}
+
+	if startPosition > remaining {
+		return NewValidationError("startPosition", "position-out-of-range", "position exceeds valid range")
+	}
+
+	return nil
+}
+
+// validateSameRoot rejects moving a task under a parent in another root's tree when
+// cross-root moves are denied; with a single root every move stays in the same tree
+func (v *taskValidator) validateSameRoot(taskID TaskID, newParentID TaskID) error {
+	if v.config.CrossRootMoves != CrossRootMovesDeny {
+		return nil
+	}
+
+	sourceRoot, err := v.navigator.GetRootOf(taskID)
+	if err != nil {
+		return err
+	}
+	targetRoot, err := v.navigator.GetRootOf(newParentID)
+	if err != nil {
+		return err
 	}
 
+	if !sourceRoot.ID().Equals(targetRoot.ID()) {
+		return NewConstraintViolationError(
+			"cross-root-move",
+			fmt.Sprintf("cannot move task into the tree of root %q: moves across root trees are denied", targetRoot.Description()),
+		)
+	}
 	return nil
 }
 
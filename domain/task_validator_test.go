@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -63,6 +64,48 @@ func TestTaskValidator_ValidateStatusChange_DONEWithIncompleteChildren(t *testin
 	}
 }
 
+func TestTaskValidator_ValidateStatusChange_NamesIncompleteChildren(t *testing.T) {
+	// Setup
+	repo := NewInMemoryTaskRepository()
+	validator := NewTaskValidator(repo)
+
+	// Create a parent with one DONE and two incomplete children
+	parent, _ := NewTask("Parent Task", nil, 0)
+	_ = repo.Save(parent)
+
+	done, _ := NewTask("Done child", &parent.id, 0)
+	_ = done.ChangeStatus(StatusDONE)
+	_ = repo.Save(done)
+
+	todo, _ := NewTask("Todo child", &parent.id, 1)
+	_ = repo.Save(todo)
+
+	inProgress, _ := NewTask("Started child", &parent.id, 2)
+	_ = inProgress.ChangeStatus(StatusInProgress)
+	_ = repo.Save(inProgress)
+
+	err := validator.ValidateStatusChange(parent, StatusDONE)
+	violation, ok := err.(ConstraintViolationError)
+	if !ok {
+		t.Fatalf("Expected ConstraintViolationError, but got: %v", err)
+	}
+
+	// Both incomplete children are named, in position order; the DONE child is not
+	details := violation.Details()
+	if len(details) != 2 {
+		t.Fatalf("Expected 2 incomplete children in the details, got %d", len(details))
+	}
+	if !details[0].TaskID.Equals(todo.ID()) || details[0].Description != "Todo child" || details[0].Status != StatusTODO {
+		t.Errorf("Unexpected first detail: %+v", details[0])
+	}
+	if !details[1].TaskID.Equals(inProgress.ID()) || details[1].Description != "Started child" || details[1].Status != StatusInProgress {
+		t.Errorf("Unexpected second detail: %+v", details[1])
+	}
+	if !strings.Contains(violation.Message, `"Todo child"`) || !strings.Contains(violation.Message, `"Started child"`) {
+		t.Errorf("Expected the message to name both children, got %q", violation.Message)
+	}
+}
+
 func TestTaskValidator_ValidateStatusChange_DONEWithAllChildrenDone(t *testing.T) {
 	// Setup
 	repo := NewInMemoryTaskRepository()
@@ -103,6 +146,88 @@ func TestTaskValidator_ValidateStatusChange_DONEWithNoChildren(t *testing.T) {
 	}
 }
 
+// TestTaskValidator_ValidateStatusChange_DecompositionTags tests that a tagged task needs children before it can be DONE
+func TestTaskValidator_ValidateStatusChange_DecompositionTags(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	config := TaskValidatorConfig{DecompositionTags: []string{"epic"}}
+
+	root, _ := NewTask("Root", nil, 0)
+	rootID := root.ID()
+	epic, _ := NewTask("Checkout epic", &rootID, 0)
+	epic.UpdateTags([]string{"epic"}, nil)
+	leaf, _ := NewTask("Plain task", &rootID, 1)
+	_ = repo.SaveAll([]*Task{root, epic, leaf})
+
+	// A tagged task without children cannot be completed
+	err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(epic, StatusDONE)
+	violation, ok := err.(ConstraintViolationError)
+	if !ok || violation.Constraint != "decomposition-required" {
+		t.Fatalf("Expected decomposition-required violation, got: %v", err)
+	}
+
+	// Incomplete statuses and untagged leaves are unaffected
+	if err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(epic, StatusInProgress); err != nil {
+		t.Errorf("Expected In Progress to be allowed, got: %v", err)
+	}
+	if err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(leaf, StatusDONE); err != nil {
+		t.Errorf("Expected an untagged leaf to be completable, got: %v", err)
+	}
+
+	// Without the rule, the tagged task is a plain leaf
+	if err := NewTaskValidator(repo).ValidateStatusChange(epic, StatusDONE); err != nil {
+		t.Errorf("Expected DONE to be allowed without decomposition tags, got: %v", err)
+	}
+
+	// One DONE child is enough by default; a higher minimum needs more
+	epicID := epic.ID()
+	child, _ := NewTask("Pay with card", &epicID, 0)
+	_ = child.ChangeStatus(StatusDONE)
+	_ = repo.Save(child)
+	if err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(epic, StatusDONE); err != nil {
+		t.Errorf("Expected DONE to be allowed with a DONE child, got: %v", err)
+	}
+	config.MinDecomposedChildren = 2
+	if err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(epic, StatusDONE); err == nil {
+		t.Errorf("Expected DONE to be rejected with 1 of 2 required children")
+	}
+}
+
+// TestTaskValidator_ValidateStatusChange_DecompositionKinds tests that a task of a decomposition kind needs children before it can be DONE
+func TestTaskValidator_ValidateStatusChange_DecompositionKinds(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	config := TaskValidatorConfig{DecompositionKinds: []string{"milestone"}}
+
+	root, _ := NewTask("Root", nil, 0)
+	rootID := root.ID()
+	milestone, _ := NewTask("Public beta", &rootID, 0)
+	milestone.UpdateKind("milestone")
+	spike, _ := NewTask("Try the new parser", &rootID, 1)
+	spike.UpdateKind("spike")
+	_ = repo.SaveAll([]*Task{root, milestone, spike})
+
+	err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(milestone, StatusDONE)
+	violation, ok := err.(ConstraintViolationError)
+	if !ok || violation.Constraint != "decomposition-required" {
+		t.Fatalf("Expected decomposition-required violation, got: %v", err)
+	}
+	if !strings.Contains(violation.Message, `of kind "milestone"`) {
+		t.Errorf("Expected the message to name the kind, got: %s", violation.Message)
+	}
+
+	// Tasks of other kinds are unaffected
+	if err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(spike, StatusDONE); err != nil {
+		t.Errorf("Expected a spike leaf to be completable, got: %v", err)
+	}
+
+	milestoneID := milestone.ID()
+	child, _ := NewTask("Invite testers", &milestoneID, 0)
+	_ = child.ChangeStatus(StatusDONE)
+	_ = repo.Save(child)
+	if err := NewTaskValidatorWithConfig(repo, config).ValidateStatusChange(milestone, StatusDONE); err != nil {
+		t.Errorf("Expected DONE to be allowed with a DONE child, got: %v", err)
+	}
+}
+
 // TestTaskValidator_ValidateStatusChange_TaskNotFound is no longer needed
 // since the validator now receives a Task object instead of TaskID.
 // The task lookup is now done in the service layer before calling the validator.
@@ -409,3 +534,60 @@ func TestTaskValidator_ValidateMove_PositionExceedsRange(t *testing.T) {
 		t.Errorf("Expected ValidationError, got %T", err)
 	}
 }
+
+func TestTaskValidator_ValidateMove_CrossRootMoves(t *testing.T) {
+	// Setup two trees: rootA -> a1, a2; rootB -> b1
+	repo := NewInMemoryTaskRepository()
+
+	rootA, _ := NewTask("Root A", nil, 0)
+	a1, _ := NewTask("A1", &rootA.id, 0)
+	a2, _ := NewTask("A2", &rootA.id, 1)
+	rootB, _ := NewTask("Root B", nil, 0)
+	b1, _ := NewTask("B1", &rootB.id, 0)
+	for _, task := range []*Task{rootA, a1, a2, rootB, b1} {
+		_ = repo.Save(task)
+	}
+
+	tests := []struct {
+		name        string
+		policy      CrossRootMovePolicy
+		newParent   *Task
+		expectError bool
+	}{
+		{"SameTreeAllowedWhenDenied", CrossRootMovesDeny, a2, false},
+		{"AcrossTreesDenied", CrossRootMovesDeny, b1, true},
+		{"AcrossTreesAllowed", CrossRootMovesAllow, b1, false},
+		{"AcrossTreesAllowedByDefault", "", rootB, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewTaskValidatorWithConfig(repo, TaskValidatorConfig{CrossRootMoves: tt.policy})
+
+			err := validator.ValidateMove(a1.ID(), &tt.newParent.id, 0)
+			if !tt.expectError {
+				if err != nil {
+					t.Errorf("Expected move to be allowed, got %v", err)
+				}
+				return
+			}
+
+			constraintErr, ok := err.(ConstraintViolationError)
+			if !ok {
+				t.Fatalf("Expected ConstraintViolationError, got %T (%v)", err, err)
+			}
+			if constraintErr.Constraint != "cross-root-move" {
+				t.Errorf("Expected cross-root-move constraint, got %s", constraintErr.Constraint)
+			}
+		})
+	}
+
+	// Bulk moves follow the same rule
+	validator := NewTaskValidatorWithConfig(repo, TaskValidatorConfig{CrossRootMoves: CrossRootMovesDeny})
+	if err := validator.ValidateBulkMove([]TaskID{a1.ID(), a2.ID()}, b1.ID(), 0); err == nil {
+		t.Error("Expected bulk move across trees to be denied, got nil")
+	}
+	if err := validator.ValidateBulkMove([]TaskID{a1.ID()}, a2.ID(), 0); err != nil {
+		t.Errorf("Expected bulk move within the tree to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// TimestampPrecision is the precision task timestamps are kept at
+// Coarser than the clock, so a timestamp survives any encoding round trip unchanged
+const TimestampPrecision = time.Microsecond
+
+// NormalizeTimestamp returns t in UTC, truncated to TimestampPrecision and without a
+// monotonic clock reading, so equal instants always compare and encode identically
+func NormalizeTimestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(TimestampPrecision)
+}
+
+// currentTimestamp returns the current time as a normalized task timestamp
+func currentTimestamp() time.Time {
+	return NormalizeTimestamp(time.Now())
+}
@@ -0,0 +1,235 @@
+package domain
+
+// TreeImportNode describes a task and its children for bulk import as a nested tree
+type TreeImportNode struct {
+	Description string
+	Status      *Status // nil uses the default status for the node's place in the tree
+	Children    []TreeImportNode
+}
+
+// Count returns the number of tasks described by the node and all its descendants
+func (n TreeImportNode) Count() int {
+	count := 1
+	for _, child := range n.Children {
+		count += child.Count()
+	}
+	return count
+}
+
+// ImportTree creates a complete tree from a nested description
+// The whole tree is validated before any task is persisted, and all tasks are persisted at once
+// Enforces the single-root constraint and the tree invariants checked by CheckTreeInvariants
+// The root check and the writes run in one transaction
+func (s *TaskService) ImportTree(root TreeImportNode) ([]*Task, error) {
+	var tasks []*Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		tasks, err = tx.importTree(root)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// importTree implements ImportTree within a transaction
+func (s *TaskService) importTree(root TreeImportNode) ([]*Task, error) {
+	// Check if a root task already exists
+	existingRoot, err := s.repo.FindRoot()
+	if err == nil && existingRoot != nil {
+		return nil, NewConstraintViolationError("single_root", "root task already exists")
+	}
+	if err != nil {
+		if _, ok := err.(NotFoundError); !ok {
+			return nil, err
+		}
+	}
+
+	// Build and validate the entire tree before persisting anything
+	tasks, err := s.buildImportTree(root)
+	if err != nil {
+		return nil, err
+	}
+	if violations := rootStatusViolations(tasks); len(violations) > 0 {
+		return nil, NewValidationError("status", violations[0].Constraint, violations[0].Message)
+	}
+	if violations := CheckTreeInvariants(tasks, s.config.CompleteStatuses); len(violations) > 0 {
+		return nil, violations[0].Err()
+	}
+
+	// Ensure the store has room for the whole tree
+	if err := s.ensureCapacity(len(tasks)); err != nil {
+		return nil, err
+	}
+
+	// Persist every task in a single operation
+	if err := s.repo.SaveAll(tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ImportSubtree creates a nested tree under an existing parent, with fresh IDs
+// The subtree is placed at position among the parent's children, or appended when position is nil;
+// existing children at or after the position shift right. The subtree's top node cannot be a
+// Root Work Item, and all tasks are validated before any is persisted
+// A DONE parent is handled per DoneParentPolicy, as for CreateChildTask, unless the whole subtree is DONE
+func (s *TaskService) ImportSubtree(node TreeImportNode, parentID TaskID, position *int) ([]*Task, error) {
+	var tasks []*Task
+	err := s.inTransaction(func(tx *TaskService) error {
+		var err error
+		tasks, err = tx.importSubtree(node, parentID, position)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// importSubtree implements ImportSubtree within a transaction
+func (s *TaskService) importSubtree(node TreeImportNode, parentID TaskID, position *int) ([]*Task, error) {
+	if err := s.validateImportNode(node); err != nil {
+		return nil, err
+	}
+
+	parent, err := s.repo.FindByID(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.FindByParentID(&parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Place the subtree like CreateChildTaskAt places a single child
+	topPosition := len(children)
+	if position != nil {
+		if *position < 0 {
+			return nil, NewValidationError("position", "negative-position", "position must be non-negative")
+		}
+		if *position > len(children) {
+			return nil, NewValidationError("position", "position-out-of-range", "position exceeds valid range")
+		}
+		topPosition = *position
+	}
+
+	// Build the entire subtree before persisting anything
+	tasks, err := s.buildImportNode(node, &parentID, topPosition, make([]*Task, 0, node.Count()))
+	if err != nil {
+		return nil, err
+	}
+	if violations := rootStatusViolations(tasks); len(violations) > 0 {
+		return nil, NewValidationError("status", violations[0].Constraint, violations[0].Message)
+	}
+	for _, violation := range CheckTreeInvariants(tasks, s.config.CompleteStatuses) {
+		if violation.Constraint == "bottom-to-top-completion" {
+			return nil, violation.Err()
+		}
+	}
+
+	// An incomplete subtree under a DONE parent would violate bottom-to-top completion
+	var reopened []*Task
+	if s.config.CompleteStatuses.Contains(parent.Status()) && !s.config.CompleteStatuses.Contains(tasks[0].Status()) {
+		if s.config.DoneParentPolicy != DoneParentReopen {
+			return nil, NewConstraintViolationError(
+				"done-parent",
+				"cannot create a child under a task that is DONE",
+			)
+		}
+		if reopened, err = s.reopenDoneAncestors(parent); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ensure the store has room for the whole subtree
+	if err := s.ensureCapacity(len(tasks)); err != nil {
+		return nil, err
+	}
+
+	// Make room among the existing children
+	shifted, err := shiftSiblingsRight(children, topPosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist the subtree together with any shifted siblings and reopened ancestors
+	toSave := append(append([]*Task{}, tasks...), shifted...)
+	if err := s.repo.SaveAll(append(toSave, reopened...)); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ValidateTree checks a nested tree against the tree invariants without creating anything
+// Malformed nodes (empty descriptions, invalid statuses) are returned as a ValidationError;
+// broken invariants, including a misplaced Root Work Item status, are returned as violations,
+// which is empty for a valid tree
+func (s *TaskService) ValidateTree(root TreeImportNode) ([]InvariantViolation, error) {
+	tasks, err := s.buildImportTree(root)
+	if err != nil {
+		return nil, err
+	}
+	return append(rootStatusViolations(tasks), CheckTreeInvariants(tasks, s.config.CompleteStatuses)...), nil
+}
+
+// buildImportTree validates every node and builds the tasks for a nested tree without persisting them
+func (s *TaskService) buildImportTree(root TreeImportNode) ([]*Task, error) {
+	if err := s.validateImportNode(root); err != nil {
+		return nil, err
+	}
+
+	// Build the tasks top-down so children can reference their parent IDs
+	tasks := make([]*Task, 0, root.Count())
+	return s.buildImportNode(root, nil, 0, tasks)
+}
+
+// validateImportNode validates the descriptions and statuses of a node and its descendants without
+// creating any tasks; where Root Work Item may appear is checked on the built tasks by rootStatusViolations
+func (s *TaskService) validateImportNode(node TreeImportNode) error {
+	if _, err := NewTask(s.normalizeDescription(node.Description), nil, 0); err != nil {
+		return err
+	}
+
+	if node.Status != nil && !node.Status.IsValid() {
+		return NewValidationError("status", "invalid-status", "invalid status value")
+	}
+
+	for _, child := range node.Children {
+		if err := s.validateImportNode(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildImportNode creates the task for a node and its descendants, appending them to tasks
+func (s *TaskService) buildImportNode(node TreeImportNode, parentID *TaskID, position int, tasks []*Task) ([]*Task, error) {
+	task, err := s.newTask(node.Description, parentID, position)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Status != nil && *node.Status != task.Status() {
+		if err := task.ChangeStatusWith(*node.Status, s.config.CompleteStatuses); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks = append(tasks, task)
+
+	taskID := task.ID()
+	for i, child := range node.Children {
+		tasks, err = s.buildImportNode(child, &taskID, i, tasks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, nil
+}
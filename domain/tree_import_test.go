@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestTaskService_ImportTree_Success(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	done := StatusDONE
+	root := TreeImportNode{
+		Description: "Root",
+		Children: []TreeImportNode{
+			{Description: "First", Status: &done},
+			{Description: "Second", Children: []TreeImportNode{{Description: "Nested"}}},
+		},
+	}
+
+	tasks, err := service.ImportTree(root)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("expected 4 tasks, got %d", len(tasks))
+	}
+
+	// Verify structure and default statuses
+	storedRoot, err := repo.FindRoot()
+	if err != nil {
+		t.Fatalf("expected root to exist, got %v", err)
+	}
+	if storedRoot.Status() != StatusRootWorkItem {
+		t.Errorf("expected root status %v, got %v", StatusRootWorkItem, storedRoot.Status())
+	}
+
+	children, _ := repo.FindByParentID(&storedRoot.id)
+	if len(children) != 2 {
+		t.Fatalf("expected 2 root children, got %d", len(children))
+	}
+	if children[0].Description() != "First" || children[0].Status() != StatusDONE {
+		t.Errorf("expected DONE 'First' at position 0, got %q (%v)", children[0].Description(), children[0].Status())
+	}
+	if children[1].Description() != "Second" || children[1].Status() != StatusTODO {
+		t.Errorf("expected TODO 'Second' at position 1, got %q (%v)", children[1].Description(), children[1].Status())
+	}
+}
+
+func TestTaskService_ImportTree_InvalidTreeCreatesNothing(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	root := TreeImportNode{
+		Description: "Root",
+		Children: []TreeImportNode{
+			{Description: "Valid"},
+			{Description: "   "},
+		},
+	}
+
+	_, err := service.ImportTree(root)
+	if err == nil {
+		t.Fatal("expected error for empty description, got nil")
+	}
+	if _, ok := err.(ValidationError); !ok {
+		t.Errorf("expected ValidationError, got %T", err)
+	}
+
+	all, _ := repo.FindAll()
+	if len(all) != 0 {
+		t.Errorf("expected no tasks to be created, got %d", len(all))
+	}
+}
+
+func TestTaskService_ImportTree_ExistingRoot(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	if _, err := service.CreateRootTask("Existing"); err != nil {
+		t.Fatalf("failed to create root: %v", err)
+	}
+
+	_, err := service.ImportTree(TreeImportNode{Description: "Imported"})
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Errorf("expected ConstraintViolationError, got %T", err)
+	}
+}
+
+func TestTaskService_ImportSubtree_TwoLevelsUnderExistingParent(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	// Create tree: root -> a, b
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	b, _ := service.CreateChildTask("B", root.ID())
+
+	done := StatusDONE
+	node := TreeImportNode{
+		Description: "Scaffold",
+		Children: []TreeImportNode{
+			{Description: "Research", Status: &done},
+			{Description: "Build"},
+		},
+	}
+
+	// Insert between a and b
+	position := 1
+	tasks, err := service.ImportSubtree(node, root.ID(), &position)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+
+	top := tasks[0]
+	if top.Description() != "Scaffold" || !top.ParentID().Equals(root.ID()) || top.Position() != 1 {
+		t.Errorf("expected Scaffold under root at 1, got %s at %d", top.Description(), top.Position())
+	}
+	if top.Status() != StatusTODO {
+		t.Errorf("expected default status %v, got %v", StatusTODO, top.Status())
+	}
+	if top.ID().Equals(a.ID()) || top.ID().Equals(b.ID()) {
+		t.Error("expected a fresh ID")
+	}
+
+	children, _ := repo.FindByParentID(&top.id)
+	if len(children) != 2 || children[0].Description() != "Research" || children[1].Description() != "Build" {
+		t.Fatalf("expected Research and Build under Scaffold, got %v", children)
+	}
+	if children[0].Status() != StatusDONE {
+		t.Errorf("expected Research to be DONE, got %v", children[0].Status())
+	}
+
+	// b shifted right to make room
+	storedB, _ := repo.FindByID(b.ID())
+	if storedB.Position() != 2 {
+		t.Errorf("expected B at 2, got %d", storedB.Position())
+	}
+
+	if violations := CheckTreeInvariants(mustFindAll(t, repo), CompleteStatuses{}); len(violations) != 0 {
+		t.Errorf("expected a valid tree, got %v", violations)
+	}
+
+	// Instantiating again creates a second, independent copy appended at the end
+	again, err := service.ImportSubtree(node, root.ID(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if again[0].ID().Equals(top.ID()) || again[0].Position() != 3 {
+		t.Errorf("expected a fresh copy at 3, got position %d", again[0].Position())
+	}
+}
+
+func TestTaskService_ImportSubtree_Rejections(t *testing.T) {
+	rootWorkItem := StatusRootWorkItem
+	done := StatusDONE
+
+	tests := []struct {
+		name       string
+		node       TreeImportNode
+		parentDone bool
+		constraint string // empty for a ValidationError
+	}{
+		{"RootWorkItemTop", TreeImportNode{Description: "Top", Status: &rootWorkItem}, false, ""},
+		{"DoneOverIncomplete", TreeImportNode{Description: "Top", Status: &done, Children: []TreeImportNode{{Description: "Child"}}}, false, "bottom-to-top-completion"},
+		{"IncompleteUnderDoneParent", TreeImportNode{Description: "Top"}, true, "done-parent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewInMemoryTaskRepository()
+			service := NewTaskService(repo)
+			root, _ := service.CreateRootTask("Root")
+			parent, _ := service.CreateChildTask("Parent", root.ID())
+			if tt.parentDone {
+				if err := service.ChangeTaskStatus(parent.ID(), StatusDONE); err != nil {
+					t.Fatalf("failed to complete parent: %v", err)
+				}
+			}
+
+			_, err := service.ImportSubtree(tt.node, parent.ID(), nil)
+			if tt.constraint == "" {
+				if _, ok := err.(ValidationError); !ok {
+					t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+				}
+			} else {
+				constraintErr, ok := err.(ConstraintViolationError)
+				if !ok {
+					t.Fatalf("expected ConstraintViolationError, got %T (%v)", err, err)
+				}
+				if constraintErr.Constraint != tt.constraint {
+					t.Errorf("expected %s constraint, got %s", tt.constraint, constraintErr.Constraint)
+				}
+			}
+
+			// Nothing was created
+			if count, _ := repo.Count(); count != 2 {
+				t.Errorf("expected 2 tasks, got %d", count)
+			}
+		})
+	}
+}
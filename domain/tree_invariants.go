@@ -0,0 +1,271 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+// InvariantViolation describes a single broken tree invariant
+type InvariantViolation struct {
+	Constraint string  // Name of the violated constraint (e.g. "single-root", "position-gap")
+	TaskID     *TaskID // Task the violation was found on, nil for tree-wide violations
+	Message    string
+}
+
+// Err converts the violation to a ConstraintViolationError
+func (v InvariantViolation) Err() error {
+	return NewConstraintViolationError(v.Constraint, v.Message)
+}
+
+// CheckTreeInvariants checks a complete collection of tasks against the tree invariants
+// Returns every violation found, or an empty slice if the tasks form a valid tree:
+//   - exactly one root task ("single-root")
+//   - every parent exists in the collection ("missing-parent")
+//   - no task is its own ancestor ("cycle-detected")
+//   - sibling positions are exactly 0..n-1 ("position-gap")
+//   - a complete task has only complete children ("bottom-to-top-completion"), where complete
+//     means one of the given complete statuses
+func CheckTreeInvariants(tasks []*Task, complete CompleteStatuses) []InvariantViolation {
+	violations := make([]InvariantViolation, 0)
+
+	byID := make(map[TaskID]*Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.id] = task
+	}
+
+	// Single root
+	roots := 0
+	for _, task := range tasks {
+		if task.parentID == nil {
+			roots++
+		}
+	}
+	if len(tasks) > 0 && roots != 1 {
+		violations = append(violations, InvariantViolation{
+			Constraint: "single-root",
+			Message:    fmt.Sprintf("tree must have exactly one root task, found %d", roots),
+		})
+	}
+
+	// Missing parents
+	for _, task := range tasks {
+		if task.parentID == nil {
+			continue
+		}
+		if _, ok := byID[*task.parentID]; !ok {
+			violations = append(violations, InvariantViolation{
+				Constraint: "missing-parent",
+				TaskID:     taskIDRef(task.id),
+				Message:    fmt.Sprintf("task %q references parent %s, which does not exist", task.description, task.parentID),
+			})
+		}
+	}
+
+	// Cycles, reported once per cycle
+	inCycle := make(map[TaskID]bool)
+	for _, task := range tasks {
+		path := map[TaskID]bool{task.id: true}
+		current := task
+		for current.parentID != nil {
+			parent, ok := byID[*current.parentID]
+			if !ok || inCycle[parent.id] {
+				break
+			}
+			if path[parent.id] {
+				// Mark every task on the cycle so it is not reported again
+				for member := parent; !inCycle[member.id]; member = byID[*member.parentID] {
+					inCycle[member.id] = true
+				}
+				violations = append(violations, InvariantViolation{
+					Constraint: "cycle-detected",
+					TaskID:     taskIDRef(parent.id),
+					Message:    fmt.Sprintf("task %q is its own ancestor", parent.description),
+				})
+				break
+			}
+			path[parent.id] = true
+			current = parent
+		}
+	}
+
+	// Sibling positions and bottom-to-top completion, checked per parent
+	children := make(map[TaskID][]*Task)
+	parentOrder := make([]TaskID, 0)
+	for _, task := range tasks {
+		if task.parentID == nil {
+			continue
+		}
+		if _, ok := children[*task.parentID]; !ok {
+			parentOrder = append(parentOrder, *task.parentID)
+		}
+		children[*task.parentID] = append(children[*task.parentID], task)
+	}
+
+	for _, parentID := range parentOrder {
+		siblings := children[parentID]
+		parent, ok := byID[parentID]
+		if !ok {
+			// Already reported as a missing parent
+			continue
+		}
+
+		if v, ok := checkSiblingPositions(parent, siblings); !ok {
+			violations = append(violations, v)
+		}
+
+		if !complete.Contains(parent.status) {
+			continue
+		}
+		for _, child := range siblings {
+			if !complete.Contains(child.status) {
+				violations = append(violations, InvariantViolation{
+					Constraint: "bottom-to-top-completion",
+					TaskID:     taskIDRef(parent.id),
+					Message:    fmt.Sprintf("task %q is %s but child %q is %s", parent.description, parent.status, child.description, child.status),
+				})
+				break
+			}
+		}
+	}
+
+	return violations
+}
+
+// rootStatusViolations reports imported tasks whose status does not match their place in the tree:
+// the root must be a Root Work Item and no other task may be one ("root-status")
+func rootStatusViolations(tasks []*Task) []InvariantViolation {
+	violations := make([]InvariantViolation, 0)
+	for _, task := range tasks {
+		var message string
+		switch {
+		case task.parentID == nil && task.status != StatusRootWorkItem:
+			message = "root task status must be Root Work Item"
+		case task.parentID != nil && task.status == StatusRootWorkItem:
+			message = "only the root task can be a Root Work Item"
+		default:
+			continue
+		}
+		violations = append(violations, InvariantViolation{
+			Constraint: "root-status",
+			TaskID:     taskIDRef(task.id),
+			Message:    fmt.Sprintf("task %q: %s", task.description, message),
+		})
+	}
+	return violations
+}
+
+// checkSiblingPositions reports whether the children of parent occupy exactly positions 0..n-1
+func checkSiblingPositions(parent *Task, siblings []*Task) (InvariantViolation, bool) {
+	positions := make([]int, len(siblings))
+	for i, sibling := range siblings {
+		positions[i] = sibling.position
+	}
+	sort.Ints(positions)
+
+	for i, position := range positions {
+		if position != i {
+			return InvariantViolation{
+				Constraint: "position-gap",
+				TaskID:     taskIDRef(parent.id),
+				Message:    fmt.Sprintf("positions %v of the children of %q are not contiguous from 0", positions, parent.description),
+			}, false
+		}
+	}
+
+	return InvariantViolation{}, true
+}
+
+// taskIDRef returns a pointer to a copy of the given task ID
+func taskIDRef(id TaskID) *TaskID {
+	return &id
+}
+
+// DuplicatePosition is a group of siblings sharing one position
+type DuplicatePosition struct {
+	ParentID TaskID
+	Position int
+	Tasks    []*Task // ordered by creation time
+}
+
+// TreeDiagnostics details what is wrong with a collection of tasks, for operators deciding
+// whether to repair it
+type TreeDiagnostics struct {
+	TaskCount          int                  // number of tasks checked
+	Violations         []InvariantViolation // everything CheckTreeInvariants reports
+	Orphans            []*Task              // tasks whose parent does not exist
+	Cycles             []*Task              // one task on each cycle
+	Roots              []*Task              // every root, when there is not exactly one
+	DuplicatePositions []DuplicatePosition  // sibling groups sharing a position
+}
+
+// Healthy reports whether no invariant is violated
+func (d TreeDiagnostics) Healthy() bool {
+	return len(d.Violations) == 0
+}
+
+// DiagnoseTree checks tasks with CheckTreeInvariants and collects the tasks involved in
+// orphans, cycles, multiple roots and duplicate positions; it changes nothing
+func DiagnoseTree(tasks []*Task, complete CompleteStatuses) TreeDiagnostics {
+	diagnostics := TreeDiagnostics{TaskCount: len(tasks), Violations: CheckTreeInvariants(tasks, complete)}
+
+	byID := make(map[TaskID]*Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.id] = task
+	}
+	for _, violation := range diagnostics.Violations {
+		if violation.TaskID == nil {
+			continue
+		}
+		switch violation.Constraint {
+		case "missing-parent":
+			diagnostics.Orphans = append(diagnostics.Orphans, byID[*violation.TaskID])
+		case "cycle-detected":
+			diagnostics.Cycles = append(diagnostics.Cycles, byID[*violation.TaskID])
+		}
+	}
+
+	var roots []*Task
+	type siblingSlot struct {
+		parentID TaskID
+		position int
+	}
+	slots := make(map[siblingSlot][]*Task)
+	for _, task := range tasks {
+		if task.parentID == nil {
+			roots = append(roots, task)
+			continue
+		}
+		slot := siblingSlot{*task.parentID, task.position}
+		slots[slot] = append(slots[slot], task)
+	}
+	if len(tasks) > 0 && len(roots) != 1 {
+		sortByCreation(roots)
+		diagnostics.Roots = roots
+	}
+	for slot, siblings := range slots {
+		if len(siblings) > 1 {
+			sortByCreation(siblings)
+			diagnostics.DuplicatePositions = append(diagnostics.DuplicatePositions, DuplicatePosition{
+				ParentID: slot.parentID,
+				Position: slot.position,
+				Tasks:    siblings,
+			})
+		}
+	}
+	sort.Slice(diagnostics.DuplicatePositions, func(i, j int) bool {
+		a, b := diagnostics.DuplicatePositions[i], diagnostics.DuplicatePositions[j]
+		if a.ParentID != b.ParentID {
+			return a.ParentID.String() < b.ParentID.String()
+		}
+		return a.Position < b.Position
+	})
+
+	return diagnostics
+}
+
+// sortByCreation orders tasks by creation time, tie-broken by ID
+func sortByCreation(tasks []*Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedBefore(tasks[j])
+	})
+}
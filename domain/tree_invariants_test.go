@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"testing"
+)
+
+// violationConstraints returns the constraint names of the given violations
+func violationConstraints(violations []InvariantViolation) []string {
+	constraints := make([]string, len(violations))
+	for i, v := range violations {
+		constraints[i] = v.Constraint
+	}
+	return constraints
+}
+
+func TestCheckTreeInvariants_ValidTree(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 1)
+	a1, _ := NewTask("A1", &a.id, 0)
+
+	violations := CheckTreeInvariants([]*Task{root, a, b, a1}, CompleteStatuses{})
+	if len(violations) != 0 {
+		t.Errorf("expected no violations, got %v", violations)
+	}
+}
+
+func TestCheckTreeInvariants_MultipleRoots(t *testing.T) {
+	root1, _ := NewTask("Root 1", nil, 0)
+	root2, _ := NewTask("Root 2", nil, 0)
+
+	violations := CheckTreeInvariants([]*Task{root1, root2}, CompleteStatuses{})
+	if len(violations) != 1 || violations[0].Constraint != "single-root" {
+		t.Errorf("expected a single-root violation, got %v", violationConstraints(violations))
+	}
+}
+
+func TestCheckTreeInvariants_Cycle(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &a.id, 0)
+	// Close the loop a -> b -> a
+	a.parentID = &b.id
+
+	violations := CheckTreeInvariants([]*Task{root, a, b}, CompleteStatuses{})
+	cycles := 0
+	for _, v := range violations {
+		if v.Constraint == "cycle-detected" {
+			cycles++
+		}
+	}
+	if cycles != 1 {
+		t.Errorf("expected the cycle to be reported once, got %v", violationConstraints(violations))
+	}
+}
+
+func TestCheckTreeInvariants_PositionGap(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	b, _ := NewTask("B", &root.id, 2)
+
+	violations := CheckTreeInvariants([]*Task{root, a, b}, CompleteStatuses{})
+	if len(violations) != 1 || violations[0].Constraint != "position-gap" {
+		t.Fatalf("expected a position-gap violation, got %v", violationConstraints(violations))
+	}
+	if violations[0].TaskID == nil || !violations[0].TaskID.Equals(root.ID()) {
+		t.Errorf("expected violation on the parent task")
+	}
+}
+
+func TestCheckTreeInvariants_DoneOverIncomplete(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	a, _ := NewTask("A", &root.id, 0)
+	a1, _ := NewTask("A1", &a.id, 0)
+	_ = a.ChangeStatus(StatusDONE)
+
+	violations := CheckTreeInvariants([]*Task{root, a, a1}, CompleteStatuses{})
+	if len(violations) != 1 || violations[0].Constraint != "bottom-to-top-completion" {
+		t.Errorf("expected a bottom-to-top-completion violation, got %v", violationConstraints(violations))
+	}
+}
+
+func TestCheckTreeInvariants_MissingParent(t *testing.T) {
+	root, _ := NewTask("Root", nil, 0)
+	missing := NewTaskID()
+	orphan, _ := NewTask("Orphan", &missing, 0)
+
+	violations := CheckTreeInvariants([]*Task{root, orphan}, CompleteStatuses{})
+	if len(violations) != 1 || violations[0].Constraint != "missing-parent" {
+		t.Errorf("expected a missing-parent violation, got %v", violationConstraints(violations))
+	}
+}
+
+func TestTaskService_ValidateTree(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	service := NewTaskService(repo)
+
+	done := StatusDONE
+	root := TreeImportNode{
+		Description: "Root",
+		Children: []TreeImportNode{
+			{Description: "Done over incomplete", Status: &done, Children: []TreeImportNode{{Description: "Open"}}},
+			{Description: "Fine"},
+		},
+	}
+
+	violations, err := service.ValidateTree(root)
+	if err != nil {
+		t.Fatalf("ValidateTree failed: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Constraint != "bottom-to-top-completion" {
+		t.Errorf("expected a bottom-to-top-completion violation, got %v", violationConstraints(violations))
+	}
+
+	// Validation never creates tasks
+	all, _ := repo.FindAll()
+	if len(all) != 0 {
+		t.Errorf("expected no tasks to be created, got %d", len(all))
+	}
+}
+
+func TestTaskService_ValidateTree_RootStatus(t *testing.T) {
+	service := NewTaskService(NewInMemoryTaskRepository())
+
+	todo, rootWorkItem := StatusTODO, StatusRootWorkItem
+	root := TreeImportNode{
+		Description: "Root",
+		Status:      &todo,
+		Children:    []TreeImportNode{{Description: "A", Status: &rootWorkItem}},
+	}
+
+	// Misplaced Root Work Item statuses are violations, not request errors
+	violations, err := service.ValidateTree(root)
+	if err != nil {
+		t.Fatalf("ValidateTree failed: %v", err)
+	}
+	constraints := violationConstraints(violations)
+	if len(constraints) != 2 || constraints[0] != "root-status" || constraints[1] != "root-status" {
+		t.Errorf("expected two root-status violations, got %v", constraints)
+	}
+
+	// Importing the same tree is still rejected
+	if _, err := service.ImportTree(root); err == nil {
+		t.Error("expected ImportTree to reject a misplaced Root Work Item status")
+	} else if _, ok := err.(ValidationError); !ok {
+		t.Errorf("expected a ValidationError, got %T", err)
+	}
+}
+
+func TestDiagnoseTree(t *testing.T) {
+	t.Run("valid tree is healthy", func(t *testing.T) {
+		root, _ := NewTask("Root", nil, 0)
+		a, _ := NewTask("A", &root.id, 0)
+
+		diagnostics := DiagnoseTree([]*Task{root, a}, CompleteStatuses{})
+		if !diagnostics.Healthy() || diagnostics.TaskCount != 2 {
+			t.Errorf("expected a healthy report of 2 tasks, got %+v", diagnostics)
+		}
+		if diagnostics.Roots != nil {
+			t.Errorf("expected a single root not to be listed, got %v", diagnostics.Roots)
+		}
+	})
+
+	t.Run("collects the tasks involved", func(t *testing.T) {
+		root, _ := NewTask("Root", nil, 0)
+		other, _ := NewTask("Other root", nil, 0)
+		a, _ := NewTask("A", &root.id, 0)
+		b, _ := NewTask("B", &root.id, 0)
+		missing := NewTaskID()
+		orphan, _ := NewTask("Orphan", &missing, 0)
+		c, _ := NewTask("C", &root.id, 1)
+		d, _ := NewTask("D", &c.id, 0)
+		// Close the loop c -> d -> c
+		c.parentID = &d.id
+
+		diagnostics := DiagnoseTree([]*Task{root, other, a, b, orphan, c, d}, CompleteStatuses{})
+		if diagnostics.Healthy() {
+			t.Fatal("expected an unhealthy report")
+		}
+		if len(diagnostics.Orphans) != 1 || diagnostics.Orphans[0] != orphan {
+			t.Errorf("expected the orphan to be reported, got %v", diagnostics.Orphans)
+		}
+		if len(diagnostics.Cycles) != 1 {
+			t.Errorf("expected one cycle, got %d", len(diagnostics.Cycles))
+		}
+		if len(diagnostics.Roots) != 2 {
+			t.Errorf("expected both roots to be listed, got %d", len(diagnostics.Roots))
+		}
+		if len(diagnostics.DuplicatePositions) != 1 {
+			t.Fatalf("expected one duplicate position group, got %d", len(diagnostics.DuplicatePositions))
+		}
+		group := diagnostics.DuplicatePositions[0]
+		if group.ParentID != root.id || group.Position != 0 || len(group.Tasks) != 2 {
+			t.Errorf("expected A and B sharing position 0 under the root, got %+v", group)
+		}
+	})
+}
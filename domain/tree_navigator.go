@@ -20,6 +20,12 @@ type TreeNavigator interface {
 	// GetRoot returns the root task of the tree
 	GetRoot() (*Task, error)
 
+	// GetRootOf returns the topmost ancestor of the given task (the task itself if it is a root)
+	GetRootOf(taskID TaskID) (*Task, error)
+
+	// GetAncestors returns the ancestors of the given task ordered from the root down to its parent
+	GetAncestors(taskID TaskID) ([]*Task, error)
+
 	// GetTree returns the complete tree structure (root task and all descendants)
 	GetTree() ([]*Task, error)
 
@@ -29,11 +35,12 @@ type TreeNavigator interface {
 
 // TreeNavigatorService implements TreeNavigator using a TaskRepository
 type TreeNavigatorService struct {
-	repo TaskRepository
+	repo TaskRepositoryTx
 }
 
 // NewTreeNavigatorService creates a new TreeNavigatorService
-func NewTreeNavigatorService(repo TaskRepository) *TreeNavigatorService {
+// Navigation only reads, so it also works on a transaction's repository
+func NewTreeNavigatorService(repo TaskRepositoryTx) *TreeNavigatorService {
 	return &TreeNavigatorService{
 		repo: repo,
 	}
@@ -157,62 +164,83 @@ func (s *TreeNavigatorService) GetRoot() (*Task, error) {
 	return s.repo.FindRoot()
 }
 
-// GetTree returns the complete tree structure (root task and all descendants)
-func (s *TreeNavigatorService) GetTree() ([]*Task, error) {
-	// Get the root task
-	root, err := s.repo.FindRoot()
+// GetRootOf returns the topmost ancestor of the given task by walking up parent links
+// Returns a ConstraintViolationError if the parent chain contains a cycle
+func (s *TreeNavigatorService) GetRootOf(taskID TaskID) (*Task, error) {
+	task, err := s.repo.FindByID(taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get the subtree starting from the root (which is the entire tree)
-	return s.GetSubtree(root.ID())
+	visited := map[string]bool{task.ID().String(): true}
+	for task.ParentID() != nil {
+		parentID := *task.ParentID()
+		if visited[parentID.String()] {
+			return nil, NewConstraintViolationError(
+				"cycle-detected",
+				"parent chain of task contains a cycle",
+			)
+		}
+		visited[parentID.String()] = true
+
+		task, err = s.repo.FindByID(parentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
 }
 
-// GetSubtree returns the given task and all its descendants
-func (s *TreeNavigatorService) GetSubtree(taskID TaskID) ([]*Task, error) {
-	// First, verify the task exists and get it
+// GetAncestors returns the ancestors of the given task ordered from the root down to its parent
+// Returns an empty slice for a root task, or a ConstraintViolationError if the parent chain contains a cycle
+func (s *TreeNavigatorService) GetAncestors(taskID TaskID) ([]*Task, error) {
 	task, err := s.repo.FindByID(taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Start with the task itself
-	result := []*Task{task}
+	ancestors := make([]*Task, 0)
+	visited := map[string]bool{task.ID().String(): true}
+	for task.ParentID() != nil {
+		parentID := *task.ParentID()
+		if visited[parentID.String()] {
+			return nil, NewConstraintViolationError(
+				"cycle-detected",
+				"parent chain of task contains a cycle",
+			)
+		}
+		visited[parentID.String()] = true
 
-	// Recursively collect all descendants
-	descendants, err := s.collectDescendants(taskID)
-	if err != nil {
-		return nil, err
+		task, err = s.repo.FindByID(parentID)
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, task)
 	}
 
-	result = append(result, descendants...)
+	// Collected parent first; reverse to root first
+	for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
+		ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
 
-	return result, nil
+	return ancestors, nil
 }
 
-// collectDescendants recursively collects all descendant tasks
-func (s *TreeNavigatorService) collectDescendants(parentID TaskID) ([]*Task, error) {
-	var descendants []*Task
-
-	// Get all direct children
-	children, err := s.repo.FindByParentID(&parentID)
+// GetTree returns the complete tree structure (root task and all descendants)
+func (s *TreeNavigatorService) GetTree() ([]*Task, error) {
+	// Get the root task
+	root, err := s.repo.FindRoot()
 	if err != nil {
 		return nil, err
 	}
 
-	// For each child, add it and its descendants
-	for _, child := range children {
-		descendants = append(descendants, child)
-
-		// Recursively get descendants of this child
-		childDescendants, err := s.collectDescendants(child.ID())
-		if err != nil {
-			return nil, err
-		}
-
-		descendants = append(descendants, childDescendants...)
-	}
+	// Get the subtree starting from the root (which is the entire tree)
+	return s.GetSubtree(root.ID())
+}
 
-	return descendants, nil
+// GetSubtree returns the given task and all its descendants
+func (s *TreeNavigatorService) GetSubtree(taskID TaskID) ([]*Task, error) {
+	// The repository collects the whole subtree in a single pass
+	return s.repo.FindSubtree(taskID)
 }
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 )
 
 // setupTreeNavigatorTest creates a test tree structure:
@@ -705,3 +706,109 @@ func TestTreeNavigator_GetTree_EmptyTree(t *testing.T) {
 		t.Errorf("Expected NotFoundError but got %T", err)
 	}
 }
+
+func TestTreeNavigator_GetRootOf(t *testing.T) {
+	_, navigator, tasks := setupTreeNavigatorTest(t)
+
+	for _, name := range []string{"root", "child2", "grandchild2"} {
+		t.Run(name, func(t *testing.T) {
+			root, err := navigator.GetRootOf(tasks[name].ID())
+			if err != nil {
+				t.Fatalf("GetRootOf failed: %v", err)
+			}
+			if !root.ID().Equals(tasks["root"].ID()) {
+				t.Errorf("Expected root %v, got %v", tasks["root"].ID(), root.ID())
+			}
+		})
+	}
+}
+
+func TestTreeNavigator_GetRootOf_DeepNode(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	navigator := NewTreeNavigatorService(repo)
+
+	// Build a chain 50 levels deep
+	root, _ := NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+	current := root
+	for i := 0; i < 50; i++ {
+		parentID := current.ID()
+		next, _ := NewTask("Level", &parentID, 0)
+		_ = repo.Save(next)
+		current = next
+	}
+
+	found, err := navigator.GetRootOf(current.ID())
+	if err != nil {
+		t.Fatalf("GetRootOf failed: %v", err)
+	}
+	if !found.ID().Equals(root.ID()) {
+		t.Errorf("Expected root %v, got %v", root.ID(), found.ID())
+	}
+}
+
+func TestTreeNavigator_GetRootOf_Cycle(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	navigator := NewTreeNavigatorService(repo)
+
+	// Corrupted data: a and b are each other's parent
+	aID := NewTaskID()
+	bID := NewTaskID()
+	now := time.Now()
+	_ = repo.Save(ReconstructTask(TaskFields{ID: aID, Description: "A", Status: StatusTODO, ParentID: &bID, CreatedAt: now, UpdatedAt: now}))
+	_ = repo.Save(ReconstructTask(TaskFields{ID: bID, Description: "B", Status: StatusTODO, ParentID: &aID, CreatedAt: now, UpdatedAt: now}))
+
+	_, err := navigator.GetRootOf(aID)
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Errorf("Expected ConstraintViolationError, got %T (%v)", err, err)
+	}
+}
+
+func TestTreeNavigator_GetRootOf_NotFound(t *testing.T) {
+	_, navigator, _ := setupTreeNavigatorTest(t)
+
+	_, err := navigator.GetRootOf(NewTaskID())
+	if _, ok := err.(NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError, got %T", err)
+	}
+}
+
+func TestTreeNavigator_GetAncestors(t *testing.T) {
+	_, navigator, tasks := setupTreeNavigatorTest(t)
+
+	ancestors, err := navigator.GetAncestors(tasks["grandchild2"].ID())
+	if err != nil {
+		t.Fatalf("GetAncestors failed: %v", err)
+	}
+	if len(ancestors) != 2 {
+		t.Fatalf("Expected 2 ancestors, got %d", len(ancestors))
+	}
+	if !ancestors[0].ID().Equals(tasks["root"].ID()) || !ancestors[1].ID().Equals(tasks["child1"].ID()) {
+		t.Errorf("Expected ancestors [root, child1], got [%v, %v]", ancestors[0].ID(), ancestors[1].ID())
+	}
+
+	ancestors, err = navigator.GetAncestors(tasks["root"].ID())
+	if err != nil {
+		t.Fatalf("GetAncestors failed: %v", err)
+	}
+	if len(ancestors) != 0 {
+		t.Errorf("Expected no ancestors for root, got %d", len(ancestors))
+	}
+}
+
+func TestTreeNavigator_GetAncestors_Cycle(t *testing.T) {
+	repo := NewInMemoryTaskRepository()
+	navigator := NewTreeNavigatorService(repo)
+
+	// Corrupted data: a and b are each other's parent
+	aID := NewTaskID()
+	bID := NewTaskID()
+	now := time.Now()
+	_ = repo.Save(ReconstructTask(TaskFields{ID: aID, Description: "A", Status: StatusTODO, ParentID: &bID, CreatedAt: now, UpdatedAt: now}))
+	_ = repo.Save(ReconstructTask(TaskFields{ID: bID, Description: "B", Status: StatusTODO, ParentID: &aID, CreatedAt: now, UpdatedAt: now}))
+
+	_, err := navigator.GetAncestors(aID)
+	if _, ok := err.(ConstraintViolationError); !ok {
+		t.Errorf("Expected ConstraintViolationError, got %T (%v)", err, err)
+	}
+}
@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// encryptedFileMagic starts every encrypted data file, telling it apart from a plaintext one
+var encryptedFileMagic = []byte("DTREE-AESGCM1\n")
+
+// ErrDecryptionFailed is returned when an encrypted file cannot be authenticated, because the
+// key differs from the one it was written with or the file was modified
+var ErrDecryptionFailed = errors.New("decryption failed: wrong encryption key or corrupted file")
+
+// ErrEncryptedWithoutKey is returned when an encrypted file is read without an encryption key
+var ErrEncryptedWithoutKey = errors.New("file is encrypted but no encryption key is set")
+
+// encryptingFileStore wraps a FileStore, encrypting files with AES-GCM on write and
+// decrypting them on read
+// Files without the magic header are read as plaintext, so an existing plaintext data file
+// is loaded as is and encrypted by the next write
+type encryptingFileStore struct {
+	FileStore
+	aead cipher.AEAD
+}
+
+// ParseEncryptionKey decodes a base64 encoded AES key of 16, 24 or 32 bytes
+func ParseEncryptionKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64 encoded: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	}
+	return nil, fmt.Errorf("encryption key must decode to 16, 24 or 32 bytes, got %d", len(key))
+}
+
+// NewEncryptingFileStore creates a FileStore that encrypts the files written through store with key
+func NewEncryptingFileStore(store FileStore, key []byte) (FileStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return &encryptingFileStore{FileStore: store, aead: aead}, nil
+}
+
+// isEncryptedData reports whether data starts with the encrypted file header
+func isEncryptedData(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedFileMagic)
+}
+
+// ReadFile reads the file at path, decrypting it if it is encrypted
+func (s *encryptingFileStore) ReadFile(path string) ([]byte, error) {
+	data, err := s.FileStore.ReadFile(path)
+	if err != nil || !isEncryptedData(data) {
+		return data, err
+	}
+
+	sealed := data[len(encryptedFileMagic):]
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrDecryptionFailed
+	}
+	plaintext, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], encryptedFileMagic)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+// WriteFile encrypts data with a fresh nonce and writes it to the file at path
+func (s *encryptingFileStore) WriteFile(path string, data []byte, perm os.FileMode) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(encryptedFileMagic)+len(nonce)+len(data)+s.aead.Overhead())
+	out = append(out, encryptedFileMagic...)
+	out = append(out, nonce...)
+	out = s.aead.Seal(out, nonce, data, encryptedFileMagic)
+	return s.FileStore.WriteFile(path, out, perm)
+}
@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"testing"
+
+	"discovery-tree/domain"
+)
+
+// testEncryptionKey returns a base64 encoded 32-byte key filled with b
+func testEncryptionKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+// saveRootTask creates a repository at path with options and saves a root task to it
+func saveRootTask(t *testing.T, path string, options FileTaskRepositoryOptions) *domain.Task {
+	t.Helper()
+	repo, err := NewFileTaskRepositoryWithOptions(path, options)
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+	task, _ := domain.NewTask("Secret root", nil, 0)
+	if err := repo.Save(task); err != nil {
+		t.Fatalf("expected no error saving task, got %v", err)
+	}
+	return task
+}
+
+// TestEncryption_RoundTrip tests that an encrypted data file hides its contents and loads with the same key
+func TestEncryption_RoundTrip(t *testing.T) {
+	testPath := "./test_data/encrypted.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	options := FileTaskRepositoryOptions{EncryptionKey: testEncryptionKey(1)}
+	task := saveRootTask(t, testPath, options)
+
+	data, err := os.ReadFile(testPath)
+	if err != nil {
+		t.Fatalf("expected data file to exist, got %v", err)
+	}
+	if !bytes.HasPrefix(data, encryptedFileMagic) {
+		t.Errorf("expected data file to start with the encrypted header")
+	}
+	if bytes.Contains(data, []byte("Secret root")) {
+		t.Errorf("expected description not to appear in the encrypted data file")
+	}
+
+	reloaded, err := NewFileTaskRepositoryWithOptions(testPath, options)
+	if err != nil {
+		t.Fatalf("expected no error loading encrypted repository, got %v", err)
+	}
+	found, err := reloaded.FindByID(task.ID())
+	if err != nil {
+		t.Fatalf("expected task to be loaded, got %v", err)
+	}
+	if found.Description() != "Secret root" {
+		t.Errorf("expected description 'Secret root', got %q", found.Description())
+	}
+}
+
+// TestEncryption_NoKeyWritesPlaintext tests that without a key the data file is plain JSON as before
+func TestEncryption_NoKeyWritesPlaintext(t *testing.T) {
+	testPath := "./test_data/plain.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	task := saveRootTask(t, testPath, FileTaskRepositoryOptions{})
+
+	data, err := os.ReadFile(testPath)
+	if err != nil {
+		t.Fatalf("expected data file to exist, got %v", err)
+	}
+	if bytes.HasPrefix(data, encryptedFileMagic) || !bytes.Contains(data, []byte("Secret root")) {
+		t.Errorf("expected a plaintext data file, got %q", data)
+	}
+
+	reloaded, err := NewFileTaskRepository(testPath)
+	if err != nil {
+		t.Fatalf("expected no error loading repository, got %v", err)
+	}
+	if _, err := reloaded.FindByID(task.ID()); err != nil {
+		t.Errorf("expected task to be loaded, got %v", err)
+	}
+}
+
+// TestEncryption_MigratesPlaintext tests that a plaintext data file loads with a key and is encrypted by the next write
+func TestEncryption_MigratesPlaintext(t *testing.T) {
+	testPath := "./test_data/migrate.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	task := saveRootTask(t, testPath, FileTaskRepositoryOptions{})
+
+	options := FileTaskRepositoryOptions{EncryptionKey: testEncryptionKey(1)}
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, options)
+	if err != nil {
+		t.Fatalf("expected plaintext data file to load with a key, got %v", err)
+	}
+	if _, err := repo.FindByID(task.ID()); err != nil {
+		t.Fatalf("expected task to be loaded, got %v", err)
+	}
+
+	if err := repo.Save(task); err != nil {
+		t.Fatalf("expected no error saving task, got %v", err)
+	}
+	data, _ := os.ReadFile(testPath)
+	if !bytes.HasPrefix(data, encryptedFileMagic) {
+		t.Errorf("expected data file to be encrypted after a write")
+	}
+}
+
+// TestEncryption_WrongKey tests that loading an encrypted data file with another key fails authentication
+func TestEncryption_WrongKey(t *testing.T) {
+	testPath := "./test_data/wrong_key.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	saveRootTask(t, testPath, FileTaskRepositoryOptions{EncryptionKey: testEncryptionKey(1)})
+
+	_, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{EncryptionKey: testEncryptionKey(2)})
+	if !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("expected ErrDecryptionFailed, got %v", err)
+	}
+}
+
+// TestEncryption_MissingKey tests that loading an encrypted data file without a key fails clearly
+func TestEncryption_MissingKey(t *testing.T) {
+	testPath := "./test_data/missing_key.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	saveRootTask(t, testPath, FileTaskRepositoryOptions{EncryptionKey: testEncryptionKey(1)})
+
+	_, err := NewFileTaskRepository(testPath)
+	if !errors.Is(err, ErrEncryptedWithoutKey) {
+		t.Errorf("expected ErrEncryptedWithoutKey, got %v", err)
+	}
+}
+
+// TestParseEncryptionKey tests key decoding and length checks
+func TestParseEncryptionKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"AES-128", base64.StdEncoding.EncodeToString(make([]byte, 16)), false},
+		{"AES-192", base64.StdEncoding.EncodeToString(make([]byte, 24)), false},
+		{"AES-256", base64.StdEncoding.EncodeToString(make([]byte, 32)), false},
+		{"wrong length", base64.StdEncoding.EncodeToString(make([]byte, 20)), true},
+		{"not base64", "not a key!", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEncryptionKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
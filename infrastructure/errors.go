@@ -1,19 +1,26 @@
 package infrastructure
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
 
 // FileSystemError represents an error that occurs during file I/O operations
+// It follows the os.PathError convention so callers can inspect which operation
+// and path failed, and errors.Is/As see through to the underlying cause
 type FileSystemError struct {
-	Operation string // The operation being performed (e.g., "read", "write", "create directory")
-	Path      string // The file path involved
-	Err       error  // The underlying error
+	Op   string // The operation being performed (e.g., "read", "write", "create directory")
+	Path string // The file path involved
+	Err  error  // The underlying error
 }
 
 func (e FileSystemError) Error() string {
 	if e.Path != "" {
-		return fmt.Sprintf("filesystem error during %s on '%s': %v", e.Operation, e.Path, e.Err)
+		return fmt.Sprintf("filesystem error during %s on '%s': %v", e.Op, e.Path, e.Err)
 	}
-	return fmt.Sprintf("filesystem error during %s: %v", e.Operation, e.Err)
+	return fmt.Sprintf("filesystem error during %s: %v", e.Op, e.Err)
 }
 
 // Unwrap returns the underlying error for error unwrapping
@@ -21,19 +28,33 @@ func (e FileSystemError) Unwrap() error {
 	return e.Err
 }
 
+// IsTransient reports whether the underlying error is likely to succeed on retry
+// (timeouts, interrupted or would-block system calls, busy resources)
+func (e FileSystemError) IsTransient() bool {
+	return errors.Is(e.Err, os.ErrDeadlineExceeded) ||
+		errors.Is(e.Err, syscall.EAGAIN) ||
+		errors.Is(e.Err, syscall.EINTR) ||
+		errors.Is(e.Err, syscall.EBUSY)
+}
+
 // NewFileSystemError creates a new FileSystemError
 func NewFileSystemError(operation, path string, err error) FileSystemError {
 	return FileSystemError{
-		Operation: operation,
-		Path:      path,
-		Err:       err,
+		Op:   operation,
+		Path: path,
+		Err:  err,
 	}
 }
 
 // WrapFileSystemError wraps an OS error with context about the file operation
+// Returns nil if err is nil; an error that is already a FileSystemError is returned as-is
 func WrapFileSystemError(operation, path string, err error) error {
 	if err == nil {
 		return nil
 	}
+	var fsErr FileSystemError
+	if errors.As(err, &fsErr) {
+		return err
+	}
 	return NewFileSystemError(operation, path, err)
 }
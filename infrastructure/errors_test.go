@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestFileSystemError_ErrorsIsAndAs(t *testing.T) {
+	err := fmt.Errorf("loading tasks: %w", WrapFileSystemError("read", "/data/tasks.json", fs.ErrNotExist))
+
+	// errors.Is sees through to the underlying cause
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("expected errors.Is to match the wrapped cause")
+	}
+
+	// errors.As recovers the operation and path
+	var fsErr FileSystemError
+	if !errors.As(err, &fsErr) {
+		t.Fatal("expected errors.As to find FileSystemError")
+	}
+	if fsErr.Op != "read" {
+		t.Errorf("expected operation 'read', got %q", fsErr.Op)
+	}
+	if fsErr.Path != "/data/tasks.json" {
+		t.Errorf("expected path '/data/tasks.json', got %q", fsErr.Path)
+	}
+}
+
+func TestWrapFileSystemError(t *testing.T) {
+	if WrapFileSystemError("read", "/data/tasks.json", nil) != nil {
+		t.Error("expected nil when wrapping nil error")
+	}
+
+	// Wrapping an existing FileSystemError keeps the original operation
+	inner := NewFileSystemError("write temporary file", "/data/tasks.json.tmp", os.ErrPermission)
+	wrapped := WrapFileSystemError("persist", "/data/tasks.json", inner)
+
+	fsErr, ok := wrapped.(FileSystemError)
+	if !ok {
+		t.Fatalf("expected FileSystemError, got %T", wrapped)
+	}
+	if fsErr.Op != "write temporary file" {
+		t.Errorf("expected original operation to be kept, got %q", fsErr.Op)
+	}
+}
+
+func TestFileSystemError_IsTransient(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"deadline exceeded", os.ErrDeadlineExceeded, true},
+		{"would block", syscall.EAGAIN, true},
+		{"interrupted", &os.PathError{Op: "write", Path: "/data", Err: syscall.EINTR}, true},
+		{"permission denied", os.ErrPermission, false},
+		{"not exist", fs.ErrNotExist, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsErr := NewFileSystemError("write", "/data/tasks.json", tt.err)
+			if fsErr.IsTransient() != tt.transient {
+				t.Errorf("expected IsTransient() = %v for %v", tt.transient, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package infrastructure
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrFileLocked is returned when another process holds the data file's lock
+var ErrFileLocked = errors.New("data file is locked by another process; is another server using the same DATA_PATH?")
+
+// fileLock is an advisory, exclusive lock held on a sidecar file next to the data file
+// The data file itself is replaced on every atomic write, so it cannot carry the lock
+type fileLock struct {
+	file *os.File
+}
+
+// acquireFileLock creates (if needed) and locks the file at path without waiting
+// Returns a FileSystemError wrapping ErrFileLocked if another process holds the lock
+func acquireFileLock(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, WrapFileSystemError("open lock file", path, err)
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, WrapFileSystemError("lock", path, err)
+	}
+
+	return &fileLock{file: file}, nil
+}
+
+// release unlocks and closes the lock file; the file itself is left in place
+func (l *fileLock) release() error {
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return WrapFileSystemError("unlock", l.file.Name(), err)
+	}
+	return WrapFileSystemError("close lock file", l.file.Name(), l.file.Close())
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package infrastructure
+
+import "os"
+
+// lockFile is a no-op on platforms without flock; the data file is not protected there
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without flock
+func unlockFile(file *os.File) error {
+	return nil
+}
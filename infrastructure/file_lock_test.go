@@ -0,0 +1,87 @@
+//go:build unix
+
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"discovery-tree/domain"
+)
+
+// TestLockFile_SecondRepositoryFails tests that a second repository on a locked data file
+// fails to initialize, and can start once the first one is closed
+func TestLockFile_SecondRepositoryFails(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	options := FileTaskRepositoryOptions{LockFile: true}
+
+	first, err := NewFileTaskRepositoryWithOptions(testPath, options)
+	if err != nil {
+		t.Fatalf("expected no error creating first repository, got %v", err)
+	}
+	root, _ := domain.NewTask("Root", nil, 0)
+	if err := first.Save(root); err != nil {
+		t.Fatalf("failed to save root: %v", err)
+	}
+
+	// The lock is held, so the second repository fails fast
+	_, err = NewFileTaskRepositoryWithOptions(testPath, options)
+	if !errors.Is(err, ErrFileLocked) {
+		t.Fatalf("expected ErrFileLocked, got %v", err)
+	}
+	var fsErr FileSystemError
+	if !errors.As(err, &fsErr) || fsErr.IsTransient() {
+		t.Errorf("expected a non-transient FileSystemError, got %v", err)
+	}
+
+	// Closing the first repository releases the lock
+	if err := first.Close(); err != nil {
+		t.Fatalf("expected no error closing first repository, got %v", err)
+	}
+	second, err := NewFileTaskRepositoryWithOptions(testPath, options)
+	if err != nil {
+		t.Fatalf("expected no error once the lock is released, got %v", err)
+	}
+	defer second.Close()
+	if count, _ := second.Count(); count != 1 {
+		t.Errorf("expected 1 task, got %d", count)
+	}
+}
+
+// TestLockFile_Disabled tests that repositories without LockFile share a data file
+func TestLockFile_Disabled(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+
+	first, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{LockFile: true})
+	if err != nil {
+		t.Fatalf("expected no error creating locked repository, got %v", err)
+	}
+	defer first.Close()
+
+	if _, err := NewFileTaskRepository(testPath); err != nil {
+		t.Errorf("expected an unlocked repository to ignore the lock, got %v", err)
+	}
+}
+
+// TestLockFile_ReleasedWhenLoadFails tests that a repository failing to load does not keep the lock
+func TestLockFile_ReleasedWhenLoadFails(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	store := NewOSFileStore()
+	if err := store.WriteFile(testPath, []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to write data file: %v", err)
+	}
+
+	options := FileTaskRepositoryOptions{LockFile: true}
+	if _, err := NewFileTaskRepositoryWithOptions(testPath, options); err == nil {
+		t.Fatal("expected error loading invalid data file, got nil")
+	}
+
+	if err := store.WriteFile(testPath, []byte("[]"), 0644); err != nil {
+		t.Fatalf("failed to write data file: %v", err)
+	}
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, options)
+	if err != nil {
+		t.Fatalf("expected the lock to be free after the failed load, got %v", err)
+	}
+	repo.Close()
+}
@@ -0,0 +1,47 @@
+package infrastructure
+
+import "os"
+
+// FileStore abstracts the file operations used by FileTaskRepository
+// The default implementation delegates to the os package; tests can substitute failures
+type FileStore interface {
+	// ReadFile reads the whole file at path
+	ReadFile(path string) ([]byte, error)
+
+	// WriteFile writes data to the file at path, creating or truncating it
+	WriteFile(path string, data []byte, perm os.FileMode) error
+
+	// Rename atomically replaces newPath with oldPath
+	Rename(oldPath, newPath string) error
+
+	// Remove deletes the file at path
+	Remove(path string) error
+}
+
+// osFileStore implements FileStore using the local filesystem
+type osFileStore struct{}
+
+// NewOSFileStore creates a FileStore backed by the local filesystem
+func NewOSFileStore() FileStore {
+	return osFileStore{}
+}
+
+// ReadFile reads the whole file at path
+func (osFileStore) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// WriteFile writes data to the file at path, creating or truncating it
+func (osFileStore) WriteFile(path string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(path, data, perm)
+}
+
+// Rename atomically replaces newPath with oldPath
+func (osFileStore) Rename(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
+// Remove deletes the file at path
+func (osFileStore) Remove(path string) error {
+	return os.Remove(path)
+}
@@ -0,0 +1,179 @@
+package infrastructure
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"discovery-tree/domain"
+)
+
+// flakyFileStore fails the first N writes with the given error, then delegates to the OS
+type flakyFileStore struct {
+	FileStore
+	failures int
+	err      error
+	writes   int
+}
+
+func (s *flakyFileStore) WriteFile(path string, data []byte, perm os.FileMode) error {
+	s.writes++
+	if s.writes <= s.failures {
+		return s.err
+	}
+	return s.FileStore.WriteFile(path, data, perm)
+}
+
+// TestPersist_RetriesTransientErrors tests that a write failing twice with a transient error eventually lands
+func TestPersist_RetriesTransientErrors(t *testing.T) {
+	testPath := "./test_data/retry.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	store := &flakyFileStore{FileStore: NewOSFileStore(), failures: 2, err: syscall.EAGAIN}
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{
+		Store:        store,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+
+	task, _ := domain.NewTask("Root", nil, 0)
+	if err := repo.Save(task); err != nil {
+		t.Fatalf("expected save to succeed after retries, got %v", err)
+	}
+
+	if store.writes != 3 {
+		t.Errorf("expected 3 write attempts, got %d", store.writes)
+	}
+
+	// Verify the write landed on disk
+	reloaded, err := NewFileTaskRepository(testPath)
+	if err != nil {
+		t.Fatalf("expected no error loading repository, got %v", err)
+	}
+	if _, err := reloaded.FindByID(task.ID()); err != nil {
+		t.Errorf("expected task to be persisted, got %v", err)
+	}
+}
+
+// TestPersist_DoesNotRetryPermanentErrors tests that permanent errors fail on the first attempt
+func TestPersist_DoesNotRetryPermanentErrors(t *testing.T) {
+	testPath := "./test_data/no_retry.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	store := &flakyFileStore{FileStore: NewOSFileStore(), failures: 1, err: syscall.ENOSPC}
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{
+		Store:        store,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+
+	task, _ := domain.NewTask("Root", nil, 0)
+	err = repo.Save(task)
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("expected ENOSPC error, got %v", err)
+	}
+
+	if store.writes != 1 {
+		t.Errorf("expected 1 write attempt, got %d", store.writes)
+	}
+}
+
+// TestPersist_GivesUpAfterMaxRetries tests that transient errors are returned once retries are exhausted
+func TestPersist_GivesUpAfterMaxRetries(t *testing.T) {
+	testPath := "./test_data/give_up.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	store := &flakyFileStore{FileStore: NewOSFileStore(), failures: 10, err: syscall.EBUSY}
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{
+		Store:        store,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+
+	task, _ := domain.NewTask("Root", nil, 0)
+	if err := repo.Save(task); !errors.Is(err, syscall.EBUSY) {
+		t.Fatalf("expected EBUSY error, got %v", err)
+	}
+
+	if store.writes != 3 {
+		t.Errorf("expected 3 write attempts, got %d", store.writes)
+	}
+}
+
+// blockingFileStore blocks every write until released, signalling when the first one starts
+type blockingFileStore struct {
+	FileStore
+	writing chan struct{} // closed when the first write starts
+	release chan struct{} // close to let writes finish
+	once    sync.Once
+}
+
+func (s *blockingFileStore) WriteFile(path string, data []byte, perm os.FileMode) error {
+	s.once.Do(func() { close(s.writing) })
+	<-s.release
+	return s.FileStore.WriteFile(path, data, perm)
+}
+
+// TestPersist_ReadsDoNotWaitForWrite tests that reads go on while a change is being written,
+// as they must while a failing write backs off
+func TestPersist_ReadsDoNotWaitForWrite(t *testing.T) {
+	changes := map[string]func(repo *FileTaskRepository, task *domain.Task) error{
+		"save": func(repo *FileTaskRepository, task *domain.Task) error {
+			return repo.Save(task)
+		},
+		"transaction": func(repo *FileTaskRepository, task *domain.Task) error {
+			return repo.WithTransaction(func(tx domain.TaskRepositoryTx) error {
+				return tx.Save(task)
+			})
+		},
+	}
+
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			store := &blockingFileStore{FileStore: NewOSFileStore(), writing: make(chan struct{}), release: make(chan struct{})}
+			repo, err := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", FileTaskRepositoryOptions{Store: store})
+			if err != nil {
+				t.Fatalf("expected no error creating repository, got %v", err)
+			}
+
+			task, _ := domain.NewTask("Root", nil, 0)
+			changed := make(chan error, 1)
+			go func() { changed <- change(repo, task) }()
+			<-store.writing
+
+			counted := make(chan struct{})
+			go func() {
+				_, _ = repo.Count()
+				close(counted)
+			}()
+			select {
+			case <-counted:
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected a read to go on while the change is written")
+			}
+
+			close(store.release)
+			if err := <-changed; err != nil {
+				t.Fatalf("expected the change to succeed, got %v", err)
+			}
+			if _, err := repo.FindByID(task.ID()); err != nil {
+				t.Errorf("expected the task after the write, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"discovery-tree/domain"
+)
+
+// FileTaskArchive implements TaskArchive with a JSON file of archived trees, kept apart from
+// the data file so archived tasks never appear in the task repository's reads
+type FileTaskArchive struct {
+	filePath string
+	store    FileStore
+	trees    map[string]domain.ArchivedTree // in-memory cache, keyed by top task ID string
+	mu       sync.RWMutex                   // protects concurrent access
+}
+
+// FileTaskArchiveOptions holds optional settings for FileTaskArchive
+type FileTaskArchiveOptions struct {
+	// Store performs the file operations (defaults to the local filesystem)
+	Store FileStore
+
+	// EncryptionKey is a base64 encoded AES key; when set, the archive file is encrypted like
+	// the data file (see FileTaskRepositoryOptions.EncryptionKey)
+	EncryptionKey string
+
+	// StrictTimestamps fails loading an archive file with a zero or out-of-order timestamp
+	// instead of correcting it; see DTOOptions.StrictTimestamps
+	StrictTimestamps bool
+}
+
+// archiveFileDTO is the layout of the archive file
+type archiveFileDTO struct {
+	Trees []archivedTreeDTO `json:"trees"`
+}
+
+// archivedTreeDTO is a data transfer object for JSON serialization of ArchivedTree
+type archivedTreeDTO struct {
+	ParentID   string    `json:"parentId"`
+	Position   int       `json:"position"`
+	ArchivedAt time.Time `json:"archivedAt"`
+	Tasks      []TaskDTO `json:"tasks"`
+}
+
+// NewFileTaskArchive creates a FileTaskArchive, loading the archive file if it exists
+// Creates the file's directory if it doesn't exist
+func NewFileTaskArchive(filePath string, options FileTaskArchiveOptions) (*FileTaskArchive, error) {
+	if options.Store == nil {
+		options.Store = NewOSFileStore()
+	}
+	if options.EncryptionKey != "" {
+		key, err := ParseEncryptionKey(options.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		if options.Store, err = NewEncryptingFileStore(options.Store, key); err != nil {
+			return nil, err
+		}
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, WrapFileSystemError("create directory", dir, err)
+	}
+
+	archive := &FileTaskArchive{
+		filePath: filePath,
+		store:    options.Store,
+		trees:    make(map[string]domain.ArchivedTree),
+	}
+	if err := archive.load(DTOOptions{StrictTimestamps: options.StrictTimestamps}); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// load reads the archive file into the cache; a missing or empty file is an empty archive
+func (a *FileTaskArchive) load(options DTOOptions) error {
+	data, err := a.store.ReadFile(a.filePath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(data) == 0) {
+		return nil
+	}
+	if err != nil {
+		return WrapFileSystemError("read", a.filePath, err)
+	}
+	if isEncryptedData(data) {
+		return WrapFileSystemError("read", a.filePath, ErrEncryptedWithoutKey)
+	}
+
+	var file archiveFileDTO
+	if err := json.Unmarshal(data, &file); err != nil {
+		return WrapFileSystemError("parse JSON", a.filePath, err)
+	}
+
+	for _, dto := range file.Trees {
+		tree, err := fromArchivedTreeDTO(dto, options)
+		if err != nil {
+			return err
+		}
+		a.trees[tree.Root().ID().String()] = tree
+	}
+	return nil
+}
+
+// persist writes the cache to the archive file atomically
+// Note: This method assumes the write lock is already held by the caller
+func (a *FileTaskArchive) persist() error {
+	trees := make([]domain.ArchivedTree, 0, len(a.trees))
+	for _, tree := range a.trees {
+		trees = append(trees, tree)
+	}
+	domain.SortArchivedTrees(trees)
+
+	file := archiveFileDTO{Trees: make([]archivedTreeDTO, len(trees))}
+	for i, tree := range trees {
+		file.Trees[i] = toArchivedTreeDTO(tree)
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return WrapFileSystemError("marshal JSON", a.filePath, err)
+	}
+
+	tmpPath := a.filePath + ".tmp"
+	if err := a.store.WriteFile(tmpPath, data, 0644); err != nil {
+		return WrapFileSystemError("write temporary file", tmpPath, err)
+	}
+	if err := a.store.Rename(tmpPath, a.filePath); err != nil {
+		a.store.Remove(tmpPath)
+		return WrapFileSystemError("atomic rename", a.filePath, err)
+	}
+	return nil
+}
+
+// Add stores an archived tree; if writing the file fails, the archive is left unchanged
+func (a *FileTaskArchive) Add(tree domain.ArchivedTree) error {
+	if len(tree.Tasks) == 0 {
+		return domain.NewValidationError("tasks", "empty-archived-tree", "archived tree cannot be empty")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := tree.Root().ID().String()
+	previous, existed := a.trees[key]
+	a.trees[key] = tree
+	if err := a.persist(); err != nil {
+		if existed {
+			a.trees[key] = previous
+		} else {
+			delete(a.trees, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// FindAll retrieves all archived trees, ordered by archive time (tie-broken by top task ID)
+func (a *FileTaskArchive) FindAll() ([]domain.ArchivedTree, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	trees := make([]domain.ArchivedTree, 0, len(a.trees))
+	for _, tree := range a.trees {
+		trees = append(trees, tree)
+	}
+	domain.SortArchivedTrees(trees)
+	return trees, nil
+}
+
+// FindByRootID retrieves the archived tree whose top task has the given ID
+func (a *FileTaskArchive) FindByRootID(rootID domain.TaskID) (domain.ArchivedTree, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	tree, ok := a.trees[rootID.String()]
+	if !ok {
+		return domain.ArchivedTree{}, domain.NewNotFoundError("Archived Tree", rootID.String())
+	}
+	return tree, nil
+}
+
+// FindTask retrieves an archived task from any archived tree
+func (a *FileTaskArchive) FindTask(id domain.TaskID) (*domain.Task, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, tree := range a.trees {
+		for _, task := range tree.Tasks {
+			if task.ID().Equals(id) {
+				return task, nil
+			}
+		}
+	}
+	return nil, domain.NewNotFoundError("Archived Task", id.String())
+}
+
+// Remove deletes the archived tree whose top task has the given ID; if writing the file fails,
+// the archive is left unchanged
+func (a *FileTaskArchive) Remove(rootID domain.TaskID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := rootID.String()
+	tree, ok := a.trees[key]
+	if !ok {
+		return domain.NewNotFoundError("Archived Tree", key)
+	}
+	delete(a.trees, key)
+	if err := a.persist(); err != nil {
+		a.trees[key] = tree
+		return err
+	}
+	return nil
+}
+
+// toArchivedTreeDTO converts an archived tree to its file representation
+func toArchivedTreeDTO(tree domain.ArchivedTree) archivedTreeDTO {
+	dto := archivedTreeDTO{
+		ParentID:   tree.ParentID.String(),
+		Position:   tree.Position,
+		ArchivedAt: tree.ArchivedAt,
+		Tasks:      make([]TaskDTO, len(tree.Tasks)),
+	}
+	for i, task := range tree.Tasks {
+		dto.Tasks[i] = ToDTO(task)
+	}
+	return dto
+}
+
+// fromArchivedTreeDTO converts an archived tree read from the archive file
+func fromArchivedTreeDTO(dto archivedTreeDTO, options DTOOptions) (domain.ArchivedTree, error) {
+	parentID, err := domain.TaskIDFromString(dto.ParentID)
+	if err != nil {
+		return domain.ArchivedTree{}, err
+	}
+	if len(dto.Tasks) == 0 {
+		return domain.ArchivedTree{}, domain.NewValidationError("tasks", "empty-archived-tree", "archived tree cannot be empty")
+	}
+
+	tree := domain.ArchivedTree{
+		ParentID:   parentID,
+		Position:   dto.Position,
+		ArchivedAt: dto.ArchivedAt,
+		Tasks:      make([]*domain.Task, len(dto.Tasks)),
+	}
+	for i, taskDTO := range dto.Tasks {
+		if tree.Tasks[i], err = FromDTOWithOptions(taskDTO, options); err != nil {
+			return domain.ArchivedTree{}, err
+		}
+	}
+	return tree, nil
+}
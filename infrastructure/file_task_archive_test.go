@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"discovery-tree/domain"
+)
+
+// archivedBranch builds an archived tree of a DONE task with one DONE child, archived from parentID
+func archivedBranch(t *testing.T, parentID domain.TaskID) domain.ArchivedTree {
+	t.Helper()
+	top, _ := domain.NewTask("Shipped feature", &parentID, 2)
+	topID := top.ID()
+	child, _ := domain.NewTask("Write docs", &topID, 0)
+	for _, task := range []*domain.Task{child, top} {
+		if err := task.ChangeStatus(domain.StatusDONE); err != nil {
+			t.Fatalf("expected no error completing task, got %v", err)
+		}
+	}
+	return domain.ArchivedTree{
+		ParentID:   parentID,
+		Position:   2,
+		ArchivedAt: time.Now().UTC().Truncate(time.Second),
+		Tasks:      []*domain.Task{top, child},
+	}
+}
+
+// TestFileTaskArchive_RoundTrip tests that archived trees survive a reload and that removing one is persisted
+func TestFileTaskArchive_RoundTrip(t *testing.T) {
+	testPath := "./test_data/archive.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	archive, err := NewFileTaskArchive(testPath, FileTaskArchiveOptions{})
+	if err != nil {
+		t.Fatalf("expected no error creating archive, got %v", err)
+	}
+	rootID := domain.NewTaskID()
+	tree := archivedBranch(t, rootID)
+	if err := archive.Add(tree); err != nil {
+		t.Fatalf("expected no error adding tree, got %v", err)
+	}
+
+	reloaded, err := NewFileTaskArchive(testPath, FileTaskArchiveOptions{})
+	if err != nil {
+		t.Fatalf("expected no error loading archive, got %v", err)
+	}
+	trees, _ := reloaded.FindAll()
+	if len(trees) != 1 {
+		t.Fatalf("expected 1 archived tree, got %d", len(trees))
+	}
+	loaded := trees[0]
+	if !loaded.Root().ID().Equals(tree.Root().ID()) || !loaded.ParentID.Equals(rootID) || loaded.Position != 2 {
+		t.Errorf("expected the archived tree to keep its top task, parent and position")
+	}
+	if !loaded.ArchivedAt.Equal(tree.ArchivedAt) {
+		t.Errorf("expected archivedAt %v, got %v", tree.ArchivedAt, loaded.ArchivedAt)
+	}
+	if len(loaded.Tasks) != 2 || loaded.Tasks[1].Description() != "Write docs" || loaded.Tasks[1].Status() != domain.StatusDONE {
+		t.Errorf("expected the child to be loaded as DONE after its parent")
+	}
+	if _, err := reloaded.FindTask(tree.Tasks[1].ID()); err != nil {
+		t.Errorf("expected the child to be found, got %v", err)
+	}
+
+	if err := reloaded.Remove(tree.Root().ID()); err != nil {
+		t.Fatalf("expected no error removing tree, got %v", err)
+	}
+	afterRemove, err := NewFileTaskArchive(testPath, FileTaskArchiveOptions{})
+	if err != nil {
+		t.Fatalf("expected no error loading archive, got %v", err)
+	}
+	if _, err := afterRemove.FindByRootID(tree.Root().ID()); err == nil {
+		t.Errorf("expected the removed tree to stay removed after a reload")
+	}
+}
+
+// TestFileTaskArchive_FailedWriteLeavesArchiveUnchanged tests that a tree is not kept if the archive file cannot be written
+func TestFileTaskArchive_FailedWriteLeavesArchiveUnchanged(t *testing.T) {
+	store := &flakyFileStore{FileStore: NewOSFileStore(), failures: 1, err: syscall.ENOSPC}
+	archive, err := NewFileTaskArchive(t.TempDir()+"/archive.json", FileTaskArchiveOptions{Store: store})
+	if err != nil {
+		t.Fatalf("expected no error creating archive, got %v", err)
+	}
+
+	tree := archivedBranch(t, domain.NewTaskID())
+	if err := archive.Add(tree); err == nil {
+		t.Fatalf("expected the failed write to be reported")
+	}
+	if _, err := archive.FindByRootID(tree.Root().ID()); err == nil {
+		t.Errorf("expected the tree not to be archived")
+	}
+}
+
+// TestFileTaskArchive_Encrypted tests that the archive file is encrypted with a key
+func TestFileTaskArchive_Encrypted(t *testing.T) {
+	testPath := t.TempDir() + "/archive.json"
+	options := FileTaskArchiveOptions{EncryptionKey: testEncryptionKey(1)}
+
+	archive, err := NewFileTaskArchive(testPath, options)
+	if err != nil {
+		t.Fatalf("expected no error creating archive, got %v", err)
+	}
+	if err := archive.Add(archivedBranch(t, domain.NewTaskID())); err != nil {
+		t.Fatalf("expected no error adding tree, got %v", err)
+	}
+
+	data, _ := os.ReadFile(testPath)
+	if !bytes.HasPrefix(data, encryptedFileMagic) || bytes.Contains(data, []byte("Shipped feature")) {
+		t.Errorf("expected an encrypted archive file")
+	}
+	if _, err := NewFileTaskArchive(testPath, options); err != nil {
+		t.Errorf("expected the encrypted archive to load with its key, got %v", err)
+	}
+	if _, err := NewFileTaskArchive(testPath, FileTaskArchiveOptions{}); err == nil {
+		t.Errorf("expected loading the encrypted archive without a key to fail")
+	}
+}
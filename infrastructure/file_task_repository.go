@@ -2,10 +2,16 @@ package infrastructure
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"discovery-tree/domain"
 )
@@ -13,8 +19,94 @@ import (
 // FileTaskRepository implements TaskRepository with JSON file persistence
 type FileTaskRepository struct {
 	filePath string
+	store    FileStore
+	options  FileTaskRepositoryOptions
 	tasks    map[string]*domain.Task // in-memory cache, keyed by task ID string
+	index    *domain.TaskIndex       // tree queries over tasks, replaced whenever tasks changes
+	seq      uint64                  // sequence number of the latest change, persisted as the file's seq
 	mu       sync.RWMutex            // protects concurrent access
+
+	coalescer *writeCoalescer // background writer, nil unless CoalesceWrites is set
+	changeMu  sync.Mutex      // held by each change until it is applied, through its write if written directly
+	fileMu    sync.Mutex      // orders coalesced writes with ReplaceAll's direct write
+	lock      *fileLock       // held on the data file's lock file, nil unless LockFile is set
+
+	directWrites atomic.Uint64 // writes made by persist, each for a single change
+
+	snapshot atomic.Pointer[taskSnapshot] // collection as of the latest successful persist, nil unless SnapshotReads is set
+}
+
+// taskSnapshot is an immutable copy of the collection that reads use without locking
+// Its tasks are clones of the collection's as written to the file; neither the map nor the
+// tasks are modified once published, so tasks returned by reads must not be modified either
+type taskSnapshot struct {
+	tasks map[string]*domain.Task
+	index *domain.TaskIndex // tree queries over tasks
+	seq   uint64
+}
+
+// PersistStats counts how changes map to file writes
+// Each change is either written by a write of its own, coalesced into a write made for
+// another change, or still pending
+type PersistStats struct {
+	Requested uint64 // changes that asked to be written
+	Flushed   uint64 // file writes performed, successful or not
+	Coalesced uint64 // changes written by a write that also covered an earlier change
+	Pending   uint64 // changes not yet written
+}
+
+// FileTaskRepositoryOptions holds optional settings for FileTaskRepository
+// The zero value uses the local filesystem and does not retry failed writes
+type FileTaskRepositoryOptions struct {
+	// Store performs the file operations (defaults to the local filesystem)
+	Store FileStore
+
+	// MaxRetries is how many times a write failing with a transient error is retried
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry; it doubles on each further retry
+	RetryBackoff time.Duration
+
+	// StrictSingleRoot fails loading a data file that contains more than one root task
+	StrictSingleRoot bool
+
+	// CoalesceWrites moves file writes to a background goroutine that batches changes from
+	// concurrent writers into a single write; each write still waits for and reports the
+	// result of a file write that includes its change
+	CoalesceWrites bool
+
+	// PersistDebounce delays each background write after a change wakes the writer, so
+	// changes made within the window share one write; it requires CoalesceWrites
+	PersistDebounce time.Duration
+
+	// LockFile takes an exclusive advisory lock on "<file>.lock" for the repository's lifetime,
+	// so a second process using the same data file fails to start instead of overwriting it
+	// The lock is released by Close
+	LockFile bool
+
+	// MinifyJSON writes the data file without indentation, making it smaller but harder to read
+	MinifyJSON bool
+
+	// SnapshotReads serves reads from an immutable copy of the collection that is swapped in
+	// atomically after each successful persist, so reads never wait for the write lock
+	// Each write clones the tasks it writes, and a change becomes visible only once it is persisted
+	SnapshotReads bool
+
+	// EncryptionKey is a base64 encoded AES key; when set, the data file is encrypted with
+	// AES-GCM and a plaintext data file is encrypted on its next write
+	EncryptionKey string
+
+	// StrictTimestamps fails loading a data file with a zero or out-of-order timestamp instead
+	// of correcting it; see DTOOptions.StrictTimestamps
+	StrictTimestamps bool
+}
+
+// dataFileDTO is the layout of the data file: the tasks and the sequence number of the
+// latest change, which may be higher than any task's if the latest change was a delete
+// Legacy data files hold just the array of tasks
+type dataFileDTO struct {
+	Seq   uint64    `json:"seq"`
+	Tasks []TaskDTO `json:"tasks"`
 }
 
 // NewFileTaskRepository creates a new FileTaskRepository
@@ -22,11 +114,32 @@ type FileTaskRepository struct {
 // Creates necessary directories if they don't exist
 // Loads existing data from file if it exists
 func NewFileTaskRepository(filePath string) (*FileTaskRepository, error) {
+	return NewFileTaskRepositoryWithOptions(filePath, FileTaskRepositoryOptions{})
+}
+
+// NewFileTaskRepositoryWithOptions creates a new FileTaskRepository with the given options
+func NewFileTaskRepositoryWithOptions(filePath string, options FileTaskRepositoryOptions) (*FileTaskRepository, error) {
 	// Use default path if empty
 	if filePath == "" {
 		filePath = "./data/tasks.json"
 	}
 
+	// Use the local filesystem if no store is given
+	if options.Store == nil {
+		options.Store = NewOSFileStore()
+	}
+
+	// Encrypt the data file if a key is given
+	if options.EncryptionKey != "" {
+		key, err := ParseEncryptionKey(options.EncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		if options.Store, err = NewEncryptingFileStore(options.Store, key); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -36,29 +149,88 @@ func NewFileTaskRepository(filePath string) (*FileTaskRepository, error) {
 	// Initialize repository
 	repo := &FileTaskRepository{
 		filePath: filePath,
+		store:    options.Store,
+		options:  options,
 		tasks:    make(map[string]*domain.Task),
 	}
 
+	// Claim the data file before reading it, so another process cannot write it meanwhile
+	if options.LockFile {
+		lock, err := acquireFileLock(filePath + ".lock")
+		if err != nil {
+			return nil, err
+		}
+		repo.lock = lock
+	}
+
 	// Load existing data from file
 	if err := repo.load(); err != nil {
+		repo.releaseLock()
 		return nil, err
 	}
+	repo.reindex()
+
+	if options.CoalesceWrites {
+		repo.coalescer = newWriteCoalescer(repo.writeSnapshot, options.PersistDebounce)
+	}
+	repo.publishSnapshot(repo.takeSnapshot(repo.tasks, repo.seq))
 
 	return repo, nil
 }
 
+// Flush waits until every change made so far has been written to the file
+// Returns the error of the latest write; it is a no-op unless CoalesceWrites is set
+func (r *FileTaskRepository) Flush() error {
+	if r.coalescer == nil {
+		return nil
+	}
+	return r.coalescer.flush()
+}
+
+// Close flushes pending changes, stops the background writer and releases the file lock
+// Changes made after Close fail; it is a no-op unless CoalesceWrites or LockFile is set
+func (r *FileTaskRepository) Close() error {
+	var err error
+	if r.coalescer != nil {
+		err = r.coalescer.close()
+	}
+	return errors.Join(err, r.releaseLock())
+}
+
+// PersistStats reports how the changes made so far map to file writes
+func (r *FileTaskRepository) PersistStats() PersistStats {
+	direct := r.directWrites.Load()
+	stats := PersistStats{Requested: direct, Flushed: direct}
+	if r.coalescer != nil {
+		coalesced := r.coalescer.stats()
+		stats.Requested += coalesced.Requested
+		stats.Flushed += coalesced.Flushed
+		stats.Coalesced = coalesced.Coalesced
+		stats.Pending = coalesced.Pending
+	}
+	return stats
+}
+
+// releaseLock releases the file lock, if held
+func (r *FileTaskRepository) releaseLock() error {
+	if r.lock == nil {
+		return nil
+	}
+	err := r.lock.release()
+	r.lock = nil
+	return err
+}
+
 // load reads tasks from the JSON file and populates the in-memory cache
 // If the file doesn't exist, initializes with an empty collection
 // Returns error if file contains invalid JSON or invalid task data
 func (r *FileTaskRepository) load() error {
-	// Check if file exists
-	if _, err := os.Stat(r.filePath); os.IsNotExist(err) {
+	// Read file contents
+	data, err := r.store.ReadFile(r.filePath)
+	if errors.Is(err, fs.ErrNotExist) {
 		// File doesn't exist, initialize with empty collection
 		return nil
 	}
-
-	// Read file contents
-	data, err := os.ReadFile(r.filePath)
 	if err != nil {
 		return WrapFileSystemError("read", r.filePath, err)
 	}
@@ -68,51 +240,250 @@ func (r *FileTaskRepository) load() error {
 		return nil
 	}
 
-	// Parse JSON
-	var dtos []TaskDTO
-	if err := json.Unmarshal(data, &dtos); err != nil {
+	// An encrypted store returns plaintext, so encrypted data here means no key was given
+	if isEncryptedData(data) {
+		return WrapFileSystemError("read", r.filePath, ErrEncryptedWithoutKey)
+	}
+
+	// Parse JSON, accepting the legacy layout of a bare array of tasks
+	var file dataFileDTO
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &file.Tasks)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
 		return WrapFileSystemError("parse JSON", r.filePath, err)
 	}
 
 	// Convert DTOs to tasks and populate cache
-	for _, dto := range dtos {
-		task, err := FromDTO(dto)
+	r.seq = file.Seq
+	for _, dto := range file.Tasks {
+		task, err := FromDTOWithOptions(dto, DTOOptions{StrictTimestamps: r.options.StrictTimestamps})
 		if err != nil {
 			// Invalid task data
 			return err
 		}
 		r.tasks[task.ID().String()] = task
+		// Legacy files have no seq of their own
+		if task.Seq() > r.seq {
+			r.seq = task.Seq()
+		}
+	}
+
+	if r.options.StrictSingleRoot {
+		return r.checkSingleRoot()
+	}
+
+	return nil
+}
+
+// checkSingleRoot returns a ConstraintViolationError naming every root if more than one is loaded
+func (r *FileTaskRepository) checkSingleRoot() error {
+	var rootIDs []string
+	for id, task := range r.tasks {
+		if task.ParentID() == nil {
+			rootIDs = append(rootIDs, id)
+		}
+	}
+
+	if len(rootIDs) > 1 {
+		sort.Strings(rootIDs)
+		return domain.NewConstraintViolationError(
+			"single-root",
+			fmt.Sprintf("data file '%s' contains %d root tasks (%s); exactly one is allowed", r.filePath, len(rootIDs), strings.Join(rootIDs, ", ")),
+		)
 	}
 
 	return nil
 }
 
-// persist writes the in-memory task collection to the JSON file atomically
+// persist writes data, the marshaled task collection, to the JSON file atomically
 // Uses atomic write pattern: write to temp file, then rename
-// Formats JSON with 2-space indentation for readability
-func (r *FileTaskRepository) persist() error {
+// Transient write failures are retried with exponential backoff
+// Note: This method assumes fileMu is already held by the caller, and not the write lock,
+// so reads go on while a failing write backs off
+func (r *FileTaskRepository) persist(data []byte) error {
+	r.directWrites.Add(1)
+	return r.writeWithRetry(data)
+}
+
+// persistAndUnlock persists a change made under changeMu and the write lock and releases both
+// The write lock is released before the file is written, so reads do not wait for a slow or
+// retrying write; changeMu keeps further changes waiting until the write is done
+// With CoalesceWrites the change is left to the background writer instead, waiting for a
+// write that includes it
+func (r *FileTaskRepository) persistAndUnlock() error {
+	if r.coalescer == nil {
+		defer r.changeMu.Unlock()
+		data, err := r.marshalTasks(r.tasks, r.seq)
+		snapshot := r.takeSnapshot(r.tasks, r.seq)
+		r.mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		r.fileMu.Lock()
+		defer r.fileMu.Unlock()
+		if err := r.persist(data); err != nil {
+			return err
+		}
+		r.publishSnapshot(snapshot)
+		return nil
+	}
+
+	seq, err := r.coalescer.request()
+	r.mu.Unlock()
+	r.changeMu.Unlock()
+	if err != nil {
+		return err
+	}
+	// The write that covered this change has published its snapshot
+	return r.coalescer.wait(seq)
+}
+
+// takeSnapshot copies tasks, the collection or a replacement for it, for reads once written
+// The tasks are cloned, so changes made to the collection afterwards do not reach the snapshot
+// Returns nil unless SnapshotReads is set
+// Note: This method assumes the lock is already held by the caller if tasks is the collection
+func (r *FileTaskRepository) takeSnapshot(tasks map[string]*domain.Task, seq uint64) *taskSnapshot {
+	if !r.options.SnapshotReads {
+		return nil
+	}
+
+	clones := make(map[string]*domain.Task, len(tasks))
+	for id, task := range tasks {
+		clones[id] = task.Clone()
+	}
+	return &taskSnapshot{tasks: clones, index: domain.NewTaskIndex(clones), seq: seq}
+}
+
+// publishSnapshot swaps in a snapshot taken for a write that succeeded; nil is ignored
+// A snapshot never replaces one taken at a later sequence number, so concurrent writers
+// publishing out of order cannot roll reads back
+func (r *FileTaskRepository) publishSnapshot(next *taskSnapshot) {
+	if next == nil {
+		return
+	}
+
+	for {
+		current := r.snapshot.Load()
+		if current != nil && current.seq > next.seq {
+			return
+		}
+		if r.snapshot.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// view returns the collection reads should use and a function to call when done with it
+// With SnapshotReads it is the latest snapshot, taken without locking; otherwise it is the
+// collection itself under the read lock
+func (r *FileTaskRepository) view() (map[string]*domain.Task, func()) {
+	if snapshot := r.snapshot.Load(); snapshot != nil {
+		return snapshot.tasks, func() {}
+	}
+	r.mu.RLock()
+	return r.tasks, r.mu.RUnlock
+}
+
+// indexView is view for tree queries: it returns the index over the collection reads should
+// use and a function to call when done with it
+func (r *FileTaskRepository) indexView() (*domain.TaskIndex, func()) {
+	if snapshot := r.snapshot.Load(); snapshot != nil {
+		return snapshot.index, func() {}
+	}
+	r.mu.RLock()
+	return r.index, r.mu.RUnlock
+}
+
+// reindex replaces the index after a change to the collection
+// Note: This method assumes the write lock is already held by the caller
+func (r *FileTaskRepository) reindex() {
+	r.index = domain.NewTaskIndex(r.tasks)
+}
+
+// writeSnapshot is the background writer's write: it snapshots the collection under the
+// read lock and writes it outside the lock, so reads and further changes are not blocked
+func (r *FileTaskRepository) writeSnapshot() error {
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
+
+	r.mu.RLock()
+	data, err := r.marshalTasks(r.tasks, r.seq)
+	snapshot := r.takeSnapshot(r.tasks, r.seq)
+	r.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := r.writeWithRetry(data); err != nil {
+		return err
+	}
+
+	// Reads see exactly what was written, not changes still waiting for the next write
+	r.publishSnapshot(snapshot)
+	return nil
+}
+
+// marshalTasks converts a collection and its latest sequence number to indented JSON, or
+// minified JSON with MinifyJSON
+// Note: This method assumes the lock is already held by the caller if tasks is the collection
+func (r *FileTaskRepository) marshalTasks(tasks map[string]*domain.Task, seq uint64) ([]byte, error) {
 	// Convert tasks to DTOs
-	dtos := make([]TaskDTO, 0, len(r.tasks))
-	for _, task := range r.tasks {
+	dtos := make([]TaskDTO, 0, len(tasks))
+	for _, task := range tasks {
 		dtos = append(dtos, ToDTO(task))
 	}
 
-	// Marshal to JSON with indentation (2 spaces)
-	data, err := json.MarshalIndent(dtos, "", "  ")
+	// Marshal to JSON with indentation (2 spaces) unless minified
+	file := dataFileDTO{Seq: seq, Tasks: dtos}
+	var data []byte
+	var err error
+	if r.options.MinifyJSON {
+		data, err = json.Marshal(file)
+	} else {
+		data, err = json.MarshalIndent(file, "", "  ")
+	}
 	if err != nil {
-		return WrapFileSystemError("marshal JSON", r.filePath, err)
+		return nil, WrapFileSystemError("marshal JSON", r.filePath, err)
+	}
+	return data, nil
+}
+
+// writeWithRetry writes data atomically, retrying transient failures with exponential backoff
+func (r *FileTaskRepository) writeWithRetry(data []byte) error {
+	var err error
+	backoff := r.options.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = r.writeAtomically(data)
+		if err == nil {
+			return nil
+		}
+
+		// Permanent errors (e.g. no space left, read-only filesystem) are not retried
+		var fsErr FileSystemError
+		if !errors.As(err, &fsErr) || !fsErr.IsTransient() || attempt >= r.options.MaxRetries {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
 	}
+}
 
+// writeAtomically writes data to a temporary file and renames it over the data file
+func (r *FileTaskRepository) writeAtomically(data []byte) error {
 	// Write to temporary file
 	tmpPath := r.filePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+	if err := r.store.WriteFile(tmpPath, data, 0644); err != nil {
 		return WrapFileSystemError("write temporary file", tmpPath, err)
 	}
 
 	// Atomic rename (replaces target file atomically on POSIX systems)
-	if err := os.Rename(tmpPath, r.filePath); err != nil {
+	if err := r.store.Rename(tmpPath, r.filePath); err != nil {
 		// Clean up temporary file on failure
-		os.Remove(tmpPath)
+		r.store.Remove(tmpPath)
 		return WrapFileSystemError("atomic rename", r.filePath, err)
 	}
 
@@ -122,23 +493,192 @@ func (r *FileTaskRepository) persist() error {
 // Save persists a task (create or update)
 func (r *FileTaskRepository) Save(task *domain.Task) error {
 	// Use write lock for thread safety
+	r.changeMu.Lock()
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	// Add task to in-memory map (or update if exists)
+	r.assignSeq(task)
 	r.tasks[task.ID().String()] = task
+	r.reindex()
 
-	// Call persist() to write to file
-	return r.persist()
+	// Write to file and release the lock
+	return r.persistAndUnlock()
 }
 
-// FindByID retrieves a task by its ID
-func (r *FileTaskRepository) FindByID(id domain.TaskID) (*domain.Task, error) {
+// SaveAll persists multiple tasks (create or update) with a single write to file
+func (r *FileTaskRepository) SaveAll(tasks []*domain.Task) error {
+	// Use write lock for thread safety
+	r.changeMu.Lock()
+	r.mu.Lock()
+
+	// Add all tasks to in-memory map (or update if they exist)
+	for _, task := range tasks {
+		r.assignSeq(task)
+		r.tasks[task.ID().String()] = task
+	}
+	r.reindex()
+
+	// Write to file once for the whole batch and release the lock
+	return r.persistAndUnlock()
+}
+
+// ReplaceAll replaces the entire collection with the given tasks and persists it once
+// If persisting fails, the previous collection is kept
+func (r *FileTaskRepository) ReplaceAll(tasks []*domain.Task) error {
+	// Written directly so it can be rolled back; fileMu keeps a concurrent
+	// coalesced write of an older snapshot from landing after it
+	r.changeMu.Lock()
+	defer r.changeMu.Unlock()
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
+
+	// Use write lock for thread safety
+	r.mu.Lock()
+	for _, task := range tasks {
+		r.assignSeq(task)
+	}
+	seq := r.seq
+	r.mu.Unlock()
+
+	return r.replaceAndPersist(tasks, seq)
+}
+
+// assignSeq assigns the next sequence number to a task being saved
+// Numbers are not reused even if persisting the change fails
+// Note: This method assumes the write lock is already held by the caller
+func (r *FileTaskRepository) assignSeq(task *domain.Task) {
+	r.seq++
+	task.AssignSeq(r.seq)
+}
+
+// LastSeq returns the sequence number of the latest change
+func (r *FileTaskRepository) LastSeq() (uint64, error) {
+	// A snapshot carries the sequence number of the tasks it holds
+	if snapshot := r.snapshot.Load(); snapshot != nil {
+		return snapshot.seq, nil
+	}
+
 	// Use read lock for thread safety
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	task, exists := r.tasks[id.String()]
+	return r.seq, nil
+}
+
+// DataFileSize returns the size of the data file in bytes, or 0 if it has not been written yet
+func (r *FileTaskRepository) DataFileSize() (int, error) {
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
+
+	data, err := r.store.ReadFile(r.filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, WrapFileSystemError("read", r.filePath, err)
+	}
+	return len(data), nil
+}
+
+// Compact rewrites the data file from the in-memory collection in the configured format,
+// atomically, and returns the size of the rewritten file
+// Changes still waiting for a coalesced write are included in the rewrite
+func (r *FileTaskRepository) Compact() (int, error) {
+	// Locked like ReplaceAll, since the file is written directly
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
+
+	r.mu.RLock()
+	data, err := r.marshalTasks(r.tasks, r.seq)
+	r.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	if err := r.persist(data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// WithTransaction runs fn against a staged copy of the collection and, if fn succeeds,
+// swaps in the staged tasks and writes the file once; a failing fn or write leaves the
+// collection and the file unchanged
+// Other changes wait until the transaction is done, so fn must only use tx, not the repository
+// itself; reads go on meanwhile and see the collection as it was before the transaction
+func (r *FileTaskRepository) WithTransaction(fn func(tx domain.TaskRepositoryTx) error) error {
+	// Locked like ReplaceAll, since the commit is written directly
+	r.changeMu.Lock()
+	defer r.changeMu.Unlock()
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
+
+	r.mu.RLock()
+	current := make([]*domain.Task, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		current = append(current, task)
+	}
+	staged := domain.NewStagedTaskRepository(current, r.seq)
+	r.mu.RUnlock()
+
+	if err := fn(staged); err != nil {
+		return err
+	}
+
+	tasks, err := staged.FindAll()
+	if err != nil {
+		return err
+	}
+	seq, err := staged.LastSeq()
+	if err != nil {
+		return err
+	}
+	return r.replaceAndPersist(tasks, seq)
+}
+
+// replaceAndPersist writes the given tasks and latest sequence number and, once written,
+// swaps them in for the collection; if the write fails the collection is left unchanged
+// The write lock is taken only to read the sequence number and to swap, so reads go on while
+// the file is written
+// Note: This method assumes changeMu and fileMu are already held by the caller
+func (r *FileTaskRepository) replaceAndPersist(tasks []*domain.Task, seq uint64) error {
+	replacement := make(map[string]*domain.Task, len(tasks))
+	for _, task := range tasks {
+		replacement[task.ID().String()] = task
+	}
+
+	// Numbers are not reused even if the write fails
+	r.mu.Lock()
+	if seq > r.seq {
+		r.seq = seq
+	}
+	seq = r.seq
+	r.mu.Unlock()
+
+	data, err := r.marshalTasks(replacement, seq)
+	if err != nil {
+		return err
+	}
+	snapshot := r.takeSnapshot(replacement, seq)
+	if err := r.persist(data); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks = replacement
+	r.reindex()
+	r.publishSnapshot(snapshot)
+
+	return nil
+}
+
+// FindByID retrieves a task by its ID
+func (r *FileTaskRepository) FindByID(id domain.TaskID) (*domain.Task, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
+
+	task, exists := tasks[id.String()]
 	if !exists {
 		return nil, domain.NewNotFoundError("Task", id.String())
 	}
@@ -148,13 +688,13 @@ func (r *FileTaskRepository) FindByID(id domain.TaskID) (*domain.Task, error) {
 
 // FindByParentID retrieves all tasks with the given parent ID, ordered by position
 func (r *FileTaskRepository) FindByParentID(parentID *domain.TaskID) ([]*domain.Task, error) {
-	// Use read lock for thread safety
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
 
 	var result []*domain.Task
 
-	for _, task := range r.tasks {
+	for _, task := range tasks {
 		// Check if this task has the matching parent
 		if parentID == nil && task.ParentID() == nil {
 			// Both are nil (root tasks)
@@ -173,93 +713,172 @@ func (r *FileTaskRepository) FindByParentID(parentID *domain.TaskID) ([]*domain.
 	return result, nil
 }
 
+// CountByParentID returns the number of tasks with the given parent ID
+// Unlike FindByParentID, it neither collects nor sorts the children
+func (r *FileTaskRepository) CountByParentID(parentID *domain.TaskID) (int, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	index, done := r.indexView()
+	defer done()
+
+	return index.CountChildren(parentID), nil
+}
+
+// NextChildPosition returns the position after the last child of the given parent
+func (r *FileTaskRepository) NextChildPosition(parentID *domain.TaskID) (int, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	index, done := r.indexView()
+	defer done()
+
+	return index.NextChildPosition(parentID), nil
+}
+
 // FindRoot retrieves the root task (task with no parent)
+// If several tasks have no parent (see StrictSingleRoot), the earliest created is
+// returned, tie-broken by ID, so the choice is stable across restarts
 func (r *FileTaskRepository) FindRoot() (*domain.Task, error) {
-	// Use read lock for thread safety
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	for _, task := range r.tasks {
-		if task.ParentID() == nil {
-			return task, nil
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
+
+	var root *domain.Task
+	for _, task := range tasks {
+		if task.ParentID() == nil && (root == nil || task.CreatedBefore(root)) {
+			root = task
 		}
 	}
 
-	return nil, domain.NewNotFoundError("Root Task", "root")
+	if root == nil {
+		return nil, domain.NewNotFoundError("Root Task", "root")
+	}
+	return root, nil
 }
 
 // FindAll retrieves all tasks
 func (r *FileTaskRepository) FindAll() ([]*domain.Task, error) {
-	// Use read lock for thread safety
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
 
-	result := make([]*domain.Task, 0, len(r.tasks))
-	for _, task := range r.tasks {
+	result := make([]*domain.Task, 0, len(tasks))
+	for _, task := range tasks {
 		result = append(result, task)
 	}
 
 	return result, nil
 }
 
+// FindByDescription retrieves all tasks whose description exactly matches (case-sensitive),
+// ordered by creation time (tie-broken by ID)
+func (r *FileTaskRepository) FindByDescription(description string) ([]*domain.Task, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	index, done := r.indexView()
+	defer done()
+
+	return index.WithDescription(description), nil
+}
+
+// ForEach calls fn for every task under a single read lock, stopping at the first error
+func (r *FileTaskRepository) ForEach(fn func(task *domain.Task) error) error {
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
+
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Count returns the total number of tasks
+func (r *FileTaskRepository) Count() (int, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
+
+	return len(tasks), nil
+}
+
+// CountByStatus returns the number of tasks in each status
+func (r *FileTaskRepository) CountByStatus() (map[domain.Status]int, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	tasks, done := r.view()
+	defer done()
+
+	counts := make(map[domain.Status]int)
+	for _, task := range tasks {
+		counts[task.Status()]++
+	}
+
+	return counts, nil
+}
+
+// FindSubtree retrieves the given task and all its descendants in depth-first order
+// The parent-to-children index is built once under a single read lock,
+// avoiding a full scan per node as with repeated FindByParentID calls
+func (r *FileTaskRepository) FindSubtree(rootID domain.TaskID) ([]*domain.Task, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	index, done := r.indexView()
+	defer done()
+
+	return index.Subtree(rootID)
+}
+
+// CountSubtree returns the number of tasks in the given task's subtree (itself included)
+func (r *FileTaskRepository) CountSubtree(rootID domain.TaskID) (int, error) {
+	// Use the snapshot, or the read lock, for thread safety
+	index, done := r.indexView()
+	defer done()
+
+	return index.CountSubtree(rootID)
+}
+
 // Delete removes a task by its ID
 func (r *FileTaskRepository) Delete(id domain.TaskID) error {
 	// Use write lock for thread safety
+	r.changeMu.Lock()
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	// Check if task exists
 	idStr := id.String()
 	if _, exists := r.tasks[idStr]; !exists {
+		r.mu.Unlock()
+		r.changeMu.Unlock()
 		return domain.NewNotFoundError("Task", idStr)
 	}
 
 	// Remove task from in-memory map
 	delete(r.tasks, idStr)
+	r.reindex()
+	r.seq++
 
-	// Call persist() to write changes
-	return r.persist()
+	// Write changes and release the lock
+	return r.persistAndUnlock()
 }
 
 // DeleteSubtree removes a task and all its descendants
 func (r *FileTaskRepository) DeleteSubtree(id domain.TaskID) error {
 	// Use write lock for thread safety
+	r.changeMu.Lock()
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
-	// Check if the task exists
-	idStr := id.String()
-	if _, exists := r.tasks[idStr]; !exists {
-		return domain.NewNotFoundError("Task", idStr)
+	// Collect the task and all its descendants, failing if the task does not exist
+	subtree, err := r.index.Subtree(id)
+	if err != nil {
+		r.mu.Unlock()
+		r.changeMu.Unlock()
+		return err
 	}
 
-	// Collect all tasks to delete (the task and all its descendants)
-	toDelete := r.collectDescendants(id)
-	toDelete = append(toDelete, id)
-
 	// Delete all collected tasks from in-memory map
-	for _, taskID := range toDelete {
-		delete(r.tasks, taskID.String())
-	}
-
-	// Call persist() to write changes
-	return r.persist()
-}
-
-// collectDescendants recursively collects all descendant task IDs
-// Note: This method assumes the lock is already held by the caller
-func (r *FileTaskRepository) collectDescendants(parentID domain.TaskID) []domain.TaskID {
-	var descendants []domain.TaskID
-
-	// Find all direct children
-	for _, task := range r.tasks {
-		if task.ParentID() != nil && task.ParentID().Equals(parentID) {
-			descendants = append(descendants, task.ID())
-			// Recursively collect descendants of this child
-			childDescendants := r.collectDescendants(task.ID())
-			descendants = append(descendants, childDescendants...)
-		}
+	for _, task := range subtree {
+		delete(r.tasks, task.ID().String())
 	}
+	r.reindex()
+	r.seq++
 
-	return descendants
+	// Write changes and release the lock
+	return r.persistAndUnlock()
 }
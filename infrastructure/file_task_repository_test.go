@@ -2,9 +2,14 @@ package infrastructure
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"discovery-tree/domain"
 )
@@ -112,6 +117,29 @@ func TestNewFileTaskRepository_EmptyFile(t *testing.T) {
 }
 
 // TestNewFileTaskRepository_LoadsExistingTasks tests loading existing tasks from file
+func TestNewFileTaskRepository_StrictTimestampsPerRepository(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+
+	// A record whose UpdatedAt is before its CreatedAt
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, _ := json.Marshal([]TaskDTO{timestampDTO(created, created.Add(-time.Hour))})
+	_ = os.WriteFile(testPath, data, 0644)
+
+	// A strict repository rejects the file while a lenient one in the same process corrects it
+	if _, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{StrictTimestamps: true}); err == nil {
+		t.Error("expected a strict repository to reject the out-of-order timestamps")
+	}
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{})
+	if err != nil {
+		t.Fatalf("expected a lenient repository to load the file, got %v", err)
+	}
+	defer repo.Close()
+	tasks, _ := repo.FindAll()
+	if len(tasks) != 1 || !tasks[0].UpdatedAt().Equal(created) {
+		t.Errorf("expected UpdatedAt clamped to %v, got %v", created, tasks)
+	}
+}
+
 func TestNewFileTaskRepository_LoadsExistingTasks(t *testing.T) {
 	testPath := "./test_data/existing.json"
 	os.RemoveAll("./test_data")
@@ -160,6 +188,85 @@ func TestNewFileTaskRepository_LoadsExistingTasks(t *testing.T) {
 	}
 }
 
+// TestNewFileTaskRepository_StrictSingleRoot tests that a file with two roots fails to load in strict mode
+func TestNewFileTaskRepository_StrictSingleRoot(t *testing.T) {
+	testPath := "./test_data/two_roots.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	// Write a file with two parentless tasks
+	root1, _ := domain.NewTask("Root 1", nil, 0)
+	root2, _ := domain.NewTask("Root 2", nil, 0)
+	_ = os.MkdirAll("./test_data", 0755)
+	data, _ := json.MarshalIndent([]TaskDTO{ToDTO(root1), ToDTO(root2)}, "", "  ")
+	_ = os.WriteFile(testPath, data, 0644)
+
+	// Without the strict check the file still loads
+	if _, err := NewFileTaskRepository(testPath); err != nil {
+		t.Fatalf("expected no error without strict check, got %v", err)
+	}
+
+	// With the strict check loading fails with an error naming both roots
+	_, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{StrictSingleRoot: true})
+	if err == nil {
+		t.Fatal("expected error for two root tasks, got nil")
+	}
+	constraintErr, ok := err.(domain.ConstraintViolationError)
+	if !ok {
+		t.Fatalf("expected ConstraintViolationError, got %T", err)
+	}
+	if constraintErr.Constraint != "single-root" {
+		t.Errorf("expected single-root constraint, got %s", constraintErr.Constraint)
+	}
+	for _, id := range []string{root1.ID().String(), root2.ID().String()} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("expected error to name root %s, got %v", id, err)
+		}
+	}
+}
+
+// TestFindRoot_DeterministicWithMultipleRoots tests that the same root is chosen repeatedly and across reloads
+func TestFindRoot_DeterministicWithMultipleRoots(t *testing.T) {
+	testPath := "./test_data/find_root_two_roots.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	// Two parentless tasks; the later-created one sorts first by ID to rule out ID-only ordering
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	earlier := domain.ReconstructTask(domain.TaskFields{ID: mustTaskID(t, "ffffffff-ffff-4fff-bfff-ffffffffffff"), Description: "Earlier", Status: domain.StatusRootWorkItem, CreatedAt: created, UpdatedAt: created})
+	later := domain.ReconstructTask(domain.TaskFields{ID: mustTaskID(t, "00000000-0000-4000-8000-000000000000"), Description: "Later", Status: domain.StatusRootWorkItem, CreatedAt: created.Add(time.Second), UpdatedAt: created.Add(time.Second)})
+
+	_ = os.MkdirAll("./test_data", 0755)
+	data, _ := json.MarshalIndent([]TaskDTO{ToDTO(later), ToDTO(earlier)}, "", "  ")
+	_ = os.WriteFile(testPath, data, 0644)
+
+	for i := 0; i < 5; i++ {
+		// Reload each time so map iteration order varies
+		repo, err := NewFileTaskRepository(testPath)
+		if err != nil {
+			t.Fatalf("expected no error loading repository, got %v", err)
+		}
+
+		root, err := repo.FindRoot()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !root.ID().Equals(earlier.ID()) {
+			t.Fatalf("expected earliest-created root %s, got %s", earlier.ID(), root.ID())
+		}
+	}
+}
+
+// mustTaskID parses a task ID or fails the test
+func mustTaskID(t *testing.T, s string) domain.TaskID {
+	t.Helper()
+	id, err := domain.TaskIDFromString(s)
+	if err != nil {
+		t.Fatalf("invalid task ID %s: %v", s, err)
+	}
+	return id
+}
+
 // TestNewFileTaskRepository_InvalidJSON tests error handling for invalid JSON
 func TestNewFileTaskRepository_InvalidJSON(t *testing.T) {
 	testPath := "./test_data/invalid.json"
@@ -176,9 +283,16 @@ func TestNewFileTaskRepository_InvalidJSON(t *testing.T) {
 		t.Fatal("expected error for invalid JSON, got nil")
 	}
 
-	// Should be a FileSystemError
-	if _, ok := err.(FileSystemError); !ok {
-		t.Errorf("expected FileSystemError, got %T", err)
+	// Should be a FileSystemError for the parse step
+	fsErr, ok := err.(FileSystemError)
+	if !ok {
+		t.Fatalf("expected FileSystemError, got %T", err)
+	}
+	if fsErr.Op != "parse JSON" {
+		t.Errorf("expected operation 'parse JSON', got %q", fsErr.Op)
+	}
+	if fsErr.Path != testPath {
+		t.Errorf("expected path %q, got %q", testPath, fsErr.Path)
 	}
 }
 
@@ -432,10 +546,11 @@ func TestSave_CreatesNewTask(t *testing.T) {
 		t.Fatalf("expected file to exist, got error: %v", err)
 	}
 
-	var dtos []TaskDTO
-	if err := json.Unmarshal(data, &dtos); err != nil {
+	var file dataFileDTO
+	if err := json.Unmarshal(data, &file); err != nil {
 		t.Fatalf("expected valid JSON, got error: %v", err)
 	}
+	dtos := file.Tasks
 
 	if len(dtos) != 1 {
 		t.Errorf("expected 1 task in file, got %d", len(dtos))
@@ -484,8 +599,9 @@ func TestSave_UpdatesExistingTask(t *testing.T) {
 		t.Fatalf("expected file to exist, got error: %v", err)
 	}
 
-	var dtos []TaskDTO
-	_ = json.Unmarshal(data, &dtos)
+	var file dataFileDTO
+	_ = json.Unmarshal(data, &file)
+	dtos := file.Tasks
 
 	if len(dtos) != 1 {
 		t.Errorf("expected 1 task in file, got %d", len(dtos))
@@ -526,8 +642,9 @@ func TestSave_MultipleTasks(t *testing.T) {
 		t.Fatalf("expected file to exist, got error: %v", err)
 	}
 
-	var dtos []TaskDTO
-	_ = json.Unmarshal(data, &dtos)
+	var file dataFileDTO
+	_ = json.Unmarshal(data, &file)
+	dtos := file.Tasks
 
 	if len(dtos) != 3 {
 		t.Errorf("expected 3 tasks in file, got %d", len(dtos))
@@ -565,6 +682,68 @@ func TestSave_PersistsToFile(t *testing.T) {
 	}
 }
 
+// TestSaveAll_PersistsAllTasks tests that SaveAll writes every task to file
+func TestSaveAll_PersistsAllTasks(t *testing.T) {
+	testPath := "./test_data/save_all.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	repo1, err := NewFileTaskRepository(testPath)
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	child1, _ := domain.NewTask("Child 1", &rootID, 0)
+	child2, _ := domain.NewTask("Child 2", &rootID, 1)
+
+	if err := repo1.SaveAll([]*domain.Task{root, child1, child2}); err != nil {
+		t.Fatalf("expected no error saving tasks, got %v", err)
+	}
+
+	// Create a new repository instance (loads from file)
+	repo2, err := NewFileTaskRepository(testPath)
+	if err != nil {
+		t.Fatalf("expected no error loading repository, got %v", err)
+	}
+
+	if len(repo2.tasks) != 3 {
+		t.Errorf("expected 3 tasks loaded from file, got %d", len(repo2.tasks))
+	}
+}
+
+// TestCount_ReflectsSavesAndDeletes tests that Count tracks the number of stored tasks
+func TestCount_ReflectsSavesAndDeletes(t *testing.T) {
+	testPath := "./test_data/count.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	repo, err := NewFileTaskRepository(testPath)
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	child, _ := domain.NewTask("Child", &rootID, 0)
+	if err := repo.SaveAll([]*domain.Task{root, child}); err != nil {
+		t.Fatalf("expected no error saving tasks, got %v", err)
+	}
+
+	if count, _ := repo.Count(); count != 2 {
+		t.Errorf("expected count 2, got %d", count)
+	}
+
+	if err := repo.Delete(child.ID()); err != nil {
+		t.Fatalf("expected no error deleting task, got %v", err)
+	}
+
+	if count, _ := repo.Count(); count != 1 {
+		t.Errorf("expected count 1 after delete, got %d", count)
+	}
+}
+
 // TestFindByID_ExistingTask tests finding a task by ID
 func TestFindByID_ExistingTask(t *testing.T) {
 	testPath := "./test_data/find_by_id.json"
@@ -1183,6 +1362,160 @@ func TestDeleteSubtree_DeepTree(t *testing.T) {
 	}
 }
 
+// TestFindSubtree_DepthFirstOrder tests that FindSubtree returns the task and its descendants in order
+func TestFindSubtree_DepthFirstOrder(t *testing.T) {
+	testPath := "./test_data/find_subtree.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	repo, _ := NewFileTaskRepository(testPath)
+
+	// Create tree: root -> a -> a1
+	//                   -> b
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	a, _ := domain.NewTask("A", &rootID, 0)
+	aID := a.ID()
+	b, _ := domain.NewTask("B", &rootID, 1)
+	a1, _ := domain.NewTask("A1", &aID, 0)
+	_ = repo.SaveAll([]*domain.Task{b, a1, root, a})
+
+	subtree, err := repo.FindSubtree(rootID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []*domain.Task{root, a, a1, b}
+	if len(subtree) != len(expected) {
+		t.Fatalf("expected %d tasks, got %d", len(expected), len(subtree))
+	}
+	for i, task := range expected {
+		if !subtree[i].ID().Equals(task.ID()) {
+			t.Errorf("expected %s at index %d, got %s", task.Description(), i, subtree[i].Description())
+		}
+	}
+
+	// Non-existent root returns NotFoundError
+	if _, err := repo.FindSubtree(domain.NewTaskID()); err == nil {
+		t.Error("expected error for non-existent task, got nil")
+	}
+}
+
+// TestCountSubtree tests that CountSubtree counts the task and its descendants
+func TestCountSubtree(t *testing.T) {
+	testPath := "./test_data/count_subtree.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	repo, _ := NewFileTaskRepository(testPath)
+
+	// Create tree: root -> a -> a1
+	//                   -> b
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	a, _ := domain.NewTask("A", &rootID, 0)
+	aID := a.ID()
+	b, _ := domain.NewTask("B", &rootID, 1)
+	a1, _ := domain.NewTask("A1", &aID, 0)
+	_ = repo.SaveAll([]*domain.Task{b, a1, root, a})
+
+	count, err := repo.CountSubtree(rootID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != 4 {
+		t.Errorf("expected 4 tasks, got %d", count)
+	}
+	if count, _ := repo.CountSubtree(aID); count != 2 {
+		t.Errorf("expected 2 tasks in branch, got %d", count)
+	}
+
+	// Non-existent root returns NotFoundError
+	if _, err := repo.CountSubtree(domain.NewTaskID()); err == nil {
+		t.Error("expected error for non-existent task, got nil")
+	}
+}
+
+func TestFindByDescription(t *testing.T) {
+	testPath := "./test_data/find_by_description.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	repo, _ := NewFileTaskRepository(testPath)
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	first, _ := domain.NewTask("Deploy", &rootID, 0)
+	second, _ := domain.NewTask("Deploy", &rootID, 1)
+	_ = repo.SaveAll([]*domain.Task{root, first, second})
+
+	matches, err := repo.FindByDescription("Deploy")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("expected 2 matches, got %d", len(matches))
+	}
+
+	// Matching is case-sensitive
+	if matches, _ := repo.FindByDescription("deploy"); len(matches) != 0 {
+		t.Errorf("expected no matches, got %d", len(matches))
+	}
+}
+
+// buildBenchmarkTree creates a tree of the given size with a fixed branching factor
+func buildBenchmarkTree(b *testing.B, repo *FileTaskRepository, size, branching int) domain.TaskID {
+	b.Helper()
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	tasks := []*domain.Task{root}
+	for i := 0; len(tasks) < size; i++ {
+		parentID := tasks[i].ID()
+		for j := 0; j < branching && len(tasks) < size; j++ {
+			child, _ := domain.NewTask("Task", &parentID, j)
+			tasks = append(tasks, child)
+		}
+	}
+
+	if err := repo.SaveAll(tasks); err != nil {
+		b.Fatalf("failed to save tasks: %v", err)
+	}
+	return root.ID()
+}
+
+// collectSubtreeRecursively collects a subtree with one FindByParentID call per node
+func collectSubtreeRecursively(repo *FileTaskRepository, taskID domain.TaskID) []*domain.Task {
+	task, _ := repo.FindByID(taskID)
+	result := []*domain.Task{task}
+	children, _ := repo.FindByParentID(&taskID)
+	for _, child := range children {
+		result = append(result, collectSubtreeRecursively(repo, child.ID())...)
+	}
+	return result
+}
+
+// BenchmarkSubtree_Recursive measures subtree collection via repeated FindByParentID calls
+func BenchmarkSubtree_Recursive(b *testing.B) {
+	repo, _ := NewFileTaskRepository(b.TempDir() + "/tasks.json")
+	rootID := buildBenchmarkTree(b, repo, 1000, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		collectSubtreeRecursively(repo, rootID)
+	}
+}
+
+// BenchmarkSubtree_SinglePass measures subtree collection via FindSubtree
+func BenchmarkSubtree_SinglePass(b *testing.B) {
+	repo, _ := NewFileTaskRepository(b.TempDir() + "/tasks.json")
+	rootID := buildBenchmarkTree(b, repo, 1000, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = repo.FindSubtree(rootID)
+	}
+}
+
 // TestConcurrentReads tests that concurrent read operations are safe
 func TestConcurrentReads(t *testing.T) {
 	testPath := "./test_data/concurrent_reads.json"
@@ -1317,6 +1650,52 @@ func TestConcurrentWrites(t *testing.T) {
 	}
 }
 
+// TestConcurrentChildCreates tests that concurrent creates under one parent get unique, contiguous positions
+func TestConcurrentChildCreates(t *testing.T) {
+	testPath := "./test_data/concurrent_child_creates.json"
+	os.RemoveAll("./test_data")
+	defer os.RemoveAll("./test_data")
+
+	repo, _ := NewFileTaskRepository(testPath)
+	service := domain.NewTaskService(repo)
+	root, err := service.CreateRootTask("Root")
+	if err != nil {
+		t.Fatalf("failed to create root: %v", err)
+	}
+
+	const numGoroutines = 50
+	var wg sync.WaitGroup
+	errCh := make(chan error, numGoroutines)
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			if _, err := service.CreateChildTask(fmt.Sprintf("Child %d", index), root.ID()); err != nil {
+				errCh <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errCh)
+	if err := <-errCh; err != nil {
+		t.Fatalf("CreateChildTask failed: %v", err)
+	}
+
+	// Reload from disk so the persisted positions are checked
+	reloaded, _ := NewFileTaskRepository(testPath)
+	rootID := root.ID()
+	children, _ := reloaded.FindByParentID(&rootID)
+	if len(children) != numGoroutines {
+		t.Fatalf("expected %d children, got %d", numGoroutines, len(children))
+	}
+	for i, child := range children {
+		if child.Position() != i {
+			t.Fatalf("expected positions 0..%d without gaps or duplicates, got %d at index %d", numGoroutines-1, child.Position(), i)
+		}
+	}
+}
+
 // TestConcurrentSaves tests that concurrent save operations are safe
 func TestConcurrentSaves(t *testing.T) {
 	testPath := "./test_data/concurrent_saves.json"
@@ -1568,3 +1947,691 @@ func TestConcurrentDeleteSubtree(t *testing.T) {
 		t.Errorf("expected 1 task in file after concurrent DeleteSubtree, got %d", len(tasks2))
 	}
 }
+
+// TestCoalesceWrites_ConcurrentSavesArePersisted tests that concurrent saves batched by the
+// background writer all reach the file and each caller sees its write succeed
+func TestCoalesceWrites_ConcurrentSavesArePersisted(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{CoalesceWrites: true})
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	if err := repo.Save(root); err != nil {
+		t.Fatalf("failed to save root: %v", err)
+	}
+	rootID := root.ID()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(position int) {
+			defer wg.Done()
+			child, _ := domain.NewTask("Child", &rootID, position)
+			errs <- repo.Save(child)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected no error from coalesced save, got %v", err)
+		}
+	}
+
+	if err := repo.Flush(); err != nil {
+		t.Fatalf("expected no error from Flush, got %v", err)
+	}
+
+	reloaded, err := NewFileTaskRepository(testPath)
+	if err != nil {
+		t.Fatalf("expected no error reloading repository, got %v", err)
+	}
+	if count, _ := reloaded.Count(); count != 51 {
+		t.Errorf("expected 51 tasks in file, got %d", count)
+	}
+}
+
+// TestTimestamps_RoundTripIsByteIdentical tests that a task created, persisted, reloaded and
+// persisted again produces byte-identical timestamps, including legacy nanosecond data
+func TestTimestamps_RoundTripIsByteIdentical(t *testing.T) {
+	// Re-saving is a change of its own, so sequence numbers differ between the two files
+	seqPattern := regexp.MustCompile(`"seq": \d+`)
+
+	// persistAndReload saves the task in a fresh repository at path, then reloads the file and
+	// saves the reloaded task again, returning the file contents after each save with the
+	// sequence numbers masked
+	persistAndReload := func(t *testing.T, path string, save func(repo *FileTaskRepository) domain.TaskID) (string, string) {
+		repo, err := NewFileTaskRepository(path)
+		if err != nil {
+			t.Fatalf("expected no error creating repository, got %v", err)
+		}
+		id := save(repo)
+		first, _ := os.ReadFile(path)
+
+		reloaded, err := NewFileTaskRepository(path)
+		if err != nil {
+			t.Fatalf("expected no error reloading repository, got %v", err)
+		}
+		task, err := reloaded.FindByID(id)
+		if err != nil {
+			t.Fatalf("expected reloaded task, got %v", err)
+		}
+		if err := reloaded.Save(task); err != nil {
+			t.Fatalf("expected no error re-persisting, got %v", err)
+		}
+		second, _ := os.ReadFile(path)
+		return seqPattern.ReplaceAllString(string(first), `"seq": N`), seqPattern.ReplaceAllString(string(second), `"seq": N`)
+	}
+
+	t.Run("NewTask", func(t *testing.T) {
+		first, second := persistAndReload(t, t.TempDir()+"/tasks.json", func(repo *FileTaskRepository) domain.TaskID {
+			task, _ := domain.NewTask("Root", nil, 0)
+			_ = task.ChangeStatus(domain.StatusDONE)
+			if err := repo.Save(task); err != nil {
+				t.Fatalf("failed to save task: %v", err)
+			}
+			return task.ID()
+		})
+		if first != second {
+			t.Errorf("expected identical files after round trip\nfirst:  %s\nsecond: %s", first, second)
+		}
+	})
+
+	t.Run("LegacyNanosecondData", func(t *testing.T) {
+		path := t.TempDir() + "/tasks.json"
+		legacy := `[{"id": "550e8400-e29b-41d4-a716-446655440000", "description": "Root", "status": "Root Work Item", "parentId": null, "position": 0,
+			"createdAt": "2024-01-02T03:04:05.123456789+02:00", "updatedAt": "2024-01-02T03:04:06.987654321+02:00"}]`
+		if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+			t.Fatalf("failed to write legacy file: %v", err)
+		}
+
+		first, second := persistAndReload(t, path, func(repo *FileTaskRepository) domain.TaskID {
+			id, _ := domain.TaskIDFromString("550e8400-e29b-41d4-a716-446655440000")
+			task, _ := repo.FindByID(id)
+			if err := repo.Save(task); err != nil {
+				t.Fatalf("failed to save task: %v", err)
+			}
+			return id
+		})
+		if first != second {
+			t.Errorf("expected identical files after round trip\nfirst:  %s\nsecond: %s", first, second)
+		}
+		if !strings.Contains(first, `"createdAt": "2024-01-02T01:04:05.123456Z"`) {
+			t.Errorf("expected createdAt in UTC at microsecond precision, got %s", first)
+		}
+	})
+}
+
+// TestPersistDebounce_CoalescesSavesWithinWindow tests that saves made within one debounce
+// window share a write and are counted as coalesced
+func TestPersistDebounce_CoalescesSavesWithinWindow(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	repo, err := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{
+		CoalesceWrites:  true,
+		PersistDebounce: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating repository, got %v", err)
+	}
+	defer repo.Close()
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	if err := repo.Save(root); err != nil {
+		t.Fatalf("failed to save root: %v", err)
+	}
+	before := repo.PersistStats()
+	if before.Requested != 1 || before.Flushed != 1 || before.Coalesced != 0 || before.Pending != 0 {
+		t.Fatalf("expected a single flushed change, got %+v", before)
+	}
+	rootID := root.ID()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(position int) {
+			defer wg.Done()
+			child, _ := domain.NewTask("Child", &rootID, position)
+			if err := repo.Save(child); err != nil {
+				t.Errorf("expected no error from debounced save, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	after := repo.PersistStats()
+	if after.Requested != 6 {
+		t.Errorf("expected 6 requested changes, got %d", after.Requested)
+	}
+	if after.Coalesced <= before.Coalesced {
+		t.Errorf("expected saves within one window to be coalesced, got %+v", after)
+	}
+	if after.Pending != 0 {
+		t.Errorf("expected no pending changes, got %d", after.Pending)
+	}
+	if after.Flushed+after.Coalesced != after.Requested {
+		t.Errorf("expected flushed + coalesced to equal requested, got %+v", after)
+	}
+}
+
+// TestPersistStats_DirectWrites tests that without CoalesceWrites every change is its own write
+func TestPersistStats_DirectWrites(t *testing.T) {
+	repo, _ := NewFileTaskRepository(t.TempDir() + "/tasks.json")
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	repo.Save(root)
+	repo.Save(root)
+
+	stats := repo.PersistStats()
+	expected := PersistStats{Requested: 2, Flushed: 2}
+	if stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+// TestCoalesceWrites_CloseRejectsFurtherWrites tests that Close flushes and stops the writer
+func TestCoalesceWrites_CloseRejectsFurtherWrites(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	repo, _ := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{CoalesceWrites: true})
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	if err := repo.Save(root); err != nil {
+		t.Fatalf("failed to save root: %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected no error from Close, got %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Errorf("expected a second Close to be a no-op, got %v", err)
+	}
+
+	rootID := root.ID()
+	child, _ := domain.NewTask("Child", &rootID, 0)
+	if err := repo.Save(child); err == nil {
+		t.Error("expected Save after Close to fail")
+	}
+
+	reloaded, _ := NewFileTaskRepository(testPath)
+	if count, _ := reloaded.Count(); count != 1 {
+		t.Errorf("expected 1 task in file, got %d", count)
+	}
+}
+
+func TestCoalesceWrites_CloseDuringConcurrentSaves(t *testing.T) {
+	repo, _ := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", FileTaskRepositoryOptions{CoalesceWrites: true})
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	if err := repo.Save(root); err != nil {
+		t.Fatalf("failed to save root: %v", err)
+	}
+	rootID := root.ID()
+
+	// Saves racing with Close either succeed or are rejected; none may panic
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(position int) {
+			defer wg.Done()
+			child, _ := domain.NewTask("Child", &rootID, position)
+			_ = repo.Save(child)
+		}(i)
+	}
+	if err := repo.Close(); err != nil {
+		t.Errorf("expected no error from Close, got %v", err)
+	}
+	wg.Wait()
+}
+
+// benchmarkConcurrentSaves measures 100 concurrent Saves into a tree of 100 tasks
+func benchmarkConcurrentSaves(b *testing.B, options FileTaskRepositoryOptions) {
+	repo, _ := NewFileTaskRepositoryWithOptions(b.TempDir()+"/tasks.json", options)
+	defer repo.Close()
+	rootID := buildBenchmarkTree(b, repo, 100, 10)
+
+	tasks := make([]*domain.Task, 100)
+	for i := range tasks {
+		tasks[i], _ = domain.NewTask("Concurrent", &rootID, 100+i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var wg sync.WaitGroup
+		for _, task := range tasks {
+			wg.Add(1)
+			go func(task *domain.Task) {
+				defer wg.Done()
+				if err := repo.Save(task); err != nil {
+					b.Errorf("failed to save task: %v", err)
+				}
+			}(task)
+		}
+		wg.Wait()
+	}
+}
+
+// BenchmarkConcurrentSaves_Synchronous writes the file once per Save under the write lock
+func BenchmarkConcurrentSaves_Synchronous(b *testing.B) {
+	benchmarkConcurrentSaves(b, FileTaskRepositoryOptions{})
+}
+
+// BenchmarkConcurrentSaves_Coalesced batches concurrent Saves into background writes
+func BenchmarkConcurrentSaves_Coalesced(b *testing.B) {
+	benchmarkConcurrentSaves(b, FileTaskRepositoryOptions{CoalesceWrites: true})
+}
+
+// TestWithTransaction_CommitsWithSingleWrite tests that all changes in a transaction are written once
+func TestWithTransaction_CommitsWithSingleWrite(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	store := &flakyFileStore{FileStore: NewOSFileStore()}
+	repo, _ := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{Store: store})
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+	writesBefore := store.writes
+
+	rootID := root.ID()
+	err := repo.WithTransaction(func(tx domain.TaskRepositoryTx) error {
+		for i := 0; i < 3; i++ {
+			child, _ := domain.NewTask("Child", &rootID, i)
+			if err := tx.Save(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if writes := store.writes - writesBefore; writes != 1 {
+		t.Errorf("expected 1 write for the transaction, got %d", writes)
+	}
+	reloaded, _ := NewFileTaskRepository(testPath)
+	if count, _ := reloaded.Count(); count != 4 {
+		t.Errorf("expected 4 tasks in file, got %d", count)
+	}
+}
+
+// TestWithTransaction_FailureLeavesStoreUnchanged tests that a failing transaction changes
+// neither the in-memory collection nor the file
+func TestWithTransaction_FailureLeavesStoreUnchanged(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	store := &flakyFileStore{FileStore: NewOSFileStore()}
+	repo, _ := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{Store: store})
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	rootID := root.ID()
+	child, _ := domain.NewTask("Child", &rootID, 0)
+	_ = repo.SaveAll([]*domain.Task{root, child})
+	writesBefore := store.writes
+
+	failure := domain.NewConstraintViolationError("test", "abort")
+	err := repo.WithTransaction(func(tx domain.TaskRepositoryTx) error {
+		staged, _ := tx.FindByID(rootID)
+		_ = staged.UpdateDescription("Renamed")
+		_ = tx.Delete(child.ID())
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("expected the transaction's error, got %v", err)
+	}
+
+	if store.writes != writesBefore {
+		t.Errorf("expected no write for a failed transaction, got %d", store.writes-writesBefore)
+	}
+	for _, check := range []*FileTaskRepository{repo, mustReload(t, testPath)} {
+		if count, _ := check.Count(); count != 2 {
+			t.Errorf("expected 2 tasks, got %d", count)
+		}
+		stored, _ := check.FindByID(rootID)
+		if stored.Description() != "Root" {
+			t.Errorf("expected description 'Root', got %q", stored.Description())
+		}
+	}
+}
+
+// TestWithTransaction_WriteFailureRollsBack tests that a commit whose write fails is discarded
+func TestWithTransaction_WriteFailureRollsBack(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	store := &flakyFileStore{FileStore: NewOSFileStore(), err: syscall.ENOSPC}
+	repo, _ := NewFileTaskRepositoryWithOptions(testPath, FileTaskRepositoryOptions{Store: store})
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+	store.failures = store.writes + 1
+
+	rootID := root.ID()
+	err := repo.WithTransaction(func(tx domain.TaskRepositoryTx) error {
+		child, _ := domain.NewTask("Child", &rootID, 0)
+		return tx.Save(child)
+	})
+	if err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+
+	if count, _ := repo.Count(); count != 1 {
+		t.Errorf("expected 1 task after rollback, got %d", count)
+	}
+}
+
+// TestSeq_IncreasesOnEachChangeAndSurvivesReload tests that every save, transaction and delete
+// moves the sequence forward and that the latest number is kept in the data file
+func TestSeq_IncreasesOnEachChangeAndSurvivesReload(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	repo, _ := NewFileTaskRepository(testPath)
+
+	lastSeq := func(repo *FileTaskRepository) uint64 {
+		seq, err := repo.LastSeq()
+		if err != nil {
+			t.Fatalf("expected no error reading seq, got %v", err)
+		}
+		return seq
+	}
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+	rootID := root.ID()
+	a, _ := domain.NewTask("A", &rootID, 0)
+	b, _ := domain.NewTask("B", &rootID, 1)
+	_ = repo.SaveAll([]*domain.Task{a, b})
+	if root.Seq() != 1 || a.Seq() != 2 || b.Seq() != 3 {
+		t.Fatalf("expected seqs 1, 2, 3, got %d, %d, %d", root.Seq(), a.Seq(), b.Seq())
+	}
+
+	// A transaction assigns numbers only to the tasks it saves
+	err := repo.WithTransaction(func(tx domain.TaskRepositoryTx) error {
+		staged, _ := tx.FindByID(a.ID())
+		_ = staged.UpdateDescription("A2")
+		return tx.Save(staged)
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	updated, _ := repo.FindByID(a.ID())
+	if updated.Seq() != 4 || lastSeq(repo) != 4 {
+		t.Errorf("expected A at seq 4 and last seq 4, got %d and %d", updated.Seq(), lastSeq(repo))
+	}
+	if stored, _ := repo.FindByID(b.ID()); stored.Seq() != 3 {
+		t.Errorf("expected B to keep seq 3, got %d", stored.Seq())
+	}
+
+	// Deleting the latest task still moves the sequence forward
+	_ = repo.Delete(a.ID())
+	if lastSeq(repo) != 5 {
+		t.Errorf("expected last seq 5 after delete, got %d", lastSeq(repo))
+	}
+
+	// The numbers, including the delete's, survive a reload and keep growing from there
+	reloaded := mustReload(t, testPath)
+	if lastSeq(reloaded) != 5 {
+		t.Errorf("expected last seq 5 after reload, got %d", lastSeq(reloaded))
+	}
+	if stored, _ := reloaded.FindByID(b.ID()); stored.Seq() != 3 {
+		t.Errorf("expected B at seq 3 after reload, got %d", stored.Seq())
+	}
+	c, _ := domain.NewTask("C", &rootID, 1)
+	_ = reloaded.Save(c)
+	if c.Seq() != 6 {
+		t.Errorf("expected C at seq 6, got %d", c.Seq())
+	}
+}
+
+// TestLoad_LegacyArrayFile tests that a data file holding a bare array of tasks still loads,
+// with the sequence continuing from the highest task seq
+func TestLoad_LegacyArrayFile(t *testing.T) {
+	testPath := t.TempDir() + "/tasks.json"
+	legacy := `[{"id": "550e8400-e29b-41d4-a716-446655440000", "description": "Root", "status": "Root Work Item", "parentId": null, "position": 0,
+		"createdAt": "2024-01-02T03:04:05Z", "updatedAt": "2024-01-02T03:04:06Z", "seq": 7}]`
+	if err := os.WriteFile(testPath, []byte(legacy), 0644); err != nil {
+		t.Fatalf("failed to write legacy file: %v", err)
+	}
+
+	repo := mustReload(t, testPath)
+	if count, _ := repo.Count(); count != 1 {
+		t.Fatalf("expected 1 task, got %d", count)
+	}
+	if seq, _ := repo.LastSeq(); seq != 7 {
+		t.Errorf("expected last seq 7, got %d", seq)
+	}
+}
+
+// mustReload loads a fresh repository from the given file
+func mustReload(t *testing.T, path string) *FileTaskRepository {
+	t.Helper()
+	repo, err := NewFileTaskRepository(path)
+	if err != nil {
+		t.Fatalf("expected no error reloading repository, got %v", err)
+	}
+	return repo
+}
+
+// TestSnapshotReads_ReadAfterWriteSeesChange tests that every kind of write is visible to reads
+// made after it returns, with direct and coalesced writes
+func TestSnapshotReads_ReadAfterWriteSeesChange(t *testing.T) {
+	for name, options := range map[string]FileTaskRepositoryOptions{
+		"direct":    {SnapshotReads: true},
+		"coalesced": {SnapshotReads: true, CoalesceWrites: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo, err := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", options)
+			if err != nil {
+				t.Fatalf("expected no error creating repository, got %v", err)
+			}
+			defer repo.Close()
+
+			root, _ := domain.NewTask("Root", nil, 0)
+			if err := repo.Save(root); err != nil {
+				t.Fatalf("failed to save root: %v", err)
+			}
+			if _, err := repo.FindByID(root.ID()); err != nil {
+				t.Fatalf("expected saved root to be readable, got %v", err)
+			}
+
+			rootID := root.ID()
+			child, _ := domain.NewTask("Child", &rootID, 0)
+			if err := repo.SaveAll([]*domain.Task{child}); err != nil {
+				t.Fatalf("failed to save child: %v", err)
+			}
+			if children, _ := repo.FindByParentID(&rootID); len(children) != 1 {
+				t.Errorf("expected 1 child after SaveAll, got %d", len(children))
+			}
+
+			grandchild, _ := domain.NewTask("Grandchild", &rootID, 1)
+			if err := repo.WithTransaction(func(tx domain.TaskRepositoryTx) error {
+				return tx.Save(grandchild)
+			}); err != nil {
+				t.Fatalf("failed to commit transaction: %v", err)
+			}
+			if subtree, _ := repo.FindSubtree(rootID); len(subtree) != 3 {
+				t.Errorf("expected 3 tasks in subtree after transaction, got %d", len(subtree))
+			}
+
+			if err := repo.Delete(grandchild.ID()); err != nil {
+				t.Fatalf("failed to delete: %v", err)
+			}
+			if _, err := repo.FindByID(grandchild.ID()); err == nil {
+				t.Error("expected deleted task to be gone")
+			}
+
+			if err := repo.DeleteSubtree(rootID); err != nil {
+				t.Fatalf("failed to delete subtree: %v", err)
+			}
+			if count, _ := repo.Count(); count != 0 {
+				t.Errorf("expected empty collection after DeleteSubtree, got %d", count)
+			}
+
+			// The snapshot's sequence number matches the collection's
+			repo.mu.RLock()
+			want := repo.seq
+			repo.mu.RUnlock()
+			if got, _ := repo.LastSeq(); got != want {
+				t.Errorf("expected LastSeq %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+// TestSnapshotReads_FailedWriteNotVisible tests that a change whose write failed is not published
+func TestSnapshotReads_FailedWriteNotVisible(t *testing.T) {
+	store := &flakyFileStore{FileStore: NewOSFileStore(), err: syscall.ENOSPC}
+	repo, _ := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", FileTaskRepositoryOptions{
+		Store:         store,
+		SnapshotReads: true,
+	})
+
+	root, _ := domain.NewTask("Root", nil, 0)
+	store.failures = 1
+	if err := repo.Save(root); err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+	if _, err := repo.FindByID(root.ID()); err == nil {
+		t.Error("expected the unpersisted task not to be readable")
+	}
+}
+
+// TestSnapshotReads_ReadsDoNotWaitForWriteLock tests that reads complete while a writer holds the lock
+func TestSnapshotReads_ReadsDoNotWaitForWriteLock(t *testing.T) {
+	repo, _ := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", FileTaskRepositoryOptions{SnapshotReads: true})
+	root, _ := domain.NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.FindRoot()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected root to be found, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("read blocked on the write lock")
+	}
+}
+
+func TestSnapshotReads_SnapshotDoesNotShareTasks(t *testing.T) {
+	repo, _ := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", FileTaskRepositoryOptions{SnapshotReads: true})
+	root, _ := domain.NewTask("Root", nil, 0)
+	_ = repo.Save(root)
+
+	// A change made to the saved task without saving it again is not visible to reads
+	_ = root.UpdateDescription("Changed")
+	read, err := repo.FindByID(root.ID())
+	if err != nil {
+		t.Fatalf("expected root to be found, got %v", err)
+	}
+	if read == root {
+		t.Fatal("expected reads to return the snapshot's copy, not the saved task")
+	}
+	if read.Description() != "Root" {
+		t.Errorf("expected the persisted description, got %q", read.Description())
+	}
+}
+
+// gatedFileStore blocks each write until a token is sent on release, announcing it on writing
+type gatedFileStore struct {
+	FileStore
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (s *gatedFileStore) WriteFile(path string, data []byte, perm os.FileMode) error {
+	s.writing <- struct{}{}
+	<-s.release
+	return s.FileStore.WriteFile(path, data, perm)
+}
+
+func TestSnapshotReads_CoalescedChangeNotVisibleUntilWritten(t *testing.T) {
+	store := &gatedFileStore{FileStore: NewOSFileStore(), writing: make(chan struct{}), release: make(chan struct{})}
+	repo, _ := NewFileTaskRepositoryWithOptions(t.TempDir()+"/tasks.json", FileTaskRepositoryOptions{
+		Store:          store,
+		CoalesceWrites: true,
+		SnapshotReads:  true,
+	})
+	defer repo.Close()
+
+	// Save a while its write is in progress, then b, whose change needs another write
+	a, _ := domain.NewTask("A", nil, 0)
+	b, _ := domain.NewTask("B", nil, 1)
+	savedA := make(chan error, 1)
+	go func() { savedA <- repo.Save(a) }()
+	<-store.writing
+
+	savedB := make(chan error, 1)
+	go func() { savedB <- repo.Save(b) }()
+	for repo.PersistStats().Requested < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Finish a's write and hold b's
+	store.release <- struct{}{}
+	<-store.writing
+	if err := <-savedA; err != nil {
+		t.Fatalf("expected a to be saved, got %v", err)
+	}
+	if _, err := repo.FindByID(a.ID()); err != nil {
+		t.Errorf("expected a to be visible once written, got %v", err)
+	}
+	if _, err := repo.FindByID(b.ID()); err == nil {
+		t.Error("expected b not to be visible before it is written")
+	}
+
+	store.release <- struct{}{}
+	if err := <-savedB; err != nil {
+		t.Fatalf("expected b to be saved, got %v", err)
+	}
+	if _, err := repo.FindByID(b.ID()); err != nil {
+		t.Errorf("expected b to be visible once written, got %v", err)
+	}
+}
+
+// benchmarkReadsUnderWrites measures FindByParentID on a tree of 100 tasks while a writer saves continuously
+func benchmarkReadsUnderWrites(b *testing.B, options FileTaskRepositoryOptions) {
+	repo, _ := NewFileTaskRepositoryWithOptions(b.TempDir()+"/tasks.json", options)
+	defer repo.Close()
+	rootID := buildBenchmarkTree(b, repo, 100, 10)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		task, _ := domain.NewTask("Writer", &rootID, 100)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = repo.Save(task)
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := repo.FindByParentID(&rootID); err != nil {
+				b.Errorf("failed to read children: %v", err)
+			}
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	wg.Wait()
+}
+
+// BenchmarkReadsUnderWrites_Locked reads under the read lock, waiting out each write
+func BenchmarkReadsUnderWrites_Locked(b *testing.B) {
+	benchmarkReadsUnderWrites(b, FileTaskRepositoryOptions{})
+}
+
+// BenchmarkReadsUnderWrites_Snapshot reads the latest snapshot without locking
+func BenchmarkReadsUnderWrites_Snapshot(b *testing.B) {
+	benchmarkReadsUnderWrites(b, FileTaskRepositoryOptions{SnapshotReads: true})
+}
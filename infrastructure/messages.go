@@ -0,0 +1,35 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"discovery-tree/domain"
+)
+
+// LoadMessagesFile reads user-facing error messages from a JSON object mapping error codes
+// (the "code" of an error response, e.g. "single_root") to the message to show instead
+// An empty path configures no overrides
+func LoadMessagesFile(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, WrapFileSystemError("read", path, err)
+	}
+
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, WrapFileSystemError("parse JSON", path, err)
+	}
+
+	for code, message := range messages {
+		if code == "" || message == "" {
+			return nil, domain.NewValidationError("messages", "empty-message", fmt.Sprintf("messages file '%s' has an empty code or message (code %q)", path, code))
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,210 @@
+package infrastructure
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"discovery-tree/domain"
+)
+
+// csvRow holds the fields of one CSV record, with its row number (the header is row 1)
+type csvRow struct {
+	row         int
+	id          string
+	key         string
+	description string
+	status      string
+	parent      string
+	position    string
+}
+
+// ReadTaskCSV reads tasks from CSV whose header row names the columns
+// Known columns are id, key, description, status, parentId and position, matched without
+// regard to case; only description is required and other columns are ignored.
+// A row without an id gets one from newID, regenerated if an earlier row took it, and its key, if any, lets other rows name it
+// as their parentId. A parentId may refer to a row later in the file; one naming neither
+// a key nor an id in the file must satisfy known (nil means no task outside the file exists).
+// An empty status is TODO, and an empty position appends the task after the siblings
+// listed before it. Errors are ValidationErrors whose message starts with the row number
+func ReadTaskCSV(r io.Reader, newID func() (domain.TaskID, error), known func(domain.TaskID) bool) ([]*domain.Task, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, domain.NewValidationError("csv", "missing-header", "CSV must start with a header row")
+	}
+	if err != nil {
+		return nil, csvReadError(err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["description"]; !ok {
+		return nil, domain.NewValidationError("csv", "missing-description-column", "CSV header must include a description column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[strings.ToLower(name)]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	// First pass: read every row and assign its ID, so parents may be defined later
+	var rows []csvRow
+	ids := make(map[domain.TaskID]bool)
+	keys := make(map[string]domain.TaskID)
+	var rowIDs []domain.TaskID
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, csvReadError(err)
+		}
+
+		current := csvRow{
+			row:         row,
+			id:          field(record, "id"),
+			key:         field(record, "key"),
+			description: field(record, "description"),
+			status:      field(record, "status"),
+			parent:      field(record, "parentId"),
+			position:    field(record, "position"),
+		}
+
+		var id domain.TaskID
+		if current.id == "" {
+			if id, err = newCSVRowID(newID, ids); err != nil {
+				return nil, err
+			}
+		} else {
+			id, err = parseCanonicalTaskID("id", current.id)
+			if err != nil {
+				return nil, csvRowError(row, err)
+			}
+			if ids[id] {
+				return nil, domain.NewValidationError("id", "duplicate-task-id", fmt.Sprintf("row %d: duplicate task ID %s", row, id))
+			}
+		}
+		ids[id] = true
+
+		if current.key != "" {
+			if _, exists := keys[current.key]; exists {
+				return nil, domain.NewValidationError("key", "duplicate-key", fmt.Sprintf("row %d: duplicate key %q", row, current.key))
+			}
+			keys[current.key] = id
+		}
+
+		rows = append(rows, current)
+		rowIDs = append(rowIDs, id)
+	}
+
+	// Second pass: resolve parents and build the tasks
+	now := time.Now()
+	nextPosition := make(map[string]int)
+	tasks := make([]*domain.Task, 0, len(rows))
+	for i, current := range rows {
+		var parentID *string
+		if current.parent != "" {
+			resolved, ok := resolveCSVParent(current.parent, keys, ids, known)
+			if !ok {
+				return nil, domain.NewValidationError("parentId", "unknown-parent", fmt.Sprintf("row %d: parent %q not found", current.row, current.parent))
+			}
+			parent := resolved.String()
+			parentID = &parent
+		}
+
+		siblings := ""
+		if parentID != nil {
+			siblings = *parentID
+		}
+		position := nextPosition[siblings]
+		if current.position != "" {
+			parsed, err := strconv.Atoi(current.position)
+			if err != nil {
+				return nil, domain.NewValidationError("position", "fractional-position", fmt.Sprintf("row %d: position must be a whole number, got %q", current.row, current.position))
+			}
+			position = parsed
+		}
+		nextPosition[siblings] = position + 1
+
+		status := current.status
+		if status == "" {
+			status = domain.StatusTODO.String()
+		}
+
+		task, err := FromDTO(TaskDTO{
+			ID:          rowIDs[i].String(),
+			Description: current.description,
+			Status:      status,
+			ParentID:    parentID,
+			Position:    position,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return nil, csvRowError(current.row, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// newCSVRowID returns an ID from newID that no earlier row in the file has taken
+func newCSVRowID(newID func() (domain.TaskID, error), taken map[domain.TaskID]bool) (domain.TaskID, error) {
+	for attempt := 0; attempt < 10; attempt++ {
+		id, err := newID()
+		if err != nil || !taken[id] {
+			return id, err
+		}
+	}
+	return domain.TaskID{}, fmt.Errorf("no unused task ID for a CSV row")
+}
+
+// resolveCSVParent resolves a parentId as a key in the file, an ID in the file,
+// or the ID of a task known outside the file, in that order
+func resolveCSVParent(reference string, keys map[string]domain.TaskID, ids map[domain.TaskID]bool, known func(domain.TaskID) bool) (domain.TaskID, bool) {
+	if id, ok := keys[reference]; ok {
+		return id, true
+	}
+
+	id, err := domain.TaskIDFromString(reference)
+	if err != nil {
+		return domain.TaskID{}, false
+	}
+	id = id.Canonical()
+	if ids[id] {
+		return id, true
+	}
+	if known != nil && known(id) {
+		return id, true
+	}
+	return domain.TaskID{}, false
+}
+
+// csvRowError prefixes a validation error's message with its row number
+func csvRowError(row int, err error) error {
+	var validationErr domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return domain.NewValidationError(validationErr.Field, validationErr.Rule, fmt.Sprintf("row %d: %s", row, validationErr.Message))
+	}
+	return err
+}
+
+// csvReadError reports malformed CSV, such as an unterminated quote, as a ValidationError
+func csvReadError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domain.NewValidationError("csv", "invalid-csv", fmt.Sprintf("line %d: invalid CSV: %v", parseErr.StartLine, parseErr.Err))
+	}
+	return err
+}
@@ -1,12 +1,24 @@
 package infrastructure
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	"discovery-tree/domain"
 )
 
+// DTOOptions configures how FromDTOWithOptions converts a record
+type DTOOptions struct {
+	// StrictTimestamps rejects a record with a zero CreatedAt or UpdatedAt, or an UpdatedAt
+	// before CreatedAt, with a ValidationError. Lenient (the default) sets missing times to
+	// now and clamps UpdatedAt to CreatedAt
+	StrictTimestamps bool
+}
+
 // TaskDTO is a data transfer object for JSON serialization of Task
 type TaskDTO struct {
 	ID          string     `json:"id"`
@@ -16,54 +28,174 @@ type TaskDTO struct {
 	Position    int        `json:"position"`
 	CreatedAt   time.Time  `json:"createdAt"`
 	UpdatedAt   time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"` // absent in legacy data
+	MoveHistory   []MoveRecordDTO   `json:"moveHistory,omitempty"`   // absent in legacy data
+	StatusHistory []StatusRecordDTO `json:"statusHistory,omitempty"` // absent in legacy data
+	Tags          []string          `json:"tags,omitempty"`          // absent in legacy data
+	Kind          string            `json:"kind,omitempty"`          // absent in legacy data
+	Seq           uint64            `json:"seq,omitempty"`           // absent in legacy data
+
+	// invalidPosition holds a decoded position that is not a whole number (e.g. 1.5),
+	// so FromDTO can reject it with the record's ID instead of failing the whole decode
+	invalidPosition string
+}
+
+// UnmarshalJSON decodes a TaskDTO, accepting positions written as integral floats (e.g. 1.0)
+func (dto *TaskDTO) UnmarshalJSON(data []byte) error {
+	type taskDTOFields TaskDTO
+	aux := struct {
+		*taskDTOFields
+		Position json.Number `json:"position"`
+	}{taskDTOFields: (*taskDTOFields)(dto)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	dto.Position, dto.invalidPosition = 0, ""
+	if aux.Position == "" {
+		return nil
+	}
+	if position, ok := parseWholePosition(aux.Position.String()); ok {
+		dto.Position = position
+	} else {
+		dto.invalidPosition = aux.Position.String()
+	}
+	return nil
+}
+
+// parseWholePosition parses a stored position, accepting integral floats like "1.0" or "1e2"
+// that hand-edited files contain; fractions and values outside the int range are not
+func parseWholePosition(number string) (int, bool) {
+	if n, err := strconv.Atoi(number); err == nil {
+		return n, true
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// MoveRecordDTO is a data transfer object for JSON serialization of a domain MoveRecord
+type MoveRecordDTO struct {
+	FromParentID *string   `json:"fromParentId"`
+	ToParentID   *string   `json:"toParentId"`
+	FromPosition int       `json:"fromPosition"`
+	ToPosition   int       `json:"toPosition"`
+	At           time.Time `json:"at"`
+}
+
+// StatusRecordDTO is a data transfer object for JSON serialization of a domain StatusRecord
+type StatusRecordDTO struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
 }
 
 // ToDTO converts a domain Task to a TaskDTO for JSON serialization
 func ToDTO(task *domain.Task) TaskDTO {
 	dto := TaskDTO{
-		ID:          task.ID().String(),
+		ID:          task.ID().Canonical().String(),
 		Description: task.Description(),
 		Status:      task.Status().String(),
 		Position:    task.Position(),
-		CreatedAt:   task.CreatedAt(),
-		UpdatedAt:   task.UpdatedAt(),
+		CreatedAt:   domain.NormalizeTimestamp(task.CreatedAt()),
+		UpdatedAt:   domain.NormalizeTimestamp(task.UpdatedAt()),
+		CompletedAt: normalizeTimestampPtr(task.CompletedAt()),
+		Tags:        task.Tags(),
+		Kind:        task.Kind(),
+		Seq:         task.Seq(),
 	}
 
 	// Handle nil parent ID conversion (nil -> null in JSON)
 	if task.ParentID() != nil {
-		parentIDStr := task.ParentID().String()
+		parentIDStr := task.ParentID().Canonical().String()
 		dto.ParentID = &parentIDStr
 	}
 
+	for _, record := range task.MoveHistory() {
+		dto.MoveHistory = append(dto.MoveHistory, MoveRecordDTO{
+			FromParentID: taskIDToDTO(record.FromParentID),
+			ToParentID:   taskIDToDTO(record.ToParentID),
+			FromPosition: record.FromPosition,
+			ToPosition:   record.ToPosition,
+			At:           domain.NormalizeTimestamp(record.At),
+		})
+	}
+
+	for _, record := range task.StatusHistory() {
+		dto.StatusHistory = append(dto.StatusHistory, StatusRecordDTO{
+			From: record.From.String(),
+			To:   record.To.String(),
+			At:   domain.NormalizeTimestamp(record.At),
+		})
+	}
+
 	return dto
 }
 
+// normalizeTimestampPtr normalizes an optional timestamp (see domain.NormalizeTimestamp)
+func normalizeTimestampPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	normalized := domain.NormalizeTimestamp(*t)
+	return &normalized
+}
+
+// taskIDToDTO converts an optional task ID to its canonical string (nil -> null in JSON)
+func taskIDToDTO(id *domain.TaskID) *string {
+	if id == nil {
+		return nil
+	}
+	idStr := id.Canonical().String()
+	return &idStr
+}
+
 // FromDTO converts a TaskDTO to a domain Task
 // This function reconstructs a Task from persisted data
 // It performs comprehensive validation to ensure data integrity:
-// - ID must be non-empty and valid UUID format
+// - ID must be non-empty and a short ID or valid UUID format in canonical layout (case is normalized)
 // - Description must be non-empty and not whitespace-only
-// - Position must be non-negative
+// - Position must be a non-negative whole number (integral floats like 1.0 are accepted when decoding)
 // - Status must be a valid status value
-// - Timestamps must be non-zero
-// - ParentID (if present) must be valid UUID format
+// - Timestamps must be non-zero with UpdatedAt not before CreatedAt, unless lenient (see
+//   DTOOptions.StrictTimestamps); they are normalized to UTC at domain.TimestampPrecision
+// - ParentID (if present) must be a short ID or valid UUID format in canonical layout
+// - Move history parent IDs (if present) must be short IDs or valid UUID format in canonical layout
+// - Status history statuses (if present) must be valid status values
+// - Tags (if present) must be valid tags (see domain.NormalizeTags)
+// - Kind (if present) must be a valid kind name (see domain.NormalizeKind); it is not checked
+//   against the configured kinds, so data keeps loading after a kind is unconfigured
+// Timestamps are handled leniently; use FromDTOWithOptions to reject insane ones
 func FromDTO(dto TaskDTO) (*domain.Task, error) {
+	return FromDTOWithOptions(dto, DTOOptions{})
+}
+
+// FromDTOWithOptions converts a TaskDTO to a domain Task like FromDTO, as options select
+func FromDTOWithOptions(dto TaskDTO, options DTOOptions) (*domain.Task, error) {
 	// Validate required fields
 	if dto.ID == "" {
-		return nil, domain.NewValidationError("id", "task ID cannot be empty")
+		return nil, domain.NewValidationError("id", "empty-task-id", "task ID cannot be empty")
 	}
 	if strings.TrimSpace(dto.Description) == "" {
-		return nil, domain.NewValidationError("description", "description cannot be empty")
+		return nil, domain.NewValidationError("description", "empty-description", "description cannot be empty")
+	}
+	if dto.invalidPosition != "" {
+		return nil, domain.NewValidationError("position", "fractional-position", fmt.Sprintf("position must be a whole number, got %s (task %s)", dto.invalidPosition, dto.ID))
 	}
 	if dto.Position < 0 {
-		return nil, domain.NewValidationError("position", "position must be non-negative")
+		return nil, domain.NewValidationError("position", "negative-position", fmt.Sprintf("position must be non-negative, got %d (task %s)", dto.Position, dto.ID))
 	}
-	if dto.CreatedAt.IsZero() || dto.UpdatedAt.IsZero() {
-		return nil, domain.NewValidationError("timestamps", "timestamps cannot be zero")
+	createdAt, updatedAt, err := dtoTimestamps(dto, options.StrictTimestamps)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse and validate TaskID (validates UUID format)
-	taskID, err := domain.TaskIDFromString(dto.ID)
+	// Parse and validate TaskID (validates UUID format and canonical layout)
+	taskID, err := parseCanonicalTaskID("id", dto.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -76,46 +208,140 @@ func FromDTO(dto TaskDTO) (*domain.Task, error) {
 
 	// Additional defensive check: ensure status is valid
 	if !status.IsValid() {
-		return nil, domain.NewValidationError("status", "invalid status value")
+		return nil, domain.NewValidationError("status", "invalid-status", "invalid status value")
 	}
 
 	// Parse ParentID (handle null -> nil conversion)
 	var parentID *domain.TaskID
 	if dto.ParentID != nil {
-		pid, err := domain.TaskIDFromString(*dto.ParentID)
+		pid, err := parseCanonicalTaskID("parentId", *dto.ParentID)
 		if err != nil {
 			return nil, err
 		}
 		parentID = &pid
 	}
 
-	// Reconstruct the task using reflection-like approach
-	// Since Task fields are private, we need to create it and then set fields
-	// For now, we'll use a helper function that creates a task with all fields
-	task := reconstructTask(
-		taskID,
-		dto.Description,
-		status,
-		parentID,
-		dto.Position,
-		dto.CreatedAt,
-		dto.UpdatedAt,
-	)
+	// Parse the move history, which legacy data does not have
+	var moveHistory []domain.MoveRecord
+	for _, recordDTO := range dto.MoveHistory {
+		record := domain.MoveRecord{
+			FromPosition: recordDTO.FromPosition,
+			ToPosition:   recordDTO.ToPosition,
+			At:           domain.NormalizeTimestamp(recordDTO.At),
+		}
+		if recordDTO.FromParentID != nil {
+			id, err := parseCanonicalTaskID("moveHistory.fromParentId", *recordDTO.FromParentID)
+			if err != nil {
+				return nil, err
+			}
+			record.FromParentID = &id
+		}
+		if recordDTO.ToParentID != nil {
+			id, err := parseCanonicalTaskID("moveHistory.toParentId", *recordDTO.ToParentID)
+			if err != nil {
+				return nil, err
+			}
+			record.ToParentID = &id
+		}
+		moveHistory = append(moveHistory, record)
+	}
+
+	// Parse the status history, which legacy data does not have
+	var statusHistory []domain.StatusRecord
+	for _, recordDTO := range dto.StatusHistory {
+		from, err := domain.NewStatus(recordDTO.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := domain.NewStatus(recordDTO.To)
+		if err != nil {
+			return nil, err
+		}
+		statusHistory = append(statusHistory, domain.StatusRecord{
+			From: from,
+			To:   to,
+			At:   domain.NormalizeTimestamp(recordDTO.At),
+		})
+	}
+
+	// Validate the tags, which legacy data does not have
+	tags, err := domain.NormalizeTags(dto.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the kind, which legacy data does not have; reconstruction gives it the default
+	kind := dto.Kind
+	if kind != "" {
+		if kind, err = domain.NormalizeKind(kind); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reconstruct the task with all fields set, since Task fields are private
+	task := domain.ReconstructTask(domain.TaskFields{
+		ID:            taskID,
+		Description:   dto.Description,
+		Status:        status,
+		ParentID:      parentID,
+		Position:      dto.Position,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+		CompletedAt:   normalizeTimestampPtr(dto.CompletedAt),
+		MoveHistory:   moveHistory,
+		StatusHistory: statusHistory,
+		Tags:          tags,
+		Kind:          kind,
+	})
+	task.AssignSeq(dto.Seq)
 
 	return task, nil
 }
 
-// reconstructTask creates a Task instance with all fields set
-// This is used for deserializing tasks from persistent storage
-// It delegates to the domain package's reconstruction function
-func reconstructTask(
-	id domain.TaskID,
-	description string,
-	status domain.Status,
-	parentID *domain.TaskID,
-	position int,
-	createdAt time.Time,
-	updatedAt time.Time,
-) *domain.Task {
-	return domain.ReconstructTask(id, description, status, parentID, position, createdAt, updatedAt)
+// dtoTimestamps returns the normalized CreatedAt and UpdatedAt of a record, rejecting or
+// correcting zero times and an UpdatedAt before CreatedAt unless strict
+func dtoTimestamps(dto TaskDTO, strict bool) (time.Time, time.Time, error) {
+	now := domain.NormalizeTimestamp(time.Now())
+
+	createdAt := domain.NormalizeTimestamp(dto.CreatedAt)
+	if dto.CreatedAt.IsZero() {
+		if strict {
+			return time.Time{}, time.Time{}, domain.NewValidationError("createdAt", "zero-timestamp", fmt.Sprintf("createdAt cannot be zero (task %s)", dto.ID))
+		}
+		createdAt = now
+	}
+
+	updatedAt := domain.NormalizeTimestamp(dto.UpdatedAt)
+	if dto.UpdatedAt.IsZero() {
+		if strict {
+			return time.Time{}, time.Time{}, domain.NewValidationError("updatedAt", "zero-timestamp", fmt.Sprintf("updatedAt cannot be zero (task %s)", dto.ID))
+		}
+		updatedAt = now
+	}
+
+	if updatedAt.Before(createdAt) {
+		if strict {
+			return time.Time{}, time.Time{}, domain.NewValidationError("updatedAt", "updated-before-created", fmt.Sprintf("updatedAt %s is before createdAt %s (task %s)",
+				updatedAt.Format(time.RFC3339Nano), createdAt.Format(time.RFC3339Nano), dto.ID))
+		}
+		updatedAt = createdAt
+	}
+
+	return createdAt, updatedAt, nil
+}
+
+// parseCanonicalTaskID parses a persisted task ID and normalizes it to canonical form
+// Uppercase IDs are lowercased; parseable but non-standard layouts (braces, URN prefix,
+// missing hyphens) are rejected so stored IDs always match the format clients see
+func parseCanonicalTaskID(field, s string) (domain.TaskID, error) {
+	id, err := domain.TaskIDFromString(s)
+	if err != nil {
+		return domain.TaskID{}, err
+	}
+
+	if !domain.HasCanonicalLayout(s) {
+		return domain.TaskID{}, domain.NewValidationError(field, "invalid-task-id", "task ID must be a short ID or in canonical UUID format")
+	}
+
+	return id.Canonical(), nil
 }
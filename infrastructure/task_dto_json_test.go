@@ -162,3 +162,82 @@ func TestTaskDTO_WithParentJSON(t *testing.T) {
 		t.Errorf("ParentID mismatch: expected %s, got %s", parentIDStr, *unmarshaledDTO.ParentID)
 	}
 }
+
+func TestFromDTO_LegacyDataWithoutCompletedAt(t *testing.T) {
+	// Legacy files have no completedAt key at all
+	data := []byte(`{
+		"id": "550e8400-e29b-41d4-a716-446655440000",
+		"description": "Legacy done task",
+		"status": "DONE",
+		"parentId": null,
+		"position": 0,
+		"createdAt": "2024-01-01T00:00:00Z",
+		"updatedAt": "2024-01-02T00:00:00Z"
+	}`)
+
+	var dto TaskDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		t.Fatalf("Failed to unmarshal legacy DTO: %v", err)
+	}
+
+	task, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Expected legacy data to load, got %v", err)
+	}
+	if task.CompletedAt() != nil {
+		t.Errorf("Expected nil CompletedAt for legacy data, got %v", *task.CompletedAt())
+	}
+}
+
+func TestFromDTO_FloatPositions(t *testing.T) {
+	tests := []struct {
+		name             string
+		position         string
+		expectedPosition int
+		expectedError    string
+	}{
+		{"IntegralFloat", "1.0", 1, ""},
+		{"Exponent", "2e0", 2, ""},
+		{"Fraction", "1.5", 0, "position must be a whole number, got 1.5 (task 550e8400-e29b-41d4-a716-446655440000)"},
+		{"Negative", "-1.0", 0, "position must be non-negative, got -1 (task 550e8400-e29b-41d4-a716-446655440000)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(`{
+				"id": "550e8400-e29b-41d4-a716-446655440000",
+				"description": "Hand-edited task",
+				"status": "TODO",
+				"parentId": null,
+				"position": ` + tt.position + `,
+				"createdAt": "2024-01-01T00:00:00Z",
+				"updatedAt": "2024-01-02T00:00:00Z"
+			}`)
+
+			// Decoding never fails on the position itself
+			var dto TaskDTO
+			if err := json.Unmarshal(data, &dto); err != nil {
+				t.Fatalf("Failed to unmarshal DTO: %v", err)
+			}
+
+			task, err := FromDTO(dto)
+			if tt.expectedError != "" {
+				validationErr, ok := err.(domain.ValidationError)
+				if !ok {
+					t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+				}
+				if validationErr.Field != "position" || validationErr.Message != tt.expectedError {
+					t.Errorf("Expected %q on position, got %q on %s", tt.expectedError, validationErr.Message, validationErr.Field)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if task.Position() != tt.expectedPosition {
+				t.Errorf("Expected position %d, got %d", tt.expectedPosition, task.Position())
+			}
+		})
+	}
+}
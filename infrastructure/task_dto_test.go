@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"discovery-tree/domain"
+	"strings"
 	"testing"
 	"time"
 )
@@ -110,11 +111,12 @@ func TestFromDTO_WithParent(t *testing.T) {
 	if task.Position() != dto.Position {
 		t.Errorf("Expected position %d, got %d", dto.Position, task.Position())
 	}
-	if !task.CreatedAt().Equal(dto.CreatedAt) {
-		t.Errorf("Expected createdAt %v, got %v", dto.CreatedAt, task.CreatedAt())
+	// Timestamps are normalized to microseconds in UTC
+	if !task.CreatedAt().Equal(domain.NormalizeTimestamp(dto.CreatedAt)) {
+		t.Errorf("Expected createdAt %v, got %v", domain.NormalizeTimestamp(dto.CreatedAt), task.CreatedAt())
 	}
-	if !task.UpdatedAt().Equal(dto.UpdatedAt) {
-		t.Errorf("Expected updatedAt %v, got %v", dto.UpdatedAt, task.UpdatedAt())
+	if !task.UpdatedAt().Equal(domain.NormalizeTimestamp(dto.UpdatedAt)) {
+		t.Errorf("Expected updatedAt %v, got %v", domain.NormalizeTimestamp(dto.UpdatedAt), task.UpdatedAt())
 	}
 }
 
@@ -188,6 +190,8 @@ func TestFromDTO_RoundTrip(t *testing.T) {
 }
 
 func TestFromDTO_InvalidData(t *testing.T) {
+	urnParentID := "urn:uuid:650e8400-e29b-41d4-a716-446655440000"
+
 	tests := []struct {
 		name string
 		dto  TaskDTO
@@ -292,36 +296,37 @@ func TestFromDTO_InvalidData(t *testing.T) {
 			},
 		},
 		{
-			name: "zero CreatedAt timestamp",
+			name: "braced ID",
 			dto: TaskDTO{
-				ID:          "550e8400-e29b-41d4-a716-446655440000",
+				ID:          "{550e8400-e29b-41d4-a716-446655440000}",
 				Description: "Test",
 				Status:      "TODO",
 				Position:    0,
-				CreatedAt:   time.Time{}, // zero value
+				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
 		},
 		{
-			name: "zero UpdatedAt timestamp",
+			name: "unhyphenated ID",
 			dto: TaskDTO{
-				ID:          "550e8400-e29b-41d4-a716-446655440000",
+				ID:          "550e8400e29b41d4a716446655440000",
 				Description: "Test",
 				Status:      "TODO",
 				Position:    0,
 				CreatedAt:   time.Now(),
-				UpdatedAt:   time.Time{}, // zero value
+				UpdatedAt:   time.Now(),
 			},
 		},
 		{
-			name: "both timestamps zero",
+			name: "URN-prefixed parent ID",
 			dto: TaskDTO{
 				ID:          "550e8400-e29b-41d4-a716-446655440000",
 				Description: "Test",
 				Status:      "TODO",
+				ParentID:    &urnParentID,
 				Position:    0,
-				CreatedAt:   time.Time{}, // zero value
-				UpdatedAt:   time.Time{}, // zero value
+				CreatedAt:   time.Now(),
+				UpdatedAt:   time.Now(),
 			},
 		},
 	}
@@ -335,3 +340,301 @@ func TestFromDTO_InvalidData(t *testing.T) {
 		})
 	}
 }
+
+// timestampDTO returns a valid DTO with the given timestamps
+func timestampDTO(createdAt, updatedAt time.Time) TaskDTO {
+	return TaskDTO{
+		ID:          "550e8400-e29b-41d4-a716-446655440000",
+		Description: "Test",
+		Status:      "TODO",
+		Position:    0,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+func TestFromDTO_StrictTimestamps(t *testing.T) {
+	strict := DTOOptions{StrictTimestamps: true}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name  string
+		dto   TaskDTO
+		field string
+	}{
+		{"zero CreatedAt timestamp", timestampDTO(time.Time{}, created), "createdAt"},
+		{"zero UpdatedAt timestamp", timestampDTO(created, time.Time{}), "updatedAt"},
+		{"both timestamps zero", timestampDTO(time.Time{}, time.Time{}), "createdAt"},
+		{"UpdatedAt before CreatedAt", timestampDTO(created, created.Add(-time.Second)), "updatedAt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromDTOWithOptions(tt.dto, strict)
+			validationErr, ok := err.(domain.ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError for %s, got %v", tt.name, err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("Expected field %s, got %s", tt.field, validationErr.Field)
+			}
+			if !strings.Contains(validationErr.Message, tt.dto.ID) {
+				t.Errorf("Expected message to name task %s, got %q", tt.dto.ID, validationErr.Message)
+			}
+		})
+	}
+
+	// Sane timestamps are accepted
+	if _, err := FromDTOWithOptions(timestampDTO(created, created), strict); err != nil {
+		t.Errorf("Expected equal timestamps to be accepted, got %v", err)
+	}
+}
+
+func TestFromDTO_LenientTimestamps(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := time.Now().UTC().Add(-time.Second)
+
+	// Missing times are set to now
+	task, err := FromDTO(timestampDTO(time.Time{}, time.Time{}))
+	if err != nil {
+		t.Fatalf("Expected zero timestamps to be corrected, got %v", err)
+	}
+	if task.CreatedAt().Before(before) || task.UpdatedAt().Before(task.CreatedAt()) {
+		t.Errorf("Expected timestamps set to now, got created %v updated %v", task.CreatedAt(), task.UpdatedAt())
+	}
+
+	task, err = FromDTO(timestampDTO(created, time.Time{}))
+	if err != nil {
+		t.Fatalf("Expected zero UpdatedAt to be corrected, got %v", err)
+	}
+	if !task.CreatedAt().Equal(created) || task.UpdatedAt().Before(before) {
+		t.Errorf("Expected CreatedAt kept and UpdatedAt set to now, got created %v updated %v", task.CreatedAt(), task.UpdatedAt())
+	}
+
+	// UpdatedAt before CreatedAt is clamped to CreatedAt
+	task, err = FromDTO(timestampDTO(created, created.Add(-time.Hour)))
+	if err != nil {
+		t.Fatalf("Expected UpdatedAt before CreatedAt to be corrected, got %v", err)
+	}
+	if !task.UpdatedAt().Equal(created) {
+		t.Errorf("Expected UpdatedAt clamped to %v, got %v", created, task.UpdatedAt())
+	}
+}
+
+func TestFromDTO_CanonicalizesUppercaseIDs(t *testing.T) {
+	parentID := "650E8400-E29B-41D4-A716-446655440000"
+	dto := TaskDTO{
+		ID:          "550E8400-E29B-41D4-A716-446655440000",
+		Description: "Test",
+		Status:      "TODO",
+		ParentID:    &parentID,
+		Position:    0,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	task, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Expected uppercase IDs to be accepted, got %v", err)
+	}
+
+	if !task.ID().IsCanonical() {
+		t.Errorf("Expected canonical task ID, got %s", task.ID().String())
+	}
+	if task.ID().String() != "550e8400-e29b-41d4-a716-446655440000" {
+		t.Errorf("Expected lowercase task ID, got %s", task.ID().String())
+	}
+
+	// Round trip back to a DTO emits the canonical lowercase form
+	out := ToDTO(task)
+	if out.ID != "550e8400-e29b-41d4-a716-446655440000" {
+		t.Errorf("Expected canonical ID in DTO, got %s", out.ID)
+	}
+	if out.ParentID == nil || *out.ParentID != "650e8400-e29b-41d4-a716-446655440000" {
+		t.Errorf("Expected canonical parent ID in DTO, got %v", out.ParentID)
+	}
+}
+
+func TestDTO_CompletedAtRoundTrip(t *testing.T) {
+	parentTask, _ := domain.NewTask("Parent", nil, 0)
+	parentID := parentTask.ID()
+	task, _ := domain.NewTask("Done task", &parentID, 0)
+	_ = task.ChangeStatus(domain.StatusDONE)
+
+	dto := ToDTO(task)
+	if dto.CompletedAt == nil {
+		t.Fatal("Expected CompletedAt in DTO for DONE task")
+	}
+
+	reconstructed, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Failed to reconstruct task: %v", err)
+	}
+	if reconstructed.CompletedAt() == nil || !reconstructed.CompletedAt().Equal(*task.CompletedAt()) {
+		t.Errorf("CompletedAt mismatch: expected %v, got %v", task.CompletedAt(), reconstructed.CompletedAt())
+	}
+}
+
+func TestDTO_MoveHistoryRoundTrip(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+	rootID := root.ID()
+	a, _ := service.CreateChildTask("A", rootID)
+	b, _ := service.CreateChildTask("B", rootID)
+	bID := b.ID()
+	if err := service.MoveTask(a.ID(), &bID, 0); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	moved, _ := repo.FindByID(a.ID())
+
+	dto := ToDTO(moved)
+	if len(dto.MoveHistory) != 1 {
+		t.Fatalf("Expected 1 move history entry in DTO, got %d", len(dto.MoveHistory))
+	}
+
+	reconstructed, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Failed to reconstruct task: %v", err)
+	}
+
+	history := reconstructed.MoveHistory()
+	if len(history) != 1 {
+		t.Fatalf("Expected 1 move history entry, got %d", len(history))
+	}
+	if !history[0].FromParentID.Equals(rootID) || !history[0].ToParentID.Equals(bID) {
+		t.Errorf("Parent mismatch: expected %v -> %v, got %v -> %v", rootID, bID, history[0].FromParentID, history[0].ToParentID)
+	}
+	if history[0].FromPosition != 0 || history[0].ToPosition != 0 {
+		t.Errorf("Position mismatch: expected 0 -> 0, got %d -> %d", history[0].FromPosition, history[0].ToPosition)
+	}
+	if !history[0].At.Equal(moved.MoveHistory()[0].At) {
+		t.Errorf("At mismatch: expected %v, got %v", moved.MoveHistory()[0].At, history[0].At)
+	}
+}
+
+func TestDTO_StatusHistoryRoundTrip(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+	a, _ := service.CreateChildTask("A", root.ID())
+	for _, status := range []domain.Status{domain.StatusInProgress, domain.StatusDONE} {
+		if err := service.ChangeTaskStatus(a.ID(), status); err != nil {
+			t.Fatalf("Failed to change status: %v", err)
+		}
+	}
+	changed, _ := repo.FindByID(a.ID())
+
+	dto := ToDTO(changed)
+	if len(dto.StatusHistory) != 2 {
+		t.Fatalf("Expected 2 status history entries in DTO, got %d", len(dto.StatusHistory))
+	}
+
+	reconstructed, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Failed to reconstruct task: %v", err)
+	}
+
+	history := reconstructed.StatusHistory()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 status history entries, got %d", len(history))
+	}
+	if history[1].From != domain.StatusInProgress || history[1].To != domain.StatusDONE {
+		t.Errorf("Status mismatch: expected In Progress -> DONE, got %s -> %s", history[1].From, history[1].To)
+	}
+	if !history[1].At.Equal(changed.StatusHistory()[1].At) {
+		t.Errorf("At mismatch: expected %v, got %v", changed.StatusHistory()[1].At, history[1].At)
+	}
+
+	// Invalid stored statuses are rejected
+	dto.StatusHistory[0].To = "Shipped"
+	if _, err := FromDTO(dto); err == nil {
+		t.Error("Expected error for invalid status in history, got nil")
+	}
+}
+
+func TestDTO_TagsRoundTrip(t *testing.T) {
+	task, err := domain.NewTask("Tagged", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	task.UpdateTags([]string{"ux", "backend"}, nil)
+
+	dto := ToDTO(task)
+	reconstructed, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Failed to reconstruct task: %v", err)
+	}
+
+	tags := reconstructed.Tags()
+	if len(tags) != 2 || tags[0] != "backend" || tags[1] != "ux" {
+		t.Errorf("Expected tags [backend ux], got %v", tags)
+	}
+
+	// Invalid stored tags are rejected
+	dto.Tags = []string{"has space"}
+	if _, err := FromDTO(dto); err == nil {
+		t.Error("Expected error for invalid tag, got nil")
+	}
+}
+
+func TestDTO_KindRoundTrip(t *testing.T) {
+	task, err := domain.NewTask("Try the new parser", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	task.UpdateKind("spike")
+
+	dto := ToDTO(task)
+	if dto.Kind != "spike" {
+		t.Errorf("Expected DTO kind spike, got %q", dto.Kind)
+	}
+	reconstructed, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Failed to reconstruct task: %v", err)
+	}
+	if reconstructed.Kind() != "spike" {
+		t.Errorf("Expected kind spike, got %q", reconstructed.Kind())
+	}
+
+	// Legacy data without a kind loads with the default kind
+	dto.Kind = ""
+	if legacy, err := FromDTO(dto); err != nil || legacy.Kind() != domain.DefaultTaskKind {
+		t.Errorf("Expected legacy data to load as kind %q, got %v", domain.DefaultTaskKind, err)
+	}
+
+	// Invalid stored kinds are rejected
+	dto.Kind = "has space"
+	if _, err := FromDTO(dto); err == nil {
+		t.Error("Expected error for invalid kind, got nil")
+	}
+}
+
+func TestDTO_ShortIDRoundTrip(t *testing.T) {
+	repo := domain.NewInMemoryTaskRepository()
+	service := domain.NewTaskService(repo)
+	root, _ := service.CreateRootTask("Root")
+
+	shortService := domain.NewTaskServiceWithConfig(repo, domain.TaskServiceConfig{IDGenerator: domain.ShortIDGenerator{}})
+	child, err := shortService.CreateChildTask("Child", root.ID())
+	if err != nil {
+		t.Fatalf("Failed to create child: %v", err)
+	}
+
+	dto := ToDTO(child)
+	if dto.ID != child.ID().String() || *dto.ParentID != root.ID().String() {
+		t.Errorf("Expected IDs %s (parent %s), got %s (parent %s)", child.ID(), root.ID(), dto.ID, *dto.ParentID)
+	}
+
+	// Uppercase short IDs are normalized like UUIDs
+	dto.ID = strings.ToUpper(dto.ID)
+	reconstructed, err := FromDTO(dto)
+	if err != nil {
+		t.Fatalf("Failed to reconstruct task: %v", err)
+	}
+	if !reconstructed.ID().Equals(child.ID()) {
+		t.Errorf("Expected ID %s, got %s", child.ID(), reconstructed.ID())
+	}
+	if !reconstructed.ParentID().Equals(root.ID()) {
+		t.Errorf("Expected legacy UUID parent %s, got %s", root.ID(), reconstructed.ParentID())
+	}
+}
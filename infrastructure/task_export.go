@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"discovery-tree/domain"
+)
+
+// The renderers below take tasks in depth-first order with the subtree root first, as
+// returned by FindSubtree. Any task may be the root: a task whose parent is not among
+// the tasks is rendered as a top-level task
+
+// WriteTaskJSONL writes one TaskDTO JSON object per line, the format ReadTaskJSONL reads
+func WriteTaskJSONL(w io.Writer, tasks []*domain.Task) error {
+	// Encode terminates every object with a newline
+	encoder := json.NewEncoder(w)
+	for _, task := range tasks {
+		if err := encoder.Encode(ToDTO(task)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTaskCSV writes the tasks as CSV with an id, description, status, parentId and
+// position header, the columns ReadTaskCSV reads. A task whose parent is not among the
+// tasks is written without parentId at position 0, so an exported subtree imports as its own tree
+func WriteTaskCSV(w io.Writer, tasks []*domain.Task) error {
+	included := taskIDSet(tasks)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "description", "status", "parentId", "position"}); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		parentID, position := "", 0
+		if task.ParentID() != nil && included[*task.ParentID()] {
+			parentID, position = task.ParentID().Canonical().String(), task.Position()
+		}
+		record := []string{
+			task.ID().Canonical().String(),
+			task.Description(),
+			task.Status().String(),
+			parentID,
+			strconv.Itoa(position),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTaskMarkdown writes the tasks as a nested Markdown checklist, indented two spaces
+// per level below the top-level tasks. Complete tasks are checked, and statuses other than
+// TODO and the complete ones follow the description in italics
+func WriteTaskMarkdown(w io.Writer, tasks []*domain.Task, complete domain.CompleteStatuses) error {
+	depths := make(map[domain.TaskID]int, len(tasks))
+	buffered := bufio.NewWriter(w)
+	for _, task := range tasks {
+		depth := 0
+		if task.ParentID() != nil {
+			if parentDepth, ok := depths[*task.ParentID()]; ok {
+				depth = parentDepth + 1
+			}
+		}
+		depths[task.ID()] = depth
+
+		check := " "
+		if complete.Contains(task.Status()) {
+			check = "x"
+		}
+		line := fmt.Sprintf("%s- [%s] %s", strings.Repeat("  ", depth), check, markdownText(task.Description()))
+		if !complete.Contains(task.Status()) && task.Status() != domain.StatusTODO {
+			line += fmt.Sprintf(" _(%s)_", task.Status())
+		}
+		if _, err := buffered.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+// markdownText keeps a description on one list item line and stops it from being read as markup
+func markdownText(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	replacer := strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+	return replacer.Replace(description)
+}
+
+// taskIDSet returns the IDs of the tasks
+func taskIDSet(tasks []*domain.Task) map[domain.TaskID]bool {
+	set := make(map[domain.TaskID]bool, len(tasks))
+	for _, task := range tasks {
+		set[task.ID()] = true
+	}
+	return set
+}
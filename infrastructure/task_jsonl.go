@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"discovery-tree/domain"
+)
+
+// maxJSONLLineSize bounds a single JSON Lines record (1 MiB)
+const maxJSONLLineSize = 1024 * 1024
+
+// ReadTaskJSONL reads one TaskDTO JSON object per line and converts each to a domain Task
+// Blank lines are skipped. The first malformed record is reported as a ValidationError
+// whose message starts with its 1-based line number
+func ReadTaskJSONL(r io.Reader) ([]*domain.Task, error) {
+	return ReadTaskJSONLWithOptions(r, DTOOptions{})
+}
+
+// ReadTaskJSONLWithOptions reads tasks like ReadTaskJSONL, converting each record as options select
+func ReadTaskJSONLWithOptions(r io.Reader, options DTOOptions) ([]*domain.Task, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJSONLLineSize)
+
+	var tasks []*domain.Task
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var dto TaskDTO
+		if err := json.Unmarshal([]byte(line), &dto); err != nil {
+			return nil, domain.NewValidationError("line", "invalid-json", fmt.Sprintf("line %d: invalid JSON: %v", lineNumber, err))
+		}
+
+		task, err := FromDTOWithOptions(dto, options)
+		if err != nil {
+			var validationErr domain.ValidationError
+			if errors.As(err, &validationErr) {
+				return nil, domain.NewValidationError(validationErr.Field, validationErr.Rule, fmt.Sprintf("line %d: %s", lineNumber, validationErr.Message))
+			}
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, domain.NewValidationError("line", "record-too-large", fmt.Sprintf("line %d: record exceeds %d bytes", lineNumber+1, maxJSONLLineSize))
+		}
+		return nil, err
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"discovery-tree/domain"
+)
+
+// templateFileExt is the extension of template files; the rest of the file name is the template name
+const templateFileExt = ".json"
+
+// DirTemplateRepository reads task templates from the nested tree JSON files in a directory
+// The directory is read on every call, so templates can be added or edited without a restart
+// An empty directory path means no templates are configured
+type DirTemplateRepository struct {
+	dir string
+}
+
+// NewDirTemplateRepository creates a DirTemplateRepository for the given directory
+func NewDirTemplateRepository(dir string) *DirTemplateRepository {
+	return &DirTemplateRepository{dir: dir}
+}
+
+// FindAll retrieves all templates, ordered by name
+// A missing directory has no templates; an unreadable or invalid template file is listed with Err set
+func (r *DirTemplateRepository) FindAll() ([]domain.TaskTemplate, error) {
+	templates := []domain.TaskTemplate{}
+	if r.dir == "" {
+		return templates, nil
+	}
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return templates, nil
+		}
+		return nil, WrapFileSystemError("read directory", r.dir, err)
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), templateFileExt)
+		if entry.IsDir() || !ok || !isValidTemplateName(name) {
+			continue
+		}
+
+		template, err := r.load(name)
+		if err != nil {
+			template = domain.TaskTemplate{Name: name, Err: err}
+		}
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// FindByName retrieves a template by its name, or returns a NotFoundError
+func (r *DirTemplateRepository) FindByName(name string) (domain.TaskTemplate, error) {
+	// Names never contain path elements, so a lookup cannot leave the directory
+	if r.dir == "" || !isValidTemplateName(name) {
+		return domain.TaskTemplate{}, domain.NewNotFoundError("Template", name)
+	}
+
+	template, err := r.load(name)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return domain.TaskTemplate{}, domain.NewNotFoundError("Template", name)
+	}
+	return template, err
+}
+
+// load reads and converts the template file for name
+func (r *DirTemplateRepository) load(name string) (domain.TaskTemplate, error) {
+	root, err := LoadTreeImportFile(filepath.Join(r.dir, name+templateFileExt))
+	if err != nil {
+		return domain.TaskTemplate{}, err
+	}
+	return domain.TaskTemplate{Name: name, Root: root}, nil
+}
+
+// isValidTemplateName reports whether name can name a template file: non-empty,
+// not hidden, and free of path separators
+func isValidTemplateName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, ".") && !strings.ContainsAny(name, `/\`)
+}
@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"discovery-tree/domain"
+)
+
+func TestDirTemplateRepository(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"sprint.json":    `{"description": "Sprint", "children": [{"description": "Plan"}, {"description": "Review"}]}`,
+		"discovery.json": `{"description": "Discovery", "status": "Root Work Item"}`,
+		"notes.txt":      `not a template`,
+		".hidden.json":   `{"description": "Hidden"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	repo := NewDirTemplateRepository(dir)
+
+	templates, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "discovery" || templates[1].Name != "sprint" {
+		t.Fatalf("expected discovery and sprint, got %v", templates)
+	}
+
+	sprint, err := repo.FindByName("sprint")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sprint.Root.Description != "Sprint" || sprint.Root.Count() != 3 {
+		t.Errorf("expected Sprint with 3 tasks, got %s with %d", sprint.Root.Description, sprint.Root.Count())
+	}
+
+	// Unknown names and names reaching outside the directory are not found
+	for _, name := range []string{"missing", "../sprint", ".hidden", ""} {
+		if _, err := repo.FindByName(name); err == nil {
+			t.Errorf("expected NotFoundError for %q, got nil", name)
+		} else if _, ok := err.(domain.NotFoundError); !ok {
+			t.Errorf("expected NotFoundError for %q, got %T", name, err)
+		}
+	}
+}
+
+func TestDirTemplateRepository_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sprint.json"), []byte(`{"description": "Sprint"}`), 0644); err != nil {
+		t.Fatalf("failed to write sprint.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"description": `), 0644); err != nil {
+		t.Fatalf("failed to write broken.json: %v", err)
+	}
+
+	repo := NewDirTemplateRepository(dir)
+
+	// A broken file is listed with its error instead of failing the whole list
+	templates, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "broken" || templates[1].Name != "sprint" {
+		t.Fatalf("expected broken and sprint, got %v", templates)
+	}
+	if templates[0].Err == nil {
+		t.Error("expected the broken template to carry its load error")
+	}
+	if templates[1].Err != nil || templates[1].Root.Description != "Sprint" {
+		t.Errorf("expected sprint to load, got %+v", templates[1])
+	}
+
+	if _, err := repo.FindByName("broken"); err == nil {
+		t.Error("expected FindByName to fail for the broken template")
+	}
+}
+
+func TestDirTemplateRepository_NotConfigured(t *testing.T) {
+	for _, dir := range []string{"", filepath.Join(t.TempDir(), "missing")} {
+		repo := NewDirTemplateRepository(dir)
+
+		templates, err := repo.FindAll()
+		if err != nil || len(templates) != 0 {
+			t.Errorf("expected no templates for %q, got %v (%v)", dir, templates, err)
+		}
+		if _, err := repo.FindByName("sprint"); err == nil {
+			t.Errorf("expected NotFoundError for %q, got nil", dir)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"os"
+
+	"discovery-tree/domain"
+)
+
+// TreeNodeDTO is a data transfer object for a nested tree of tasks in JSON
+type TreeNodeDTO struct {
+	Description string        `json:"description"`
+	Status      string        `json:"status,omitempty"` // empty uses the default status
+	Children    []TreeNodeDTO `json:"children,omitempty"`
+}
+
+// ToImportNode converts a TreeNodeDTO to a domain TreeImportNode
+// Returns a ValidationError if any status value is invalid
+func (dto TreeNodeDTO) ToImportNode() (domain.TreeImportNode, error) {
+	node := domain.TreeImportNode{
+		Description: dto.Description,
+	}
+
+	if dto.Status != "" {
+		status, err := domain.NewStatus(dto.Status)
+		if err != nil {
+			return domain.TreeImportNode{}, err
+		}
+		node.Status = &status
+	}
+
+	for _, childDTO := range dto.Children {
+		child, err := childDTO.ToImportNode()
+		if err != nil {
+			return domain.TreeImportNode{}, err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+// LoadTreeImportFile reads a nested tree JSON file and converts it to a domain TreeImportNode
+func LoadTreeImportFile(path string) (domain.TreeImportNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.TreeImportNode{}, WrapFileSystemError("read", path, err)
+	}
+
+	var dto TreeNodeDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return domain.TreeImportNode{}, WrapFileSystemError("parse JSON", path, err)
+	}
+
+	return dto.ToImportNode()
+}
@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errRepositoryClosed is returned for changes made after the repository was closed
+var errRepositoryClosed = errors.New("repository is closed")
+
+// writeCoalescer hands persistence to a single background goroutine so that changes
+// requested while a write is in progress are batched into the next write (group commit)
+// Each requester waits for a write that includes its change and receives that write's error
+// A debounce delays each write after the writer is woken, so a burst of changes shares one write
+type writeCoalescer struct {
+	write    func() error  // writes a snapshot covering every change requested before it was called
+	debounce time.Duration // delay between waking and snapshotting
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	requested uint64 // sequence number of the latest requested change
+	completed uint64 // sequence number covered by the latest finished write
+	writes    uint64 // number of writes performed
+	lastErr   error  // result of the latest finished write
+	closed    bool
+
+	wake chan struct{} // buffered wake-up; never closed, so a late request cannot send on a closed channel
+	stop chan struct{} // closed by close once pending changes are flushed
+	done chan struct{} // closed when the background writer exits
+}
+
+// newWriteCoalescer creates a writeCoalescer and starts its background writer
+func newWriteCoalescer(write func() error, debounce time.Duration) *writeCoalescer {
+	w := &writeCoalescer{
+		write:    write,
+		debounce: debounce,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// request records a change and wakes the writer, returning the sequence number to wait for
+// Callers must apply the change to the in-memory collection before calling request
+func (w *writeCoalescer) request() (uint64, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, errRepositoryClosed
+	}
+	w.requested++
+	seq := w.requested
+	w.mu.Unlock()
+
+	// A pending wake-up already covers this change
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	return seq, nil
+}
+
+// wait blocks until a write covering seq has finished and returns its error
+func (w *writeCoalescer) wait(seq uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.completed < seq {
+		w.cond.Wait()
+	}
+	return w.lastErr
+}
+
+// flush waits for every change requested so far to be written
+func (w *writeCoalescer) flush() error {
+	w.mu.Lock()
+	seq := w.requested
+	w.mu.Unlock()
+	return w.wait(seq)
+}
+
+// stats reports how the changes requested so far map to writes
+func (w *writeCoalescer) stats() PersistStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return PersistStats{
+		Requested: w.requested,
+		Flushed:   w.writes,
+		Coalesced: w.completed - w.writes,
+		Pending:   w.requested - w.completed,
+	}
+}
+
+// close flushes pending changes and stops the background writer
+func (w *writeCoalescer) close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	// Requests that passed the closed check before it was set are included in the flush
+	err := w.flush()
+	close(w.stop)
+	<-w.done
+	return err
+}
+
+// run writes until every requested change is covered, each time it is woken, until stopped
+func (w *writeCoalescer) run() {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.wake:
+		case <-w.stop:
+			return
+		}
+
+		// Let changes arriving shortly after this one join its write
+		if w.debounce > 0 {
+			time.Sleep(w.debounce)
+		}
+
+		for {
+			w.mu.Lock()
+			target := w.requested
+			pending := target > w.completed
+			w.mu.Unlock()
+			if !pending {
+				break
+			}
+
+			err := w.write()
+
+			w.mu.Lock()
+			w.completed = target
+			w.writes++
+			w.lastErr = err
+			w.cond.Broadcast()
+			w.mu.Unlock()
+		}
+	}
+}